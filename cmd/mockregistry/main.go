@@ -0,0 +1,37 @@
+// Command mockregistry serves a stand-in government person registry on
+// localhost, so registry-backed verification strategies can be tried
+// without access to the real one.
+package main
+
+import (
+	"AsaExchange/internal/adapters/govregistry"
+	"AsaExchange/internal/shared/logger"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+)
+
+func main() {
+	listen := flag.String("listen", "localhost:8090", "address to listen on")
+	peopleFile := flag.String("people", "", "JSON file with the people the registry knows (default: built-in samples)")
+	flag.Parse()
+
+	log := logger.New(true)
+
+	people := govregistry.SamplePeople
+	if *peopleFile != "" {
+		data, err := os.ReadFile(*peopleFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to read people file")
+		}
+		if err := json.Unmarshal(data, &people); err != nil {
+			log.Fatal().Err(err).Msg("Failed to parse people file")
+		}
+	}
+
+	log.Info().Str("listen", *listen).Int("people", len(people)).Msg("Stand-in registry listening")
+	if err := http.ListenAndServe(*listen, govregistry.NewStandIn(people, &log)); err != nil {
+		log.Fatal().Err(err).Msg("Stand-in registry stopped")
+	}
+}
@@ -2,9 +2,13 @@ package main
 
 import (
 	"AsaExchange/internal/adapters/eventbus"
+	"AsaExchange/internal/adapters/govregistry"
 	"AsaExchange/internal/adapters/postgres"
 	"AsaExchange/internal/adapters/security"
 	"AsaExchange/internal/adapters/telegram"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
 	"AsaExchange/internal/shared/config"
 	"AsaExchange/internal/shared/logger"
 	"context"
@@ -54,19 +58,67 @@ func main() {
 
 	// 4. Initialize Repositories
 	userRepo := postgres.NewUserRepository(db, secSvc, &baseLogger)
-	_ = postgres.NewUserBankAccountRepository(db, secSvc, &baseLogger)
+	bankRepo := postgres.NewUserBankAccountRepository(db, secSvc, &baseLogger)
+	platformRepo := postgres.NewPlatformAccountRepository(db, secSvc, &baseLogger)
+	requestRepo := postgres.NewRequestRepository(db, &baseLogger)
+	bidRepo := postgres.NewBidRepository(db, &baseLogger)
+	txRepo := postgres.NewTransactionRepository(db, &baseLogger)
+	disputeRepo := postgres.NewDisputeRepository(db, &baseLogger)
+	proofRepo := postgres.NewPayoutProofRepository(db, secSvc, &baseLogger)
+	feeRepo := postgres.NewFeeScheduleRepository(db, &baseLogger)
+	locker := postgres.NewAdvisoryLocker(db, &baseLogger)
+	outboxRepo := postgres.NewOutboxRepository(db, secSvc, &baseLogger)
+	deadLetterRepo := postgres.NewDeadLetterRepository(db, secSvc, &baseLogger)
 
-	// Create the EventBus first
-	bus := eventbus.NewInMemoryEventBus(&baseLogger)
+	// Create the EventBus first. Events go through the outbox, so they
+	// survive a restart; the orchestrator starts delivering them.
+	bus := eventbus.NewOutboxEventBus(outboxRepo, deadLetterRepo, &baseLogger)
+
+	// 5. Initialize Core Services
+	txService := services.NewTransactionService(txRepo, db, bus, &baseLogger)
+	platformAccounts := services.NewPlatformAccountService(platformRepo, &baseLogger)
+	requestService := services.NewRequestService(requestRepo, db, bus, &baseLogger)
+	disputes := services.NewDisputeService(disputeRepo, bidRepo, txService, requestService, db, bus, &baseLogger)
+	// A completed trade closes its request
+	events.SubscribeWithRetry(bus, "request_service", requestService.HandleTransactionCompleted, ports.DefaultRetryPolicy)
+	cancellations := services.NewCancellationService(requestRepo, bidRepo, txRepo, requestService, txService, db, bus, &baseLogger)
+	limits := services.NewLimitService(cfg.TradingLimits, userRepo, requestRepo, txRepo, &baseLogger)
+	fees := services.NewFeeEngine(cfg.FeeSchedule, cfg.Currencies, feeRepo, &baseLogger)
+	if err := fees.Register(ctx); err != nil {
+		baseLogger.Fatal().Err(err).Msg("Failed to register fee schedule, bump fees.version after changing the rules")
+	}
+	verifier := services.NewVerificationService(userRepo, db, bus, &baseLogger)
+	for _, r := range cfg.Verification.Registries {
+		if err := verifier.Register(govregistry.NewStrategy(r.Strategy, r.URL, r.Timeout, &baseLogger)); err != nil {
+			baseLogger.Fatal().Err(err).Msg("Failed to register verification strategy")
+		}
+	}
 
 	// 6. Initialize Bot Orchestrator
 	// Pass the bus to the constructor
-	orchestrator := telegram.NewOrchestrator(
-		cfg,
-		userRepo,
-		bus,
-		&baseLogger,
-	)
+	orchestrator := telegram.NewOrchestrator(&telegram.OrchestratorDeps{
+		Cfg:              cfg,
+		UserRepo:         userRepo,
+		RequestRepo:      requestRepo,
+		BidRepo:          bidRepo,
+		BankAccountRepo:  bankRepo,
+		PlatformAcctRepo: platformRepo,
+		TransactionRepo:  txRepo,
+		DisputeRepo:      disputeRepo,
+		PayoutProofRepo:  proofRepo,
+		DeadLetterRepo:   deadLetterRepo,
+		TxService:        txService,
+		PlatformAccounts: platformAccounts,
+		Disputes:         disputes,
+		Cancellations:    cancellations,
+		Fees:             fees,
+		Limits:           limits,
+		Verifier:         verifier,
+		Locker:           locker,
+		Transactor:       db,
+		Bus:              bus,
+		BaseLogger:       &baseLogger,
+	})
 
 	// 7. Start Bot Orchestrator
 	baseLogger.Info().Msg("Application starting...")
@@ -75,4 +127,18 @@ func main() {
 	}
 
 	baseLogger.Info().Msg("Application shutting down")
+
+	// 8. Let in-flight events finish while the database is still open
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelDrain()
+	if undelivered := bus.Drain(drainCtx); len(undelivered) > 0 {
+		// Stored events are delivered again on the next start, the rest are lost
+		lost := 0
+		for _, u := range undelivered {
+			if !u.Stored {
+				lost++
+			}
+		}
+		baseLogger.Warn().Int("undelivered", len(undelivered)).Int("lost", lost).Msg("Shut down with undelivered events")
+	}
 }
@@ -0,0 +1,46 @@
+package eventbus
+
+import (
+	"AsaExchange/internal/core/events"
+	"encoding/json"
+	"fmt"
+)
+
+// envelope is how a stored event is encoded, in the outbox and in dead letters.
+// The version lets a newer build refuse payloads of an older schema.
+type envelope struct {
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// durableEvent returns data as an event that can be stored for topic.
+// Everything else, like Telegram updates, only lives in memory.
+func durableEvent(topic string, data interface{}) (events.Event, bool) {
+	event, ok := data.(events.Event)
+	if !ok || event.Topic() != topic || !events.IsDurable(topic) {
+		return nil, false
+	}
+	return event, true
+}
+
+// encodeEvent stores a durable event in its envelope.
+func encodeEvent(topic string, data interface{}) ([]byte, error) {
+	event, ok := durableEvent(topic, data)
+	if !ok {
+		return nil, fmt.Errorf("%s carries %T, which cannot be stored", topic, data)
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope{Version: event.Version(), Data: payload})
+}
+
+// decodeEvent turns a stored envelope back into the event its publisher sent.
+func decodeEvent(topic string, stored []byte) (events.Event, error) {
+	var env envelope
+	if err := json.Unmarshal(stored, &env); err != nil {
+		return nil, err
+	}
+	return events.Decode(topic, env.Version, env.Data)
+}
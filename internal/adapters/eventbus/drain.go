@@ -0,0 +1,117 @@
+package eventbus
+
+import (
+	"AsaExchange/internal/core/ports"
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+)
+
+// delivery is one event on its way to one subscription.
+type delivery struct {
+	sub    *subscription
+	report ports.UndeliveredEvent // What Drain reports if it doesn't finish
+}
+
+// flights tracks the deliveries in flight, so the bus can be drained.
+type flights struct {
+	mu       sync.Mutex
+	inflight map[*delivery]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// begin registers a delivery per subscription. It fails once the bus is draining.
+func (f *flights) begin(subs []*subscription, report ports.UndeliveredEvent) ([]*delivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil, ports.ErrBusClosed
+	}
+	if f.inflight == nil {
+		f.inflight = make(map[*delivery]struct{})
+	}
+
+	deliveries := make([]*delivery, len(subs))
+	for i, sub := range subs {
+		d := &delivery{sub: sub, report: report}
+		d.report.Subscriber = sub.name
+		f.inflight[d] = struct{}{}
+		deliveries[i] = d
+	}
+	f.wg.Add(len(deliveries))
+	return deliveries, nil
+}
+
+// start marks a delivery as handed to its handler.
+func (f *flights) start(d *delivery) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d.report.Started = true
+}
+
+// finish forgets a delivery, whatever its outcome.
+func (f *flights) finish(d *delivery) {
+	f.mu.Lock()
+	delete(f.inflight, d)
+	f.mu.Unlock()
+	f.wg.Done()
+}
+
+// close refuses new deliveries and returns a channel closed once none are left.
+func (f *flights) close() <-chan struct{} {
+	f.mu.Lock()
+	f.closed = true
+	f.mu.Unlock()
+
+	idle := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(idle)
+	}()
+	return idle
+}
+
+// pending lists the deliveries that have not finished.
+func (f *flights) pending() []ports.UndeliveredEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	undelivered := make([]ports.UndeliveredEvent, 0, len(f.inflight))
+	for d := range f.inflight {
+		undelivered = append(undelivered, d.report)
+	}
+	slices.SortFunc(undelivered, func(a, b ports.UndeliveredEvent) int {
+		return cmp.Or(cmp.Compare(a.Topic, b.Topic), cmp.Compare(a.Subscriber, b.Subscriber), cmp.Compare(a.Key, b.Key))
+	})
+	return undelivered
+}
+
+// Drain stops accepting publishes and waits for the handlers in flight until
+// ctx is done. Handlers still running then see their context cancelled.
+func (b *inMemoryEventBus) Drain(ctx context.Context) []ports.UndeliveredEvent {
+	b.log.Info().Msg("Draining event bus")
+	idle := b.flights.close()
+
+	select {
+	case <-idle:
+		b.stopRun()
+		b.log.Info().Msg("Event bus drained")
+		return nil
+	case <-ctx.Done():
+	}
+
+	undelivered := b.flights.pending()
+	b.stopRun()
+	for _, u := range undelivered {
+		b.log.Warn().
+			Str("topic", u.Topic).
+			Str("subscriber", u.Subscriber).
+			Str("key", u.Key).
+			Bool("started", u.Started).
+			Bool("stored", u.Stored).
+			Msg("Event undelivered at shutdown")
+	}
+	return undelivered
+}
@@ -3,33 +3,59 @@ package eventbus
 import (
 	"AsaExchange/internal/core/ports"
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/rs/zerolog"
 )
 
+// subscription is one handler registered for a topic.
+type subscription struct {
+	name    string // Empty for plain Subscribe, whose failures are only logged
+	handler ports.EventHandler
+	policy  ports.RetryPolicy
+}
+
 // inMemoryEventBus implements the ports.EventBus interface
 type inMemoryEventBus struct {
 	log         zerolog.Logger
-	subscribers map[string][]ports.EventHandler
+	subscribers map[string][]*subscription
+	deadLetters ports.DeadLetterRepository // Optional
+	keyed       *keyedPool
+	flights     flights
+	runCtx      context.Context // Handlers' context, cancelled when a drain times out
+	stopRun     context.CancelFunc
 	mu          sync.RWMutex
 }
 
-// NewInMemoryEventBus creates a new, empty event bus
-func NewInMemoryEventBus(baseLogger *zerolog.Logger) ports.EventBus {
+// NewInMemoryEventBus creates a new, empty event bus.
+// deadLetters may be nil, then exhausted events are only logged.
+func NewInMemoryEventBus(deadLetters ports.DeadLetterRepository, baseLogger *zerolog.Logger) ports.EventBus {
+	return newInMemoryEventBus(deadLetters, baseLogger)
+}
+
+func newInMemoryEventBus(deadLetters ports.DeadLetterRepository, baseLogger *zerolog.Logger) *inMemoryEventBus {
+	runCtx, stopRun := context.WithCancel(context.Background())
 	return &inMemoryEventBus{
 		log:         baseLogger.With().Str("component", "in_memory_bus").Logger(),
-		subscribers: make(map[string][]ports.EventHandler),
+		subscribers: make(map[string][]*subscription),
+		deadLetters: deadLetters,
+		keyed:       newKeyedPool(runCtx, keyedWorkers, keyedQueueSize),
+		runCtx:      runCtx,
+		stopRun:     stopRun,
 	}
 }
 
-// Publish sends an event to all subscribers of a topic
+// Publish sends an event to all subscribers of a topic.
+// It returns ports.ErrBusClosed once the bus is draining.
 func (b *inMemoryEventBus) Publish(ctx context.Context, topic string, data interface{}) error {
-	b.mu.RLock() // Lock for reading the map
-	defer b.mu.RUnlock()
-
-	handlers, ok := b.subscribers[topic]
-	if !ok {
+	subs := b.subscriptionsFor(topic)
+	deliveries, err := b.flights.begin(subs, ports.UndeliveredEvent{Topic: topic})
+	if err != nil {
+		b.log.Warn().Str("topic", topic).Msg("Event published while the bus is draining, dropped")
+		return err
+	}
+	if len(subs) == 0 {
 		// No subscribers for this topic, which is fine
 		b.log.Warn().Str("topic", topic).Msg("Published event with no subscribers")
 		return nil
@@ -42,25 +68,201 @@ func (b *inMemoryEventBus) Publish(ctx context.Context, topic string, data inter
 
 	// We launch each handler in its own goroutine
 	// so that one slow handler doesn't block all the others.
-	for _, handler := range handlers {
-		go func(h ports.EventHandler) {
-			// We pass a new background context so the handler
-			// isn't cancelled if the *publisher's* context is.
-			if err := h(context.Background(), event); err != nil {
-				b.log.Error().Err(err).Str("topic", topic).Msg("Event handler failed")
-			}
-		}(handler)
+	for _, d := range deliveries {
+		go b.handle(b.runCtx, d, event)
+	}
+
+	b.log.Info().Str("topic", topic).Int("handlers", len(subs)).Msg("Event published")
+	return nil
+}
+
+// PublishKeyed sends an event to all subscribers of a topic, after every
+// earlier event with the same key has been handled by all of its subscribers.
+func (b *inMemoryEventBus) PublishKeyed(ctx context.Context, topic, key string, data interface{}) error {
+	if key == "" {
+		return b.Publish(ctx, topic, data)
+	}
+	subs := b.subscriptionsFor(topic)
+	deliveries, err := b.flights.begin(subs, ports.UndeliveredEvent{Topic: topic, Key: key})
+	if err != nil {
+		b.log.Warn().Str("topic", topic).Str("key", key).Msg("Event published while the bus is draining, dropped")
+		return err
+	}
+	if len(subs) == 0 {
+		b.log.Warn().Str("topic", topic).Msg("Published event with no subscribers")
+		return nil
+	}
+
+	event := ports.Event{Topic: topic, Data: data}
+	if err := b.keyed.submit(ctx, key, func(ctx context.Context) {
+		b.handleAll(ctx, deliveries, event)
+	}); err != nil {
+		for _, d := range deliveries {
+			b.flights.finish(d)
+		}
+		b.log.Error().Err(err).Str("topic", topic).Str("key", key).Msg("Keyed event not queued")
+		return err
 	}
 
-	b.log.Info().Str("topic", topic).Int("handlers", len(handlers)).Msg("Event published")
+	b.log.Info().Str("topic", topic).Str("key", key).Int("handlers", len(subs)).Msg("Keyed event published")
 	return nil
 }
 
 // Subscribe registers a handler for a specific topic
 func (b *inMemoryEventBus) Subscribe(topic string, handler ports.EventHandler) {
+	b.subscribe(topic, &subscription{handler: handler})
+}
+
+// SubscribeWithRetry registers a handler that is retried under policy and
+// dead-lettered under name once the attempts run out.
+func (b *inMemoryEventBus) SubscribeWithRetry(topic, name string, handler ports.EventHandler, policy ports.RetryPolicy) {
+	b.subscribe(topic, &subscription{name: name, handler: handler, policy: policy})
+}
+
+func (b *inMemoryEventBus) subscribe(topic string, sub *subscription) {
 	b.mu.Lock() // Lock for writing to the map
 	defer b.mu.Unlock()
 
-	b.subscribers[topic] = append(b.subscribers[topic], handler)
-	b.log.Info().Str("topic", topic).Msg("New handler subscribed to topic")
+	b.subscribers[topic] = append(b.subscribers[topic], sub)
+	b.log.Info().Str("topic", topic).Str("subscriber", sub.name).Msg("New handler subscribed to topic")
+}
+
+// subscriptionsFor returns a copy of the subscriptions to a topic
+func (b *inMemoryEventBus) subscriptionsFor(topic string) []*subscription {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return append([]*subscription(nil), b.subscribers[topic]...)
+}
+
+// handleAll runs every delivery of an event at once and waits for all of them.
+func (b *inMemoryEventBus) handleAll(ctx context.Context, deliveries []*delivery, event ports.Event) {
+	var wg sync.WaitGroup
+	for _, d := range deliveries {
+		wg.Add(1)
+		go func(d *delivery) {
+			defer wg.Done()
+			b.handle(ctx, d, event)
+		}(d)
+	}
+	wg.Wait()
+}
+
+// handle runs one delivery. Handlers get ctx, derived from the bus's context
+// rather than the publisher's, so they only stop early when a drain runs out
+// of time.
+func (b *inMemoryEventBus) handle(ctx context.Context, d *delivery, event ports.Event) {
+	defer b.flights.finish(d)
+	if ctx.Err() != nil {
+		return // Still queued when the drain ran out of time, already reported
+	}
+	b.flights.start(d)
+	b.run(ctx, d.sub, event)
+}
+
+// run hands the event to one subscription, retrying under its policy.
+// An event that still fails is dead-lettered.
+func (b *inMemoryEventBus) run(ctx context.Context, sub *subscription, event ports.Event) {
+	log := b.log.With().Str("topic", event.Topic).Str("subscriber", sub.name).Logger()
+
+	attempts := max(sub.policy.MaxAttempts, 1)
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		if err = sub.handler(ctx, event); err == nil {
+			return
+		}
+		if attempt >= attempts {
+			break
+		}
+		delay := backoff(sub.policy, attempt)
+		log.Warn().Err(err).Int("attempt", attempt).Dur("retry_in", delay).Msg("Event handler failed, retrying")
+		if !sleep(ctx, delay) {
+			break
+		}
+	}
+
+	if ctx.Err() != nil {
+		// Stopped by a drain that ran out of time; Drain reports the event
+		log.Warn().Err(err).Int("attempts", attempt).Msg("Event handler stopped by shutdown")
+		return
+	}
+	log.Error().Err(err).Int("attempts", attempt).Msg("Event handler failed")
+	b.deadLetter(sub, event, attempt, err)
+}
+
+// deadLetter stores an event its subscriber gave up on, so a moderator can replay it.
+func (b *inMemoryEventBus) deadLetter(sub *subscription, event ports.Event, attempts int, handlerErr error) {
+	if sub.name == "" || b.deadLetters == nil {
+		return
+	}
+	log := b.log.With().Str("topic", event.Topic).Str("subscriber", sub.name).Logger()
+
+	payload, err := encodeEvent(event.Topic, event.Data)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to encode dead letter, the event is lost")
+		return
+	}
+	letter := &ports.DeadLetter{
+		Topic:      event.Topic,
+		Subscriber: sub.name,
+		Payload:    payload,
+		Error:      handlerErr.Error(),
+		Attempts:   attempts,
+	}
+	// The event is already lost to the caller, don't lose it to a cancelled ctx too
+	if err := b.deadLetters.Add(context.Background(), letter); err != nil {
+		log.Error().Err(err).Msg("Failed to store dead letter, the event is lost")
+		return
+	}
+	log.Warn().Int64("dead_letter_id", letter.ID).Msg("Event dead-lettered")
+}
+
+// Replay runs the subscriber of a dead letter once more on the stored event.
+// The letter is claimed first, so concurrent replays run the subscriber at
+// most once; a failed replay gives the claim back.
+func (b *inMemoryEventBus) Replay(ctx context.Context, id int64) error {
+	if b.deadLetters == nil {
+		return ports.ErrDeadLetterNotFound
+	}
+	letter, err := b.deadLetters.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if letter == nil {
+		return ports.ErrDeadLetterNotFound
+	}
+	if letter.ReplayedAt != nil {
+		return ports.ErrDeadLetterReplayed
+	}
+
+	var sub *subscription
+	for _, s := range b.subscriptionsFor(letter.Topic) {
+		if s.name == letter.Subscriber {
+			sub = s
+			break
+		}
+	}
+	if sub == nil {
+		return fmt.Errorf("no subscriber %q on %s", letter.Subscriber, letter.Topic)
+	}
+
+	data, err := decodeEvent(letter.Topic, letter.Payload)
+	if err != nil {
+		return err
+	}
+
+	log := b.log.With().Int64("dead_letter_id", id).Str("topic", letter.Topic).Str("subscriber", sub.name).Logger()
+	if err := b.deadLetters.ClaimReplay(ctx, id); err != nil {
+		return err
+	}
+	if err := sub.handler(ctx, ports.Event{Topic: letter.Topic, Data: data}); err != nil {
+		// Release even if ctx was cancelled, or the letter stays claimed forever
+		if releaseErr := b.deadLetters.ReleaseReplay(context.WithoutCancel(ctx), id); releaseErr != nil {
+			log.Error().Err(releaseErr).Msg("Failed to release dead letter after a failed replay")
+		}
+		return err
+	}
+	log.Info().Msg("Dead letter replayed")
+	return nil
 }
@@ -0,0 +1,387 @@
+package eventbus
+
+import (
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// fakeDeadLetters is an in-memory ports.DeadLetterRepository.
+type fakeDeadLetters struct {
+	mu      sync.Mutex
+	letters []*ports.DeadLetter
+	added   chan struct{}
+}
+
+func newFakeDeadLetters() *fakeDeadLetters {
+	return &fakeDeadLetters{added: make(chan struct{}, 10)}
+}
+
+func (f *fakeDeadLetters) Add(ctx context.Context, letter *ports.DeadLetter) error {
+	f.mu.Lock()
+	letter.ID = int64(len(f.letters) + 1)
+	letter.CreatedAt = time.Now()
+	f.letters = append(f.letters, letter)
+	f.mu.Unlock()
+	f.added <- struct{}{}
+	return nil
+}
+
+func (f *fakeDeadLetters) GetByID(ctx context.Context, id int64) (*ports.DeadLetter, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, letter := range f.letters {
+		if letter.ID == id {
+			return letter, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeDeadLetters) ListPending(ctx context.Context, limit int) ([]*ports.DeadLetter, error) {
+	return nil, nil
+}
+
+func (f *fakeDeadLetters) ClaimReplay(ctx context.Context, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.letters[id-1].ReplayedAt != nil {
+		return ports.ErrDeadLetterReplayed
+	}
+	now := time.Now()
+	f.letters[id-1].ReplayedAt = &now
+	return nil
+}
+
+func (f *fakeDeadLetters) ReleaseReplay(ctx context.Context, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.letters[id-1].ReplayedAt = nil
+	return nil
+}
+
+var fastRetry = ports.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 4 * time.Millisecond}
+
+func TestInMemoryEventBus_RetriesUntilSuccess(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	deadLetters := newFakeDeadLetters()
+	bus := newInMemoryEventBus(deadLetters, &nopLogger)
+
+	var calls atomic.Int32
+	done := make(chan struct{})
+	bus.SubscribeWithRetry("user:approved", "notification", func(ctx context.Context, event ports.Event) error {
+		if calls.Add(1) < 3 {
+			return errors.New("telegram is down")
+		}
+		close(done)
+		return nil
+	}, fastRetry)
+
+	if err := events.Publish(t.Context(), bus, events.UserApproved{UserRef: events.UserRef{UserID: uuid.New()}}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Handler did not succeed, %d calls", calls.Load())
+	}
+	if len(deadLetters.letters) != 0 {
+		t.Errorf("A successful retry was dead-lettered: %+v", deadLetters.letters)
+	}
+}
+
+func TestInMemoryEventBus_DeadLetterAndReplay(t *testing.T) {
+	// 1. Setup: the handler fails until it is "fixed"
+	nopLogger := zerolog.Nop()
+	deadLetters := newFakeDeadLetters()
+	bus := newInMemoryEventBus(deadLetters, &nopLogger)
+
+	var fixed atomic.Bool
+	var calls atomic.Int32
+	received := make(chan events.UserApproved, 1)
+	events.SubscribeWithRetry(bus, "notification", func(ctx context.Context, event events.UserApproved) error {
+		calls.Add(1)
+		if !fixed.Load() {
+			return errors.New("telegram is down")
+		}
+		received <- event
+		return nil
+	}, fastRetry)
+
+	approved := events.UserApproved{UserRef: events.UserRef{UserID: uuid.New(), TelegramID: 42}}
+	if err := events.Publish(t.Context(), bus, approved); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	// 2. The event is dead-lettered after every attempt failed
+	select {
+	case <-deadLetters.added:
+	case <-time.After(time.Second):
+		t.Fatal("Event was not dead-lettered")
+	}
+	letter := deadLetters.letters[0]
+	if letter.Topic != "user:approved" || letter.Subscriber != "notification" || letter.Attempts != 3 || calls.Load() != 3 {
+		t.Errorf("Dead letter mismatch: %+v after %d calls", letter, calls.Load())
+	}
+
+	// 3. A failed replay leaves the letter pending
+	if err := bus.Replay(t.Context(), letter.ID); err == nil {
+		t.Fatal("Expected the replay to fail while the handler is broken")
+	}
+	if letter.ReplayedAt != nil {
+		t.Error("Failed replay kept its claim on the dead letter")
+	}
+
+	// 4. Replay hands the decoded event to the same subscriber
+	fixed.Store(true)
+	if err := bus.Replay(t.Context(), letter.ID); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if got := <-received; got != approved {
+		t.Errorf("Replayed payload mismatch: got %+v, want %+v", got, approved)
+	}
+	if letter.ReplayedAt == nil {
+		t.Error("Dead letter was not marked replayed")
+	}
+
+	// 5. A second replay or an unknown ID is refused
+	if err := bus.Replay(t.Context(), letter.ID); !errors.Is(err, ports.ErrDeadLetterReplayed) {
+		t.Errorf("Expected ErrDeadLetterReplayed, got: %v", err)
+	}
+	if err := bus.Replay(t.Context(), 42); !errors.Is(err, ports.ErrDeadLetterNotFound) {
+		t.Errorf("Expected ErrDeadLetterNotFound, got: %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := ports.RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{9, 500 * time.Millisecond, time.Second}, // Capped
+	}
+	for _, tt := range tests {
+		for range 20 {
+			if got := backoff(policy, tt.attempt); got < tt.min || got > tt.max {
+				t.Errorf("attempt %d: backoff = %s, want between %s and %s", tt.attempt, got, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestInMemoryEventBus_PublishKeyedKeepsOrder(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	bus := newInMemoryEventBus(nil, &nopLogger)
+
+	var mu sync.Mutex
+	got := make(map[string][]int)
+	done := make(chan struct{}, 40)
+	bus.Subscribe("tx:step", func(ctx context.Context, event ports.Event) error {
+		step := event.Data.([2]int)
+		key := string(rune('a' + step[0]))
+		time.Sleep(time.Duration(step[1]%3) * time.Millisecond) // Uneven handlers must not reorder a key
+		mu.Lock()
+		got[key] = append(got[key], step[1])
+		mu.Unlock()
+		done <- struct{}{}
+		return nil
+	})
+
+	for i := range 10 {
+		for k := range 4 {
+			if err := bus.PublishKeyed(t.Context(), "tx:step", string(rune('a'+k)), [2]int{k, i}); err != nil {
+				t.Fatalf("PublishKeyed failed: %v", err)
+			}
+		}
+	}
+	for range 40 {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Keyed events were not all delivered")
+		}
+	}
+
+	for key, steps := range got {
+		for i, step := range steps {
+			if step != i {
+				t.Fatalf("Key %s delivered out of order: %v", key, steps)
+			}
+		}
+	}
+}
+
+func TestKeyedPool_BlocksWhenFull(t *testing.T) {
+	pool := newKeyedPool(t.Context(), 1, 1)
+	release := make(chan struct{})
+	defer close(release)
+
+	// One job runs, one waits in the queue, the third has no room
+	started := make(chan struct{})
+	if err := pool.submit(t.Context(), "a", func(context.Context) { close(started); <-release }); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	<-started
+	if err := pool.submit(t.Context(), "b", func(context.Context) {}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.submit(ctx, "c", func(context.Context) {}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the publisher to be held back, got: %v", err)
+	}
+}
+
+func TestInMemoryEventBus_PublishKeyedOntoOwnKey(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	bus := newInMemoryEventBus(nil, &nopLogger)
+	bus.keyed = newKeyedPool(bus.runCtx, 1, 1)
+
+	// The first step publishes three more on its own key, overflowing the queue
+	// while its only worker is the one publishing
+	var mu sync.Mutex
+	var got []int
+	done := make(chan struct{})
+	bus.Subscribe("tx:step", func(ctx context.Context, event ports.Event) error {
+		step := event.Data.(int)
+		mu.Lock()
+		got = append(got, step)
+		mu.Unlock()
+		switch step {
+		case 1:
+			for _, next := range []int{2, 3, 4} {
+				if err := bus.PublishKeyed(ctx, "tx:step", "tx-1", next); err != nil {
+					return err
+				}
+			}
+		case 4:
+			close(done)
+		}
+		return nil
+	})
+
+	if err := bus.PublishKeyed(t.Context(), "tx:step", "tx-1", 1); err != nil {
+		t.Fatalf("PublishKeyed failed: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Handler deadlocked publishing onto its own key")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, step := range got {
+		if step != i+1 {
+			t.Fatalf("Steps delivered out of order: %v", got)
+		}
+	}
+}
+
+func TestInMemoryEventBus_DrainWaitsForHandlers(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	bus := newInMemoryEventBus(nil, &nopLogger)
+
+	release := make(chan struct{})
+	var finished atomic.Bool
+	bus.Subscribe("user:approved", func(ctx context.Context, event ports.Event) error {
+		<-release
+		finished.Store(true)
+		return nil
+	})
+	if err := bus.Publish(t.Context(), "user:approved", "event"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	drained := make(chan []ports.UndeliveredEvent)
+	go func() { drained <- bus.Drain(t.Context()) }()
+
+	// New publishes are refused as soon as the drain starts
+	time.Sleep(10 * time.Millisecond)
+	if err := bus.Publish(t.Context(), "user:approved", "late"); !errors.Is(err, ports.ErrBusClosed) {
+		t.Errorf("Expected ErrBusClosed, got: %v", err)
+	}
+
+	close(release)
+	if undelivered := <-drained; len(undelivered) != 0 {
+		t.Errorf("Expected nothing undelivered, got %+v", undelivered)
+	}
+	if !finished.Load() {
+		t.Error("Drain returned before the handler finished")
+	}
+}
+
+func TestInMemoryEventBus_DrainReportsUndelivered(t *testing.T) {
+	// 1. Setup: a handler that only stops when its context is cancelled
+	nopLogger := zerolog.Nop()
+	deadLetters := newFakeDeadLetters()
+	bus := newInMemoryEventBus(deadLetters, &nopLogger)
+
+	started := make(chan struct{}, 1)
+	stopped := make(chan error, 1)
+	bus.SubscribeWithRetry("tx:step", "notification", func(ctx context.Context, event ports.Event) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return ctx.Err()
+	}, fastRetry)
+
+	// The second event waits behind the first, on the same key
+	for range 2 {
+		if err := bus.PublishKeyed(t.Context(), "tx:step", "tx-1", "step"); err != nil {
+			t.Fatalf("PublishKeyed failed: %v", err)
+		}
+	}
+	<-started
+
+	// 2. Drain runs out of time
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	undelivered := bus.Drain(ctx)
+
+	// 3. Both events are reported, the running handler is cancelled and
+	// nothing is dead-lettered for a shutdown
+	if len(undelivered) != 2 {
+		t.Fatalf("Expected 2 undelivered events, got %+v", undelivered)
+	}
+	var running int
+	for _, u := range undelivered {
+		if u.Topic != "tx:step" || u.Subscriber != "notification" || u.Key != "tx-1" || u.Stored {
+			t.Errorf("Report mismatch: %+v", u)
+		}
+		if u.Started {
+			running++
+		}
+	}
+	if running != 1 {
+		t.Errorf("Expected 1 started delivery, got %d", running)
+	}
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Handler stopped with %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Running handler was not cancelled")
+	}
+	time.Sleep(10 * time.Millisecond)
+	deadLetters.mu.Lock()
+	defer deadLetters.mu.Unlock()
+	if len(deadLetters.letters) != 0 {
+		t.Errorf("Shutdown dead-lettered events: %+v", deadLetters.letters)
+	}
+}
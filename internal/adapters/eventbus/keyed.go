@@ -0,0 +1,114 @@
+package eventbus
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+)
+
+const (
+	keyedWorkers   = 16 // Keys handled in parallel
+	keyedQueueSize = 64 // Events waiting per worker before PublishKeyed blocks
+)
+
+// keyedPool runs the deliveries of keyed events. A key always lands on the
+// same worker, so its events run one after another in publish order, while
+// different keys spread over the workers. A full queue blocks the publisher:
+// a flood of updates slows the poller down instead of piling up goroutines.
+//
+// A job that submits onto its own worker is never blocked, since the worker
+// could not make room while it waits. Jobs see that through the context
+// their worker hands them, so handlers must publish with that context.
+type keyedPool struct {
+	workers   []*keyedWorker
+	queueSize int
+	start     sync.Once
+}
+
+// keyedWorker is one queue of jobs and the goroutine draining it.
+type keyedWorker struct {
+	ctx   context.Context // Handed to its jobs, marks them as running here
+	mu    sync.Mutex
+	jobs  []func(ctx context.Context)
+	ready chan struct{} // Wakes the worker up after a submit
+	space chan struct{} // Closed when a full queue gets room
+}
+
+// workerKey carries the worker running a job in its context.
+type workerKey struct{}
+
+// newKeyedPool creates a pool whose jobs run under ctx.
+func newKeyedPool(ctx context.Context, workers, queueSize int) *keyedPool {
+	p := &keyedPool{workers: make([]*keyedWorker, workers), queueSize: queueSize}
+	for i := range p.workers {
+		w := &keyedWorker{ready: make(chan struct{}, 1), space: make(chan struct{})}
+		w.ctx = context.WithValue(ctx, workerKey{}, w)
+		p.workers[i] = w
+	}
+	return p
+}
+
+// submit queues job behind the earlier jobs of key. It blocks while the
+// worker of key is full and gives up once ctx is done, unless it is called
+// from a job of that same worker.
+func (p *keyedPool) submit(ctx context.Context, key string, job func(ctx context.Context)) error {
+	p.start.Do(func() {
+		for _, w := range p.workers {
+			go w.work(p.queueSize)
+		}
+	})
+
+	w := p.workers[p.index(key)]
+	own := ctx.Value(workerKey{}) == w
+	for {
+		w.mu.Lock()
+		if own || len(w.jobs) < p.queueSize {
+			w.jobs = append(w.jobs, job)
+			w.mu.Unlock()
+			select {
+			case w.ready <- struct{}{}:
+			default: // Already woken up
+			}
+			return nil
+		}
+		space := w.space
+		w.mu.Unlock()
+
+		select {
+		case <-space:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// index picks the worker of a key.
+func (p *keyedPool) index(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(p.workers)))
+}
+
+// work runs the jobs of one worker in order.
+func (w *keyedWorker) work(queueSize int) {
+	for range w.ready {
+		for {
+			w.mu.Lock()
+			if len(w.jobs) == 0 {
+				w.mu.Unlock()
+				break
+			}
+			job := w.jobs[0]
+			w.jobs[0] = nil
+			w.jobs = w.jobs[1:]
+			if len(w.jobs) == queueSize-1 {
+				// Publishers waiting on the full queue may go on
+				close(w.space)
+				w.space = make(chan struct{})
+			}
+			w.mu.Unlock()
+
+			job(w.ctx)
+		}
+	}
+}
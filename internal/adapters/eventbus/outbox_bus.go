@@ -0,0 +1,241 @@
+package eventbus
+
+import (
+	"AsaExchange/internal/core/ports"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	outboxBatchSize     = 100
+	outboxLease         = time.Minute     // Handlers running longer, retries included, may see their event twice
+	outboxPollInterval  = 5 * time.Second // Catches events whose NOTIFY was missed
+	outboxRetention     = 7 * 24 * time.Hour
+	outboxPruneInterval = time.Hour
+	listenRetryDelay    = 5 * time.Second
+)
+
+var (
+	_ ports.EventBus           = (*OutboxEventBus)(nil) // Ensure compliance
+	_ ports.EventDispatcher    = (*OutboxEventBus)(nil)
+	_ ports.DeadLetterReplayer = (*OutboxEventBus)(nil)
+	_ ports.EventDrainer       = (*OutboxEventBus)(nil)
+)
+
+// OutboxEventBus is an EventBus that survives restarts. Publish writes the
+// event to the outbox, inside the caller's transaction when there is one,
+// and Run delivers it to the subscribers afterwards. Delivery is at least
+// once: an event is only marked done after all its handlers returned.
+//
+// Only durable events (see events.IsDurable) are stored. Telegram updates are
+// not worth replaying and go straight to the subscribers, like on the
+// in-memory bus.
+type OutboxEventBus struct {
+	repo   ports.OutboxRepository
+	memory *inMemoryEventBus // Holds the subscribers and delivers transient topics
+	wake   chan struct{}
+	log    zerolog.Logger
+}
+
+// NewOutboxEventBus creates an event bus backed by the outbox repository.
+// Events whose handlers run out of retries go to deadLetters.
+func NewOutboxEventBus(repo ports.OutboxRepository, deadLetters ports.DeadLetterRepository, baseLogger *zerolog.Logger) *OutboxEventBus {
+	return &OutboxEventBus{
+		repo:   repo,
+		memory: newInMemoryEventBus(deadLetters, baseLogger),
+		wake:   make(chan struct{}, 1),
+		log:    baseLogger.With().Str("component", "outbox_bus").Logger(),
+	}
+}
+
+// Publish stores an event in the outbox. Within ports.Transactor.WithinTx it
+// commits or rolls back with the caller's changes.
+func (b *OutboxEventBus) Publish(ctx context.Context, topic string, data interface{}) error {
+	return b.PublishKeyed(ctx, topic, "", data)
+}
+
+// PublishKeyed stores an event in the outbox under key. The dispatcher
+// delivers events sharing a key one at a time, oldest first.
+func (b *OutboxEventBus) PublishKeyed(ctx context.Context, topic, key string, data interface{}) error {
+	if _, ok := durableEvent(topic, data); !ok {
+		return b.memory.PublishKeyed(ctx, topic, key, data)
+	}
+
+	payload, err := encodeEvent(topic, data)
+	if err != nil {
+		return fmt.Errorf("encode %s event: %w", topic, err)
+	}
+	if err := b.repo.Add(ctx, topic, key, payload); err != nil {
+		return err
+	}
+	b.log.Info().Str("topic", topic).Msg("Event stored in outbox")
+	return nil
+}
+
+// Drain stops delivering and waits for the handlers in flight until ctx is
+// done. Durable events are still stored while the bus drains: they start no
+// handler, and the next run delivers them. So are events whose delivery
+// Drain cuts short.
+func (b *OutboxEventBus) Drain(ctx context.Context) []ports.UndeliveredEvent {
+	return b.memory.Drain(ctx)
+}
+
+// Subscribe registers a handler for a specific topic
+func (b *OutboxEventBus) Subscribe(topic string, handler ports.EventHandler) {
+	b.memory.Subscribe(topic, handler)
+}
+
+// SubscribeWithRetry registers a handler that is retried under policy and
+// dead-lettered under name once the attempts run out.
+func (b *OutboxEventBus) SubscribeWithRetry(topic, name string, handler ports.EventHandler, policy ports.RetryPolicy) {
+	b.memory.SubscribeWithRetry(topic, name, handler, policy)
+}
+
+// Replay runs the subscriber of a dead letter once more on the stored event.
+func (b *OutboxEventBus) Replay(ctx context.Context, id int64) error {
+	return b.memory.Replay(ctx, id)
+}
+
+// Run delivers outbox events until ctx is done, starting with the ones the
+// last run left undelivered.
+func (b *OutboxEventBus) Run(ctx context.Context) {
+	go b.listen(ctx)
+
+	poll := time.NewTicker(outboxPollInterval)
+	defer poll.Stop()
+	prune := time.NewTicker(outboxPruneInterval)
+	defer prune.Stop()
+
+	b.log.Info().Msg("Outbox dispatcher started")
+	b.prune(ctx)
+	for {
+		b.drain(ctx)
+		select {
+		case <-ctx.Done():
+			b.log.Info().Msg("Outbox dispatcher stopped")
+			return
+		case <-b.wake:
+		case <-poll.C:
+		case <-prune.C:
+			b.prune(ctx)
+		}
+	}
+}
+
+// listen wakes the dispatcher whenever events are committed.
+func (b *OutboxEventBus) listen(ctx context.Context) {
+	for {
+		err := b.repo.Listen(ctx, b.notify)
+		if ctx.Err() != nil {
+			return
+		}
+		b.log.Warn().Err(err).Msg("Outbox listener stopped, retrying")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// notify wakes the dispatcher without blocking; one pending wake-up is enough.
+func (b *OutboxEventBus) notify() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// drain delivers claimed events batch by batch until none are left.
+func (b *OutboxEventBus) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		events, err := b.repo.Claim(ctx, outboxBatchSize, outboxLease)
+		if err != nil {
+			if ctx.Err() == nil {
+				b.log.Error().Err(err).Msg("Failed to claim outbox events")
+			}
+			return
+		}
+		if len(events) == 0 {
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, partition := range partitionByKey(events) {
+			wg.Add(1)
+			go func(partition []*ports.OutboxEvent) {
+				defer wg.Done()
+				for _, event := range partition {
+					b.deliver(event)
+				}
+			}(partition)
+		}
+		wg.Wait()
+	}
+}
+
+// partitionByKey groups claimed events by key, keeping their order. Every
+// event without a key is a partition of its own.
+func partitionByKey(events []*ports.OutboxEvent) [][]*ports.OutboxEvent {
+	var partitions [][]*ports.OutboxEvent
+	byKey := make(map[string]int)
+	for _, event := range events {
+		if i, ok := byKey[event.Key]; ok && event.Key != "" {
+			partitions[i] = append(partitions[i], event)
+			continue
+		}
+		if event.Key != "" {
+			byKey[event.Key] = len(partitions)
+		}
+		partitions = append(partitions, []*ports.OutboxEvent{event})
+	}
+	return partitions
+}
+
+// deliver hands one event to every subscriber of its topic and marks it done
+// once each has succeeded or dead-lettered it. Events that cannot be decoded
+// are marked done too, they would never succeed.
+func (b *OutboxEventBus) deliver(event *ports.OutboxEvent) {
+	log := b.log.With().Int64("event_id", event.ID).Str("topic", event.Topic).Logger()
+
+	if data, err := decodeEvent(event.Topic, event.Payload); err != nil {
+		log.Error().Err(err).Msg("Dropping undecodable outbox event")
+	} else {
+		subs := b.memory.subscriptionsFor(event.Topic)
+		deliveries, err := b.memory.flights.begin(subs, ports.UndeliveredEvent{Topic: event.Topic, Key: event.Key, Stored: true})
+		if err != nil {
+			log.Warn().Msg("Bus is draining, event stays in the outbox")
+			return
+		}
+		if len(subs) == 0 {
+			log.Warn().Msg("Delivered event with no subscribers")
+		}
+
+		b.memory.handleAll(b.memory.runCtx, deliveries, ports.Event{Topic: event.Topic, Data: data})
+		if b.memory.runCtx.Err() != nil {
+			log.Warn().Msg("Delivery cut short by shutdown, event stays in the outbox")
+			return
+		}
+		log.Info().Int("handlers", len(subs)).Msg("Event delivered")
+	}
+
+	// The delivery is done even if shutdown started meanwhile
+	if err := b.repo.MarkDelivered(context.Background(), event.ID); err != nil {
+		log.Error().Err(err).Msg("Failed to mark event delivered, it will be delivered again")
+	}
+}
+
+// prune removes delivered events older than the retention period.
+func (b *OutboxEventBus) prune(ctx context.Context) {
+	deleted, err := b.repo.DeleteDelivered(ctx, time.Now().Add(-outboxRetention))
+	if err != nil {
+		return
+	}
+	if deleted > 0 {
+		b.log.Info().Int64("deleted", deleted).Msg("Pruned delivered outbox events")
+	}
+}
@@ -0,0 +1,219 @@
+package eventbus
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// fakeOutbox is an in-memory ports.OutboxRepository.
+type fakeOutbox struct {
+	mu        sync.Mutex
+	nextID    int64
+	events    []*ports.OutboxEvent
+	delivered map[int64]bool
+}
+
+func newFakeOutbox() *fakeOutbox {
+	return &fakeOutbox{delivered: make(map[int64]bool)}
+}
+
+func (f *fakeOutbox) Add(ctx context.Context, topic, key string, payload []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	f.events = append(f.events, &ports.OutboxEvent{ID: f.nextID, Topic: topic, Key: key, Payload: payload, CreatedAt: time.Now()})
+	return nil
+}
+
+// Claim returns every undelivered event once; leases never run out here.
+func (f *fakeOutbox) Claim(ctx context.Context, limit int, lease time.Duration) ([]*ports.OutboxEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var claimed []*ports.OutboxEvent
+	for _, event := range f.events {
+		if !f.delivered[event.ID] && len(claimed) < limit {
+			claimed = append(claimed, event)
+		}
+	}
+	f.events = f.events[len(claimed):]
+	return claimed, nil
+}
+
+func (f *fakeOutbox) MarkDelivered(ctx context.Context, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delivered[id] = true
+	return nil
+}
+
+func (f *fakeOutbox) DeleteDelivered(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeOutbox) Listen(ctx context.Context, notify func()) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (f *fakeOutbox) pending() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.events)
+}
+
+func TestOutboxEventBus_PublishStoresDurableTopics(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	repo := newFakeOutbox()
+	bus := NewOutboxEventBus(repo, nil, &nopLogger)
+
+	if err := events.Publish(t.Context(), bus, events.UserApproved{UserRef: events.UserRef{UserID: uuid.New()}}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if repo.pending() != 1 {
+		t.Errorf("Expected the event in the outbox, got %d events", repo.pending())
+	}
+
+	// Telegram updates are delivered directly and never stored
+	received := make(chan ports.Event, 1)
+	bus.Subscribe("telegram:moderator:update", func(ctx context.Context, event ports.Event) error {
+		received <- event
+		return nil
+	})
+	if err := bus.Publish(t.Context(), "telegram:moderator:update", "update"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Error("Transient event was not delivered")
+	}
+	if repo.pending() != 1 {
+		t.Errorf("Transient event was stored: %d events", repo.pending())
+	}
+}
+
+func TestOutboxEventBus_RunReplaysUndeliveredEvents(t *testing.T) {
+	// 1. Setup: an event is stored before the dispatcher starts, as after a crash
+	nopLogger := zerolog.Nop()
+	repo := newFakeOutbox()
+	bus := NewOutboxEventBus(repo, nil, &nopLogger)
+
+	created := events.RequestCreated{Request: domain.Request{ID: uuid.New(), Status: domain.RequestStatusOpen}}
+	if err := events.Publish(t.Context(), bus, created); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	received := make(chan events.RequestCreated, 1)
+	events.Subscribe(bus, func(ctx context.Context, event events.RequestCreated) error {
+		received <- event
+		return nil
+	})
+
+	// 2. Run
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		bus.Run(ctx)
+		close(done)
+	}()
+
+	// 3. Verify: the subscriber gets the payload with its original type
+	select {
+	case got := <-received:
+		if got.Request.ID != created.Request.ID || got.Request.Status != domain.RequestStatusOpen {
+			t.Errorf("Payload mismatch: got %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stored event was not delivered")
+	}
+
+	cancel()
+	<-done
+	if !repo.delivered[1] {
+		t.Error("Event was not marked delivered")
+	}
+}
+
+func TestEventCodec(t *testing.T) {
+	// Durable events survive the round trip with their type
+	rejected := events.DepositRejected{TransactionID: uuid.New(), UserID: uuid.New(), Leg: domain.LegBuyer, Reason: "blurry"}
+	stored, err := encodeEvent(rejected.Topic(), rejected)
+	if err != nil {
+		t.Fatalf("encodeEvent failed: %v", err)
+	}
+	decoded, err := decodeEvent(rejected.Topic(), stored)
+	if err != nil {
+		t.Fatalf("decodeEvent failed: %v", err)
+	}
+	if decoded != rejected {
+		t.Errorf("Round trip mismatch: got %+v, want %+v", decoded, rejected)
+	}
+
+	// Payloads that are not the event of their topic are never stored
+	if _, err := encodeEvent("user:approved", &domain.User{}); err == nil {
+		t.Error("Expected an error for a raw domain payload")
+	}
+	if _, err := encodeEvent("user:rejected", rejected); err == nil {
+		t.Error("Expected an error for an event on another topic")
+	}
+
+	// A payload of another schema version is refused
+	if _, err := decodeEvent(rejected.Topic(), []byte(`{"version":2,"data":{}}`)); err == nil {
+		t.Error("Expected an error for an unknown schema version")
+	}
+}
+
+func TestPartitionByKey(t *testing.T) {
+	events := []*ports.OutboxEvent{
+		{ID: 1, Key: "tx-1"},
+		{ID: 2},
+		{ID: 3, Key: "tx-2"},
+		{ID: 4, Key: "tx-1"},
+		{ID: 5},
+	}
+	var got [][]int64
+	for _, partition := range partitionByKey(events) {
+		var ids []int64
+		for _, event := range partition {
+			ids = append(ids, event.ID)
+		}
+		got = append(got, ids)
+	}
+
+	want := [][]int64{{1, 4}, {2}, {3}, {5}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Partitions = %v, want %v", got, want)
+	}
+}
+
+func TestOutboxEventBus_DrainKeepsStoring(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	repo := newFakeOutbox()
+	bus := NewOutboxEventBus(repo, nil, &nopLogger)
+
+	if undelivered := bus.Drain(t.Context()); len(undelivered) != 0 {
+		t.Fatalf("Expected nothing undelivered, got %+v", undelivered)
+	}
+
+	// Durable events still reach the outbox for the next start...
+	if err := events.Publish(t.Context(), bus, events.UserApproved{UserRef: events.UserRef{UserID: uuid.New()}}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if repo.pending() != 1 {
+		t.Errorf("Expected the event in the outbox, got %d events", repo.pending())
+	}
+	// ...while in-memory ones are refused
+	if err := bus.Publish(t.Context(), "telegram:moderator:update", "update"); !errors.Is(err, ports.ErrBusClosed) {
+		t.Errorf("Expected ErrBusClosed, got: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package eventbus
+
+import (
+	"AsaExchange/internal/core/ports"
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// backoff returns the delay before retry number attempt (starting at 1):
+// BaseDelay doubled per earlier retry, capped at MaxDelay, with the upper half
+// randomised so handlers failing together don't retry in lockstep.
+func backoff(policy ports.RetryPolicy, attempt int) time.Duration {
+	delay := policy.BaseDelay
+	for i := 1; i < attempt && (policy.MaxDelay <= 0 || delay < policy.MaxDelay); i++ {
+		delay *= 2
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	if half := delay / 2; half > 0 {
+		return half + rand.N(half)
+	}
+	return delay
+}
+
+// sleep waits for d and reports false if ctx was done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,132 @@
+package govregistry
+
+import (
+	"AsaExchange/internal/core/ports"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// verifyPath is the lookup endpoint of the registry API.
+const verifyPath = "/v1/persons/verify"
+
+// Results returned by the registry.
+const (
+	resultMatch      = "match"      // The person exists and every detail matches
+	resultMismatch   = "mismatch"   // The person exists but a detail differs
+	resultNotFound   = "not_found"  // No person with this national ID
+	resultIncomplete = "incomplete" // More details are needed, see Missing
+)
+
+// fieldPrompts are the questions for the details the registry may ask for.
+var fieldPrompts = map[string]string{
+	"birth_date":  "Please reply with your date of birth as it appears on your ID, in the form YYYY-MM-DD.",
+	"father_name": "Please reply with your father's first name as it appears on your ID.",
+}
+
+// verifyRequest is the body of a lookup.
+type verifyRequest struct {
+	NationalID string            `json:"national_id"`
+	FirstName  string            `json:"first_name"`
+	LastName   string            `json:"last_name"`
+	Details    map[string]string `json:"details,omitempty"` // Answers to earlier "incomplete" results
+}
+
+// verifyResponse is the answer to a lookup.
+type verifyResponse struct {
+	Result  string   `json:"result"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Strategy is a ports.VerificationStrategy that checks new users against a
+// government person registry over HTTP. A match approves, a mismatch rejects,
+// an unknown person goes to the moderators.
+type Strategy struct {
+	name    string
+	baseURL string
+	client  *http.Client
+	log     zerolog.Logger
+}
+
+// NewStrategy creates a registry strategy registered under name.
+func NewStrategy(name, baseURL string, timeout time.Duration, baseLogger *zerolog.Logger) *Strategy {
+	return &Strategy{
+		name:    name,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+		log:     baseLogger.With().Str("component", "govregistry").Str("strategy", name).Logger(),
+	}
+}
+
+// Name returns the strategy name from the config.
+func (s *Strategy) Name() string {
+	return s.name
+}
+
+// Verify looks the user up in the registry.
+func (s *Strategy) Verify(ctx context.Context, input ports.VerificationInput) (*ports.VerificationDecision, error) {
+	user := input.User
+	if user.GovernmentID == nil || user.FirstName == nil || user.LastName == nil {
+		return &ports.VerificationDecision{Outcome: ports.VerificationManual, Reason: "registration details are incomplete"}, nil
+	}
+
+	body, err := json.Marshal(verifyRequest{
+		NationalID: *user.GovernmentID,
+		FirstName:  *user.FirstName,
+		LastName:   *user.LastName,
+		Details:    input.Fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+verifyPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("registry request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned status %d", resp.StatusCode)
+	}
+
+	var result verifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid registry response: %w", err)
+	}
+	s.log.Debug().Str("user_id", user.ID.String()).Str("result", result.Result).Msg("Registry lookup done")
+
+	switch result.Result {
+	case resultMatch:
+		return &ports.VerificationDecision{Outcome: ports.VerificationApprove, Reason: "registry match"}, nil
+	case resultMismatch:
+		return &ports.VerificationDecision{Outcome: ports.VerificationReject, Reason: "details do not match the registry"}, nil
+	case resultNotFound:
+		return &ports.VerificationDecision{Outcome: ports.VerificationManual, Reason: "not found in the registry"}, nil
+	case resultIncomplete:
+		if len(result.Missing) == 0 {
+			return nil, errors.New("registry asked for more details without naming them")
+		}
+		fields := make([]ports.VerificationField, 0, len(result.Missing))
+		for _, key := range result.Missing {
+			prompt, ok := fieldPrompts[key]
+			if !ok {
+				prompt = fmt.Sprintf("Please reply with your %s as it appears on your ID.", strings.ReplaceAll(key, "_", " "))
+			}
+			fields = append(fields, ports.VerificationField{Key: key, Prompt: prompt})
+		}
+		return &ports.VerificationDecision{Outcome: ports.VerificationNeedFields, Reason: "registry needs more details", Fields: fields}, nil
+	}
+	return nil, fmt.Errorf("unknown registry result %q", result.Result)
+}
@@ -0,0 +1,85 @@
+package govregistry
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// newTestStrategy starts the stand-in with the sample people and points a strategy at it.
+func newTestStrategy(t *testing.T) *Strategy {
+	nopLogger := zerolog.Nop()
+	server := httptest.NewServer(NewStandIn(SamplePeople, &nopLogger))
+	t.Cleanup(server.Close)
+	return NewStrategy("dolate_man", server.URL, 2*time.Second, &nopLogger)
+}
+
+func testUser(nationalID, firstName, lastName string) *domain.User {
+	return &domain.User{
+		ID:           uuid.New(),
+		GovernmentID: &nationalID,
+		FirstName:    &firstName,
+		LastName:     &lastName,
+	}
+}
+
+func TestStrategy_Verify(t *testing.T) {
+	strategy := newTestStrategy(t)
+	ctx := context.Background()
+	sara := SamplePeople[0]
+
+	tests := []struct {
+		name   string
+		user   *domain.User
+		fields map[string]string
+		want   ports.VerificationOutcome
+	}{
+		{"unknown person", testUser("9999999999", "No", "Body"), nil, ports.VerificationManual},
+		{"wrong name", testUser(sara.NationalID, "Maryam", sara.LastName), nil, ports.VerificationReject},
+		{"birth date missing", testUser(sara.NationalID, "sara ", sara.LastName), nil, ports.VerificationNeedFields},
+		{"wrong birth date", testUser(sara.NationalID, sara.FirstName, sara.LastName), map[string]string{"birth_date": "1991-01-01"}, ports.VerificationReject},
+		{"match", testUser(sara.NationalID, sara.FirstName, sara.LastName), map[string]string{"birth_date": sara.BirthDate}, ports.VerificationApprove},
+		{"incomplete registration", &domain.User{ID: uuid.New()}, nil, ports.VerificationManual},
+	}
+	for _, tt := range tests {
+		decision, err := strategy.Verify(ctx, ports.VerificationInput{User: tt.user, Fields: tt.fields})
+		if err != nil {
+			t.Errorf("%s: Verify failed: %v", tt.name, err)
+			continue
+		}
+		if decision.Outcome != tt.want {
+			t.Errorf("%s: outcome = %s, want %s", tt.name, decision.Outcome, tt.want)
+		}
+		if decision.Outcome == ports.VerificationNeedFields {
+			if len(decision.Fields) != 1 || decision.Fields[0].Key != "birth_date" || decision.Fields[0].Prompt == "" {
+				t.Errorf("%s: unexpected fields %+v", tt.name, decision.Fields)
+			}
+		}
+	}
+}
+
+func TestStrategy_Verify_RegistryErrors(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	strategy := NewStrategy("dolate_man", server.URL, 2*time.Second, &nopLogger)
+	user := testUser("0012345678", "Sara", "Ahmadi")
+	if _, err := strategy.Verify(context.Background(), ports.VerificationInput{User: user}); err == nil {
+		t.Error("expected an error for a failing registry")
+	}
+
+	server.Close()
+	if _, err := strategy.Verify(context.Background(), ports.VerificationInput{User: user}); err == nil {
+		t.Error("expected an error for an unreachable registry")
+	}
+}
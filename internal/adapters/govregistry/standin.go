@@ -0,0 +1,91 @@
+package govregistry
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Person is one entry of the stand-in registry.
+type Person struct {
+	NationalID string `json:"national_id"`
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
+	BirthDate  string `json:"birth_date"` // YYYY-MM-DD
+}
+
+// SamplePeople are the people the stand-in knows when no list is given.
+var SamplePeople = []Person{
+	{NationalID: "0012345678", FirstName: "Sara", LastName: "Ahmadi", BirthDate: "1990-03-21"},
+	{NationalID: "0087654321", FirstName: "Reza", LastName: "Karimi", BirthDate: "1985-11-02"},
+}
+
+// StandIn is a local HTTP stand-in for a government person registry, for
+// development and tests. It speaks the same API as the real one: the birth
+// date is always asked for before a person can match.
+type StandIn struct {
+	people map[string]Person
+	log    zerolog.Logger
+}
+
+// NewStandIn creates a stand-in registry that knows the given people.
+func NewStandIn(people []Person, baseLogger *zerolog.Logger) *StandIn {
+	byID := make(map[string]Person, len(people))
+	for _, p := range people {
+		byID[p.NationalID] = p
+	}
+	return &StandIn{
+		people: byID,
+		log:    baseLogger.With().Str("component", "govregistry_standin").Logger(),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != verifyPath {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req verifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	result := s.lookup(req)
+	s.log.Info().Str("national_id", req.NationalID).Str("result", result.Result).Msg("Registry lookup")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// lookup decides a lookup the way the real registry does.
+func (s *StandIn) lookup(req verifyRequest) verifyResponse {
+	person, ok := s.people[strings.TrimSpace(req.NationalID)]
+	if !ok {
+		return verifyResponse{Result: resultNotFound}
+	}
+	if !sameName(person.FirstName, req.FirstName) || !sameName(person.LastName, req.LastName) {
+		return verifyResponse{Result: resultMismatch}
+	}
+	birthDate, ok := req.Details["birth_date"]
+	if !ok {
+		return verifyResponse{Result: resultIncomplete, Missing: []string{"birth_date"}}
+	}
+	if strings.TrimSpace(birthDate) != person.BirthDate {
+		return verifyResponse{Result: resultMismatch}
+	}
+	return verifyResponse{Result: resultMatch}
+}
+
+// sameName compares names ignoring case and surrounding spaces.
+func sameName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/ports"
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+var _ ports.JobLocker = (*advisoryLocker)(nil) // Ensure compliance
+
+// advisoryLocker implements ports.JobLocker with session-level Postgres
+// advisory locks, so replicas sharing a database never run the same job twice.
+type advisoryLocker struct {
+	db  *DB
+	log zerolog.Logger
+}
+
+// NewAdvisoryLocker creates a new job locker backed by pg_try_advisory_lock.
+func NewAdvisoryLocker(db *DB, baseLogger *zerolog.Logger) ports.JobLocker {
+	return &advisoryLocker{
+		db:  db,
+		log: baseLogger.With().Str("component", "advisory_locker").Logger(),
+	}
+}
+
+// TryLock takes the advisory lock for the job name.
+// A session lock belongs to one connection, so the connection is held until unlock.
+func (l *advisoryLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := l.db.pool.Acquire(ctx)
+	if err != nil {
+		l.log.Error().Err(err).Str("job", name).Msg("Failed to acquire connection for advisory lock")
+		return nil, false, err
+	}
+
+	var ok bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, name).Scan(&ok); err != nil {
+		conn.Release()
+		l.log.Error().Err(err).Str("job", name).Msg("Failed to take advisory lock")
+		return nil, false, err
+	}
+	if !ok {
+		conn.Release()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		// Use a fresh context: the job's context may already be cancelled
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, name); err != nil {
+			// The session still holds the lock. Back in the pool it would keep
+			// every replica off the job, so close it: that ends the session
+			// and frees the lock with it.
+			l.log.Error().Err(err).Str("job", name).Msg("Failed to release advisory lock, closing its connection")
+			if err := conn.Hijack().Close(context.Background()); err != nil {
+				l.log.Error().Err(err).Str("job", name).Msg("Failed to close advisory lock connection")
+			}
+			return
+		}
+		conn.Release()
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,346 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog"
+)
+
+var _ ports.BidRepository = (*bidRepository)(nil) // Ensure compliance
+
+type bidRepository struct {
+	db  *DB
+	log zerolog.Logger
+}
+
+// NewBidRepository creates a new repository for bid operations.
+func NewBidRepository(db *DB, baseLogger *zerolog.Logger) ports.BidRepository {
+	return &bidRepository{
+		db:  db,
+		log: baseLogger.With().Str("component", "bid_repo").Logger(),
+	}
+}
+
+// bidQueryCols is the list of columns for scanning
+const bidQueryCols = `
+	id, user_id, request_id, status, notes, created_at, updated_at
+`
+
+// Create saves a new bid.
+func (r *bidRepository) Create(ctx context.Context, bid *domain.Bid) error {
+	query := `
+		INSERT INTO bids (
+			id, user_id, request_id, status, notes
+		) VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.db.q(ctx).Exec(ctx, query,
+		bid.ID,
+		bid.UserID,
+		bid.RequestID,
+		bid.Status,
+		bid.Notes,
+	)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return ports.ErrBidExists
+		}
+		r.log.Error().Err(err).
+			Str("user_id", bid.UserID.String()).
+			Str("request_id", bid.RequestID.String()).
+			Msg("Failed to insert new bid")
+	}
+	return err
+}
+
+// scanBid is a helper to scan a row into a Bid struct
+func (r *bidRepository) scanBid(row pgx.Row) (*domain.Bid, error) {
+	var bid domain.Bid
+	err := row.Scan(
+		&bid.ID,
+		&bid.UserID,
+		&bid.RequestID,
+		&bid.Status,
+		&bid.Notes,
+		&bid.CreatedAt,
+		&bid.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err // Return specific error
+		}
+		r.log.Error().Err(err).Msg("Failed to scan bid row")
+		return nil, err
+	}
+	return &bid, nil
+}
+
+// GetByID finds a bid by its UUID.
+func (r *bidRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Bid, error) {
+	query := `SELECT ` + bidQueryCols + ` FROM bids WHERE id = $1`
+
+	row := r.db.q(ctx).QueryRow(ctx, query, id)
+	bid, err := r.scanBid(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Info().Str("bid_id", id.String()).Msg("Bid not found")
+			return nil, nil // Return nil, nil for "not found"
+		}
+		return nil, err
+	}
+	return bid, nil
+}
+
+// GetByRequestID finds all bids placed on a request.
+func (r *bidRepository) GetByRequestID(ctx context.Context, requestID uuid.UUID) ([]*domain.Bid, error) {
+	query := `SELECT ` + bidQueryCols + ` FROM bids
+		WHERE request_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.q(ctx).Query(ctx, query, requestID)
+	if err != nil {
+		r.log.Error().Err(err).Str("request_id", requestID.String()).Msg("Failed to query bids")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bids []*domain.Bid
+	for rows.Next() {
+		bid, err := r.scanBid(rows)
+		if err != nil {
+			r.log.Error().Err(err).Str("request_id", requestID.String()).Msg("Failed during row scan for bids")
+			return nil, err
+		}
+		bids = append(bids, bid)
+	}
+
+	if rows.Err() != nil {
+		r.log.Error().Err(rows.Err()).Str("request_id", requestID.String()).Msg("Error iterating bid rows")
+		return nil, rows.Err()
+	}
+
+	return bids, nil
+}
+
+// GetByUserID finds all bids placed by a user.
+func (r *bidRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Bid, error) {
+	query := `SELECT ` + bidQueryCols + ` FROM bids
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	return r.queryBids(ctx, query, userID)
+}
+
+// TransitionStatus saves a new status if the stored one is still 'from'.
+func (r *bidRepository) TransitionStatus(ctx context.Context, bid *domain.Bid, from domain.BidStatus) error {
+	return r.transition(ctx, bid, from, bid.Status, ports.ErrBidStatusChanged)
+}
+
+// CancelPending cancels a bid if it is still pending.
+// The status check keeps a concurrent accept from being overwritten.
+func (r *bidRepository) CancelPending(ctx context.Context, bid *domain.Bid) error {
+	return r.transition(ctx, bid, domain.BidStatusPending, domain.BidStatusCancelled, ports.ErrBidNotPending)
+}
+
+// RejectPending rejects a bid if it is still pending.
+func (r *bidRepository) RejectPending(ctx context.Context, bid *domain.Bid) error {
+	return r.transition(ctx, bid, domain.BidStatusPending, domain.BidStatusRejected, ports.ErrBidNotPending)
+}
+
+// transition moves a bid from status 'from' to 'to'. It returns changed if
+// the stored status is no longer 'from'.
+func (r *bidRepository) transition(ctx context.Context, bid *domain.Bid, from, to domain.BidStatus, changed error) error {
+	query := `
+		UPDATE bids SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3
+		RETURNING updated_at
+	`
+	err := r.db.q(ctx).QueryRow(ctx, query, to, bid.ID, from).Scan(&bid.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return changed
+		}
+		r.log.Error().Err(err).Str("bid_id", bid.ID.String()).Str("from", string(from)).Str("to", string(to)).Msg("Failed to transition bid status")
+		return err
+	}
+	bid.Status = to
+	return nil
+}
+
+// CancelPendingByRequest cancels the pending bids of one request.
+func (r *bidRepository) CancelPendingByRequest(ctx context.Context, requestID uuid.UUID) ([]*domain.Bid, error) {
+	query := `
+		UPDATE bids SET status = $1, updated_at = NOW()
+		WHERE request_id = $2 AND status = $3
+		RETURNING ` + bidQueryCols
+	return r.queryBids(ctx, query, domain.BidStatusCancelled, requestID, domain.BidStatusPending)
+}
+
+// queryBids runs a query returning bid rows and collects them.
+func (r *bidRepository) queryBids(ctx context.Context, query string, args ...any) ([]*domain.Bid, error) {
+	rows, err := r.db.q(ctx).Query(ctx, query, args...)
+	if err != nil {
+		r.log.Error().Err(err).Msg("Failed to query bids")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bids []*domain.Bid
+	for rows.Next() {
+		bid, err := r.scanBid(rows)
+		if err != nil {
+			return nil, err
+		}
+		bids = append(bids, bid)
+	}
+
+	if rows.Err() != nil {
+		r.log.Error().Err(rows.Err()).Msg("Error iterating bid rows")
+		return nil, rows.Err()
+	}
+
+	return bids, nil
+}
+
+// CancelPendingOnClosedRequests cancels pending bids left behind on
+// expired or cancelled requests.
+func (r *bidRepository) CancelPendingOnClosedRequests(ctx context.Context) ([]*domain.Bid, error) {
+	query := `
+		UPDATE bids SET status = $1, updated_at = NOW()
+		WHERE status = $2 AND request_id IN (
+			SELECT id FROM requests WHERE status <> $3
+		)
+		RETURNING ` + bidQueryCols
+
+	return r.queryBids(ctx, query, domain.BidStatusCancelled, domain.BidStatusPending, domain.RequestStatusOpen)
+}
+
+// Update saves the mutable fields of a bid.
+func (r *bidRepository) Update(ctx context.Context, bid *domain.Bid) error {
+	query := `
+		UPDATE bids SET
+			status = $1,
+			notes = $2,
+			updated_at = NOW()
+		WHERE id = $3
+	`
+	cmdTag, err := r.db.q(ctx).Exec(ctx, query,
+		bid.Status,
+		bid.Notes,
+		bid.ID, // The WHERE clause
+	)
+
+	if err != nil {
+		r.log.Error().Err(err).Str("bid_id", bid.ID.String()).Msg("Failed to update bid")
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		r.log.Error().Err(errors.New("no rows affected")).Str("bid_id", bid.ID.String()).Msg("Bid not found when trying to update")
+		return errors.New("bid not found")
+	}
+
+	return nil
+}
+
+// Accept matches a bid with its request inside one database transaction.
+// The request row is locked first, so two owners' clicks cannot both win.
+func (r *bidRepository) Accept(ctx context.Context, bidID uuid.UUID, tx *domain.Transaction) ([]*domain.Bid, error) {
+	log := r.log.With().Str("bid_id", bidID.String()).Str("request_id", tx.RequestID.String()).Logger()
+
+	dbTx, err := r.db.q(ctx).Begin(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin transaction")
+		return nil, err
+	}
+	defer dbTx.Rollback(ctx) // No-op after a successful commit
+
+	// 1. Lock the request and make sure it is still open
+	var reqStatus domain.RequestStatus
+	err = dbTx.QueryRow(ctx, `SELECT status FROM requests WHERE id = $1 FOR UPDATE`, tx.RequestID).Scan(&reqStatus)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ports.ErrRequestNotOpen
+		}
+		log.Error().Err(err).Msg("Failed to lock request")
+		return nil, err
+	}
+	if reqStatus != domain.RequestStatusOpen {
+		return nil, ports.ErrRequestNotOpen
+	}
+
+	// 2. Accept the bid
+	cmdTag, err := dbTx.Exec(ctx, `
+		UPDATE bids SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND request_id = $3 AND status = $4
+	`, domain.BidStatusAccepted, bidID, tx.RequestID, domain.BidStatusPending)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to accept bid")
+		return nil, err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return nil, ports.ErrBidNotPending
+	}
+
+	// 3. Reject every other pending bid
+	rows, err := dbTx.Query(ctx, `
+		UPDATE bids SET status = $1, updated_at = NOW()
+		WHERE request_id = $2 AND id <> $3 AND status = $4
+		RETURNING `+bidQueryCols,
+		domain.BidStatusRejected, tx.RequestID, bidID, domain.BidStatusPending)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to reject competing bids")
+		return nil, err
+	}
+	var rejected []*domain.Bid
+	for rows.Next() {
+		bid, err := r.scanBid(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rejected = append(rejected, bid)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		log.Error().Err(rows.Err()).Msg("Error iterating rejected bid rows")
+		return nil, rows.Err()
+	}
+
+	// 4. Flip the request to matched
+	if _, err := dbTx.Exec(ctx, `
+		UPDATE requests SET status = $1, updated_at = NOW() WHERE id = $2
+	`, domain.RequestStatusMatched, tx.RequestID); err != nil {
+		log.Error().Err(err).Msg("Failed to mark request as matched")
+		return nil, err
+	}
+
+	// 5. Open the transaction
+	if _, err := dbTx.Exec(ctx, `
+		INSERT INTO transactions (
+			id, request_id, bid_id, seller_user_id, buyer_user_id, status,
+			platform_deposit_base_account_id, platform_deposit_quote_account_id,
+			fee_schedule_version, seller_fee, seller_net, buyer_fee, buyer_net
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`, tx.ID, tx.RequestID, bidID, tx.SellerUserID, tx.BuyerUserID, tx.Status,
+		tx.PlatformDepositBaseAccountID, tx.PlatformDepositQuoteAccountID,
+		tx.FeeScheduleVersion, tx.SellerFee, tx.SellerNet, tx.BuyerFee, tx.BuyerNet); err != nil {
+		log.Error().Err(err).Msg("Failed to insert transaction")
+		return nil, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to commit bid acceptance")
+		return nil, err
+	}
+	tx.BidID = bidID
+	return rejected, nil
+}
@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestBidRepository_Create_GetByID_Roundtrip(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	repo := NewBidRepository(testDB, &nopLogger)
+
+	owner, cleanupOwner := createTestUser(t, userRepo)
+	defer cleanupOwner()
+	bidder, cleanupBidder := createTestUser(t, userRepo)
+	defer cleanupBidder()
+	req, cleanupReq := createTestRequest(t, reqRepo, owner.ID)
+	defer cleanupReq()
+
+	notes := "Can pay within the hour"
+	bid := &domain.Bid{
+		ID:        uuid.New(),
+		UserID:    bidder.ID,
+		RequestID: req.ID,
+		Status:    domain.BidStatusPending,
+		Notes:     &notes,
+	}
+
+	// 2. Run Create
+	if err := repo.Create(ctx, bid); err != nil {
+		t.Fatalf("Failed to create bid: %v", err)
+	}
+
+	// 3. Run GetByID
+	found, err := repo.GetByID(ctx, bid.ID)
+	if err != nil {
+		t.Fatalf("Failed to get bid by ID: %v", err)
+	}
+	if found == nil {
+		t.Fatal("GetByID: bid not found, but should exist")
+	}
+
+	// 4. Verify
+	if found.UserID != bidder.ID || found.RequestID != req.ID {
+		t.Errorf("Owner mismatch: got user %v request %v", found.UserID, found.RequestID)
+	}
+	if found.Status != domain.BidStatusPending {
+		t.Errorf("Status mismatch: got %s, want %s", found.Status, domain.BidStatusPending)
+	}
+	if found.Notes == nil || *found.Notes != notes {
+		t.Errorf("Notes mismatch: got %v, want %s", found.Notes, notes)
+	}
+}
+
+func TestBidRepository_Create_DuplicateFails(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	repo := NewBidRepository(testDB, &nopLogger)
+
+	owner, cleanupOwner := createTestUser(t, userRepo)
+	defer cleanupOwner()
+	bidder, cleanupBidder := createTestUser(t, userRepo)
+	defer cleanupBidder()
+	req, cleanupReq := createTestRequest(t, reqRepo, owner.ID)
+	defer cleanupReq()
+	_, cleanupBid := createTestBid(t, repo, bidder.ID, req.ID)
+	defer cleanupBid()
+
+	// A user can only bid once per request
+	second := &domain.Bid{
+		ID:        uuid.New(),
+		UserID:    bidder.ID,
+		RequestID: req.ID,
+		Status:    domain.BidStatusPending,
+	}
+	if err := repo.Create(ctx, second); !errors.Is(err, ports.ErrBidExists) {
+		t.Fatalf("Second bid on the same request should fail with ErrBidExists, got: %v", err)
+	}
+}
+
+func TestBidRepository_Update_GetByRequestID(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	repo := NewBidRepository(testDB, &nopLogger)
+
+	owner, cleanupOwner := createTestUser(t, userRepo)
+	defer cleanupOwner()
+	bidder, cleanupBidder := createTestUser(t, userRepo)
+	defer cleanupBidder()
+	req, cleanupReq := createTestRequest(t, reqRepo, owner.ID)
+	defer cleanupReq()
+	bid, cleanupBid := createTestBid(t, repo, bidder.ID, req.ID)
+	defer cleanupBid()
+
+	bid.Status = domain.BidStatusRejected
+	if err := repo.Update(ctx, bid); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	found, err := repo.GetByRequestID(ctx, req.ID)
+	if err != nil {
+		t.Fatalf("GetByRequestID failed: %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("Found %d bids, expected 1", len(found))
+	}
+	if found[0].Status != domain.BidStatusRejected {
+		t.Errorf("Status was not updated: got %s, want %s", found[0].Status, domain.BidStatusRejected)
+	}
+}
+
+func TestBidRepository_Accept(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	repo := NewBidRepository(testDB, &nopLogger)
+
+	owner, cleanupOwner := createTestUser(t, userRepo)
+	defer cleanupOwner()
+	winner, cleanupWinner := createTestUser(t, userRepo)
+	defer cleanupWinner()
+	loser, cleanupLoser := createTestUser(t, userRepo)
+	defer cleanupLoser()
+	req, cleanupReq := createTestRequest(t, reqRepo, owner.ID)
+	defer cleanupReq()
+	winningBid, cleanupWinningBid := createTestBid(t, repo, winner.ID, req.ID)
+	defer cleanupWinningBid()
+	losingBid, cleanupLosingBid := createTestBid(t, repo, loser.ID, req.ID)
+	defer cleanupLosingBid()
+
+	tx := &domain.Transaction{
+		ID:           uuid.New(),
+		RequestID:    req.ID,
+		SellerUserID: owner.ID,
+		BuyerUserID:  winner.ID,
+		Status:       domain.TxStatusPendingDeposits,
+	}
+
+	// 1. Accept the first bid
+	rejected, err := repo.Accept(ctx, winningBid.ID, tx)
+	if err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	defer cleanupTestTransaction(t, tx.ID)
+
+	if len(rejected) != 1 || rejected[0].ID != losingBid.ID {
+		t.Fatalf("Expected only the losing bid to be rejected, got %v", rejected)
+	}
+
+	// 2. Verify every row moved together
+	foundReq, _ := reqRepo.GetByID(ctx, req.ID)
+	if foundReq.Status != domain.RequestStatusMatched {
+		t.Errorf("Request status: got %s, want %s", foundReq.Status, domain.RequestStatusMatched)
+	}
+	foundWinner, _ := repo.GetByID(ctx, winningBid.ID)
+	if foundWinner.Status != domain.BidStatusAccepted {
+		t.Errorf("Winning bid status: got %s, want %s", foundWinner.Status, domain.BidStatusAccepted)
+	}
+	foundLoser, _ := repo.GetByID(ctx, losingBid.ID)
+	if foundLoser.Status != domain.BidStatusRejected {
+		t.Errorf("Losing bid status: got %s, want %s", foundLoser.Status, domain.BidStatusRejected)
+	}
+
+	// 3. A second acceptance must fail, the request is no longer open
+	again := &domain.Transaction{ID: uuid.New(), RequestID: req.ID, SellerUserID: owner.ID, BuyerUserID: loser.ID, Status: domain.TxStatusPendingDeposits}
+	if _, err := repo.Accept(ctx, losingBid.ID, again); !errors.Is(err, ports.ErrRequestNotOpen) {
+		t.Errorf("Expected ErrRequestNotOpen, got: %v", err)
+	}
+}
+
+func TestBidRepository_CancelPending(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	repo := NewBidRepository(testDB, &nopLogger)
+
+	owner, cleanupOwner := createTestUser(t, userRepo)
+	defer cleanupOwner()
+	bidder, cleanupBidder := createTestUser(t, userRepo)
+	defer cleanupBidder()
+	req, cleanupReq := createTestRequest(t, reqRepo, owner.ID)
+	defer cleanupReq()
+	bid, cleanupBid := createTestBid(t, repo, bidder.ID, req.ID)
+	defer cleanupBid()
+
+	// 1. The first cancel wins
+	if err := repo.CancelPending(ctx, bid); err != nil {
+		t.Fatalf("CancelPending failed: %v", err)
+	}
+	if bid.Status != domain.BidStatusCancelled {
+		t.Errorf("Status mismatch: got %s", bid.Status)
+	}
+
+	// 2. The bid is no longer pending
+	if err := repo.CancelPending(ctx, bid); !errors.Is(err, ports.ErrBidNotPending) {
+		t.Errorf("Expected ErrBidNotPending, got: %v", err)
+	}
+}
+
+func TestBidRepository_RejectPending(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	repo := NewBidRepository(testDB, &nopLogger)
+
+	owner, cleanupOwner := createTestUser(t, userRepo)
+	defer cleanupOwner()
+	bidder, cleanupBidder := createTestUser(t, userRepo)
+	defer cleanupBidder()
+	req, cleanupReq := createTestRequest(t, reqRepo, owner.ID)
+	defer cleanupReq()
+	bid, cleanupBid := createTestBid(t, repo, bidder.ID, req.ID)
+	defer cleanupBid()
+
+	// 1. A pending bid is rejected
+	if err := repo.RejectPending(ctx, bid); err != nil {
+		t.Fatalf("RejectPending failed: %v", err)
+	}
+	if bid.Status != domain.BidStatusRejected {
+		t.Errorf("Status mismatch: got %s", bid.Status)
+	}
+
+	// 2. A resolved bid is left alone
+	if err := repo.CancelPending(ctx, bid); !errors.Is(err, ports.ErrBidNotPending) {
+		t.Errorf("Expected ErrBidNotPending, got: %v", err)
+	}
+	stored, err := repo.GetByID(ctx, bid.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if stored.Status != domain.BidStatusRejected {
+		t.Errorf("Rejected bid was overwritten: %s", stored.Status)
+	}
+}
+
+func TestBidRepository_TransitionStatus(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	repo := NewBidRepository(testDB, &nopLogger)
+
+	owner, cleanupOwner := createTestUser(t, userRepo)
+	defer cleanupOwner()
+	bidder, cleanupBidder := createTestUser(t, userRepo)
+	defer cleanupBidder()
+	req, cleanupReq := createTestRequest(t, reqRepo, owner.ID)
+	defer cleanupReq()
+	bid, cleanupBid := createTestBid(t, repo, bidder.ID, req.ID)
+	defer cleanupBid()
+
+	// 1. The stored status matches
+	bid.Status = domain.BidStatusAccepted
+	if err := repo.TransitionStatus(ctx, bid, domain.BidStatusPending); err != nil {
+		t.Fatalf("TransitionStatus failed: %v", err)
+	}
+
+	// 2. The stored status moved on
+	bid.Status = domain.BidStatusCancelled
+	if err := repo.TransitionStatus(ctx, bid, domain.BidStatusPending); !errors.Is(err, ports.ErrBidStatusChanged) {
+		t.Errorf("Expected ErrBidStatusChanged, got: %v", err)
+	}
+	stored, err := repo.GetByID(ctx, bid.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if stored.Status != domain.BidStatusAccepted {
+		t.Errorf("Accepted bid was overwritten: %s", stored.Status)
+	}
+}
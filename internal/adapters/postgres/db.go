@@ -1,12 +1,17 @@
 package postgres
 
 import (
+	"AsaExchange/internal/core/ports"
 	"context"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
 )
 
+var _ ports.Transactor = (*DB)(nil) // Ensure compliance
+
 // DB holds the connection pool.
 type DB struct {
 	pool *pgxpool.Pool
@@ -48,3 +53,50 @@ func (db *DB) Close() {
 	db.log.Info().Msg("Closing database connection pool")
 	db.pool.Close()
 }
+
+// querier is what repositories run statements on: the pool, or the
+// transaction opened by WithinTx.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// txKey is the context key of the transaction opened by WithinTx.
+type txKey struct{}
+
+// q returns the transaction carried by ctx, or the pool.
+// Begin on a transaction opens a savepoint, so repositories that need their
+// own transaction still nest inside WithinTx.
+func (db *DB) q(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db.pool
+}
+
+// WithinTx runs fn in one database transaction. Every repository called with
+// the ctx passed to fn joins it, so state changes and the events announcing
+// them commit together. Nested calls join the outer transaction.
+func (db *DB) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		db.log.Error().Err(err).Msg("Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback(ctx) // No-op after a successful commit
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		db.log.Error().Err(err).Msg("Failed to commit transaction")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+var _ ports.DeadLetterRepository = (*deadLetterRepository)(nil) // Ensure compliance
+
+type deadLetterRepository struct {
+	db     *DB
+	secSvc ports.SecurityPort
+	log    zerolog.Logger
+}
+
+// NewDeadLetterRepository creates a new repository for dead-lettered events.
+// Payloads are encrypted, like in the outbox.
+func NewDeadLetterRepository(db *DB, secSvc ports.SecurityPort, baseLogger *zerolog.Logger) ports.DeadLetterRepository {
+	return &deadLetterRepository{
+		db:     db,
+		secSvc: secSvc,
+		log:    baseLogger.With().Str("component", "dead_letter_repo").Logger(),
+	}
+}
+
+// Add stores a dead letter and fills in its ID and creation time.
+func (r *deadLetterRepository) Add(ctx context.Context, letter *ports.DeadLetter) error {
+	encrypted, err := r.secSvc.Encrypt(letter.Payload)
+	if err != nil {
+		r.log.Error().Err(err).Str("topic", letter.Topic).Msg("Failed to encrypt dead letter payload")
+		return err
+	}
+
+	err = r.db.q(ctx).QueryRow(ctx, `
+		INSERT INTO event_dead_letters (topic, subscriber, payload, error, attempts)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, letter.Topic, letter.Subscriber, encrypted, letter.Error, letter.Attempts).Scan(&letter.ID, &letter.CreatedAt)
+	if err != nil {
+		r.log.Error().Err(err).Str("topic", letter.Topic).Msg("Failed to add dead letter")
+		return err
+	}
+	return nil
+}
+
+// GetByID returns nil if no dead letter has the ID.
+func (r *deadLetterRepository) GetByID(ctx context.Context, id int64) (*ports.DeadLetter, error) {
+	row := r.db.q(ctx).QueryRow(ctx, `
+		SELECT id, topic, subscriber, payload, error, attempts, created_at, replayed_at
+		FROM event_dead_letters WHERE id = $1
+	`, id)
+	letter, err := r.scan(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Error().Err(err).Int64("dead_letter_id", id).Msg("Failed to get dead letter")
+		return nil, err
+	}
+	return letter, nil
+}
+
+// ListPending returns the newest dead letters not replayed yet.
+func (r *deadLetterRepository) ListPending(ctx context.Context, limit int) ([]*ports.DeadLetter, error) {
+	rows, err := r.db.q(ctx).Query(ctx, `
+		SELECT id, topic, subscriber, payload, error, attempts, created_at, replayed_at
+		FROM event_dead_letters WHERE replayed_at IS NULL
+		ORDER BY id DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		r.log.Error().Err(err).Msg("Failed to list dead letters")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var letters []*ports.DeadLetter
+	for rows.Next() {
+		letter, err := r.scan(rows)
+		if err != nil {
+			r.log.Error().Err(err).Msg("Failed to scan dead letter")
+			return nil, err
+		}
+		letters = append(letters, letter)
+	}
+	return letters, rows.Err()
+}
+
+// ClaimReplay marks a pending dead letter replayed.
+// It returns ports.ErrDeadLetterReplayed if it was not pending.
+func (r *deadLetterRepository) ClaimReplay(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.q(ctx).Exec(ctx, `
+		UPDATE event_dead_letters SET replayed_at = NOW()
+		WHERE id = $1 AND replayed_at IS NULL
+	`, id)
+	if err != nil {
+		r.log.Error().Err(err).Int64("dead_letter_id", id).Msg("Failed to claim dead letter")
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ports.ErrDeadLetterReplayed
+	}
+	return nil
+}
+
+// ReleaseReplay makes a claimed dead letter pending again.
+func (r *deadLetterRepository) ReleaseReplay(ctx context.Context, id int64) error {
+	if _, err := r.db.q(ctx).Exec(ctx, `
+		UPDATE event_dead_letters SET replayed_at = NULL WHERE id = $1
+	`, id); err != nil {
+		r.log.Error().Err(err).Int64("dead_letter_id", id).Msg("Failed to release dead letter")
+		return err
+	}
+	return nil
+}
+
+// scan reads one dead letter and decrypts its payload.
+func (r *deadLetterRepository) scan(row pgx.Row) (*ports.DeadLetter, error) {
+	var letter ports.DeadLetter
+	var encrypted []byte
+	if err := row.Scan(
+		&letter.ID,
+		&letter.Topic,
+		&letter.Subscriber,
+		&encrypted,
+		&letter.Error,
+		&letter.Attempts,
+		&letter.CreatedAt,
+		&letter.ReplayedAt,
+	); err != nil {
+		return nil, err
+	}
+	payload, err := r.secSvc.Decrypt(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	letter.Payload = payload
+	return &letter, nil
+}
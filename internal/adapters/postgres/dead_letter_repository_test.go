@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/ports"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestDeadLetterRepository_AddAndReplay(t *testing.T) {
+	// 1. Setup
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	repo := NewDeadLetterRepository(testDB, testSecSvc, &nopLogger)
+
+	letter := &ports.DeadLetter{
+		Topic:      "test:" + uuid.NewString(),
+		Subscriber: "notification",
+		Payload:    []byte(`{"id":"42"}`),
+		Error:      "telegram is down",
+		Attempts:   5,
+	}
+	defer func() {
+		if _, err := testDB.pool.Exec(ctx, "DELETE FROM event_dead_letters WHERE topic = $1", letter.Topic); err != nil {
+			t.Errorf("Failed to clean up dead letters: %v", err)
+		}
+	}()
+
+	// 2. Add
+	if err := repo.Add(ctx, letter); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if letter.ID == 0 || letter.CreatedAt.IsZero() {
+		t.Fatalf("Add did not fill in ID and CreatedAt: %+v", letter)
+	}
+
+	// 3. The payload round-trips and the letter is pending
+	stored, err := repo.GetByID(ctx, letter.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if string(stored.Payload) != `{"id":"42"}` || stored.Attempts != 5 || stored.ReplayedAt != nil {
+		t.Errorf("Stored letter mismatch: %+v", stored)
+	}
+	pending, err := repo.ListPending(ctx, 100)
+	if err != nil {
+		t.Fatalf("ListPending failed: %v", err)
+	}
+	if !containsLetter(pending, letter.ID) {
+		t.Error("New letter is not pending")
+	}
+
+	// 4. A claimed letter is no longer pending and cannot be claimed twice
+	if err := repo.ClaimReplay(ctx, letter.ID); err != nil {
+		t.Fatalf("ClaimReplay failed: %v", err)
+	}
+	pending, err = repo.ListPending(ctx, 100)
+	if err != nil {
+		t.Fatalf("ListPending failed: %v", err)
+	}
+	if containsLetter(pending, letter.ID) {
+		t.Error("Claimed letter is still pending")
+	}
+	if err := repo.ClaimReplay(ctx, letter.ID); !errors.Is(err, ports.ErrDeadLetterReplayed) {
+		t.Errorf("Expected ErrDeadLetterReplayed, got: %v", err)
+	}
+
+	// 5. A released letter is pending again
+	if err := repo.ReleaseReplay(ctx, letter.ID); err != nil {
+		t.Fatalf("ReleaseReplay failed: %v", err)
+	}
+	pending, err = repo.ListPending(ctx, 100)
+	if err != nil {
+		t.Fatalf("ListPending failed: %v", err)
+	}
+	if !containsLetter(pending, letter.ID) {
+		t.Error("Released letter is not pending")
+	}
+
+	// 6. Unknown IDs are not found
+	if missing, err := repo.GetByID(ctx, -1); err != nil || missing != nil {
+		t.Errorf("Expected nil for an unknown ID, got %+v, %v", missing, err)
+	}
+}
+
+func containsLetter(letters []*ports.DeadLetter, id int64) bool {
+	for _, letter := range letters {
+		if letter.ID == id {
+			return true
+		}
+	}
+	return false
+}
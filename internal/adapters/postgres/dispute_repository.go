@@ -0,0 +1,214 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog"
+)
+
+var _ ports.DisputeRepository = (*disputeRepository)(nil) // Ensure compliance
+
+type disputeRepository struct {
+	db  *DB
+	log zerolog.Logger
+}
+
+// NewDisputeRepository creates a new repository for dispute operations.
+func NewDisputeRepository(db *DB, baseLogger *zerolog.Logger) ports.DisputeRepository {
+	return &disputeRepository{
+		db:  db,
+		log: baseLogger.With().Str("component", "dispute_repo").Logger(),
+	}
+}
+
+// disputeQueryCols is the list of columns for scanning
+const disputeQueryCols = `
+	id, transaction_id, opened_by_user_id, reason, status,
+	resolution, resolved_by, resolved_at, created_at, updated_at
+`
+
+// evidenceQueryCols is the list of evidence columns for scanning
+const evidenceQueryCols = `
+	id, dispute_id, user_id, leg, statement, file_id, created_at
+`
+
+// Create saves a new dispute.
+func (r *disputeRepository) Create(ctx context.Context, dispute *domain.Dispute) error {
+	query := `
+		INSERT INTO disputes (
+			id, transaction_id, opened_by_user_id, reason, status
+		) VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.db.q(ctx).Exec(ctx, query,
+		dispute.ID,
+		dispute.TransactionID,
+		dispute.OpenedByUserID,
+		dispute.Reason,
+		dispute.Status,
+	)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return ports.ErrDisputeExists
+		}
+		r.log.Error().Err(err).
+			Str("transaction_id", dispute.TransactionID.String()).
+			Msg("Failed to insert new dispute")
+	}
+	return err
+}
+
+// scanDispute is a helper to scan a row into a Dispute struct
+func (r *disputeRepository) scanDispute(row pgx.Row) (*domain.Dispute, error) {
+	var d domain.Dispute
+	err := row.Scan(
+		&d.ID,
+		&d.TransactionID,
+		&d.OpenedByUserID,
+		&d.Reason,
+		&d.Status,
+		&d.Resolution,
+		&d.ResolvedBy,
+		&d.ResolvedAt,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err // Return specific error
+		}
+		r.log.Error().Err(err).Msg("Failed to scan dispute row")
+		return nil, err
+	}
+	return &d, nil
+}
+
+// GetByID finds a dispute by its UUID.
+func (r *disputeRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Dispute, error) {
+	query := `SELECT ` + disputeQueryCols + ` FROM disputes WHERE id = $1`
+
+	dispute, err := r.scanDispute(r.db.q(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Info().Str("dispute_id", id.String()).Msg("Dispute not found")
+			return nil, nil // Return nil, nil for "not found"
+		}
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// GetOpenByTransactionID finds the open dispute of a transaction.
+func (r *disputeRepository) GetOpenByTransactionID(ctx context.Context, transactionID uuid.UUID) (*domain.Dispute, error) {
+	query := `SELECT ` + disputeQueryCols + ` FROM disputes WHERE transaction_id = $1 AND status = $2`
+
+	dispute, err := r.scanDispute(r.db.q(ctx).QueryRow(ctx, query, transactionID, domain.DisputeStatusOpen))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // No open dispute
+		}
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// Resolve closes an open dispute with its outcome.
+// The status check makes two moderators' clicks race safely.
+func (r *disputeRepository) Resolve(ctx context.Context, dispute *domain.Dispute) error {
+	query := `
+		UPDATE disputes SET
+			status = $1,
+			resolution = $2,
+			resolved_by = $3,
+			resolved_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $4 AND status = $5
+		RETURNING resolved_at, updated_at
+	`
+	err := r.db.q(ctx).QueryRow(ctx, query,
+		domain.DisputeStatusResolved,
+		dispute.Resolution,
+		dispute.ResolvedBy,
+		dispute.ID, // The WHERE clause
+		domain.DisputeStatusOpen,
+	).Scan(&dispute.ResolvedAt, &dispute.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ports.ErrDisputeNotOpen
+		}
+		r.log.Error().Err(err).Str("dispute_id", dispute.ID.String()).Msg("Failed to resolve dispute")
+		return err
+	}
+
+	dispute.Status = domain.DisputeStatusResolved
+	return nil
+}
+
+// AddEvidence saves a party's statement and photo.
+func (r *disputeRepository) AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	query := `
+		INSERT INTO dispute_evidence (
+			id, dispute_id, user_id, leg, statement, file_id
+		) VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.q(ctx).Exec(ctx, query,
+		evidence.ID,
+		evidence.DisputeID,
+		evidence.UserID,
+		evidence.Leg,
+		evidence.Statement,
+		evidence.FileID,
+	)
+	if err != nil {
+		r.log.Error().Err(err).Str("dispute_id", evidence.DisputeID.String()).Msg("Failed to insert dispute evidence")
+	}
+	return err
+}
+
+// GetEvidence returns all evidence of a dispute, oldest first.
+func (r *disputeRepository) GetEvidence(ctx context.Context, disputeID uuid.UUID) ([]*domain.DisputeEvidence, error) {
+	query := `SELECT ` + evidenceQueryCols + ` FROM dispute_evidence
+		WHERE dispute_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.q(ctx).Query(ctx, query, disputeID)
+	if err != nil {
+		r.log.Error().Err(err).Str("dispute_id", disputeID.String()).Msg("Failed to query dispute evidence")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var evidence []*domain.DisputeEvidence
+	for rows.Next() {
+		var ev domain.DisputeEvidence
+		if err := rows.Scan(
+			&ev.ID,
+			&ev.DisputeID,
+			&ev.UserID,
+			&ev.Leg,
+			&ev.Statement,
+			&ev.FileID,
+			&ev.CreatedAt,
+		); err != nil {
+			r.log.Error().Err(err).Str("dispute_id", disputeID.String()).Msg("Failed to scan dispute evidence row")
+			return nil, err
+		}
+		evidence = append(evidence, &ev)
+	}
+
+	if rows.Err() != nil {
+		r.log.Error().Err(rows.Err()).Str("dispute_id", disputeID.String()).Msg("Error iterating dispute evidence rows")
+		return nil, rows.Err()
+	}
+
+	return evidence, nil
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestDisputeRepository_Lifecycle(t *testing.T) {
+	// 1. Setup
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	bidRepo := NewBidRepository(testDB, &nopLogger)
+	txRepo := NewTransactionRepository(testDB, &nopLogger)
+	repo := NewDisputeRepository(testDB, &nopLogger)
+
+	seller, cleanupSeller := createTestUser(t, userRepo)
+	defer cleanupSeller()
+	buyer, cleanupBuyer := createTestUser(t, userRepo)
+	defer cleanupBuyer()
+	req, cleanupReq := createTestRequest(t, reqRepo, seller.ID)
+	defer cleanupReq()
+	bid, cleanupBid := createTestBid(t, bidRepo, buyer.ID, req.ID)
+	defer cleanupBid()
+
+	tx := &domain.Transaction{
+		ID:           uuid.New(),
+		RequestID:    req.ID,
+		BidID:        bid.ID,
+		SellerUserID: seller.ID,
+		BuyerUserID:  buyer.ID,
+		Status:       domain.TxStatusDisputed,
+	}
+	if err := txRepo.Create(ctx, tx); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	defer cleanupTestTransaction(t, tx.ID) // Cascades to disputes and evidence
+
+	// 2. Open a dispute; a second open one is refused
+	dispute := &domain.Dispute{
+		ID:             uuid.New(),
+		TransactionID:  tx.ID,
+		OpenedByUserID: buyer.ID,
+		Reason:         "Never received the payout",
+		Status:         domain.DisputeStatusOpen,
+	}
+	if err := repo.Create(ctx, dispute); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	second := *dispute
+	second.ID = uuid.New()
+	if err := repo.Create(ctx, &second); !errors.Is(err, ports.ErrDisputeExists) {
+		t.Fatalf("Expected ErrDisputeExists, got: %v", err)
+	}
+
+	// 3. Evidence round-trips
+	if err := repo.AddEvidence(ctx, &domain.DisputeEvidence{
+		ID:        uuid.New(),
+		DisputeID: dispute.ID,
+		UserID:    buyer.ID,
+		Leg:       domain.LegBuyer,
+		Statement: "Here is my bank statement",
+		FileID:    "file-123",
+	}); err != nil {
+		t.Fatalf("AddEvidence failed: %v", err)
+	}
+	evidence, err := repo.GetEvidence(ctx, dispute.ID)
+	if err != nil {
+		t.Fatalf("GetEvidence failed: %v", err)
+	}
+	if len(evidence) != 1 || evidence[0].Leg != domain.LegBuyer || evidence[0].FileID != "file-123" {
+		t.Errorf("Evidence mismatch: %+v", evidence)
+	}
+
+	// 4. Resolve once; the second resolution loses
+	open, err := repo.GetOpenByTransactionID(ctx, tx.ID)
+	if err != nil || open == nil || open.ID != dispute.ID {
+		t.Fatalf("GetOpenByTransactionID mismatch: %v, %v", open, err)
+	}
+	resolution := domain.DisputeResolutionRefund
+	moderatorID := seller.ID // Any existing user will do for the FK
+	open.Resolution = &resolution
+	open.ResolvedBy = &moderatorID
+	if err := repo.Resolve(ctx, open); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if err := repo.Resolve(ctx, open); !errors.Is(err, ports.ErrDisputeNotOpen) {
+		t.Fatalf("Expected ErrDisputeNotOpen, got: %v", err)
+	}
+
+	resolved, err := repo.GetByID(ctx, dispute.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if resolved.Status != domain.DisputeStatusResolved || resolved.Resolution == nil || *resolved.Resolution != resolution {
+		t.Errorf("Resolution not saved: %+v", resolved)
+	}
+	if open, _ := repo.GetOpenByTransactionID(ctx, tx.ID); open != nil {
+		t.Errorf("Resolved dispute is still returned as open")
+	}
+}
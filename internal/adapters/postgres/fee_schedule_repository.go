@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+var _ ports.FeeScheduleRepository = (*feeScheduleRepository)(nil) // Ensure compliance
+
+type feeScheduleRepository struct {
+	db  *DB
+	log zerolog.Logger
+}
+
+// NewFeeScheduleRepository creates a new repository for fee schedule versions.
+func NewFeeScheduleRepository(db *DB, baseLogger *zerolog.Logger) ports.FeeScheduleRepository {
+	return &feeScheduleRepository{
+		db:  db,
+		log: baseLogger.With().Str("component", "fee_schedule_repo").Logger(),
+	}
+}
+
+// Register stores the schedule under its version, or checks it against the stored one.
+func (r *feeScheduleRepository) Register(ctx context.Context, schedule *domain.FeeSchedule) error {
+	log := r.log.With().Str("version", schedule.Version).Logger()
+
+	definition, err := json.Marshal(schedule)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.db.q(ctx).Exec(ctx, `
+		INSERT INTO fee_schedules (version, definition) VALUES ($1, $2)
+		ON CONFLICT (version) DO NOTHING
+	`, schedule.Version, definition); err != nil {
+		log.Error().Err(err).Msg("Failed to register fee schedule")
+		return err
+	}
+
+	stored, err := r.GetByVersion(ctx, schedule.Version)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		return errors.New("fee schedule vanished after registration")
+	}
+	// Compare the canonical encodings, JSONB does not keep the original bytes
+	storedDefinition, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(storedDefinition, definition) {
+		log.Error().Msg("Fee schedule version is already registered with different rules")
+		return ports.ErrFeeScheduleChanged
+	}
+	return nil
+}
+
+// GetByVersion finds a registered schedule.
+func (r *feeScheduleRepository) GetByVersion(ctx context.Context, version string) (*domain.FeeSchedule, error) {
+	var definition []byte
+	err := r.db.q(ctx).QueryRow(ctx, `SELECT definition FROM fee_schedules WHERE version = $1`, version).Scan(&definition)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Return nil, nil for "not found"
+		}
+		r.log.Error().Err(err).Str("version", version).Msg("Failed to get fee schedule")
+		return nil, err
+	}
+
+	var schedule domain.FeeSchedule
+	if err := json.Unmarshal(definition, &schedule); err != nil {
+		r.log.Error().Err(err).Str("version", version).Msg("Failed to decode fee schedule")
+		return nil, err
+	}
+	return &schedule, nil
+}
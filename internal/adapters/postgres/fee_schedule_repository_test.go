@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestFeeScheduleRepository_Register(t *testing.T) {
+	// 1. Setup
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	repo := NewFeeScheduleRepository(testDB, &nopLogger)
+
+	schedule := &domain.FeeSchedule{
+		Version: "test-" + uuid.NewString(),
+		Default: domain.FeeRule{Percent: domain.MustParseDecimal("0.50")},
+		Rules: []domain.FeeRule{{
+			Base:    "EUR",
+			Quote:   "IRR",
+			Percent: domain.MustParseDecimal("0.4"),
+			Flat:    map[string]domain.Decimal{"EUR": domain.MustParseDecimal("1")},
+		}},
+	}
+	defer func() {
+		if _, err := testDB.pool.Exec(ctx, "DELETE FROM fee_schedules WHERE version = $1", schedule.Version); err != nil {
+			t.Errorf("Failed to clean up fee schedule: %v", err)
+		}
+	}()
+
+	// 2. Register twice: the second time is a no-op
+	if err := repo.Register(ctx, schedule); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := repo.Register(ctx, schedule); err != nil {
+		t.Fatalf("Registering the same rules again failed: %v", err)
+	}
+
+	// 3. The stored schedule round-trips exactly
+	stored, err := repo.GetByVersion(ctx, schedule.Version)
+	if err != nil || stored == nil {
+		t.Fatalf("GetByVersion failed: %v", err)
+	}
+	if !stored.Rules[0].Flat["EUR"].Equal(domain.MustParseDecimal("1")) || !stored.Default.Percent.Equal(domain.MustParseDecimal("0.5")) {
+		t.Errorf("Stored schedule mismatch: %+v", stored)
+	}
+
+	// 4. Changing the rules under the same version is refused
+	changed := *schedule
+	changed.Default = domain.FeeRule{Percent: domain.MustParseDecimal("0.6")}
+	if err := repo.Register(ctx, &changed); !errors.Is(err, ports.ErrFeeScheduleChanged) {
+		t.Errorf("Expected ErrFeeScheduleChanged, got: %v", err)
+	}
+
+	// 5. Unknown versions are not found
+	if missing, err := repo.GetByVersion(ctx, "missing-"+uuid.NewString()); err != nil || missing != nil {
+		t.Errorf("Expected nil, nil for a missing version, got %v, %v", missing, err)
+	}
+}
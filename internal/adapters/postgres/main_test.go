@@ -101,3 +101,69 @@ func cleanupTestUserBankAccount(t *testing.T, id uuid.UUID) {
 		t.Logf("Warning: Failed to cleanup bank account %s: %v", id, err)
 	}
 }
+
+// Helper to create an open request for testing
+func createTestRequest(t *testing.T, repo ports.RequestRepository, userID uuid.UUID) (*domain.Request, func()) {
+	req := &domain.Request{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Type:          domain.RequestTypeSell,
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "IRR",
+		BaseAmount:    domain.NewDecimalFromInt(1000),
+		ExchangeRate:  domain.NewDecimalFromInt(600000),
+		Status:        domain.RequestStatusOpen,
+	}
+	err := repo.Create(t.Context(), req)
+	if err != nil {
+		log.Fatalf("createTestRequest failed: %v", err)
+	}
+
+	cleanup := func() {
+		_, err := testDB.pool.Exec(context.Background(), "DELETE FROM requests WHERE id = $1", req.ID)
+		if err != nil {
+			log.Printf("Warning: failed to cleanup test request %s: %v", req.ID, err)
+		}
+	}
+
+	return req, cleanup
+}
+
+// Helper to create a pending bid for testing
+func createTestBid(t *testing.T, repo ports.BidRepository, userID, requestID uuid.UUID) (*domain.Bid, func()) {
+	bid := &domain.Bid{
+		ID:        uuid.New(),
+		UserID:    userID,
+		RequestID: requestID,
+		Status:    domain.BidStatusPending,
+	}
+	err := repo.Create(t.Context(), bid)
+	if err != nil {
+		log.Fatalf("createTestBid failed: %v", err)
+	}
+
+	cleanup := func() {
+		_, err := testDB.pool.Exec(context.Background(), "DELETE FROM bids WHERE id = $1", bid.ID)
+		if err != nil {
+			log.Printf("Warning: failed to cleanup test bid %s: %v", bid.ID, err)
+		}
+	}
+
+	return bid, cleanup
+}
+
+// Helper to clean up a transaction
+func cleanupTestTransaction(t *testing.T, id uuid.UUID) {
+	_, err := testDB.pool.Exec(context.Background(), "DELETE FROM transactions WHERE id = $1", id)
+	if err != nil {
+		t.Logf("Warning: Failed to cleanup transaction %s: %v", id, err)
+	}
+}
+
+// Helper to clean up a platform account
+func cleanupTestPlatformAccount(t *testing.T, id uuid.UUID) {
+	_, err := testDB.pool.Exec(context.Background(), "DELETE FROM platform_accounts WHERE id = $1", id)
+	if err != nil {
+		t.Logf("Warning: Failed to cleanup platform account %s: %v", id, err)
+	}
+}
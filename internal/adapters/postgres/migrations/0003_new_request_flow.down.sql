@@ -0,0 +1,28 @@
+-- Rollback
+ALTER TABLE users
+DROP COLUMN IF EXISTS state_data;
+
+-- Postgres cannot drop ENUM values, so we rebuild the type.
+-- Anyone caught mid-flow is sent back to idle.
+UPDATE users SET user_state = 'none'
+WHERE user_state::text LIKE 'awaiting_request_%';
+
+ALTER TABLE users ALTER COLUMN user_state DROP DEFAULT;
+ALTER TYPE user_state_enum RENAME TO user_state_enum_old;
+
+CREATE TYPE user_state_enum AS ENUM (
+    'none',
+    'awaiting_first_name',
+    'awaiting_last_name',
+    'awaiting_phone_number',
+    'awaiting_gov_id',
+    'awaiting_location',
+    'awaiting_identity_doc',
+    'awaiting_policy_approval'
+);
+
+ALTER TABLE users
+ALTER COLUMN user_state TYPE user_state_enum USING user_state::text::user_state_enum;
+ALTER TABLE users ALTER COLUMN user_state SET DEFAULT 'awaiting_first_name';
+
+DROP TYPE user_state_enum_old;
@@ -0,0 +1,10 @@
+-- States for the /newrequest conversational flow
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_request_base_currency';
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_request_quote_currency';
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_request_amount';
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_request_rate';
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_request_confirmation';
+
+-- Scratchpad for answers collected during a multi-step flow
+ALTER TABLE users
+ADD COLUMN state_data JSONB NOT NULL DEFAULT '{}'::jsonb;
@@ -0,0 +1,29 @@
+-- Rollback
+-- Postgres cannot drop ENUM values, so we rebuild the type.
+UPDATE users SET user_state = 'none'
+WHERE user_state = 'awaiting_bid_note';
+
+ALTER TABLE users ALTER COLUMN user_state DROP DEFAULT;
+ALTER TYPE user_state_enum RENAME TO user_state_enum_old;
+
+CREATE TYPE user_state_enum AS ENUM (
+    'none',
+    'awaiting_first_name',
+    'awaiting_last_name',
+    'awaiting_phone_number',
+    'awaiting_gov_id',
+    'awaiting_location',
+    'awaiting_identity_doc',
+    'awaiting_policy_approval',
+    'awaiting_request_base_currency',
+    'awaiting_request_quote_currency',
+    'awaiting_request_amount',
+    'awaiting_request_rate',
+    'awaiting_request_confirmation'
+);
+
+ALTER TABLE users
+ALTER COLUMN user_state TYPE user_state_enum USING user_state::text::user_state_enum;
+ALTER TABLE users ALTER COLUMN user_state SET DEFAULT 'awaiting_first_name';
+
+DROP TYPE user_state_enum_old;
@@ -0,0 +1,2 @@
+-- State for the optional note a bidder can attach to a bid
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_bid_note';
@@ -0,0 +1,2 @@
+-- Rollback
+DROP TABLE IF EXISTS transaction_status_history;
@@ -0,0 +1,11 @@
+-- Audit trail of every transaction status change
+CREATE TABLE transaction_status_history (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id  UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    from_status     transaction_status NOT NULL,
+    to_status       transaction_status NOT NULL,
+    moderator_id    UUID REFERENCES users(id), -- NULL for system or customer moves
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON transaction_status_history (transaction_id, created_at);
@@ -0,0 +1,2 @@
+-- State for a party uploading the receipt of their deposit
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_deposit_receipt';
@@ -0,0 +1,2 @@
+-- State for a moderator uploading the proof of a payout
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_payout_proof';
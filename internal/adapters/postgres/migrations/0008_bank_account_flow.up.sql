@@ -0,0 +1,6 @@
+-- States for the /myaccounts flow
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_account_name';
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_account_currency';
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_account_bank';
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_account_details';
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_account_rename';
@@ -0,0 +1,3 @@
+-- Rollback
+ALTER TABLE users DROP COLUMN IF EXISTS pending_start_payload;
+ALTER TABLE users DROP COLUMN IF EXISTS referred_by_user_id;
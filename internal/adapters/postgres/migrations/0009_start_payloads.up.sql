@@ -0,0 +1,7 @@
+-- Deep-link /start payloads
+-- Who invited the user (from a ref_<code> link)
+ALTER TABLE users ADD COLUMN referred_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+-- A deep link opened before approval, replayed once the user is verified
+ALTER TABLE users ADD COLUMN pending_start_payload TEXT;
+
+CREATE INDEX ON users (referred_by_user_id);
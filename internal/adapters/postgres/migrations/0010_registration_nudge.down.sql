@@ -0,0 +1,2 @@
+-- Rollback
+ALTER TABLE users DROP COLUMN IF EXISTS registration_nudged_at;
@@ -0,0 +1,2 @@
+-- Set when the expiry scheduler reminded a user to finish registering
+ALTER TABLE users ADD COLUMN registration_nudged_at TIMESTAMPTZ;
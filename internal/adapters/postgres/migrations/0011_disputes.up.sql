@@ -0,0 +1,36 @@
+-- Disputes raised on transactions and the evidence both parties submit
+CREATE TYPE dispute_status AS ENUM ('open', 'resolved');
+CREATE TYPE dispute_resolution AS ENUM ('refund', 'complete', 'cancel');
+
+CREATE TABLE disputes (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id      UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    opened_by_user_id   UUID NOT NULL REFERENCES users(id),
+    reason              TEXT NOT NULL,
+    status              dispute_status NOT NULL DEFAULT 'open',
+    resolution          dispute_resolution,
+    resolved_by         UUID REFERENCES users(id), -- The moderator
+    resolved_at         TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A transaction has at most one open dispute
+CREATE UNIQUE INDEX disputes_one_open_per_transaction
+    ON disputes (transaction_id) WHERE status = 'open';
+
+CREATE TABLE dispute_evidence (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    dispute_id  UUID NOT NULL REFERENCES disputes(id) ON DELETE CASCADE,
+    user_id     UUID NOT NULL REFERENCES users(id),
+    leg         TEXT NOT NULL,
+    statement   TEXT NOT NULL,
+    file_id     TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON dispute_evidence (dispute_id, created_at);
+
+-- States for the dispute flow
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_dispute_reason';
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_dispute_evidence';
@@ -0,0 +1,9 @@
+-- Rollback
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS buyer_net,
+    DROP COLUMN IF EXISTS buyer_fee,
+    DROP COLUMN IF EXISTS seller_net,
+    DROP COLUMN IF EXISTS seller_fee,
+    DROP COLUMN IF EXISTS fee_schedule_version;
+
+DROP TABLE IF EXISTS fee_schedules;
@@ -0,0 +1,22 @@
+-- Versioned fee schedules and the fees fixed on each transaction at match time
+CREATE TABLE fee_schedules (
+    version     TEXT PRIMARY KEY,
+    definition  JSONB NOT NULL, -- The rules, exactly as they were configured
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- The seller is paid in the quote currency, the buyer in the base currency.
+-- Quote totals can exceed NUMERIC(19, 8), hence the wider columns.
+ALTER TABLE transactions
+    ADD COLUMN fee_schedule_version TEXT REFERENCES fee_schedules(version),
+    ADD COLUMN seller_fee NUMERIC(28, 8) NOT NULL DEFAULT 0,
+    ADD COLUMN seller_net NUMERIC(28, 8) NOT NULL DEFAULT 0,
+    ADD COLUMN buyer_fee  NUMERIC(28, 8) NOT NULL DEFAULT 0,
+    ADD COLUMN buyer_net  NUMERIC(28, 8) NOT NULL DEFAULT 0;
+
+-- Trades matched before fees existed pay out in full
+UPDATE transactions t
+SET seller_net = r.base_amount * r.exchange_rate,
+    buyer_net  = r.base_amount
+FROM requests r
+WHERE r.id = t.request_id;
@@ -0,0 +1,61 @@
+-- Rollback
+ALTER TABLE users
+    DROP COLUMN IF EXISTS upgrade_requested_at,
+    DROP COLUMN IF EXISTS selfie_doc_ref,
+    DROP COLUMN IF EXISTS address_doc_ref;
+
+-- Postgres cannot drop ENUM values, so we rebuild the types.
+-- level_2 users fall back to level_1, anyone caught mid-upgrade is sent back to idle.
+UPDATE users SET verification_status = 'level_1' WHERE verification_status::text = 'level_2';
+UPDATE users SET user_state = 'none'
+WHERE user_state::text IN ('awaiting_address_proof', 'awaiting_selfie');
+
+ALTER TABLE users ALTER COLUMN verification_status DROP DEFAULT;
+ALTER TYPE user_verification_status RENAME TO user_verification_status_old;
+
+CREATE TYPE user_verification_status AS ENUM (
+    'pending',
+    'level_1',
+    'rejected'
+);
+
+ALTER TABLE users
+ALTER COLUMN verification_status TYPE user_verification_status USING verification_status::text::user_verification_status;
+ALTER TABLE users ALTER COLUMN verification_status SET DEFAULT 'pending';
+
+DROP TYPE user_verification_status_old;
+
+ALTER TABLE users ALTER COLUMN user_state DROP DEFAULT;
+ALTER TYPE user_state_enum RENAME TO user_state_enum_old;
+
+CREATE TYPE user_state_enum AS ENUM (
+    'none',
+    'awaiting_first_name',
+    'awaiting_last_name',
+    'awaiting_phone_number',
+    'awaiting_gov_id',
+    'awaiting_location',
+    'awaiting_identity_doc',
+    'awaiting_policy_approval',
+    'awaiting_request_base_currency',
+    'awaiting_request_quote_currency',
+    'awaiting_request_amount',
+    'awaiting_request_rate',
+    'awaiting_request_confirmation',
+    'awaiting_bid_note',
+    'awaiting_deposit_receipt',
+    'awaiting_payout_proof',
+    'awaiting_account_name',
+    'awaiting_account_currency',
+    'awaiting_account_bank',
+    'awaiting_account_details',
+    'awaiting_account_rename',
+    'awaiting_dispute_reason',
+    'awaiting_dispute_evidence'
+);
+
+ALTER TABLE users
+ALTER COLUMN user_state TYPE user_state_enum USING user_state::text::user_state_enum;
+ALTER TABLE users ALTER COLUMN user_state SET DEFAULT 'awaiting_first_name';
+
+DROP TYPE user_state_enum_old;
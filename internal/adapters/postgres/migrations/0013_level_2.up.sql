@@ -0,0 +1,12 @@
+-- Second verification tier
+ALTER TYPE user_verification_status ADD VALUE IF NOT EXISTS 'level_2'; -- Enhanced due diligence passed
+
+-- Upgrade flow steps
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_address_proof';
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_selfie';
+
+-- Upgrade documents, stored like identity_doc_ref (message IDs in the upload channel)
+ALTER TABLE users ADD COLUMN address_doc_ref TEXT;
+ALTER TABLE users ADD COLUMN selfie_doc_ref TEXT;
+-- Set while a level_2 upgrade waits for review
+ALTER TABLE users ADD COLUMN upgrade_requested_at TIMESTAMPTZ;
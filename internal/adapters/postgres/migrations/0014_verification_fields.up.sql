@@ -0,0 +1,2 @@
+-- A verification strategy asked the user for extra details
+ALTER TYPE user_state_enum ADD VALUE IF NOT EXISTS 'awaiting_verification_field';
@@ -0,0 +1,13 @@
+-- Events are written here in the same transaction as the change they
+-- announce, then delivered to the subscribers by the outbox dispatcher.
+CREATE TABLE event_outbox (
+    id            BIGSERIAL PRIMARY KEY,
+    topic         TEXT NOT NULL,
+    payload       BYTEA NOT NULL, -- Encrypted JSON, events carry personal data
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    claimed_until TIMESTAMPTZ,    -- Lease of the dispatcher delivering it
+    delivered_at  TIMESTAMPTZ
+);
+
+CREATE INDEX idx_event_outbox_undelivered ON event_outbox (id) WHERE delivered_at IS NULL;
+CREATE INDEX idx_event_outbox_delivered_at ON event_outbox (delivered_at) WHERE delivered_at IS NOT NULL;
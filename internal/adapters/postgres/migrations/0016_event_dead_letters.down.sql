@@ -0,0 +1,2 @@
+-- Rollback
+DROP TABLE IF EXISTS event_dead_letters;
@@ -0,0 +1,14 @@
+-- Events a subscriber kept failing on after all its retries.
+-- Moderators inspect them and replay them once the cause is fixed.
+CREATE TABLE event_dead_letters (
+    id          BIGSERIAL PRIMARY KEY,
+    topic       TEXT NOT NULL,
+    subscriber  TEXT NOT NULL,
+    payload     BYTEA NOT NULL, -- Encrypted JSON, like event_outbox
+    error       TEXT NOT NULL,
+    attempts    INT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    replayed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_event_dead_letters_pending ON event_dead_letters (id) WHERE replayed_at IS NULL;
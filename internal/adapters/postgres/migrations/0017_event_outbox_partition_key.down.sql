@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_event_outbox_undelivered_key;
+ALTER TABLE event_outbox DROP COLUMN IF EXISTS partition_key;
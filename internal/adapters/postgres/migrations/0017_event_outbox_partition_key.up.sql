@@ -0,0 +1,6 @@
+-- Events sharing a partition key (a transaction, a user) are delivered one
+-- at a time, oldest first. NULL for events without an order.
+ALTER TABLE event_outbox ADD COLUMN partition_key TEXT;
+
+CREATE INDEX idx_event_outbox_undelivered_key ON event_outbox (partition_key, id)
+    WHERE delivered_at IS NULL AND partition_key IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Rollback
+DROP INDEX IF EXISTS idx_requests_open_opened_at;
+ALTER TABLE requests DROP COLUMN IF EXISTS opened_at;
@@ -0,0 +1,9 @@
+-- When a request last became open: set on creation and again when a
+-- cancelled trade reopens it. Open requests expire relative to it.
+ALTER TABLE requests ADD COLUMN opened_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- Open requests may have been reopened since they were created; their last
+-- change is the best bound we have, so they get a full TTL from there.
+UPDATE requests SET opened_at = CASE WHEN status = 'open' THEN updated_at ELSE created_at END;
+
+CREATE INDEX idx_requests_open_opened_at ON requests (opened_at) WHERE status = 'open';
@@ -0,0 +1,11 @@
+-- Payout proof photos, kept out of the event outbox and dead letters.
+-- Events only carry the ID of a proof.
+CREATE TABLE payout_proofs (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id  UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    leg             TEXT NOT NULL,
+    photo           BYTEA NOT NULL, -- Encrypted
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON payout_proofs (transaction_id);
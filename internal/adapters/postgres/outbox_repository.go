@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/ports"
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var _ ports.OutboxRepository = (*outboxRepository)(nil) // Ensure compliance
+
+// outboxChannel is the NOTIFY channel that wakes the dispatchers.
+// Postgres only delivers a NOTIFY once its transaction commits.
+const outboxChannel = "event_outbox"
+
+type outboxRepository struct {
+	db     *DB
+	secSvc ports.SecurityPort
+	log    zerolog.Logger
+}
+
+// NewOutboxRepository creates a new repository for the event outbox.
+// Payloads are encrypted, like every other piece of personal data.
+func NewOutboxRepository(db *DB, secSvc ports.SecurityPort, baseLogger *zerolog.Logger) ports.OutboxRepository {
+	return &outboxRepository{
+		db:     db,
+		secSvc: secSvc,
+		log:    baseLogger.With().Str("component", "outbox_repo").Logger(),
+	}
+}
+
+// Add stores an event and wakes the dispatchers once it commits.
+func (r *outboxRepository) Add(ctx context.Context, topic, key string, payload []byte) error {
+	encrypted, err := r.secSvc.Encrypt(payload)
+	if err != nil {
+		r.log.Error().Err(err).Str("topic", topic).Msg("Failed to encrypt event payload")
+		return err
+	}
+
+	if _, err := r.db.q(ctx).Exec(ctx, `
+		WITH added AS (
+			INSERT INTO event_outbox (topic, partition_key, payload) VALUES ($1, NULLIF($2, ''), $3)
+		)
+		SELECT pg_notify($4, '')
+	`, topic, key, encrypted, outboxChannel); err != nil {
+		r.log.Error().Err(err).Str("topic", topic).Msg("Failed to add event to outbox")
+		return err
+	}
+	return nil
+}
+
+// Claim leases the oldest undelivered events.
+// SKIP LOCKED lets several dispatchers share the table without taking the same event.
+// A keyed event is only claimed together with every older undelivered event
+// of its key. An older event this claim did not lock may be leased, or locked
+// by a dispatcher whose lease is not committed yet, so the key is skipped.
+func (r *outboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*ports.OutboxEvent, error) {
+	rows, err := r.db.q(ctx).Query(ctx, `
+		WITH candidates AS (
+			SELECT e.id, e.partition_key FROM event_outbox e
+			WHERE e.delivered_at IS NULL
+			  AND (e.claimed_until IS NULL OR e.claimed_until < NOW())
+			  AND NOT EXISTS (
+				SELECT 1 FROM event_outbox older
+				WHERE older.partition_key = e.partition_key
+				  AND older.id < e.id
+				  AND older.delivered_at IS NULL
+				  AND older.claimed_until >= NOW()
+			  )
+			ORDER BY e.id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE event_outbox SET claimed_until = NOW() + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT c.id FROM candidates c
+			WHERE c.partition_key IS NULL OR NOT EXISTS (
+				SELECT 1 FROM event_outbox older
+				WHERE older.partition_key = c.partition_key
+				  AND older.id < c.id
+				  AND older.delivered_at IS NULL
+				  AND older.id NOT IN (SELECT id FROM candidates)
+			)
+		)
+		RETURNING id, topic, COALESCE(partition_key, ''), payload, created_at
+	`, limit, lease.Milliseconds())
+	if err != nil {
+		r.log.Error().Err(err).Msg("Failed to claim outbox events")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*ports.OutboxEvent
+	for rows.Next() {
+		var event ports.OutboxEvent
+		var encrypted []byte
+		if err := rows.Scan(&event.ID, &event.Topic, &event.Key, &encrypted, &event.CreatedAt); err != nil {
+			r.log.Error().Err(err).Msg("Failed to scan outbox event")
+			return nil, err
+		}
+		if event.Payload, err = r.secSvc.Decrypt(encrypted); err != nil {
+			r.log.Error().Err(err).Int64("event_id", event.ID).Msg("Failed to decrypt event payload")
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the subquery's order
+	slices.SortFunc(events, func(a, b *ports.OutboxEvent) int { return cmp.Compare(a.ID, b.ID) })
+	return events, nil
+}
+
+// MarkDelivered records that every subscriber has seen the event.
+func (r *outboxRepository) MarkDelivered(ctx context.Context, id int64) error {
+	if _, err := r.db.q(ctx).Exec(ctx, `
+		UPDATE event_outbox SET delivered_at = NOW(), claimed_until = NULL WHERE id = $1
+	`, id); err != nil {
+		r.log.Error().Err(err).Int64("event_id", id).Msg("Failed to mark outbox event delivered")
+		return err
+	}
+	return nil
+}
+
+// DeleteDelivered removes events delivered before the given time.
+func (r *outboxRepository) DeleteDelivered(ctx context.Context, before time.Time) (int64, error) {
+	cmdTag, err := r.db.q(ctx).Exec(ctx, `
+		DELETE FROM event_outbox WHERE delivered_at IS NOT NULL AND delivered_at < $1
+	`, before)
+	if err != nil {
+		r.log.Error().Err(err).Msg("Failed to delete delivered outbox events")
+		return 0, err
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+// Listen holds one connection for LISTEN until ctx is done.
+func (r *outboxRepository) Listen(ctx context.Context, notify func()) error {
+	conn, err := r.db.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+outboxChannel); err != nil {
+		return err
+	}
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		notify()
+	}
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestOutboxRepository_TransactionalAdd(t *testing.T) {
+	// 1. Setup
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	repo := NewOutboxRepository(testDB, testSecSvc, &nopLogger)
+
+	topic := "test:" + uuid.NewString()
+	defer func() {
+		if _, err := testDB.pool.Exec(ctx, "DELETE FROM event_outbox WHERE topic = $1", topic); err != nil {
+			t.Errorf("Failed to clean up outbox: %v", err)
+		}
+	}()
+
+	// 2. A rolled back transaction leaves no event behind
+	rollback := errors.New("rollback")
+	err := testDB.WithinTx(ctx, func(ctx context.Context) error {
+		if err := repo.Add(ctx, topic, "", []byte(`{"kept":false}`)); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("Expected the rollback error, got: %v", err)
+	}
+
+	// 3. A committed one keeps it
+	if err := testDB.WithinTx(ctx, func(ctx context.Context) error {
+		return repo.Add(ctx, topic, "", []byte(`{"kept":true}`))
+	}); err != nil {
+		t.Fatalf("WithinTx failed: %v", err)
+	}
+
+	// 4. Claim returns the payload decrypted, and only once per lease
+	events, err := repo.Claim(ctx, 1000, time.Minute)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	var found int
+	var id int64
+	for _, event := range events {
+		if event.Topic == topic {
+			found++
+			id = event.ID
+			if string(event.Payload) != `{"kept":true}` {
+				t.Errorf("Payload mismatch: got %s", event.Payload)
+			}
+		}
+	}
+	if found != 1 {
+		t.Fatalf("Expected exactly 1 committed event, found %d", found)
+	}
+
+	again, err := repo.Claim(ctx, 1000, time.Minute)
+	if err != nil {
+		t.Fatalf("Second Claim failed: %v", err)
+	}
+	for _, event := range again {
+		if event.ID == id {
+			t.Error("Leased event was claimed twice")
+		}
+	}
+
+	// 5. Delivered events are pruned
+	if err := repo.MarkDelivered(ctx, id); err != nil {
+		t.Fatalf("MarkDelivered failed: %v", err)
+	}
+	deleted, err := repo.DeleteDelivered(ctx, time.Now().Add(time.Minute))
+	if err != nil || deleted < 1 {
+		t.Errorf("DeleteDelivered failed: deleted %d, err %v", deleted, err)
+	}
+}
+
+func TestOutboxRepository_ClaimKeepsKeyOrderAcrossDispatchers(t *testing.T) {
+	// 1. Setup: two events with the same key
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	repo := NewOutboxRepository(testDB, testSecSvc, &nopLogger)
+
+	topic := "test:" + uuid.NewString()
+	key := uuid.NewString()
+	defer func() {
+		if _, err := testDB.pool.Exec(ctx, "DELETE FROM event_outbox WHERE topic = $1", topic); err != nil {
+			t.Errorf("Failed to clean up outbox: %v", err)
+		}
+	}()
+	for _, payload := range []string{`{"n":1}`, `{"n":2}`} {
+		if err := repo.Add(ctx, topic, key, []byte(payload)); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	var older, newer int64
+	if err := testDB.pool.QueryRow(ctx,
+		"SELECT MIN(id), MAX(id) FROM event_outbox WHERE topic = $1", topic,
+	).Scan(&older, &newer); err != nil {
+		t.Fatalf("Failed to read event IDs: %v", err)
+	}
+
+	// 2. Dispatcher A has locked the older event but not committed its lease.
+	// Dispatcher B, on another connection, must not jump ahead to the newer one.
+	err := testDB.WithinTx(ctx, func(txCtx context.Context) error {
+		if _, err := testDB.q(txCtx).Exec(txCtx, "SELECT id FROM event_outbox WHERE id = $1 FOR UPDATE", older); err != nil {
+			return err
+		}
+		claimed, err := repo.Claim(ctx, 1000, time.Minute)
+		if err != nil {
+			return err
+		}
+		for _, event := range claimed {
+			if event.ID == newer {
+				t.Error("Dispatcher B claimed the newer event while the older one was being claimed")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Concurrent claim failed: %v", err)
+	}
+
+	// 3. Once A is gone, both events are claimed together, oldest first
+	claimed, err := repo.Claim(ctx, 1000, time.Minute)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	var ours []int64
+	for _, event := range claimed {
+		if event.Topic == topic {
+			ours = append(ours, event.ID)
+		}
+	}
+	if len(ours) != 2 || ours[0] != older || ours[1] != newer {
+		t.Errorf("Expected both events in order %d, %d, got %v", older, newer, ours)
+	}
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+var _ ports.PayoutProofRepository = (*payoutProofRepository)(nil) // Ensure compliance
+
+type payoutProofRepository struct {
+	db     *DB
+	secSvc ports.SecurityPort
+	log    zerolog.Logger
+}
+
+// NewPayoutProofRepository creates a new repository for payout proofs.
+// Photos show bank details, so they are encrypted at rest.
+func NewPayoutProofRepository(db *DB, secSvc ports.SecurityPort, baseLogger *zerolog.Logger) ports.PayoutProofRepository {
+	return &payoutProofRepository{
+		db:     db,
+		secSvc: secSvc,
+		log:    baseLogger.With().Str("component", "payout_proof_repo").Logger(),
+	}
+}
+
+// Create encrypts and saves a new proof, filling in its creation time.
+func (r *payoutProofRepository) Create(ctx context.Context, proof *domain.PayoutProof) error {
+	encrypted, err := r.secSvc.Encrypt(proof.Photo)
+	if err != nil {
+		r.log.Error().Err(err).Str("transaction_id", proof.TransactionID.String()).Msg("Failed to encrypt payout proof")
+		return err
+	}
+
+	err = r.db.q(ctx).QueryRow(ctx, `
+		INSERT INTO payout_proofs (id, transaction_id, leg, photo)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`, proof.ID, proof.TransactionID, proof.Leg, encrypted).Scan(&proof.CreatedAt)
+	if err != nil {
+		r.log.Error().Err(err).Str("transaction_id", proof.TransactionID.String()).Msg("Failed to insert payout proof")
+		return err
+	}
+	return nil
+}
+
+// GetByID finds a proof and decrypts its photo.
+func (r *payoutProofRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PayoutProof, error) {
+	var proof domain.PayoutProof
+	var encrypted []byte
+	err := r.db.q(ctx).QueryRow(ctx, `
+		SELECT id, transaction_id, leg, photo, created_at
+		FROM payout_proofs WHERE id = $1
+	`, id).Scan(&proof.ID, &proof.TransactionID, &proof.Leg, &encrypted, &proof.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Error().Err(err).Str("proof_id", id.String()).Msg("Failed to get payout proof")
+		return nil, err
+	}
+
+	photo, err := r.secSvc.Decrypt(encrypted)
+	if err != nil {
+		r.log.Error().Err(err).Str("proof_id", id.String()).Msg("Failed to decrypt payout proof")
+		return nil, err
+	}
+	proof.Photo = photo
+	return &proof, nil
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestPayoutProofRepository_CreateAndGet(t *testing.T) {
+	// 1. Setup
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	bidRepo := NewBidRepository(testDB, &nopLogger)
+	txRepo := NewTransactionRepository(testDB, &nopLogger)
+	repo := NewPayoutProofRepository(testDB, testSecSvc, &nopLogger)
+
+	seller, cleanupSeller := createTestUser(t, userRepo)
+	defer cleanupSeller()
+	buyer, cleanupBuyer := createTestUser(t, userRepo)
+	defer cleanupBuyer()
+	req, cleanupReq := createTestRequest(t, reqRepo, seller.ID)
+	defer cleanupReq()
+	bid, cleanupBid := createTestBid(t, bidRepo, buyer.ID, req.ID)
+	defer cleanupBid()
+
+	tx := &domain.Transaction{
+		ID:           uuid.New(),
+		RequestID:    req.ID,
+		BidID:        bid.ID,
+		SellerUserID: seller.ID,
+		BuyerUserID:  buyer.ID,
+		Status:       domain.TxStatusPendingPayouts,
+	}
+	if err := txRepo.Create(ctx, tx); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	defer cleanupTestTransaction(t, tx.ID) // Cascades to payout proofs
+
+	// 2. Create
+	proof := &domain.PayoutProof{
+		ID:            uuid.New(),
+		TransactionID: tx.ID,
+		Leg:           domain.LegSeller,
+		Photo:         []byte{0xff, 0xd8, 0xff, 0xe0},
+	}
+	if err := repo.Create(ctx, proof); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if proof.CreatedAt.IsZero() {
+		t.Error("Create did not fill in CreatedAt")
+	}
+
+	// 3. The photo round-trips
+	found, err := repo.GetByID(ctx, proof.ID)
+	if err != nil || found == nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if !bytes.Equal(found.Photo, proof.Photo) || found.Leg != domain.LegSeller || found.TransactionID != tx.ID {
+		t.Errorf("Stored proof mismatch: %+v", found)
+	}
+
+	// 4. Unknown IDs are not found
+	if missing, err := repo.GetByID(ctx, uuid.New()); err != nil || missing != nil {
+		t.Errorf("Expected nil for an unknown ID, got %+v, %v", missing, err)
+	}
+}
@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"encoding/base64"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+var _ ports.PlatformAccountRepository = (*platformAccountRepository)(nil) // Ensure compliance
+
+type platformAccountRepository struct {
+	db     *DB
+	secSvc ports.SecurityPort
+	log    zerolog.Logger
+}
+
+// NewPlatformAccountRepository creates a new repo for platform account operations.
+// Account details are encrypted at rest, like user bank accounts.
+func NewPlatformAccountRepository(db *DB, secSvc ports.SecurityPort, baseLogger *zerolog.Logger) ports.PlatformAccountRepository {
+	return &platformAccountRepository{
+		db:     db,
+		secSvc: secSvc,
+		log:    baseLogger.With().Str("component", "platform_acct_repo").Logger(),
+	}
+}
+
+// platformAccountQueryCols is the list of columns for scanning
+const platformAccountQueryCols = `
+	id, account_name, currency, bank_name, account_details,
+	verification_strategy, is_active, created_at, updated_at
+`
+
+// encryptDetails encrypts and Base64-encodes the account details.
+func (r *platformAccountRepository) encryptDetails(details string) (string, error) {
+	encBytes, err := r.secSvc.Encrypt([]byte(details))
+	if err != nil {
+		r.log.Error().Err(err).Msg("Failed to encrypt account details")
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(encBytes), nil
+}
+
+// Create encrypts and saves a new platform account.
+func (r *platformAccountRepository) Create(ctx context.Context, acct *domain.PlatformAccount) error {
+	encDetails, err := r.encryptDetails(acct.AccountDetails)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO platform_accounts (
+			id, account_name, currency, bank_name, account_details,
+			verification_strategy, is_active
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err = r.db.q(ctx).Exec(ctx, query,
+		acct.ID,
+		acct.AccountName,
+		acct.Currency,
+		acct.BankName,
+		encDetails,
+		acct.VerificationStrategy,
+		acct.IsActive,
+	)
+
+	if err != nil {
+		r.log.Error().Err(err).Str("currency", acct.Currency).Msg("Failed to insert new platform account")
+	}
+	return err
+}
+
+// scanAcct is a helper to scan a row into a PlatformAccount struct and decrypt it.
+func (r *platformAccountRepository) scanAcct(row pgx.Row) (*domain.PlatformAccount, error) {
+	var acct domain.PlatformAccount
+	var encDetails string // Read encrypted data first
+
+	err := row.Scan(
+		&acct.ID,
+		&acct.AccountName,
+		&acct.Currency,
+		&acct.BankName,
+		&encDetails,
+		&acct.VerificationStrategy,
+		&acct.IsActive,
+		&acct.CreatedAt,
+		&acct.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		r.log.Error().Err(err).Msg("Failed to scan platform account row")
+		return nil, err
+	}
+
+	decBytes, err := base64.StdEncoding.DecodeString(encDetails)
+	if err != nil {
+		r.log.Error().Err(err).Str("acct_id", acct.ID.String()).Msg("Failed to base64-decode account details")
+		return nil, err
+	}
+	dec, err := r.secSvc.Decrypt(decBytes)
+	if err != nil {
+		r.log.Error().Err(err).Str("acct_id", acct.ID.String()).Msg("Failed to decrypt account details")
+		return nil, err
+	}
+
+	acct.AccountDetails = string(dec)
+	return &acct, nil
+}
+
+// GetByID finds a platform account by its UUID.
+func (r *platformAccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PlatformAccount, error) {
+	query := `SELECT ` + platformAccountQueryCols + ` FROM platform_accounts WHERE id = $1`
+
+	row := r.db.q(ctx).QueryRow(ctx, query, id)
+	acct, err := r.scanAcct(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Info().Str("acct_id", id.String()).Msg("Platform account not found")
+			return nil, nil // Return nil, nil for "not found"
+		}
+		return nil, err
+	}
+	return acct, nil
+}
+
+// GetActiveByCurrency finds all active accounts for a currency.
+func (r *platformAccountRepository) GetActiveByCurrency(ctx context.Context, currency string) ([]*domain.PlatformAccount, error) {
+	query := `SELECT ` + platformAccountQueryCols + ` FROM platform_accounts
+		WHERE currency = $1 AND is_active = TRUE
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.q(ctx).Query(ctx, query, currency)
+	if err != nil {
+		r.log.Error().Err(err).Str("currency", currency).Msg("Failed to query platform accounts")
+		return nil, err
+	}
+	return r.collectAccounts(rows)
+}
+
+// List finds all platform accounts, active ones first.
+func (r *platformAccountRepository) List(ctx context.Context) ([]*domain.PlatformAccount, error) {
+	query := `SELECT ` + platformAccountQueryCols + ` FROM platform_accounts
+		ORDER BY is_active DESC, currency ASC, created_at ASC
+	`
+
+	rows, err := r.db.q(ctx).Query(ctx, query)
+	if err != nil {
+		r.log.Error().Err(err).Msg("Failed to query platform accounts")
+		return nil, err
+	}
+	return r.collectAccounts(rows)
+}
+
+// collectAccounts scans and closes a set of platform account rows.
+func (r *platformAccountRepository) collectAccounts(rows pgx.Rows) ([]*domain.PlatformAccount, error) {
+	defer rows.Close()
+
+	var accounts []*domain.PlatformAccount
+	for rows.Next() {
+		acct, err := r.scanAcct(rows)
+		if err != nil {
+			r.log.Error().Err(err).Msg("Failed during row scan for platform accounts")
+			return nil, err
+		}
+		accounts = append(accounts, acct)
+	}
+
+	if rows.Err() != nil {
+		r.log.Error().Err(rows.Err()).Msg("Error iterating platform account rows")
+		return nil, rows.Err()
+	}
+
+	return accounts, nil
+}
+
+// Update re-encrypts and saves all mutable fields of a platform account.
+func (r *platformAccountRepository) Update(ctx context.Context, acct *domain.PlatformAccount) error {
+	encDetails, err := r.encryptDetails(acct.AccountDetails)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE platform_accounts SET
+			account_name = $1,
+			currency = $2,
+			bank_name = $3,
+			account_details = $4,
+			verification_strategy = $5,
+			is_active = $6,
+			updated_at = NOW()
+		WHERE id = $7
+	`
+	cmdTag, err := r.db.q(ctx).Exec(ctx, query,
+		acct.AccountName,
+		acct.Currency,
+		acct.BankName,
+		encDetails,
+		acct.VerificationStrategy,
+		acct.IsActive,
+		acct.ID, // The WHERE clause
+	)
+
+	if err != nil {
+		r.log.Error().Err(err).Str("acct_id", acct.ID.String()).Msg("Failed to update platform account")
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		r.log.Error().Err(errors.New("no rows affected")).Str("acct_id", acct.ID.String()).Msg("Platform account not found when trying to update")
+		return errors.New("platform account not found")
+	}
+
+	return nil
+}
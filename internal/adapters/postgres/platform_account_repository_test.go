@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestPlatformAccountRepository_Create_GetActiveByCurrency(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	repo := NewPlatformAccountRepository(testDB, testSecSvc, &nopLogger)
+
+	// Use a currency code no real data will have
+	currency := "ZZT"
+	active := &domain.PlatformAccount{
+		ID:                   uuid.New(),
+		AccountName:          "Primary Test",
+		Currency:             currency,
+		BankName:             "Test Bank",
+		AccountDetails:       "IBAN: DE89 3704 0044 0532 0130 00",
+		VerificationStrategy: "manual",
+		IsActive:             true,
+	}
+	inactive := &domain.PlatformAccount{
+		ID:                   uuid.New(),
+		AccountName:          "Old Test",
+		Currency:             currency,
+		BankName:             "Test Bank",
+		AccountDetails:       "IBAN: DE00 0000 0000 0000 0000 00",
+		VerificationStrategy: "manual",
+		IsActive:             false,
+	}
+
+	// 2. Run Create
+	if err := repo.Create(ctx, active); err != nil {
+		t.Fatalf("Failed to create active account: %v", err)
+	}
+	defer cleanupTestPlatformAccount(t, active.ID)
+	if err := repo.Create(ctx, inactive); err != nil {
+		t.Fatalf("Failed to create inactive account: %v", err)
+	}
+	defer cleanupTestPlatformAccount(t, inactive.ID)
+
+	// 3. Run GetActiveByCurrency
+	found, err := repo.GetActiveByCurrency(ctx, currency)
+	if err != nil {
+		t.Fatalf("GetActiveByCurrency failed: %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("Found %d active accounts, expected 1", len(found))
+	}
+	if found[0].ID != active.ID {
+		t.Errorf("ID mismatch: got %v, want %v", found[0].ID, active.ID)
+	}
+	if found[0].AccountDetails != active.AccountDetails {
+		t.Errorf("AccountDetails mismatch: got %s, want %s", found[0].AccountDetails, active.AccountDetails)
+	}
+}
+
+func TestPlatformAccountRepository_Update(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	repo := NewPlatformAccountRepository(testDB, testSecSvc, &nopLogger)
+
+	acct := &domain.PlatformAccount{
+		ID:                   uuid.New(),
+		AccountName:          "Primary Test",
+		Currency:             "ZZT",
+		BankName:             "Test Bank",
+		AccountDetails:       "Card: 6037 0000 0000 0000",
+		VerificationStrategy: "manual",
+		IsActive:             true,
+	}
+	if err := repo.Create(ctx, acct); err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	defer cleanupTestPlatformAccount(t, acct.ID)
+
+	acct.IsActive = false
+	if err := repo.Update(ctx, acct); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	found, err := repo.GetByID(ctx, acct.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if found == nil {
+		t.Fatal("GetByID: account not found, but should exist")
+	}
+	if found.IsActive {
+		t.Error("IsActive was not updated")
+	}
+}
+
+func TestPlatformAccountRepository_List_EncryptsDetails(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	repo := NewPlatformAccountRepository(testDB, testSecSvc, &nopLogger)
+
+	acct := &domain.PlatformAccount{
+		ID:                   uuid.New(),
+		AccountName:          "Encrypted Test",
+		Currency:             "ZZT",
+		BankName:             "Test Bank",
+		AccountDetails:       "IBAN: DE89 3704 0044 0532 0130 00",
+		VerificationStrategy: "manual",
+		IsActive:             true,
+	}
+	if err := repo.Create(ctx, acct); err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	defer cleanupTestPlatformAccount(t, acct.ID)
+
+	// The stored column must not hold the plaintext
+	var stored string
+	if err := testDB.pool.QueryRow(ctx, "SELECT account_details FROM platform_accounts WHERE id = $1", acct.ID).Scan(&stored); err != nil {
+		t.Fatalf("Failed to read raw account details: %v", err)
+	}
+	if stored == acct.AccountDetails {
+		t.Error("Account details were stored in plaintext")
+	}
+
+	all, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	for _, found := range all {
+		if found.ID == acct.ID {
+			if found.AccountDetails != acct.AccountDetails {
+				t.Errorf("AccountDetails mismatch: got %s, want %s", found.AccountDetails, acct.AccountDetails)
+			}
+			return
+		}
+	}
+	t.Error("List did not return the new account")
+}
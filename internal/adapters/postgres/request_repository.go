@@ -0,0 +1,352 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+var _ ports.RequestRepository = (*requestRepository)(nil) // Ensure compliance
+
+type requestRepository struct {
+	db  *DB
+	log zerolog.Logger
+}
+
+// NewRequestRepository creates a new repository for marketplace requests.
+func NewRequestRepository(db *DB, baseLogger *zerolog.Logger) ports.RequestRepository {
+	return &requestRepository{
+		db:  db,
+		log: baseLogger.With().Str("component", "request_repo").Logger(),
+	}
+}
+
+// requestQueryCols is the list of columns for scanning
+const requestQueryCols = `
+	id, user_id, channel_message_id, request_type, base_currency,
+	quote_currency, base_amount, exchange_rate, status,
+	created_at, updated_at
+`
+
+// Create saves a new request.
+func (r *requestRepository) Create(ctx context.Context, req *domain.Request) error {
+	query := `
+		INSERT INTO requests (
+			id, user_id, channel_message_id, request_type, base_currency,
+			quote_currency, base_amount, exchange_rate, status
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.db.q(ctx).Exec(ctx, query,
+		req.ID,
+		req.UserID,
+		req.ChannelMessageID,
+		req.Type,
+		req.BaseCurrency,
+		req.QuoteCurrency,
+		req.BaseAmount,
+		req.ExchangeRate,
+		req.Status,
+	)
+
+	if err != nil {
+		r.log.Error().Err(err).Str("user_id", req.UserID.String()).Msg("Failed to insert new request")
+	}
+	return err
+}
+
+// scanRequest is a helper to scan a row into a Request struct
+func (r *requestRepository) scanRequest(row pgx.Row) (*domain.Request, error) {
+	var req domain.Request
+	err := row.Scan(
+		&req.ID,
+		&req.UserID,
+		&req.ChannelMessageID,
+		&req.Type,
+		&req.BaseCurrency,
+		&req.QuoteCurrency,
+		&req.BaseAmount,
+		&req.ExchangeRate,
+		&req.Status,
+		&req.CreatedAt,
+		&req.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err // Return specific error
+		}
+		r.log.Error().Err(err).Msg("Failed to scan request row")
+		return nil, err
+	}
+	return &req, nil
+}
+
+// GetByID finds a request by its UUID.
+func (r *requestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Request, error) {
+	query := `SELECT ` + requestQueryCols + ` FROM requests WHERE id = $1`
+
+	row := r.db.q(ctx).QueryRow(ctx, query, id)
+	req, err := r.scanRequest(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Info().Str("request_id", id.String()).Msg("Request not found")
+			return nil, nil // Return nil, nil for "not found"
+		}
+		return nil, err
+	}
+	return req, nil
+}
+
+// GetByUserID finds all requests created by a user.
+func (r *requestRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Request, error) {
+	query := `SELECT ` + requestQueryCols + ` FROM requests
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.q(ctx).Query(ctx, query, userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to query requests")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*domain.Request
+	for rows.Next() {
+		req, err := r.scanRequest(rows)
+		if err != nil {
+			r.log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed during row scan for requests")
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	if rows.Err() != nil {
+		r.log.Error().Err(rows.Err()).Str("user_id", userID.String()).Msg("Error iterating request rows")
+		return nil, rows.Err()
+	}
+
+	return requests, nil
+}
+
+// reopenedAt is the SQL for opened_at when status $1 is saved: it restarts
+// when the request becomes open again, so expiry counts from then.
+const reopenedAt = `CASE WHEN $1::request_status = 'open' AND status <> 'open' THEN NOW() ELSE opened_at END`
+
+// Update saves the mutable fields of a request.
+func (r *requestRepository) Update(ctx context.Context, req *domain.Request) error {
+	query := `
+		UPDATE requests SET
+			status = $1,
+			channel_message_id = $2,
+			opened_at = ` + reopenedAt + `,
+			updated_at = NOW()
+		WHERE id = $3
+	`
+	cmdTag, err := r.db.q(ctx).Exec(ctx, query,
+		req.Status,
+		req.ChannelMessageID,
+		req.ID, // The WHERE clause
+	)
+
+	if err != nil {
+		r.log.Error().Err(err).Str("request_id", req.ID.String()).Msg("Failed to update request")
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		r.log.Error().Err(errors.New("no rows affected")).Str("request_id", req.ID.String()).Msg("Request not found when trying to update")
+		return errors.New("request not found")
+	}
+
+	return nil
+}
+
+// TransitionStatus saves a new status if the stored one is still 'from'.
+func (r *requestRepository) TransitionStatus(ctx context.Context, req *domain.Request, from domain.RequestStatus) error {
+	query := `
+		UPDATE requests SET status = $1, opened_at = ` + reopenedAt + `, updated_at = NOW()
+		WHERE id = $2 AND status = $3
+		RETURNING updated_at
+	`
+	err := r.db.q(ctx).QueryRow(ctx, query, req.Status, req.ID, from).Scan(&req.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ports.ErrRequestStatusChanged
+		}
+		r.log.Error().Err(err).Str("request_id", req.ID.String()).Msg("Failed to transition request status")
+		return err
+	}
+	return nil
+}
+
+// SetChannelMessageID records the public channel post of a request.
+// It leaves the status alone, so a concurrent match is never overwritten.
+func (r *requestRepository) SetChannelMessageID(ctx context.Context, id uuid.UUID, messageID int64) error {
+	query := `UPDATE requests SET channel_message_id = $1, updated_at = NOW() WHERE id = $2`
+
+	cmdTag, err := r.db.q(ctx).Exec(ctx, query, messageID, id)
+	if err != nil {
+		r.log.Error().Err(err).Str("request_id", id.String()).Msg("Failed to set channel message ID")
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return errors.New("request not found")
+	}
+	return nil
+}
+
+// ExpireOpen cancels the requests that have been open the longest, since before the cutoff.
+// The status check in the outer UPDATE keeps a concurrent match from being overwritten.
+func (r *requestRepository) ExpireOpen(ctx context.Context, openedBefore time.Time, limit int) ([]*domain.Request, error) {
+	query := `
+		UPDATE requests SET status = $1, updated_at = NOW()
+		WHERE status = $2 AND id IN (
+			SELECT id FROM requests
+			WHERE status = $2 AND opened_at < $3
+			ORDER BY opened_at ASC
+			LIMIT $4
+		)
+		RETURNING ` + requestQueryCols
+
+	rows, err := r.db.q(ctx).Query(ctx, query,
+		domain.RequestStatusCancelled,
+		domain.RequestStatusOpen,
+		openedBefore,
+		limit,
+	)
+	if err != nil {
+		r.log.Error().Err(err).Msg("Failed to expire open requests")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*domain.Request
+	for rows.Next() {
+		req, err := r.scanRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	if rows.Err() != nil {
+		r.log.Error().Err(rows.Err()).Msg("Error iterating expired request rows")
+		return nil, rows.Err()
+	}
+
+	return requests, nil
+}
+
+// ListOpen returns one page of open requests matching the filter.
+// The WHERE clause follows the (status, base_currency, quote_currency) index.
+func (r *requestRepository) ListOpen(ctx context.Context, filter ports.RequestFilter, limit, offset int) ([]*domain.Request, int, error) {
+	where := `
+		WHERE status = $1
+		  AND ($2 = '' OR base_currency = $2)
+		  AND ($3 = '' OR quote_currency = $3)
+		  AND ($4 = '' OR request_type::text = $4)
+	`
+	args := []any{
+		domain.RequestStatusOpen,
+		filter.BaseCurrency,
+		filter.QuoteCurrency,
+		string(filter.Type),
+	}
+
+	// 1. Count all matches (for the page indicator)
+	var total int
+	if err := r.db.q(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM requests `+where, args...).Scan(&total); err != nil {
+		r.log.Error().Err(err).Msg("Failed to count open requests")
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	// 2. Fetch the page
+	query := `SELECT ` + requestQueryCols + ` FROM requests ` + where + `
+		ORDER BY created_at ASC
+		LIMIT $5 OFFSET $6
+	`
+	rows, err := r.db.q(ctx).Query(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		r.log.Error().Err(err).Msg("Failed to query open requests")
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var requests []*domain.Request
+	for rows.Next() {
+		req, err := r.scanRequest(rows)
+		if err != nil {
+			r.log.Error().Err(err).Msg("Failed during row scan for open requests")
+			return nil, 0, err
+		}
+		requests = append(requests, req)
+	}
+
+	if rows.Err() != nil {
+		r.log.Error().Err(rows.Err()).Msg("Error iterating open request rows")
+		return nil, 0, rows.Err()
+	}
+
+	return requests, total, nil
+}
+
+// ListOpenCurrencies returns the distinct currencies found in open requests.
+func (r *requestRepository) ListOpenCurrencies(ctx context.Context) ([]string, []string, error) {
+	base, err := r.distinctOpenColumn(ctx, "base_currency")
+	if err != nil {
+		return nil, nil, err
+	}
+	quote, err := r.distinctOpenColumn(ctx, "quote_currency")
+	if err != nil {
+		return nil, nil, err
+	}
+	return base, quote, nil
+}
+
+// distinctOpenColumn is a helper for ListOpenCurrencies.
+// 'column' is always one of our own constants, never user input.
+func (r *requestRepository) distinctOpenColumn(ctx context.Context, column string) ([]string, error) {
+	query := `SELECT DISTINCT ` + column + ` FROM requests WHERE status = $1 ORDER BY 1`
+
+	rows, err := r.db.q(ctx).Query(ctx, query, domain.RequestStatusOpen)
+	if err != nil {
+		r.log.Error().Err(err).Str("column", column).Msg("Failed to query open currencies")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var currencies []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			r.log.Error().Err(err).Str("column", column).Msg("Failed to scan currency")
+			return nil, err
+		}
+		currencies = append(currencies, code)
+	}
+	return currencies, rows.Err()
+}
+
+// CountOpenByUser returns how many open requests a user has.
+func (r *requestRepository) CountOpenByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.q(ctx).QueryRow(ctx,
+		`SELECT COUNT(*) FROM requests WHERE user_id = $1 AND status = $2`,
+		userID, domain.RequestStatusOpen,
+	).Scan(&count)
+	if err != nil {
+		r.log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to count open requests")
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,349 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestRequestRepository_Create_GetByID_Roundtrip(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	repo := NewRequestRepository(testDB, &nopLogger)
+
+	user, cleanup := createTestUser(t, userRepo)
+	defer cleanup()
+
+	req := &domain.Request{
+		ID:            uuid.New(),
+		UserID:        user.ID,
+		Type:          domain.RequestTypeSell,
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "IRR",
+		BaseAmount:    domain.MustParseDecimal("1250.12345678"), // Every digit NUMERIC(19, 8) can hold
+		ExchangeRate:  domain.MustParseDecimal("99999999999.5"),
+		Status:        domain.RequestStatusOpen,
+	}
+
+	// 2. Run Create
+	if err := repo.Create(ctx, req); err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	// 3. Run GetByID
+	found, err := repo.GetByID(ctx, req.ID)
+	if err != nil {
+		t.Fatalf("Failed to get request by ID: %v", err)
+	}
+	if found == nil {
+		t.Fatal("GetByID: request not found, but should exist")
+	}
+
+	// 4. Verify
+	if found.UserID != req.UserID {
+		t.Errorf("UserID mismatch: got %v, want %v", found.UserID, req.UserID)
+	}
+	if found.Type != req.Type {
+		t.Errorf("Type mismatch: got %s, want %s", found.Type, req.Type)
+	}
+	if found.BaseCurrency != req.BaseCurrency || found.QuoteCurrency != req.QuoteCurrency {
+		t.Errorf("Currency mismatch: got %s/%s, want %s/%s",
+			found.BaseCurrency, found.QuoteCurrency, req.BaseCurrency, req.QuoteCurrency)
+	}
+	if !found.BaseAmount.Equal(req.BaseAmount) {
+		t.Errorf("BaseAmount mismatch: got %v, want %v", found.BaseAmount, req.BaseAmount)
+	}
+	if !found.ExchangeRate.Equal(req.ExchangeRate) {
+		t.Errorf("ExchangeRate mismatch: got %v, want %v", found.ExchangeRate, req.ExchangeRate)
+	}
+	if found.Status != domain.RequestStatusOpen {
+		t.Errorf("Status mismatch: got %s, want %s", found.Status, domain.RequestStatusOpen)
+	}
+	if found.ChannelMessageID != nil {
+		t.Errorf("ChannelMessageID should be nil, got %d", *found.ChannelMessageID)
+	}
+}
+
+func TestRequestRepository_GetByID_NotFound(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	repo := NewRequestRepository(testDB, &nopLogger)
+
+	found, err := repo.GetByID(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("GetByID for non-existent request returned an error: %v", err)
+	}
+	if found != nil {
+		t.Fatal("GetByID found a request, but it should not exist")
+	}
+}
+
+func TestRequestRepository_Update(t *testing.T) {
+	// 1. Setup
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	repo := NewRequestRepository(testDB, &nopLogger)
+
+	user, cleanup := createTestUser(t, userRepo)
+	defer cleanup()
+	req, cleanupReq := createTestRequest(t, repo, user.ID)
+	defer cleanupReq()
+
+	// 2. Modify and update
+	msgID := int64(4242)
+	req.ChannelMessageID = &msgID
+	req.Status = domain.RequestStatusMatched
+	if err := repo.Update(ctx, req); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// 3. Verify
+	updated, err := repo.GetByID(ctx, req.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if updated.Status != domain.RequestStatusMatched {
+		t.Errorf("Status was not updated: got %s, want %s", updated.Status, domain.RequestStatusMatched)
+	}
+	if updated.ChannelMessageID == nil || *updated.ChannelMessageID != msgID {
+		t.Errorf("ChannelMessageID was not updated: got %v, want %d", updated.ChannelMessageID, msgID)
+	}
+}
+
+func TestRequestRepository_SetChannelMessageID(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	repo := NewRequestRepository(testDB, &nopLogger)
+
+	user, cleanup := createTestUser(t, userRepo)
+	defer cleanup()
+	req, cleanupReq := createTestRequest(t, repo, user.ID)
+	defer cleanupReq()
+
+	// The request was matched after the post went out
+	req.Status = domain.RequestStatusMatched
+	if err := repo.Update(ctx, req); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := repo.SetChannelMessageID(ctx, req.ID, 777); err != nil {
+		t.Fatalf("SetChannelMessageID failed: %v", err)
+	}
+
+	updated, err := repo.GetByID(ctx, req.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if updated.ChannelMessageID == nil || *updated.ChannelMessageID != 777 {
+		t.Errorf("ChannelMessageID was not set: got %v", updated.ChannelMessageID)
+	}
+	if updated.Status != domain.RequestStatusMatched {
+		t.Errorf("Status was overwritten: got %s", updated.Status)
+	}
+}
+
+func TestRequestRepository_ExpireOpen(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	repo := NewRequestRepository(testDB, &nopLogger)
+
+	user, cleanup := createTestUser(t, userRepo)
+	defer cleanup()
+	stale, cleanupStale := createTestRequest(t, repo, user.ID)
+	defer cleanupStale()
+	fresh, cleanupFresh := createTestRequest(t, repo, user.ID)
+	defer cleanupFresh()
+	reopened, cleanupReopened := createTestRequest(t, repo, user.ID)
+	defer cleanupReopened()
+
+	// Backdate two requests past the cutoff
+	for _, id := range []uuid.UUID{stale.ID, reopened.ID} {
+		if _, err := testDB.pool.Exec(ctx, `UPDATE requests SET created_at = NOW() - INTERVAL '30 days', opened_at = NOW() - INTERVAL '30 days' WHERE id = $1`, id); err != nil {
+			t.Fatalf("Failed to backdate request: %v", err)
+		}
+	}
+	// A cancelled trade puts one back on the market, which restarts its clock
+	reopened.Status = domain.RequestStatusMatched
+	if err := repo.TransitionStatus(ctx, reopened, domain.RequestStatusOpen); err != nil {
+		t.Fatalf("TransitionStatus failed: %v", err)
+	}
+	reopened.Status = domain.RequestStatusOpen
+	if err := repo.TransitionStatus(ctx, reopened, domain.RequestStatusMatched); err != nil {
+		t.Fatalf("TransitionStatus failed: %v", err)
+	}
+
+	expired, err := repo.ExpireOpen(ctx, time.Now().Add(-24*time.Hour), 100)
+	if err != nil {
+		t.Fatalf("ExpireOpen failed: %v", err)
+	}
+	found := false
+	for _, req := range expired {
+		if req.ID == fresh.ID {
+			t.Errorf("Fresh request was expired")
+		}
+		if req.ID == reopened.ID {
+			t.Errorf("Reopened request was expired")
+		}
+		if req.ID == stale.ID {
+			found = true
+			if req.Status != domain.RequestStatusCancelled {
+				t.Errorf("Status mismatch: got %s", req.Status)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Stale request was not expired")
+	}
+}
+
+func TestRequestRepository_GetByUserID(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	repo := NewRequestRepository(testDB, &nopLogger)
+
+	user, cleanup := createTestUser(t, userRepo)
+	defer cleanup()
+	_, cleanupReq1 := createTestRequest(t, repo, user.ID)
+	defer cleanupReq1()
+	_, cleanupReq2 := createTestRequest(t, repo, user.ID)
+	defer cleanupReq2()
+
+	found, err := repo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetByUserID failed: %v", err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("Found %d requests, expected 2", len(found))
+	}
+}
+
+func TestRequestRepository_ListOpen_Filters(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	repo := NewRequestRepository(testDB, &nopLogger)
+
+	user, cleanup := createTestUser(t, userRepo)
+	defer cleanup()
+
+	// Use a currency code no real data will have
+	base := "ZZL"
+	for i := 0; i < 3; i++ {
+		req := &domain.Request{
+			ID:            uuid.New(),
+			UserID:        user.ID,
+			Type:          domain.RequestTypeSell,
+			BaseCurrency:  base,
+			QuoteCurrency: "IRR",
+			BaseAmount:    domain.NewDecimalFromInt(100),
+			ExchangeRate:  domain.NewDecimalFromInt(10),
+			Status:        domain.RequestStatusOpen,
+		}
+		if i == 2 {
+			req.Type = domain.RequestTypeBuy
+		}
+		if err := repo.Create(ctx, req); err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+	}
+
+	// 1. Filter by base currency, paged
+	page, total, err := repo.ListOpen(ctx, ports.RequestFilter{BaseCurrency: base}, 2, 0)
+	if err != nil {
+		t.Fatalf("ListOpen failed: %v", err)
+	}
+	if total != 3 {
+		t.Errorf("Total mismatch: got %d, want 3", total)
+	}
+	if len(page) != 2 {
+		t.Errorf("Page size mismatch: got %d, want 2", len(page))
+	}
+
+	// 2. Add a type filter
+	_, total, err = repo.ListOpen(ctx, ports.RequestFilter{BaseCurrency: base, Type: domain.RequestTypeBuy}, 10, 0)
+	if err != nil {
+		t.Fatalf("ListOpen failed: %v", err)
+	}
+	if total != 1 {
+		t.Errorf("Total mismatch with type filter: got %d, want 1", total)
+	}
+
+	// 3. Currencies include our test code
+	bases, _, err := repo.ListOpenCurrencies(ctx)
+	if err != nil {
+		t.Fatalf("ListOpenCurrencies failed: %v", err)
+	}
+	found := false
+	for _, code := range bases {
+		if code == base {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ListOpenCurrencies did not include %s: %v", base, bases)
+	}
+}
+
+func TestRequestRepository_TransitionStatus(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	repo := NewRequestRepository(testDB, &nopLogger)
+
+	user, cleanup := createTestUser(t, userRepo)
+	defer cleanup()
+	req, cleanupReq := createTestRequest(t, repo, user.ID)
+	defer cleanupReq()
+
+	// 1. Move it from the status it is in
+	req.Status = domain.RequestStatusCancelled
+	if err := repo.TransitionStatus(ctx, req, domain.RequestStatusOpen); err != nil {
+		t.Fatalf("TransitionStatus failed: %v", err)
+	}
+	found, _ := repo.GetByID(ctx, req.ID)
+	if found.Status != domain.RequestStatusCancelled {
+		t.Errorf("Status mismatch: got %s", found.Status)
+	}
+
+	// 2. A stale 'from' must not overwrite the row
+	req.Status = domain.RequestStatusMatched
+	if err := repo.TransitionStatus(ctx, req, domain.RequestStatusOpen); !errors.Is(err, ports.ErrRequestStatusChanged) {
+		t.Errorf("Expected ErrRequestStatusChanged, got: %v", err)
+	}
+}
+
+func TestRequestRepository_CountOpenByUser(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	repo := NewRequestRepository(testDB, &nopLogger)
+
+	user, cleanup := createTestUser(t, userRepo)
+	defer cleanup()
+	_, cleanupReq1 := createTestRequest(t, repo, user.ID)
+	defer cleanupReq1()
+	closed, cleanupReq2 := createTestRequest(t, repo, user.ID)
+	defer cleanupReq2()
+	closed.Status = domain.RequestStatusCancelled
+	if err := repo.Update(ctx, closed); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	count, err := repo.CountOpenByUser(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("CountOpenByUser failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Counted %d open requests, expected 1", count)
+	}
+}
@@ -0,0 +1,314 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+var _ ports.TransactionRepository = (*transactionRepository)(nil) // Ensure compliance
+
+type transactionRepository struct {
+	db  *DB
+	log zerolog.Logger
+}
+
+// NewTransactionRepository creates a new repository for transaction operations.
+func NewTransactionRepository(db *DB, baseLogger *zerolog.Logger) ports.TransactionRepository {
+	return &transactionRepository{
+		db:  db,
+		log: baseLogger.With().Str("component", "transaction_repo").Logger(),
+	}
+}
+
+// transactionQueryCols is the list of columns for scanning
+const transactionQueryCols = `
+	id, request_id, bid_id, seller_user_id, buyer_user_id, moderator_id,
+	status, platform_deposit_base_account_id, platform_deposit_quote_account_id,
+	seller_payout_account_id, buyer_payout_account_id,
+	fee_schedule_version, seller_fee, seller_net, buyer_fee, buyer_net,
+	created_at, updated_at
+`
+
+// Create saves a new transaction.
+func (r *transactionRepository) Create(ctx context.Context, tx *domain.Transaction) error {
+	query := `
+		INSERT INTO transactions (
+			id, request_id, bid_id, seller_user_id, buyer_user_id, moderator_id,
+			status, platform_deposit_base_account_id, platform_deposit_quote_account_id,
+			seller_payout_account_id, buyer_payout_account_id,
+			fee_schedule_version, seller_fee, seller_net, buyer_fee, buyer_net
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`
+	_, err := r.db.q(ctx).Exec(ctx, query,
+		tx.ID,
+		tx.RequestID,
+		tx.BidID,
+		tx.SellerUserID,
+		tx.BuyerUserID,
+		tx.ModeratorID,
+		tx.Status,
+		tx.PlatformDepositBaseAccountID,
+		tx.PlatformDepositQuoteAccountID,
+		tx.SellerPayoutAccountID,
+		tx.BuyerPayoutAccountID,
+		tx.FeeScheduleVersion,
+		tx.SellerFee,
+		tx.SellerNet,
+		tx.BuyerFee,
+		tx.BuyerNet,
+	)
+
+	if err != nil {
+		r.log.Error().Err(err).Str("request_id", tx.RequestID.String()).Msg("Failed to insert new transaction")
+	}
+	return err
+}
+
+// scanTransaction is a helper to scan a row into a Transaction struct
+func (r *transactionRepository) scanTransaction(row pgx.Row) (*domain.Transaction, error) {
+	var tx domain.Transaction
+	err := row.Scan(
+		&tx.ID,
+		&tx.RequestID,
+		&tx.BidID,
+		&tx.SellerUserID,
+		&tx.BuyerUserID,
+		&tx.ModeratorID,
+		&tx.Status,
+		&tx.PlatformDepositBaseAccountID,
+		&tx.PlatformDepositQuoteAccountID,
+		&tx.SellerPayoutAccountID,
+		&tx.BuyerPayoutAccountID,
+		&tx.FeeScheduleVersion,
+		&tx.SellerFee,
+		&tx.SellerNet,
+		&tx.BuyerFee,
+		&tx.BuyerNet,
+		&tx.CreatedAt,
+		&tx.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err // Return specific error
+		}
+		r.log.Error().Err(err).Msg("Failed to scan transaction row")
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// GetByID finds a transaction by its UUID.
+func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Transaction, error) {
+	query := `SELECT ` + transactionQueryCols + ` FROM transactions WHERE id = $1`
+
+	row := r.db.q(ctx).QueryRow(ctx, query, id)
+	tx, err := r.scanTransaction(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Info().Str("transaction_id", id.String()).Msg("Transaction not found")
+			return nil, nil // Return nil, nil for "not found"
+		}
+		return nil, err
+	}
+	return tx, nil
+}
+
+// GetByUserID finds all transactions where the user is on either side.
+func (r *transactionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Transaction, error) {
+	query := `SELECT ` + transactionQueryCols + ` FROM transactions
+		WHERE seller_user_id = $1 OR buyer_user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.q(ctx).Query(ctx, query, userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to query transactions")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txs []*domain.Transaction
+	for rows.Next() {
+		tx, err := r.scanTransaction(rows)
+		if err != nil {
+			r.log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed during row scan for transactions")
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+
+	if rows.Err() != nil {
+		r.log.Error().Err(rows.Err()).Str("user_id", userID.String()).Msg("Error iterating transaction rows")
+		return nil, rows.Err()
+	}
+
+	return txs, nil
+}
+
+// Update saves the mutable fields of a transaction.
+func (r *transactionRepository) Update(ctx context.Context, tx *domain.Transaction) error {
+	query := `
+		UPDATE transactions SET
+			moderator_id = $1,
+			status = $2,
+			platform_deposit_base_account_id = $3,
+			platform_deposit_quote_account_id = $4,
+			seller_payout_account_id = $5,
+			buyer_payout_account_id = $6,
+			updated_at = NOW()
+		WHERE id = $7
+	`
+	cmdTag, err := r.db.q(ctx).Exec(ctx, query,
+		tx.ModeratorID,
+		tx.Status,
+		tx.PlatformDepositBaseAccountID,
+		tx.PlatformDepositQuoteAccountID,
+		tx.SellerPayoutAccountID,
+		tx.BuyerPayoutAccountID,
+		tx.ID, // The WHERE clause
+	)
+
+	if err != nil {
+		r.log.Error().Err(err).Str("transaction_id", tx.ID.String()).Msg("Failed to update transaction")
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		r.log.Error().Err(errors.New("no rows affected")).Str("transaction_id", tx.ID.String()).Msg("Transaction not found when trying to update")
+		return errors.New("transaction not found")
+	}
+
+	return nil
+}
+
+// Transition applies a status change and records it in the history table.
+// The WHERE status = 'from' guard makes concurrent moves fail instead of overwrite.
+func (r *transactionRepository) Transition(ctx context.Context, tx *domain.Transaction, from domain.TransactionStatus, moderatorID *uuid.UUID) error {
+	log := r.log.With().
+		Str("transaction_id", tx.ID.String()).
+		Str("from", string(from)).
+		Str("to", string(tx.Status)).
+		Logger()
+
+	dbTx, err := r.db.q(ctx).Begin(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin transaction")
+		return err
+	}
+	defer dbTx.Rollback(ctx) // No-op after a successful commit
+
+	// 1. Move the status
+	err = dbTx.QueryRow(ctx, `
+		UPDATE transactions SET
+			status = $1,
+			moderator_id = COALESCE($2, moderator_id),
+			updated_at = NOW()
+		WHERE id = $3 AND status = $4
+		RETURNING moderator_id, updated_at
+	`, tx.Status, moderatorID, tx.ID, from).Scan(&tx.ModeratorID, &tx.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Warn().Msg("Transaction status changed before transition")
+			return ports.ErrTransactionStatusChanged
+		}
+		log.Error().Err(err).Msg("Failed to update transaction status")
+		return err
+	}
+
+	// 2. Record who did it and when
+	if _, err := dbTx.Exec(ctx, `
+		INSERT INTO transaction_status_history (
+			transaction_id, from_status, to_status, moderator_id, created_at
+		) VALUES ($1, $2, $3, $4, $5)
+	`, tx.ID, from, tx.Status, moderatorID, tx.UpdatedAt); err != nil {
+		log.Error().Err(err).Msg("Failed to insert transaction history")
+		return err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to commit transaction transition")
+		return err
+	}
+	return nil
+}
+
+// SetPayoutAccount records the payout account of one leg.
+// Only that column is written, so it cannot race with a status transition.
+func (r *transactionRepository) SetPayoutAccount(ctx context.Context, txID uuid.UUID, leg domain.TransactionLeg, accountID uuid.UUID) error {
+	column := "buyer_payout_account_id"
+	if leg == domain.LegSeller {
+		column = "seller_payout_account_id"
+	}
+	query := `UPDATE transactions SET ` + column + ` = $1, updated_at = NOW() WHERE id = $2`
+
+	cmdTag, err := r.db.q(ctx).Exec(ctx, query, accountID, txID)
+	if err != nil {
+		r.log.Error().Err(err).Str("transaction_id", txID.String()).Msg("Failed to set payout account")
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return errors.New("transaction not found")
+	}
+	return nil
+}
+
+// GetHistory returns the status history of a transaction.
+func (r *transactionRepository) GetHistory(ctx context.Context, transactionID uuid.UUID) ([]*domain.TransactionTransition, error) {
+	query := `
+		SELECT id, transaction_id, from_status, to_status, moderator_id, created_at
+		FROM transaction_status_history
+		WHERE transaction_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.q(ctx).Query(ctx, query, transactionID)
+	if err != nil {
+		r.log.Error().Err(err).Str("transaction_id", transactionID.String()).Msg("Failed to query transaction history")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*domain.TransactionTransition
+	for rows.Next() {
+		var t domain.TransactionTransition
+		if err := rows.Scan(&t.ID, &t.TransactionID, &t.From, &t.To, &t.ModeratorID, &t.CreatedAt); err != nil {
+			r.log.Error().Err(err).Str("transaction_id", transactionID.String()).Msg("Failed to scan transaction history row")
+			return nil, err
+		}
+		history = append(history, &t)
+	}
+
+	if rows.Err() != nil {
+		r.log.Error().Err(rows.Err()).Str("transaction_id", transactionID.String()).Msg("Error iterating transaction history rows")
+		return nil, rows.Err()
+	}
+
+	return history, nil
+}
+
+// TradedVolume sums the base amounts of the user's non-cancelled trades in a currency.
+func (r *transactionRepository) TradedVolume(ctx context.Context, userID uuid.UUID, baseCurrency string, since time.Time) (domain.Decimal, error) {
+	query := `
+		SELECT COALESCE(SUM(r.base_amount), 0)
+		FROM transactions t
+		JOIN requests r ON r.id = t.request_id
+		WHERE (t.seller_user_id = $1 OR t.buyer_user_id = $1)
+		  AND r.base_currency = $2
+		  AND t.created_at >= $3
+		  AND t.status <> $4
+	`
+
+	var volume domain.Decimal
+	if err := r.db.q(ctx).QueryRow(ctx, query, userID, baseCurrency, since, domain.TxStatusCancelled).Scan(&volume); err != nil {
+		r.log.Error().Err(err).Str("user_id", userID.String()).Str("currency", baseCurrency).Msg("Failed to sum traded volume")
+		return domain.Decimal{}, err
+	}
+	return volume, nil
+}
@@ -0,0 +1,280 @@
+package postgres
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestTransactionRepository_Create_GetByID_Update(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	bidRepo := NewBidRepository(testDB, &nopLogger)
+	repo := NewTransactionRepository(testDB, &nopLogger)
+
+	seller, cleanupSeller := createTestUser(t, userRepo)
+	defer cleanupSeller()
+	buyer, cleanupBuyer := createTestUser(t, userRepo)
+	defer cleanupBuyer()
+	req, cleanupReq := createTestRequest(t, reqRepo, seller.ID)
+	defer cleanupReq()
+	bid, cleanupBid := createTestBid(t, bidRepo, buyer.ID, req.ID)
+	defer cleanupBid()
+
+	tx := &domain.Transaction{
+		ID:           uuid.New(),
+		RequestID:    req.ID,
+		BidID:        bid.ID,
+		SellerUserID: seller.ID,
+		BuyerUserID:  buyer.ID,
+		Status:       domain.TxStatusPendingDeposits,
+	}
+
+	// 2. Run Create
+	if err := repo.Create(ctx, tx); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	defer cleanupTestTransaction(t, tx.ID)
+
+	// 3. Run GetByID
+	found, err := repo.GetByID(ctx, tx.ID)
+	if err != nil {
+		t.Fatalf("Failed to get transaction by ID: %v", err)
+	}
+	if found == nil {
+		t.Fatal("GetByID: transaction not found, but should exist")
+	}
+	if found.SellerUserID != seller.ID || found.BuyerUserID != buyer.ID {
+		t.Errorf("Party mismatch: got seller %v buyer %v", found.SellerUserID, found.BuyerUserID)
+	}
+	if found.Status != domain.TxStatusPendingDeposits {
+		t.Errorf("Status mismatch: got %s, want %s", found.Status, domain.TxStatusPendingDeposits)
+	}
+	if found.ModeratorID != nil {
+		t.Errorf("ModeratorID should be nil, got %v", *found.ModeratorID)
+	}
+
+	// 4. Run Update
+	found.Status = domain.TxStatusSellerDepositReceived
+	found.ModeratorID = &seller.ID
+	if err := repo.Update(ctx, found); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// 5. Verify via GetByUserID (buyer side)
+	txs, err := repo.GetByUserID(ctx, buyer.ID)
+	if err != nil {
+		t.Fatalf("GetByUserID failed: %v", err)
+	}
+	if len(txs) != 1 {
+		t.Fatalf("Found %d transactions, expected 1", len(txs))
+	}
+	if txs[0].Status != domain.TxStatusSellerDepositReceived {
+		t.Errorf("Status was not updated: got %s, want %s", txs[0].Status, domain.TxStatusSellerDepositReceived)
+	}
+	if txs[0].ModeratorID == nil || *txs[0].ModeratorID != seller.ID {
+		t.Errorf("ModeratorID was not updated: got %v, want %v", txs[0].ModeratorID, seller.ID)
+	}
+}
+
+func TestTransactionRepository_GetByID_NotFound(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	repo := NewTransactionRepository(testDB, &nopLogger)
+
+	found, err := repo.GetByID(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("GetByID for non-existent transaction returned an error: %v", err)
+	}
+	if found != nil {
+		t.Fatal("GetByID found a transaction, but it should not exist")
+	}
+}
+
+func TestTransactionRepository_Transition_RecordsHistory(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	bidRepo := NewBidRepository(testDB, &nopLogger)
+	repo := NewTransactionRepository(testDB, &nopLogger)
+
+	seller, cleanupSeller := createTestUser(t, userRepo)
+	defer cleanupSeller()
+	buyer, cleanupBuyer := createTestUser(t, userRepo)
+	defer cleanupBuyer()
+	req, cleanupReq := createTestRequest(t, reqRepo, seller.ID)
+	defer cleanupReq()
+	bid, cleanupBid := createTestBid(t, bidRepo, buyer.ID, req.ID)
+	defer cleanupBid()
+
+	tx := &domain.Transaction{
+		ID:           uuid.New(),
+		RequestID:    req.ID,
+		BidID:        bid.ID,
+		SellerUserID: seller.ID,
+		BuyerUserID:  buyer.ID,
+		Status:       domain.TxStatusPendingDeposits,
+	}
+	if err := repo.Create(ctx, tx); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	defer cleanupTestTransaction(t, tx.ID)
+
+	// 1. A valid move with a moderator
+	moderatorID := seller.ID // Any existing user will do for the FK
+	tx.Status = domain.TxStatusSellerDepositReceived
+	if err := repo.Transition(ctx, tx, domain.TxStatusPendingDeposits, &moderatorID); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	if tx.ModeratorID == nil || *tx.ModeratorID != moderatorID {
+		t.Errorf("ModeratorID not set on transition: got %v", tx.ModeratorID)
+	}
+
+	// 2. A stale 'from' status must be refused
+	tx.Status = domain.TxStatusPendingPayouts
+	if err := repo.Transition(ctx, tx, domain.TxStatusPendingDeposits, nil); !errors.Is(err, ports.ErrTransactionStatusChanged) {
+		t.Fatalf("Expected ErrTransactionStatusChanged, got: %v", err)
+	}
+
+	// 3. Only the first move is in the history
+	history, err := repo.GetHistory(ctx, tx.ID)
+	if err != nil {
+		t.Fatalf("GetHistory failed: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("Found %d history entries, expected 1", len(history))
+	}
+	if history[0].From != domain.TxStatusPendingDeposits || history[0].To != domain.TxStatusSellerDepositReceived {
+		t.Errorf("History mismatch: got %s -> %s", history[0].From, history[0].To)
+	}
+	if history[0].ModeratorID == nil || *history[0].ModeratorID != moderatorID {
+		t.Errorf("History moderator mismatch: got %v", history[0].ModeratorID)
+	}
+}
+
+func TestTransactionRepository_SetPayoutAccount(t *testing.T) {
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	bidRepo := NewBidRepository(testDB, &nopLogger)
+	bankRepo := NewUserBankAccountRepository(testDB, testSecSvc, &nopLogger)
+	repo := NewTransactionRepository(testDB, &nopLogger)
+
+	seller, cleanupSeller := createTestUser(t, userRepo)
+	defer cleanupSeller()
+	buyer, cleanupBuyer := createTestUser(t, userRepo)
+	defer cleanupBuyer()
+	req, cleanupReq := createTestRequest(t, reqRepo, seller.ID)
+	defer cleanupReq()
+	bid, cleanupBid := createTestBid(t, bidRepo, buyer.ID, req.ID)
+	defer cleanupBid()
+
+	acct := &domain.UserBankAccount{
+		ID:             uuid.New(),
+		UserID:         buyer.ID,
+		AccountName:    "Payout",
+		Currency:       req.BaseCurrency,
+		BankName:       "Test Bank",
+		AccountDetails: "IBAN: TEST",
+	}
+	if err := bankRepo.Create(ctx, acct); err != nil {
+		t.Fatalf("Failed to create bank account: %v", err)
+	}
+	defer cleanupTestUserBankAccount(t, acct.ID)
+
+	tx := &domain.Transaction{
+		ID:           uuid.New(),
+		RequestID:    req.ID,
+		BidID:        bid.ID,
+		SellerUserID: seller.ID,
+		BuyerUserID:  buyer.ID,
+		Status:       domain.TxStatusPendingPayouts,
+	}
+	if err := repo.Create(ctx, tx); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	defer cleanupTestTransaction(t, tx.ID)
+
+	if err := repo.SetPayoutAccount(ctx, tx.ID, domain.LegBuyer, acct.ID); err != nil {
+		t.Fatalf("SetPayoutAccount failed: %v", err)
+	}
+
+	found, err := repo.GetByID(ctx, tx.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if found.BuyerPayoutAccountID == nil || *found.BuyerPayoutAccountID != acct.ID {
+		t.Errorf("BuyerPayoutAccountID mismatch: got %v", found.BuyerPayoutAccountID)
+	}
+	if found.SellerPayoutAccountID != nil {
+		t.Errorf("SellerPayoutAccountID should stay unset, got %v", found.SellerPayoutAccountID)
+	}
+
+	if err := repo.SetPayoutAccount(ctx, uuid.New(), domain.LegSeller, acct.ID); err == nil {
+		t.Error("Expected an error for a missing transaction")
+	}
+}
+
+func TestTransactionRepository_TradedVolume(t *testing.T) {
+	// 1. Setup: two trades of 1000 EUR, one of them cancelled
+	ctx := t.Context()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	reqRepo := NewRequestRepository(testDB, &nopLogger)
+	bidRepo := NewBidRepository(testDB, &nopLogger)
+	repo := NewTransactionRepository(testDB, &nopLogger)
+
+	seller, cleanupSeller := createTestUser(t, userRepo)
+	defer cleanupSeller()
+	buyer, cleanupBuyer := createTestUser(t, userRepo)
+	defer cleanupBuyer()
+
+	for _, status := range []domain.TransactionStatus{domain.TxStatusPendingDeposits, domain.TxStatusCancelled} {
+		req, cleanupReq := createTestRequest(t, reqRepo, seller.ID)
+		defer cleanupReq()
+		bid, cleanupBid := createTestBid(t, bidRepo, buyer.ID, req.ID)
+		defer cleanupBid()
+		tx := &domain.Transaction{
+			ID:           uuid.New(),
+			RequestID:    req.ID,
+			BidID:        bid.ID,
+			SellerUserID: seller.ID,
+			BuyerUserID:  buyer.ID,
+			Status:       status,
+		}
+		if err := repo.Create(ctx, tx); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+		defer cleanupTestTransaction(t, tx.ID)
+	}
+
+	// 2. Both parties count the live trade only
+	since := time.Now().Add(-time.Hour)
+	for _, userID := range []uuid.UUID{seller.ID, buyer.ID} {
+		volume, err := repo.TradedVolume(ctx, userID, "EUR", since)
+		if err != nil {
+			t.Fatalf("TradedVolume failed: %v", err)
+		}
+		if !volume.Equal(domain.NewDecimalFromInt(1000)) {
+			t.Errorf("Volume mismatch: got %s, want 1000", volume)
+		}
+	}
+
+	// 3. Other currencies and later cutoffs see nothing
+	if volume, err := repo.TradedVolume(ctx, seller.ID, "USD", since); err != nil || !volume.IsZero() {
+		t.Errorf("Expected no USD volume, got %s (%v)", volume, err)
+	}
+	if volume, err := repo.TradedVolume(ctx, seller.ID, "EUR", time.Now().Add(time.Hour)); err != nil || !volume.IsZero() {
+		t.Errorf("Expected no volume after the cutoff, got %s (%v)", volume, err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/rs/zerolog"
 )
 
@@ -46,7 +47,7 @@ func (r *userBankAccountRepository) Create(ctx context.Context, acct *domain.Use
 			id, user_id, account_name, currency, bank_name, account_details
 		) VALUES ($1, $2, $3, $4, $5, $6)
 	`
-	_, err = r.db.pool.Exec(ctx, query,
+	_, err = r.db.q(ctx).Exec(ctx, query,
 		acct.ID,
 		acct.UserID,
 		acct.AccountName,
@@ -101,6 +102,26 @@ func (r *userBankAccountRepository) scanAcct(row pgx.Row) (*domain.UserBankAccou
 	return &acct, nil
 }
 
+// GetByID finds a bank account by its UUID.
+func (r *userBankAccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.UserBankAccount, error) {
+	query := `
+		SELECT id, user_id, account_name, currency, bank_name, 
+			   account_details, created_at, updated_at
+		FROM user_bank_accounts
+		WHERE id = $1
+	`
+
+	acct, err := r.scanAcct(r.db.q(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		r.log.Error().Err(err).Str("acct_id", id.String()).Msg("Failed to get bank account by ID")
+		return nil, err
+	}
+	return acct, nil
+}
+
 // GetByUserID finds all bank accounts for a given user.
 func (r *userBankAccountRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.UserBankAccount, error) {
 	query := `
@@ -111,7 +132,7 @@ func (r *userBankAccountRepository) GetByUserID(ctx context.Context, userID uuid
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.pool.Query(ctx, query, userID)
+	rows, err := r.db.q(ctx).Query(ctx, query, userID)
 	if err != nil {
 		r.log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to query bank accounts")
 		return nil, err
@@ -137,3 +158,61 @@ func (r *userBankAccountRepository) GetByUserID(ctx context.Context, userID uuid
 
 	return accounts, nil
 }
+
+// Update re-encrypts and saves an account owned by acct.UserID.
+func (r *userBankAccountRepository) Update(ctx context.Context, acct *domain.UserBankAccount) error {
+	encBytes, err := r.secSvc.Encrypt([]byte(acct.AccountDetails))
+	if err != nil {
+		r.log.Error().Err(err).Msg("Failed to encrypt account details")
+		return err
+	}
+	encDetails := base64.StdEncoding.EncodeToString(encBytes)
+
+	query := `
+		UPDATE user_bank_accounts SET
+			account_name = $1,
+			currency = $2,
+			bank_name = $3,
+			account_details = $4,
+			updated_at = NOW()
+		WHERE id = $5 AND user_id = $6
+	`
+	cmdTag, err := r.db.q(ctx).Exec(ctx, query,
+		acct.AccountName,
+		acct.Currency,
+		acct.BankName,
+		encDetails,
+		acct.ID,     // The WHERE clause
+		acct.UserID, // Ownership check
+	)
+	if err != nil {
+		r.log.Error().Err(err).Str("acct_id", acct.ID.String()).Msg("Failed to update bank account")
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		r.log.Error().Err(errors.New("no rows affected")).Str("acct_id", acct.ID.String()).Msg("Bank account not found when trying to update")
+		return errors.New("bank account not found")
+	}
+	return nil
+}
+
+// Delete removes an account owned by userID.
+func (r *userBankAccountRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	query := `DELETE FROM user_bank_accounts WHERE id = $1 AND user_id = $2`
+
+	cmdTag, err := r.db.q(ctx).Exec(ctx, query, id, userID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+			return ports.ErrBankAccountInUse
+		}
+		r.log.Error().Err(err).Str("acct_id", id.String()).Msg("Failed to delete bank account")
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return errors.New("bank account not found")
+	}
+	return nil
+}
@@ -65,6 +65,19 @@ func TestUserBankAccountRepository_Create_GetByUserID_Roundtrip(t *testing.T) {
 		t.Errorf("AccountDetails mismatch (decryption failed?): got %s, want %s",
 			foundAcct.AccountDetails, acctDetails)
 	}
+
+	// 5. Run GetByID
+	byID, err := bankRepo.GetByID(ctx, acct.ID)
+	if err != nil {
+		t.Fatalf("Failed to get bank account by ID: %v", err)
+	}
+	if byID == nil || byID.AccountDetails != acctDetails {
+		t.Fatalf("GetByID mismatch: got %+v", byID)
+	}
+	missing, err := bankRepo.GetByID(ctx, uuid.New())
+	if err != nil || missing != nil {
+		t.Fatalf("GetByID for a missing account: got %+v, %v", missing, err)
+	}
 	t.Logf("Successfully created and retrieved bank account %s", acct.ID)
 }
 
@@ -83,3 +96,60 @@ func TestUserBankAccountRepository_GetByUserID_NotFound(t *testing.T) {
 		t.Fatalf("Found %d accounts, but should be 0", len(foundAccts))
 	}
 }
+
+func TestUserBankAccountRepository_Update_Delete_Ownership(t *testing.T) {
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	userRepo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	bankRepo := NewUserBankAccountRepository(testDB, testSecSvc, &nopLogger)
+
+	owner, cleanupOwner := createTestUser(t, userRepo)
+	defer cleanupOwner()
+	stranger, cleanupStranger := createTestUser(t, userRepo)
+	defer cleanupStranger()
+
+	acct := &domain.UserBankAccount{
+		ID:             uuid.New(),
+		UserID:         owner.ID,
+		AccountName:    "Old Name",
+		Currency:       "EUR",
+		BankName:       "N26",
+		AccountDetails: "IBAN: DE89 3704 0044 0532 0130 00",
+	}
+	if err := bankRepo.Create(ctx, acct); err != nil {
+		t.Fatalf("Failed to create bank account: %v", err)
+	}
+	defer cleanupTestUserBankAccount(t, acct.ID)
+
+	// 1. Someone else cannot rename it
+	hijack := *acct
+	hijack.UserID = stranger.ID
+	hijack.AccountName = "Hijacked"
+	if err := bankRepo.Update(ctx, &hijack); err == nil {
+		t.Fatal("Update by a non-owner should fail")
+	}
+
+	// 2. The owner can
+	acct.AccountName = "New Name"
+	if err := bankRepo.Update(ctx, acct); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	found, err := bankRepo.GetByID(ctx, acct.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if found.AccountName != "New Name" || found.AccountDetails != acct.AccountDetails {
+		t.Errorf("Update mismatch: got %+v", found)
+	}
+
+	// 3. Someone else cannot delete it, the owner can
+	if err := bankRepo.Delete(ctx, acct.ID, stranger.ID); err == nil {
+		t.Fatal("Delete by a non-owner should fail")
+	}
+	if err := bankRepo.Delete(ctx, acct.ID, owner.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if found, _ := bankRepo.GetByID(ctx, acct.ID); found != nil {
+		t.Error("Account still exists after Delete")
+	}
+}
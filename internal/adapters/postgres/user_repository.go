@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/base64"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -60,10 +61,12 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 		INSERT INTO users (
 			id, telegram_id, first_name, last_name, phone_number,
 			government_id, location_country, verification_status, user_state, 
-			verification_strategy, identity_doc_ref, is_moderator
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			state_data, verification_strategy, identity_doc_ref, is_moderator,
+			referred_by_user_id, pending_start_payload, address_doc_ref,
+			selfie_doc_ref, upgrade_requested_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 	`
-	_, err = r.db.pool.Exec(ctx, query,
+	_, err = r.db.q(ctx).Exec(ctx, query,
 		user.ID,
 		user.TelegramID,
 		user.FirstName,
@@ -73,9 +76,15 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 		user.LocationCountry,
 		user.VerificationStatus,
 		user.State,
+		stateDataOrEmpty(user.StateData),
 		user.VerificationStrategy,
 		user.IdentityDocRef,
 		user.IsModerator,
+		user.ReferredByUserID,
+		user.PendingStartPayload,
+		user.AddressDocRef,
+		user.SelfieDocRef,
+		user.UpgradeRequestedAt,
 	)
 
 	if err != nil {
@@ -100,9 +109,15 @@ func (r *userRepository) scanUser(row pgx.Row) (*domain.User, error) {
 		&user.LocationCountry,
 		&user.VerificationStatus,
 		&user.State,
+		&user.StateData,
 		&user.VerificationStrategy,
 		&user.IdentityDocRef,
 		&user.IsModerator,
+		&user.ReferredByUserID,
+		&user.PendingStartPayload,
+		&user.AddressDocRef,
+		&user.SelfieDocRef,
+		&user.UpgradeRequestedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -156,15 +171,16 @@ func (r *userRepository) scanUser(row pgx.Row) (*domain.User, error) {
 const userQueryCols = `
 	id, telegram_id, first_name, last_name, phone_number,
 	government_id, location_country, verification_status, user_state, 
-	verification_strategy, identity_doc_ref, is_moderator,
-	created_at, updated_at
+	state_data, verification_strategy, identity_doc_ref, is_moderator,
+	referred_by_user_id, pending_start_payload, address_doc_ref,
+	selfie_doc_ref, upgrade_requested_at, created_at, updated_at
 `
 
 // GetByTelegramID finds and decrypts a user by their Telegram ID.
 func (r *userRepository) GetByTelegramID(ctx context.Context, telegramID int64) (*domain.User, error) {
 	query := `SELECT ` + userQueryCols + ` FROM users WHERE telegram_id = $1`
 
-	row := r.db.pool.QueryRow(ctx, query, telegramID)
+	row := r.db.q(ctx).QueryRow(ctx, query, telegramID)
 	user, err := r.scanUser(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -180,7 +196,7 @@ func (r *userRepository) GetByTelegramID(ctx context.Context, telegramID int64)
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	query := `SELECT ` + userQueryCols + ` FROM users WHERE id = $1`
 
-	row := r.db.pool.QueryRow(ctx, query, id)
+	row := r.db.q(ctx).QueryRow(ctx, query, id)
 	user, err := r.scanUser(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -230,10 +246,15 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 			is_moderator = $8,
 			verification_strategy = $9,
 			identity_doc_ref = $10,
+			state_data = $11,
+			pending_start_payload = $12,
+			address_doc_ref = $13,
+			selfie_doc_ref = $14,
+			upgrade_requested_at = $15,
 			updated_at = NOW()
-		WHERE id = $11
+		WHERE id = $16
 	`
-	cmdTag, err := r.db.pool.Exec(ctx, query,
+	cmdTag, err := r.db.q(ctx).Exec(ctx, query,
 		user.FirstName,
 		user.LastName,
 		encPhone,
@@ -244,6 +265,11 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 		user.IsModerator,
 		user.VerificationStrategy,
 		user.IdentityDocRef,
+		stateDataOrEmpty(user.StateData),
+		user.PendingStartPayload,
+		user.AddressDocRef,
+		user.SelfieDocRef,
+		user.UpgradeRequestedAt,
 		user.ID, // The WHERE clause
 	)
 
@@ -264,7 +290,7 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM users WHERE id = $1`
 
-	cmdTag, err := r.db.pool.Exec(ctx, query, id)
+	cmdTag, err := r.db.q(ctx).Exec(ctx, query, id)
 	if err != nil {
 		r.log.Error().Err(err).Str("user_id", id.String()).Msg("Failed to delete user")
 		return err
@@ -286,7 +312,7 @@ func (r *userRepository) GetNextPendingUser(ctx context.Context) (*domain.User,
 		LIMIT 1
 	`
 
-	row := r.db.pool.QueryRow(ctx, query, domain.VerificationPending)
+	row := r.db.q(ctx).QueryRow(ctx, query, domain.VerificationPending)
 	user, err := r.scanUser(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -296,3 +322,96 @@ func (r *userRepository) GetNextPendingUser(ctx context.Context) (*domain.User,
 	}
 	return user, nil
 }
+
+// ClaimStalledRegistrations marks stalled sign-ups as nudged and returns them.
+// updated_at is left alone, so the claim does not count as user activity.
+func (r *userRepository) ClaimStalledRegistrations(ctx context.Context, idleSince time.Time, limit int) ([]*domain.User, error) {
+	states := make([]string, len(domain.RegistrationStates))
+	for i, s := range domain.RegistrationStates {
+		states[i] = string(s)
+	}
+
+	query := `
+		UPDATE users SET registration_nudged_at = NOW()
+		WHERE id IN (
+			SELECT id FROM users
+			WHERE verification_status = $1
+			  AND user_state::text = ANY($2)
+			  AND updated_at < $3
+			  AND registration_nudged_at IS NULL
+			ORDER BY updated_at ASC
+			LIMIT $4
+		)
+		RETURNING ` + userQueryCols
+
+	rows, err := r.db.q(ctx).Query(ctx, query, domain.VerificationPending, states, idleSince, limit)
+	if err != nil {
+		r.log.Error().Err(err).Msg("Failed to claim stalled registrations")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*domain.User
+	for rows.Next() {
+		user, err := r.scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if rows.Err() != nil {
+		r.log.Error().Err(rows.Err()).Msg("Error iterating stalled registration rows")
+		return nil, rows.Err()
+	}
+
+	return users, nil
+}
+
+// ClaimPendingStartPayload clears the stashed deep link of a user and returns
+// it. RETURNING shows the new row, so the old value comes from a locked read.
+func (r *userRepository) ClaimPendingStartPayload(ctx context.Context, id uuid.UUID) (*string, error) {
+	query := `
+		UPDATE users u SET pending_start_payload = NULL
+		FROM (
+			SELECT id, pending_start_payload FROM users
+			WHERE id = $1 AND pending_start_payload IS NOT NULL
+			FOR UPDATE
+		) old
+		WHERE u.id = old.id AND u.pending_start_payload IS NOT NULL
+		RETURNING old.pending_start_payload`
+
+	var payload string
+	err := r.db.q(ctx).QueryRow(ctx, query, id).Scan(&payload)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // Nothing stashed, or claimed by someone else
+	}
+	if err != nil {
+		r.log.Error().Err(err).Str("user_id", id.String()).Msg("Failed to claim start payload")
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// stateDataOrEmpty makes sure we never write SQL NULL into the NOT NULL state_data column.
+func stateDataOrEmpty(data map[string]string) map[string]string {
+	if data == nil {
+		return map[string]string{}
+	}
+	return data
+}
+
+// LockForUpdate takes the row locks of the users, sorted by id.
+func (r *userRepository) LockForUpdate(ctx context.Context, ids ...uuid.UUID) error {
+	query := `SELECT id FROM users WHERE id = ANY($1::uuid[]) ORDER BY id FOR UPDATE`
+
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = id.String()
+	}
+	if _, err := r.db.q(ctx).Exec(ctx, query, strIDs); err != nil {
+		r.log.Error().Err(err).Int("users", len(ids)).Msg("Failed to lock users")
+		return err
+	}
+	return nil
+}
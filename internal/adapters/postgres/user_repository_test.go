@@ -113,6 +113,7 @@ func TestUserRepository_Update(t *testing.T) {
 	user.LastName = &newLastName
 	user.State = newState
 	user.IdentityDocRef = &newDocRef
+	user.StateData = map[string]string{"base_currency": "EUR"}
 
 	// 3. Run Update
 	err := repo.Update(ctx, user)
@@ -138,6 +139,9 @@ func TestUserRepository_Update(t *testing.T) {
 	if updatedUser.State != newState {
 		t.Errorf("State was not updated: got %s, want %s", updatedUser.State, newState)
 	}
+	if updatedUser.StateData["base_currency"] != "EUR" {
+		t.Errorf("StateData was not updated: got %v", updatedUser.StateData)
+	}
 	t.Logf("Successfully updated user")
 }
 
@@ -165,3 +169,148 @@ func TestUserRepository_Delete(t *testing.T) {
 	}
 	t.Logf("Successfully deleted user")
 }
+
+func TestUserRepository_Referral_PendingStartPayload(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	repo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	ctx := t.Context()
+
+	referrer, cleanupReferrer := createTestUser(t, repo)
+	defer cleanupReferrer()
+
+	// 1. Create a referred user with a stashed deep link
+	payload := "offer_" + uuid.NewString()
+	user := &domain.User{
+		ID:                  uuid.New(),
+		TelegramID:          time.Now().UnixNano(),
+		State:               domain.StateAwaitingFirstName,
+		VerificationStatus:  domain.VerificationPending,
+		ReferredByUserID:    &referrer.ID,
+		PendingStartPayload: &payload,
+	}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	defer cleanupTestUser(t, user.ID)
+
+	found, err := repo.GetByID(ctx, user.ID)
+	if err != nil || found == nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if found.ReferredByUserID == nil || *found.ReferredByUserID != referrer.ID {
+		t.Errorf("ReferredByUserID mismatch: got %v, want %v", found.ReferredByUserID, referrer.ID)
+	}
+	if found.PendingStartPayload == nil || *found.PendingStartPayload != payload {
+		t.Errorf("PendingStartPayload mismatch: got %v, want %s", found.PendingStartPayload, payload)
+	}
+
+	// 2. Claiming the link returns it once and clears it
+	claimed, err := repo.ClaimPendingStartPayload(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("ClaimPendingStartPayload failed: %v", err)
+	}
+	if claimed == nil || *claimed != payload {
+		t.Errorf("Claimed payload mismatch: got %v, want %s", claimed, payload)
+	}
+	if again, err := repo.ClaimPendingStartPayload(ctx, user.ID); err != nil || again != nil {
+		t.Errorf("Second claim should get nothing, got %v, %v", again, err)
+	}
+	cleared, err := repo.GetByID(ctx, user.ID)
+	if err != nil || cleared == nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if cleared.PendingStartPayload != nil {
+		t.Errorf("PendingStartPayload was not cleared: got %s", *cleared.PendingStartPayload)
+	}
+}
+
+func TestUserRepository_Level2Upgrade(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	repo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	ctx := t.Context()
+
+	user, cleanup := createTestUser(t, repo)
+	defer cleanup()
+
+	// 1. Submit the upgrade documents
+	identityRef := "identity_ref"
+	addressRef := "address_ref"
+	selfieRef := "selfie_ref"
+	requestedAt := time.Now().Truncate(time.Microsecond)
+
+	user.VerificationStatus = domain.VerificationLevel1
+	user.IdentityDocRef = &identityRef
+	user.AddressDocRef = &addressRef
+	user.SelfieDocRef = &selfieRef
+	user.UpgradeRequestedAt = &requestedAt
+	if err := repo.Update(ctx, user); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	found, err := repo.GetByID(ctx, user.ID)
+	if err != nil || found == nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if found.AddressDocRef == nil || *found.AddressDocRef != addressRef {
+		t.Errorf("AddressDocRef mismatch: got %v, want %s", found.AddressDocRef, addressRef)
+	}
+	if found.SelfieDocRef == nil || *found.SelfieDocRef != selfieRef {
+		t.Errorf("SelfieDocRef mismatch: got %v, want %s", found.SelfieDocRef, selfieRef)
+	}
+	if found.UpgradeRequestedAt == nil || !found.UpgradeRequestedAt.Equal(requestedAt) {
+		t.Errorf("UpgradeRequestedAt mismatch: got %v, want %v", found.UpgradeRequestedAt, requestedAt)
+	}
+
+	// 2. Approve it; the level_1 data must survive
+	found.VerificationStatus = domain.VerificationLevel2
+	found.UpgradeRequestedAt = nil
+	if err := repo.Update(ctx, found); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	upgraded, err := repo.GetByID(ctx, user.ID)
+	if err != nil || upgraded == nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if upgraded.VerificationStatus != domain.VerificationLevel2 {
+		t.Errorf("VerificationStatus mismatch: got %s, want %s", upgraded.VerificationStatus, domain.VerificationLevel2)
+	}
+	if upgraded.UpgradeRequestedAt != nil {
+		t.Errorf("UpgradeRequestedAt was not cleared: got %v", *upgraded.UpgradeRequestedAt)
+	}
+	if upgraded.IdentityDocRef == nil || *upgraded.IdentityDocRef != identityRef {
+		t.Errorf("IdentityDocRef was lost on upgrade: got %v", upgraded.IdentityDocRef)
+	}
+}
+
+func TestUserRepository_LockForUpdate(t *testing.T) {
+	// 1. Setup
+	nopLogger := zerolog.Nop()
+	repo := NewUserRepository(testDB, testSecSvc, &nopLogger)
+	ctx := t.Context()
+
+	first, cleanupFirst := createTestUser(t, repo)
+	defer cleanupFirst()
+	second, cleanupSecond := createTestUser(t, repo)
+	defer cleanupSecond()
+
+	// 2. Hold the locks and try to take one from another connection
+	err := testDB.WithinTx(ctx, func(ctx context.Context) error {
+		if err := repo.LockForUpdate(ctx, second.ID, first.ID); err != nil {
+			return err
+		}
+		_, err := testDB.pool.Exec(ctx, "SELECT id FROM users WHERE id = $1 FOR UPDATE NOWAIT", first.ID)
+		if err == nil {
+			t.Error("Expected the row to be locked")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("LockForUpdate failed: %v", err)
+	}
+
+	// 3. The commit released them
+	if _, err := testDB.pool.Exec(ctx, "SELECT id FROM users WHERE id = $1 FOR UPDATE NOWAIT", first.ID); err != nil {
+		t.Errorf("Expected the lock to be released, got: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"AsaExchange/internal/core/ports"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// entry is a job and how often it runs.
+type entry struct {
+	job      ports.Job
+	interval time.Duration
+}
+
+// Scheduler runs periodic jobs in-process.
+// Each run takes the job's lock first, so with several replicas only one of them does the work.
+type Scheduler struct {
+	locker  ports.JobLocker
+	entries []entry
+	log     zerolog.Logger
+}
+
+// NewScheduler creates a new, empty scheduler.
+func NewScheduler(locker ports.JobLocker, baseLogger *zerolog.Logger) *Scheduler {
+	return &Scheduler{
+		locker: locker,
+		log:    baseLogger.With().Str("component", "scheduler").Logger(),
+	}
+}
+
+// Register adds a job that runs every interval. Call it before Start.
+func (s *Scheduler) Register(job ports.Job, interval time.Duration) {
+	s.entries = append(s.entries, entry{job: job, interval: interval})
+	s.log.Info().Str("job", job.Name()).Dur("interval", interval).Msg("Job registered")
+}
+
+// Start runs every job once, then on its interval, until ctx is cancelled.
+// It blocks until all job loops have stopped.
+func (s *Scheduler) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range s.entries {
+		wg.Add(1)
+		go func(e entry) {
+			defer wg.Done()
+			s.loop(ctx, e)
+		}(e)
+	}
+	s.log.Info().Int("jobs", len(s.entries)).Msg("Scheduler started")
+	wg.Wait()
+	s.log.Info().Msg("Scheduler stopped")
+}
+
+// loop runs one job on its ticker.
+func (s *Scheduler) loop(ctx context.Context, e entry) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, e.job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs the job if no other replica is running it.
+func (s *Scheduler) runOnce(ctx context.Context, job ports.Job) {
+	log := s.log.With().Str("job", job.Name()).Logger()
+
+	unlock, ok, err := s.locker.TryLock(ctx, job.Name())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to take job lock")
+		return
+	}
+	if !ok {
+		log.Debug().Msg("Job is running on another replica, skipping")
+		return
+	}
+	defer unlock()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		log.Error().Err(err).Msg("Job failed")
+		return
+	}
+	log.Debug().Dur("took", time.Since(start)).Msg("Job finished")
+}
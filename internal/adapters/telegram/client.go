@@ -3,7 +3,13 @@ package telegram
 import (
 	"AsaExchange/internal/core/ports"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/rs/zerolog"
@@ -11,6 +17,14 @@ import (
 
 var _ ports.BotClientPort = (*tgClient)(nil) // Ensure compliance
 
+const (
+	downloadTimeout = 30 * time.Second
+	maxDownloadSize = 20 << 20 // The Bot API serves files up to 20 MB
+)
+
+// downloadClient fetches files from the Bot API file endpoint.
+var downloadClient = &http.Client{Timeout: downloadTimeout}
+
 // tgClient implements the BotClientPort.
 type tgClient struct {
 	api *tgbotapi.BotAPI
@@ -97,6 +111,8 @@ func (c *tgClient) SetMenuCommands(ctx context.Context, chatID int64, isAdmin bo
 		commands = []tgbotapi.BotCommand{
 			{Command: "/start", Description: "Start the bot"},
 			{Command: "/newrequest", Description: "Create a new exchange request"},
+			{Command: "/listrequests", Description: "Browse open exchange requests"},
+			{Command: "/myoffers", Description: "Your open requests and bids"},
 			{Command: "/myaccounts", Description: "Manage your payout accounts"},
 		}
 	}
@@ -126,7 +142,7 @@ func (c *tgClient) EditMessageText(ctx context.Context, params ports.EditMessage
 	}
 
 	// Send the request
-	if _, err := c.api.Send(msg); err != nil {
+	if _, err := c.api.Send(msg); err != nil && !isNotModified(err) {
 		c.log.Error().Err(err).
 			Int64("chat_id", params.ChatID).
 			Int("message_id", params.MessageID).
@@ -150,7 +166,7 @@ func (c *tgClient) EditMessageCaption(ctx context.Context, params ports.EditMess
 		msg.ReplyMarkup = &inlineMarkup
 	}
 
-	if _, err := c.api.Send(msg); err != nil {
+	if _, err := c.api.Send(msg); err != nil && !isNotModified(err) {
 		c.log.Error().Err(err).
 			Int64("chat_id", params.ChatID).
 			Int("message_id", params.MessageID).
@@ -160,6 +176,18 @@ func (c *tgClient) EditMessageCaption(ctx context.Context, params ports.EditMess
 	return nil
 }
 
+// DeleteMessage deletes a message the bot sent.
+func (c *tgClient) DeleteMessage(ctx context.Context, chatID int64, messageID int) error {
+	if _, err := c.api.Request(tgbotapi.NewDeleteMessage(chatID, messageID)); err != nil {
+		c.log.Error().Err(err).
+			Int64("chat_id", chatID).
+			Int("message_id", messageID).
+			Msg("Failed to delete message")
+		return err
+	}
+	return nil
+}
+
 // AnswerCallbackQuery sends a response to a callback query (stops the spinner)
 func (c *tgClient) AnswerCallbackQuery(ctx context.Context, params ports.AnswerCallbackParams) error {
 	callbackConfig := tgbotapi.NewCallback(params.CallbackQueryID, params.Text)
@@ -181,6 +209,8 @@ func (c *tgClient) SendPhoto(ctx context.Context, params ports.SendPhotoParams)
 		file = tgbotapi.FilePath(filePath)
 	} else if fileID, ok := params.File.(tgbotapi.FileID); ok {
 		file = fileID
+	} else if fileBytes, ok := params.File.(tgbotapi.FileBytes); ok {
+		file = fileBytes
 	} else {
 		return 0, fmt.Errorf("invalid file type for SendPhoto: %T", params.File)
 	}
@@ -202,3 +232,55 @@ func (c *tgClient) SendPhoto(ctx context.Context, params ports.SendPhotoParams)
 	}
 	return sentMessage.MessageID, nil
 }
+
+// DownloadFile downloads a file by its FileID through the Bot API file endpoint.
+// File URLs carry the bot token, so errors never include them.
+func (c *tgClient) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	fileURL, err := c.api.GetFileDirectURL(fileID)
+	if err != nil {
+		err = fmt.Errorf("resolve file %s: %w", fileID, stripURL(err))
+		c.log.Error().Err(err).Str("file_id", fileID).Msg("Failed to resolve file URL")
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("download file %s: %w", fileID, stripURL(err))
+	}
+	resp, err := downloadClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("download file %s: %w", fileID, stripURL(err))
+		c.log.Error().Err(err).Str("file_id", fileID).Msg("Failed to download file")
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download file %s: unexpected status %s", fileID, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download file %s: %w", fileID, stripURL(err))
+	}
+	if len(data) > maxDownloadSize {
+		return nil, fmt.Errorf("download file %s: larger than %d bytes", fileID, maxDownloadSize)
+	}
+	return data, nil
+}
+
+// isNotModified reports whether Telegram refused an edit because the message
+// already looks like that. The edit is done, so callers treat it as success.
+func isNotModified(err error) bool {
+	var apiErr *tgbotapi.Error
+	return errors.As(err, &apiErr) && strings.Contains(apiErr.Message, "message is not modified")
+}
+
+// stripURL drops the request URL from an HTTP error, since Bot API URLs
+// contain the bot token.
+func stripURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"AsaExchange/internal/bot/moderator"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// evidenceCaptionHeader is the first caption line of every dispute evidence photo.
+const evidenceCaptionHeader = "Dispute Evidence"
+
+// telegramEvidenceQueue implements the EvidenceQueue interface
+// on top of the same private upload channel as telegramQueue.
+type telegramEvidenceQueue struct {
+	customerBot ports.BotClientPort // Used to Publish
+	channelID   int64
+	bus         ports.EventBus
+	log         zerolog.Logger
+}
+
+// NewTelegramEvidenceQueue creates our MVP dispute evidence "queue"
+func NewTelegramEvidenceQueue(
+	customerBot ports.BotClientPort,
+	channelID int64,
+	bus ports.EventBus,
+	baseLogger *zerolog.Logger,
+) ports.EvidenceQueue {
+	return &telegramEvidenceQueue{
+		customerBot: customerBot,
+		channelID:   channelID,
+		bus:         bus,
+		log:         baseLogger.With().Str("component", "telegram_evidence_queue").Logger(),
+	}
+}
+
+// Publish sends the evidence photo to the private channel.
+func (t *telegramEvidenceQueue) Publish(ctx context.Context, event ports.DisputeEvidenceEvent) (string, error) {
+	caption := fmt.Sprintf("%s\nDisputeID: %s\nEvidenceID: %s",
+		evidenceCaptionHeader, event.DisputeID, event.EvidenceID)
+
+	params := ports.SendPhotoParams{
+		ChatID:    t.channelID,
+		File:      tgbotapi.FileID(event.FileID),
+		Caption:   caption,
+		ParseMode: "", // Plain text
+	}
+
+	messageID, err := t.customerBot.SendPhoto(ctx, params)
+	if err != nil {
+		t.log.Error().Err(err).Msg("Failed to publish evidence to queue channel")
+		return "", err
+	}
+
+	// The storage reference IS the message ID
+	return fmt.Sprintf("%d", messageID), nil
+}
+
+// Subscribe registers the queue's handler with the event bus.
+func (t *telegramEvidenceQueue) Subscribe(ctx context.Context, handler func(event ports.DisputeEvidenceEvent) error) {
+	events.Subscribe(t.bus, t.handleChannelPost(handler))
+	t.log.Info().Int64("channel_id", t.channelID).Msg("Subscribed to 'telegram:mod:channel_post' topic")
+}
+
+// handleChannelPost parses evidence posts and passes them to the handler.
+func (t *telegramEvidenceQueue) handleChannelPost(handler func(event ports.DisputeEvidenceEvent) error) events.Handler[moderator.ChannelPostReceived] {
+	return func(ctx context.Context, event moderator.ChannelPostReceived) error {
+		update := event.Update
+		if update.ChannelPost == nil {
+			t.log.Error().Msg("Received bad channel_post event from bus")
+			return nil // Don't retry
+		}
+
+		msg := update.ChannelPost
+		if msg.Chat.ID != t.channelID || msg.Photo == nil || !strings.HasPrefix(msg.Caption, evidenceCaptionHeader) {
+			return nil // Not evidence
+		}
+
+		t.log.Info().Int("message_id", msg.MessageID).Msg("Received new dispute evidence from queue")
+
+		newEvent, err := t.parseCaption(msg.Caption)
+		if err != nil {
+			t.log.Error().Err(err).Int("msg_id", msg.MessageID).Msg("Failed to parse evidence caption")
+			return nil
+		}
+		// The FileID is now the one the *Moderator Bot* can use
+		newEvent.FileID = msg.Photo[len(msg.Photo)-1].FileID
+
+		if err := handler(newEvent); err != nil {
+			t.log.Error().Err(err).Str("dispute_id", newEvent.DisputeID.String()).Msg("Queue handler failed to process evidence")
+			return err
+		}
+		return nil
+	}
+}
+
+// parseCaption is the reverse of the caption built in Publish.
+func (t *telegramEvidenceQueue) parseCaption(caption string) (ports.DisputeEvidenceEvent, error) {
+	fields := map[string]string{}
+	for _, line := range strings.Split(caption, "\n") {
+		if key, value, ok := strings.Cut(line, ": "); ok {
+			fields[key] = value
+		}
+	}
+
+	disputeID, err := uuid.Parse(fields["DisputeID"])
+	if err != nil {
+		return ports.DisputeEvidenceEvent{}, fmt.Errorf("invalid DisputeID: %w", err)
+	}
+	evidenceID, err := uuid.Parse(fields["EvidenceID"])
+	if err != nil {
+		return ports.DisputeEvidenceEvent{}, fmt.Errorf("invalid EvidenceID: %w", err)
+	}
+
+	return ports.DisputeEvidenceEvent{DisputeID: disputeID, EvidenceID: evidenceID}, nil
+}
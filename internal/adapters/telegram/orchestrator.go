@@ -1,11 +1,15 @@
 package telegram
 
 import (
+	"AsaExchange/internal/adapters/scheduler"
 	"AsaExchange/internal/bot/customer"
 	custHandle "AsaExchange/internal/bot/customer/handlers"
 	"AsaExchange/internal/bot/moderator"
 	modHandle "AsaExchange/internal/bot/moderator/handlers"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
 	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
 	"AsaExchange/internal/shared/config"
 	"context"
 	"fmt"
@@ -15,56 +19,77 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// Bus is the event bus the orchestrator runs: handlers publish and subscribe
+// on it, the dispatcher delivers its stored events and /deadletters replays
+// through it.
+type Bus interface {
+	ports.EventBus
+	ports.EventDispatcher
+	ports.DeadLetterReplayer
+}
+
+// OrchestratorDeps holds everything the bot servers are built from.
+type OrchestratorDeps struct {
+	Cfg              *config.Config
+	UserRepo         ports.UserRepository
+	RequestRepo      ports.RequestRepository
+	BidRepo          ports.BidRepository
+	BankAccountRepo  ports.UserBankAccountRepository
+	PlatformAcctRepo ports.PlatformAccountRepository
+	TransactionRepo  ports.TransactionRepository
+	DisputeRepo      ports.DisputeRepository
+	PayoutProofRepo  ports.PayoutProofRepository
+	DeadLetterRepo   ports.DeadLetterRepository
+	TxService        *services.TransactionService
+	PlatformAccounts *services.PlatformAccountService
+	Disputes         *services.DisputeService
+	Cancellations    *services.CancellationService
+	Fees             *services.FeeEngine
+	Limits           *services.LimitService
+	Verifier         *services.VerificationService
+	Locker           ports.JobLocker
+	Transactor       ports.Transactor
+	Bus              Bus
+	BaseLogger       *zerolog.Logger
+}
+
 // Orchestrator manages all bot servers.
 type Orchestrator struct {
-	cfg        *config.Config
-	userRepo   ports.UserRepository
-	bus        ports.EventBus
-	baseLogger *zerolog.Logger
-	wg         sync.WaitGroup
+	deps *OrchestratorDeps
+	wg   sync.WaitGroup
 }
 
 // NewOrchestrator creates a new bot orchestrator.
-func NewOrchestrator(
-	cfg *config.Config,
-	userRepo ports.UserRepository,
-	bus ports.EventBus,
-	baseLogger *zerolog.Logger,
-) *Orchestrator {
-	return &Orchestrator{
-		cfg:        cfg,
-		userRepo:   userRepo,
-		bus:        bus,
-		baseLogger: baseLogger,
-	}
+func NewOrchestrator(deps *OrchestratorDeps) *Orchestrator {
+	return &Orchestrator{deps: deps}
 }
 
 // Start launches all bot servers and waits for them to complete.
 func (o *Orchestrator) Start(ctx context.Context) error {
-	// We are launching 2 main servers
-	o.wg.Add(2)
+	// We are launching 2 main servers, the job scheduler and the event dispatcher
+	o.wg.Add(4)
 
 	// --- 1. Create Customer Bot Dependencies ---
-	custLog := o.baseLogger.With().Str("bot", "customer").Logger()
-	custCfg := &o.cfg.Bot.Customer
+	custLog := o.deps.BaseLogger.With().Str("bot", "customer").Logger()
+	custCfg := &o.deps.Cfg.Bot.Customer
 	custAPI, err := tgbotapi.NewBotAPI(custCfg.Token)
 	if err != nil {
 		return fmt.Errorf("customer bot API failed: %w", err)
 	}
-	custAPI.Debug = o.cfg.AppEnv == "development"
+	custAPI.Debug = o.deps.Cfg.AppEnv == "development"
 	custLog.Info().Str("username", custAPI.Self.UserName).Msg("Bot API connected")
 	custClient := NewClient(custAPI, &custLog)
 
 	// --- 2. Create Moderator Bot Dependencies ---
-	modLog := o.baseLogger.With().Str("bot", "moderator").Logger()
-	modCfg := &o.cfg.Bot.Moderator
+	modLog := o.deps.BaseLogger.With().Str("bot", "moderator").Logger()
+	modCfg := &o.deps.Cfg.Bot.Moderator
 
 	// Create the ONE AND ONLY API for the moderator
 	modAPI, err := tgbotapi.NewBotAPI(modCfg.Token)
 	if err != nil {
 		return fmt.Errorf("moderator bot API failed: %w", err)
 	}
-	modAPI.Debug = o.cfg.AppEnv == "development"
+	modAPI.Debug = o.deps.Cfg.AppEnv == "development"
 	modLog.Info().Str("username", modAPI.Self.UserName).Msg("Bot API (commands) connected")
 	modClient := NewClient(modAPI, &modLog)
 
@@ -72,40 +97,137 @@ func (o *Orchestrator) Start(ctx context.Context) error {
 	// It's injected with the bus so it can *subscribe*
 	queue := NewTelegramQueue(
 		custClient, // Customer client (to Publish)
-		o.cfg.Bot.PrivateUploadChannelID,
-		o.bus, // The event bus (to Subscribe)
-		o.baseLogger,
+		o.deps.Cfg.Bot.PrivateUploadChannelID,
+		o.deps.Bus, // The event bus (to Subscribe)
+		o.deps.BaseLogger,
 	)
+	// Deposit receipts travel through the same channel
+	receiptQueue := NewTelegramReceiptQueue(custClient, o.deps.Cfg.Bot.PrivateUploadChannelID, o.deps.Bus, o.deps.BaseLogger)
+	// ...and so does dispute evidence
+	evidenceQueue := NewTelegramEvidenceQueue(custClient, o.deps.Cfg.Bot.PrivateUploadChannelID, o.deps.Bus, o.deps.BaseLogger)
 
 	// 4. --- Create and Subscribe Handlers ---
 
 	// Create the Customer Router
-	custRouter := customer.NewCustomerRouter(o.userRepo, custClient, &custLog)
+	custRouter := customer.NewCustomerRouter(o.deps.UserRepo, custClient, &custLog)
 	// Register all customer handlers (which also injects the queue)
-	customer.RegisterAllHandlers(o.cfg, custRouter, o.userRepo, custClient, queue, &custLog)
+	customer.RegisterAllHandlers(custRouter, &customer.HandlerDeps{
+		Cfg:              o.deps.Cfg,
+		UserRepo:         o.deps.UserRepo,
+		RequestRepo:      o.deps.RequestRepo,
+		BidRepo:          o.deps.BidRepo,
+		TransactionRepo:  o.deps.TransactionRepo,
+		BankAccountRepo:  o.deps.BankAccountRepo,
+		DisputeRepo:      o.deps.DisputeRepo,
+		PlatformAccounts: o.deps.PlatformAccounts,
+		Disputes:         o.deps.Disputes,
+		Cancellations:    o.deps.Cancellations,
+		Fees:             o.deps.Fees,
+		Limits:           o.deps.Limits,
+		Verifier:         o.deps.Verifier,
+		BotClient:        custClient,
+		Queue:            queue,
+		ReceiptQueue:     receiptQueue,
+		EvidenceQueue:    evidenceQueue,
+		Transactor:       o.deps.Transactor,
+		Bus:              o.deps.Bus,
+		BaseLogger:       &custLog,
+	})
 
 	// Create the Moderator Router (which subscribes to the bus)
-	modRouter := moderator.NewModeratorRouter(o.userRepo, modClient, o.bus, &modLog)
+	modRouter := moderator.NewModeratorRouter(o.deps.UserRepo, modClient, o.deps.Bus, &modLog)
 	// Register all moderator handlers (commands/callbacks)
-	moderator.RegisterAllHandlers(o.cfg, modRouter, o.userRepo, modClient, o.bus, &modLog)
+	moderator.RegisterAllHandlers(modRouter, &moderator.HandlerDeps{
+		Cfg:              o.deps.Cfg,
+		UserRepo:         o.deps.UserRepo,
+		RequestRepo:      o.deps.RequestRepo,
+		TransactionRepo:  o.deps.TransactionRepo,
+		BankAccountRepo:  o.deps.BankAccountRepo,
+		PlatformAcctRepo: o.deps.PlatformAcctRepo,
+		DisputeRepo:      o.deps.DisputeRepo,
+		PayoutProofRepo:  o.deps.PayoutProofRepo,
+		TxService:        o.deps.TxService,
+		Disputes:         o.deps.Disputes,
+		BotClient:        modClient,
+		Bus:              o.deps.Bus,
+		Transactor:       o.deps.Transactor,
+		DeadLetterRepo:   o.deps.DeadLetterRepo,
+		DeadLetters:      o.deps.Bus,
+		BaseLogger:       &modLog,
+	})
 
 	// Create the Notification Handler (it's not a router plugin)
 	// It uses the CUSTOMER client to send messages
-	notificationHandler := custHandle.NewNotificationHandler(custClient, o.userRepo, &custLog)
-	// Subscribe it to the events published by the approval_handler
-	o.bus.Subscribe("user:approved", notificationHandler.HandleUserApproved)
-	o.bus.Subscribe("user:rejected", notificationHandler.HandleUserRejected)
+	notificationHandler := custHandle.NewNotificationHandler(custClient, o.deps.UserRepo, o.deps.RequestRepo, o.deps.TransactionRepo, o.deps.PlatformAcctRepo, o.deps.PayoutProofRepo, o.deps.Cfg.Currencies, &custLog)
+	// Subscribe it to the events published by the approval_handler.
+	// Failed notifications are retried, then dead-lettered for /deadletters.
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleUserApproved, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleUserRejected, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleUserUpgraded, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleUpgradeRejected, ports.DefaultRetryPolicy)
+	// ...and to the events published by the bid_callback handler
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleBidCreated, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleBidAccepted, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleBidRejected, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleBidCancelled, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleBidWithdrawn, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleRequestExpired, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleRegistrationStalled, ports.DefaultRetryPolicy)
+	// ...and to the deposit steps of a transaction
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleSellerDepositReceived, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleBuyerDepositReceived, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleDepositRejected, ports.DefaultRetryPolicy)
+	// ...and to the payout steps
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandlePayoutSent, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleTransactionWithdrawn, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "notification", notificationHandler.HandleDisputeOpened, ports.DefaultRetryPolicy)
+	// Messages for both parties of a trade get one subscription per party,
+	// so a retry or a /replay only re-sends to the party it failed for
+	for _, leg := range []domain.TransactionLeg{domain.LegSeller, domain.LegBuyer} {
+		name := "notification_" + string(leg)
+		events.SubscribeWithRetry(o.deps.Bus, name, notificationHandler.HandleTransactionCreated(leg), ports.DefaultRetryPolicy)
+		events.SubscribeWithRetry(o.deps.Bus, name, notificationHandler.HandlePayoutsDue(leg), ports.DefaultRetryPolicy)
+		events.SubscribeWithRetry(o.deps.Bus, name, notificationHandler.HandleTransactionCompleted(leg), ports.DefaultRetryPolicy)
+		events.SubscribeWithRetry(o.deps.Bus, name, notificationHandler.HandleDisputeResolved(leg), ports.DefaultRetryPolicy)
+	}
 
 	// Create the Forwarding Handler (the queue's subscriber)
 	fwdHandler := modHandle.NewForwardingHandler(
-		o.cfg,
-		o.userRepo,
+		o.deps.Cfg,
+		o.deps.UserRepo,
 		modClient, // Use modClient to post to the admin channel
 		&modLog,
 	)
 	// Manually subscribe the queue to its handler
 	queue.Subscribe(ctx, fwdHandler.HandleEvent)
 
+	// Same for deposit receipts
+	receiptFwdHandler := modHandle.NewReceiptForwardingHandler(
+		o.deps.Cfg,
+		o.deps.UserRepo,
+		o.deps.RequestRepo,
+		o.deps.TransactionRepo,
+		modClient,
+		&modLog,
+	)
+	receiptQueue.Subscribe(ctx, receiptFwdHandler.HandleEvent)
+
+	// Post dispute cards for the moderators
+	disputeFwdHandler := modHandle.NewDisputeForwardingHandler(o.deps.Cfg, o.deps.UserRepo, o.deps.DisputeRepo, modClient, &modLog)
+	evidenceQueue.Subscribe(ctx, disputeFwdHandler.HandleEvent)
+
+	// Announce due payouts to the moderators
+	payoutDueHandler := modHandle.NewPayoutDueHandler(o.deps.Cfg, o.deps.RequestRepo, modClient, &modLog)
+	events.SubscribeWithRetry(o.deps.Bus, "payout_due", payoutDueHandler.HandleEvent, ports.DefaultRetryPolicy)
+
+	// Keep the public channel in sync with the order book
+	channelHandler := modHandle.NewPublicChannelHandler(o.deps.Cfg, o.deps.RequestRepo, modClient, custAPI.Self.UserName, &modLog)
+	events.SubscribeWithRetry(o.deps.Bus, "public_channel", channelHandler.HandleRequestCreated, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "public_channel", channelHandler.HandleRequestMatched, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "public_channel", channelHandler.HandleRequestCompleted, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "public_channel", channelHandler.HandleRequestCancelled, ports.DefaultRetryPolicy)
+	events.SubscribeWithRetry(o.deps.Bus, "public_channel", channelHandler.HandleRequestReopened, ports.DefaultRetryPolicy)
+
 	// --- 5. Start Customer Bot Server ---
 	go func() {
 		defer o.wg.Done()
@@ -123,13 +245,32 @@ func (o *Orchestrator) Start(ctx context.Context) error {
 		modClient.SetMenuCommands(ctx, 0, true) // admin menu
 
 		// This server will poll and PUBLISH to the bus
-		server := moderator.NewModeratorServer(modAPI, &modCfg.Connection, o.bus, &modLog)
+		server := moderator.NewModeratorServer(modAPI, &modCfg.Connection, o.deps.Bus, &modLog)
 
 		if err := server.Start(ctx); err != nil {
 			modLog.Error().Err(err).Msg("ModeratorBot Server failed")
 		}
 	}()
 
-	o.wg.Wait() // Wait for both goroutines to finish
+	// --- 7. Start the Job Scheduler ---
+	go func() {
+		defer o.wg.Done()
+		schedCfg := o.deps.Cfg.Scheduler
+		sched := scheduler.NewScheduler(o.deps.Locker, o.deps.BaseLogger)
+		sched.Register(services.NewExpireRequestsJob(o.deps.RequestRepo, o.deps.Transactor, o.deps.Bus, schedCfg.RequestTTL, o.deps.BaseLogger), schedCfg.Interval)
+		sched.Register(services.NewCancelStaleBidsJob(o.deps.BidRepo, o.deps.Transactor, o.deps.Bus, o.deps.BaseLogger), schedCfg.Interval)
+		sched.Register(services.NewRegistrationNudgeJob(o.deps.UserRepo, o.deps.Transactor, o.deps.Bus, schedCfg.RegistrationNudgeAfter, o.deps.BaseLogger), schedCfg.Interval)
+		sched.Start(ctx)
+	}()
+
+	// --- 8. Start the Event Dispatcher ---
+	// Every subscriber is registered by now, so events left undelivered
+	// by the last run are replayed to all of them.
+	go func() {
+		defer o.wg.Done()
+		o.deps.Bus.Run(ctx)
+	}()
+
+	o.wg.Wait() // Wait for all goroutines to finish
 	return nil
 }
@@ -1,6 +1,8 @@
 package telegram
 
 import (
+	"AsaExchange/internal/bot/moderator"
+	"AsaExchange/internal/core/events"
 	"AsaExchange/internal/core/ports"
 	"context"
 	"errors"
@@ -36,12 +38,20 @@ func NewTelegramQueue(
 	}
 }
 
+// documentCaptionPrefix marks the line of the caption that names the document.
+// Sign-up captions predate it, so a missing line means an identity document.
+const documentCaptionPrefix = "Document: "
+
 // Publish sends the photo+caption to the private channel
 func (t *telegramQueue) Publish(ctx context.Context, event ports.NewVerificationEvent) (string, error) {
+	caption := event.Caption
+	if event.Document != "" && event.Document != ports.VerificationDocIdentity {
+		caption += documentCaptionPrefix + string(event.Document) + "\n"
+	}
 	params := ports.SendPhotoParams{
 		ChatID:    t.channelID,
 		File:      tgbotapi.FileID(event.FileID),
-		Caption:   event.Caption,
+		Caption:   caption,
 		ParseMode: "", // Plain text
 	}
 
@@ -60,17 +70,17 @@ func (t *telegramQueue) Publish(ctx context.Context, event ports.NewVerification
 // It no longer polls.
 func (t *telegramQueue) Subscribe(ctx context.Context, handler func(event ports.NewVerificationEvent) error) {
 	// Register our internal method as the handler for this topic
-	t.bus.Subscribe("telegram:mod:channel_post", t.handleChannelPost(handler))
+	events.Subscribe(t.bus, t.handleChannelPost(handler))
 	t.log.Info().Int64("channel_id", t.channelID).Msg("Subscribed to 'telegram:mod:channel_post' topic")
 }
 
 // handleChannelPost is the internal function that the EventBus will call.
 // It wraps the final handler with our parsing logic.
-func (t *telegramQueue) handleChannelPost(handler func(event ports.NewVerificationEvent) error) ports.EventHandler {
+func (t *telegramQueue) handleChannelPost(handler func(event ports.NewVerificationEvent) error) events.Handler[moderator.ChannelPostReceived] {
 	// The event bus calls this function
-	return func(ctx context.Context, event ports.Event) error {
-		update, ok := event.Data.(tgbotapi.Update)
-		if !ok || update.ChannelPost == nil {
+	return func(ctx context.Context, event moderator.ChannelPostReceived) error {
+		update := event.Update
+		if update.ChannelPost == nil {
 			t.log.Error().Msg("Received bad channel_post event from bus")
 			return nil // Don't retry
 		}
@@ -88,6 +98,12 @@ func (t *telegramQueue) handleChannelPost(handler func(event ports.NewVerificati
 			return nil
 		}
 
+		// Deposit receipts and dispute evidence share this channel;
+		// receipt_queue.go and evidence_queue.go handle them
+		if strings.HasPrefix(msg.Caption, receiptCaptionHeader) || strings.HasPrefix(msg.Caption, evidenceCaptionHeader) {
+			return nil
+		}
+
 		t.log.Info().Int("message_id", msg.MessageID).Msg("Received new event from queue")
 
 		// Parse the UserID from the caption
@@ -102,9 +118,10 @@ func (t *telegramQueue) handleChannelPost(handler func(event ports.NewVerificati
 		// Re-create the event
 		// The FileID is now the one the *Moderator Bot* can use
 		newEvent := ports.NewVerificationEvent{
-			UserID:  userID,
-			FileID:  bestPhoto.FileID,
-			Caption: msg.Caption,
+			UserID:   userID,
+			Document: parseDocumentFromCaption(msg.Caption),
+			FileID:   bestPhoto.FileID,
+			Caption:  msg.Caption,
 		}
 
 		// Call the final handler (the forwarding_handler)
@@ -133,3 +150,13 @@ func (t *telegramQueue) parseUserIDFromCaption(caption string) (uuid.UUID, error
 	}
 	return uuid.Nil, errors.New("UserID not found in caption")
 }
+
+// parseDocumentFromCaption finds the document kind in the caption.
+func parseDocumentFromCaption(caption string) ports.VerificationDocument {
+	for _, line := range strings.Split(caption, "\n") {
+		if kind, ok := strings.CutPrefix(line, documentCaptionPrefix); ok {
+			return ports.VerificationDocument(kind)
+		}
+	}
+	return ports.VerificationDocIdentity
+}
@@ -0,0 +1,131 @@
+package telegram
+
+import (
+	"AsaExchange/internal/bot/moderator"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// receiptCaptionHeader is the first caption line of every deposit receipt.
+// Receipts share the upload channel with verification photos, so both queues use it
+// to tell their posts apart.
+const receiptCaptionHeader = "Deposit Receipt"
+
+// telegramReceiptQueue implements the ReceiptQueue interface
+// on top of the same private upload channel as telegramQueue.
+type telegramReceiptQueue struct {
+	customerBot ports.BotClientPort // Used to Publish
+	channelID   int64
+	bus         ports.EventBus
+	log         zerolog.Logger
+}
+
+// NewTelegramReceiptQueue creates our MVP deposit receipt "queue"
+func NewTelegramReceiptQueue(
+	customerBot ports.BotClientPort,
+	channelID int64,
+	bus ports.EventBus,
+	baseLogger *zerolog.Logger,
+) ports.ReceiptQueue {
+	return &telegramReceiptQueue{
+		customerBot: customerBot,
+		channelID:   channelID,
+		bus:         bus,
+		log:         baseLogger.With().Str("component", "telegram_receipt_queue").Logger(),
+	}
+}
+
+// Publish sends the receipt photo to the private channel.
+// Everything the moderator side needs is encoded in the caption.
+func (t *telegramReceiptQueue) Publish(ctx context.Context, event ports.DepositReceiptEvent) (string, error) {
+	caption := fmt.Sprintf("%s\nTransactionID: %s\nUserID: %s\nLeg: %s",
+		receiptCaptionHeader, event.TransactionID, event.UserID, event.Leg)
+
+	params := ports.SendPhotoParams{
+		ChatID:    t.channelID,
+		File:      tgbotapi.FileID(event.FileID),
+		Caption:   caption,
+		ParseMode: "", // Plain text
+	}
+
+	messageID, err := t.customerBot.SendPhoto(ctx, params)
+	if err != nil {
+		t.log.Error().Err(err).Msg("Failed to publish receipt to queue channel")
+		return "", err
+	}
+
+	// The storage reference IS the message ID
+	return fmt.Sprintf("%d", messageID), nil
+}
+
+// Subscribe registers the queue's handler with the event bus.
+func (t *telegramReceiptQueue) Subscribe(ctx context.Context, handler func(event ports.DepositReceiptEvent) error) {
+	events.Subscribe(t.bus, t.handleChannelPost(handler))
+	t.log.Info().Int64("channel_id", t.channelID).Msg("Subscribed to 'telegram:mod:channel_post' topic")
+}
+
+// handleChannelPost parses receipt posts and passes them to the handler.
+func (t *telegramReceiptQueue) handleChannelPost(handler func(event ports.DepositReceiptEvent) error) events.Handler[moderator.ChannelPostReceived] {
+	return func(ctx context.Context, event moderator.ChannelPostReceived) error {
+		update := event.Update
+		if update.ChannelPost == nil {
+			t.log.Error().Msg("Received bad channel_post event from bus")
+			return nil // Don't retry
+		}
+
+		msg := update.ChannelPost
+		if msg.Chat.ID != t.channelID || msg.Photo == nil || !strings.HasPrefix(msg.Caption, receiptCaptionHeader) {
+			return nil // Not a receipt
+		}
+
+		t.log.Info().Int("message_id", msg.MessageID).Msg("Received new receipt from queue")
+
+		newEvent, err := t.parseCaption(msg.Caption)
+		if err != nil {
+			t.log.Error().Err(err).Int("msg_id", msg.MessageID).Msg("Failed to parse receipt caption")
+			return nil
+		}
+		// The FileID is now the one the *Moderator Bot* can use
+		newEvent.FileID = msg.Photo[len(msg.Photo)-1].FileID
+
+		if err := handler(newEvent); err != nil {
+			t.log.Error().Err(err).Str("transaction_id", newEvent.TransactionID.String()).Msg("Queue handler failed to process receipt")
+			return err
+		}
+		return nil
+	}
+}
+
+// parseCaption is the reverse of the caption built in Publish.
+func (t *telegramReceiptQueue) parseCaption(caption string) (ports.DepositReceiptEvent, error) {
+	fields := map[string]string{}
+	for _, line := range strings.Split(caption, "\n") {
+		if key, value, ok := strings.Cut(line, ": "); ok {
+			fields[key] = value
+		}
+	}
+
+	txID, err := uuid.Parse(fields["TransactionID"])
+	if err != nil {
+		return ports.DepositReceiptEvent{}, fmt.Errorf("invalid TransactionID: %w", err)
+	}
+	userID, err := uuid.Parse(fields["UserID"])
+	if err != nil {
+		return ports.DepositReceiptEvent{}, fmt.Errorf("invalid UserID: %w", err)
+	}
+	leg := domain.TransactionLeg(fields["Leg"])
+	if leg != domain.LegSeller && leg != domain.LegBuyer {
+		return ports.DepositReceiptEvent{}, errors.New("invalid Leg")
+	}
+
+	return ports.DepositReceiptEvent{TransactionID: txID, UserID: userID, Leg: leg}, nil
+}
@@ -0,0 +1,439 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCallback(NewBidCallbackHandler)
+}
+
+// Keys used in user.StateData while a bid note is being written.
+const draftKeyBidRequest = "bid_request_id"
+
+// bidCallbackHandler handles every button of the bid lifecycle:
+// the bidder's side (view, place, note, abort) and the owner's side (accept, reject).
+type bidCallbackHandler struct {
+	log         zerolog.Logger
+	userRepo    ports.UserRepository
+	requestRepo ports.RequestRepository
+	bidRepo     ports.BidRepository
+	currencies  *domain.CurrencyRegistry
+	accounts    *services.PlatformAccountService
+	fees        *services.FeeEngine
+	limits      *services.LimitService
+	bot         ports.BotClientPort
+	transactor  ports.Transactor
+	bus         ports.EventBus
+}
+
+// NewBidCallbackHandler creates a new handler for "bid_" callbacks.
+func NewBidCallbackHandler(deps *customer.HandlerDeps) ports.CallbackHandler {
+	return &bidCallbackHandler{
+		log:         deps.BaseLogger.With().Str("component", "bid_callback").Logger(),
+		userRepo:    deps.UserRepo,
+		requestRepo: deps.RequestRepo,
+		bidRepo:     deps.BidRepo,
+		currencies:  deps.Cfg.Currencies,
+		accounts:    deps.PlatformAccounts,
+		fees:        deps.Fees,
+		limits:      deps.Limits,
+		bot:         deps.BotClient,
+		transactor:  deps.Transactor,
+		bus:         deps.Bus,
+	}
+}
+
+// Prefix returns the prefix this handler is responsible for.
+func (h *bidCallbackHandler) Prefix() string {
+	return "bid_"
+}
+
+// Handle processes "bid_<action>_<id>".
+// For view/place/note/abort the id is a request ID, for accept/reject it is a bid ID.
+func (h *bidCallbackHandler) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	// 1. Parse the callback data
+	parts := strings.Split(*update.CallbackData, "_")
+	if len(parts) != 3 {
+		log.Warn().Str("data", *update.CallbackData).Msg("Invalid bid callback format")
+		return h.answer(ctx, update, "")
+	}
+	action := parts[1]
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		log.Warn().Err(err).Str("data", *update.CallbackData).Msg("Failed to parse UUID from callback")
+		return h.answer(ctx, update, "")
+	}
+
+	if !user.VerificationStatus.IsVerified() {
+		log.Warn().Msg("Unverified user pressed a bid button")
+		return h.answer(ctx, update, "Only verified accounts can trade.")
+	}
+
+	// 2. Route the action
+	switch action {
+	case "view":
+		return h.handleView(ctx, update, user, id)
+	case "place":
+		return h.handlePlace(ctx, update, user, id)
+	case "note":
+		return h.handleNote(ctx, update, user, id)
+	case "abort":
+		return h.handleAbort(ctx, update, user)
+	case "accept":
+		return h.handleAccept(ctx, update, user, id)
+	case "reject":
+		return h.handleReject(ctx, update, user, id)
+	}
+
+	log.Warn().Str("data", *update.CallbackData).Msg("Unknown bid callback")
+	return h.answer(ctx, update, "")
+}
+
+// handleView shows the offer with the bid options.
+func (h *bidCallbackHandler) handleView(ctx context.Context, update *ports.BotUpdate, user *domain.User, requestID uuid.UUID) error {
+	req, reason := h.biddableRequest(ctx, user, requestID)
+	if req == nil {
+		return h.answer(ctx, update, reason)
+	}
+	h.answer(ctx, update, "")
+
+	_, err := h.bot.SendMessage(ctx, offerViewMessage(h.currencies, update.ChatID, req))
+	return err
+}
+
+// handlePlace creates a bid without a note.
+func (h *bidCallbackHandler) handlePlace(ctx context.Context, update *ports.BotUpdate, user *domain.User, requestID uuid.UUID) error {
+	h.answer(ctx, update, "")
+	reply := placeBid(ctx, h.log, h.requestRepo, h.bidRepo, h.limits, h.transactor, h.bus, user, requestID, nil)
+	return h.editMessage(ctx, update, reply)
+}
+
+// handleNote moves the bidder into the note step (see bid_note_flow.go).
+func (h *bidCallbackHandler) handleNote(ctx context.Context, update *ports.BotUpdate, user *domain.User, requestID uuid.UUID) error {
+	req, reason := h.biddableRequest(ctx, user, requestID)
+	if req == nil {
+		return h.answer(ctx, update, reason)
+	}
+	h.answer(ctx, update, "")
+
+	user.State = domain.StateAwaitingBidNote
+	user.StateData = map[string]string{draftKeyBidRequest: req.ID.String()}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to update user state for bid note")
+		return h.editMessage(ctx, update, "An internal error occurred.")
+	}
+
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:    update.ChatID,
+		MessageID: update.MessageID,
+		Text:      formatOfferDetails(h.currencies, req) + "\nPlease reply with your note \\(up to 500 characters\\)\\.",
+		ParseMode: "MarkdownV2",
+		ReplyMarkup: &ports.ReplyMarkup{
+			IsInline: true,
+			Buttons:  [][]ports.Button{{{Text: "✖️ Cancel", Data: "bid_abort_" + req.ID.String()}}},
+		},
+	})
+}
+
+// handleAbort leaves the note step without bidding.
+func (h *bidCallbackHandler) handleAbort(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	h.answer(ctx, update, "")
+
+	if user.State == domain.StateAwaitingBidNote {
+		user.State = domain.StateNone
+		user.StateData = map[string]string{}
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to clear bid note state")
+			return h.editMessage(ctx, update, "An internal error occurred.")
+		}
+	}
+	return h.editMessage(ctx, update, "Bid cancelled.")
+}
+
+// handleAccept lets the request owner accept a bid.
+// The bid, its competitors, the request, the new transaction and their
+// events are all saved in one DB transaction.
+func (h *bidCallbackHandler) handleAccept(ctx context.Context, update *ports.BotUpdate, owner *domain.User, bidID uuid.UUID) error {
+	log := h.log.With().Str("user_id", owner.ID.String()).Str("bid_id", bidID.String()).Logger()
+
+	bid, req, reason := h.ownedBid(ctx, owner, bidID)
+	if bid == nil {
+		return h.answer(ctx, update, reason)
+	}
+	h.answer(ctx, update, "")
+
+	// The owner's side of the trade follows the request type
+	tx := &domain.Transaction{
+		ID:        uuid.New(),
+		RequestID: req.ID,
+		Status:    domain.TxStatusPendingDeposits,
+	}
+	if req.Type == domain.RequestTypeSell {
+		tx.SellerUserID, tx.BuyerUserID = owner.ID, bid.UserID
+	} else {
+		tx.SellerUserID, tx.BuyerUserID = bid.UserID, owner.ID
+	}
+
+	bidder, err := h.userRepo.GetByID(ctx, bid.UserID)
+	if err != nil || bidder == nil {
+		log.Error().Err(err).Msg("Failed to load bidder")
+		return h.editMessage(ctx, update, "An internal error occurred. Please try again.")
+	}
+
+	// Fees are fixed now, each party at their own verification level
+	sellerLevel, buyerLevel := owner.VerificationStatus, bidder.VerificationStatus
+	if tx.SellerUserID == bidder.ID {
+		sellerLevel, buyerLevel = buyerLevel, sellerLevel
+	}
+	tx.ApplyFees(h.fees.Quote(req, sellerLevel, buyerLevel))
+
+	// Pick where each party deposits. A missing account is not fatal:
+	// the moderators then send the details by hand.
+	if acct, err := h.accounts.PickDepositAccount(ctx, req.BaseCurrency); err != nil {
+		log.Error().Err(err).Str("currency", req.BaseCurrency).Msg("Failed to pick platform deposit account")
+	} else if acct != nil {
+		tx.PlatformDepositBaseAccountID = &acct.ID
+	}
+	if acct, err := h.accounts.PickDepositAccount(ctx, req.QuoteCurrency); err != nil {
+		log.Error().Err(err).Str("currency", req.QuoteCurrency).Msg("Failed to pick platform deposit account")
+	} else if acct != nil {
+		tx.PlatformDepositQuoteAccountID = &acct.ID
+	}
+
+	var rejected []*domain.Bid
+	var bidderOverLimit bool
+	err = h.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// Both parties must still be within their limits; volumes may have
+		// moved since the request and the bid were made. Their locks are
+		// taken together and held until the trade is saved.
+		if err := h.limits.Lock(ctx, owner, bidder); err != nil {
+			return err
+		}
+		if err := h.limits.CheckTrade(ctx, owner, req); err != nil {
+			return err
+		}
+		if err := h.limits.CheckTrade(ctx, bidder, req); err != nil {
+			bidderOverLimit = limitProblem(err) != ""
+			return err
+		}
+
+		var err error
+		rejected, err = h.bidRepo.Accept(ctx, bid.ID, tx)
+		if err != nil {
+			return err
+		}
+
+		// Mirror the saved state before handing the structs to the bus
+		bid.Status = domain.BidStatusAccepted
+		req.Status = domain.RequestStatusMatched
+
+		if err := events.Publish(ctx, h.bus, events.BidAccepted{BidRef: events.NewBidRef(bid)}); err != nil {
+			return err
+		}
+		for _, other := range rejected {
+			if err := events.Publish(ctx, h.bus, events.BidRejected{BidRef: events.NewBidRef(other)}); err != nil {
+				return err
+			}
+		}
+		if err := events.PublishKeyed(ctx, h.bus, req.ID.String(), events.RequestMatched{Request: events.SnapshotRequest(req)}); err != nil {
+			return err
+		}
+		return events.Publish(ctx, h.bus, events.TransactionCreated{Transaction: events.SnapshotTransaction(tx)})
+	})
+	if bidderOverLimit {
+		return h.editMessage(ctx, update, "This bidder has reached their trading limit and cannot take this trade. Please pick another bid.")
+	}
+	if problem := limitProblem(err); problem != "" {
+		return h.editMessage(ctx, update, problem)
+	}
+	if errors.Is(err, ports.ErrRequestNotOpen) || errors.Is(err, ports.ErrBidNotPending) {
+		log.Warn().Err(err).Msg("Bid could not be accepted")
+		return h.editMessage(ctx, update, "This bid can no longer be accepted.")
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to accept bid")
+		return h.editMessage(ctx, update, "An internal error occurred. Please try again.")
+	}
+	log.Info().Str("transaction_id", tx.ID.String()).Int("rejected", len(rejected)).Msg("Bid accepted")
+
+	return h.editMessage(ctx, update, "✅ Bid accepted. Your trade has been opened and we will send you deposit instructions shortly.")
+}
+
+// handleReject lets the request owner reject a single bid.
+func (h *bidCallbackHandler) handleReject(ctx context.Context, update *ports.BotUpdate, owner *domain.User, bidID uuid.UUID) error {
+	log := h.log.With().Str("user_id", owner.ID.String()).Str("bid_id", bidID.String()).Logger()
+
+	bid, _, reason := h.ownedBid(ctx, owner, bidID)
+	if bid == nil {
+		return h.answer(ctx, update, reason)
+	}
+	h.answer(ctx, update, "")
+
+	err := h.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// Conditional, so a bid accepted or withdrawn meanwhile is left alone
+		if err := h.bidRepo.RejectPending(ctx, bid); err != nil {
+			return err
+		}
+		return events.Publish(ctx, h.bus, events.BidRejected{BidRef: events.NewBidRef(bid)})
+	})
+	if errors.Is(err, ports.ErrBidNotPending) {
+		return h.editMessage(ctx, update, "This bid is no longer pending.")
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to reject bid")
+		return h.editMessage(ctx, update, "An internal error occurred. Please try again.")
+	}
+	log.Info().Msg("Bid rejected")
+
+	return h.editMessage(ctx, update, "❌ Bid rejected.")
+}
+
+// biddableRequest loads an open request the user may bid on.
+// On failure it returns nil and the reason to show the user.
+func (h *bidCallbackHandler) biddableRequest(ctx context.Context, user *domain.User, requestID uuid.UUID) (*domain.Request, string) {
+	req, err := h.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		h.log.Error().Err(err).Str("request_id", requestID.String()).Msg("Failed to get request for bid")
+		return nil, "An internal error occurred."
+	}
+	if req == nil || req.Status != domain.RequestStatusOpen {
+		return nil, "This offer is no longer available."
+	}
+	if req.UserID == user.ID {
+		return nil, "You cannot bid on your own request."
+	}
+	return req, ""
+}
+
+// ownedBid loads a pending bid on one of the owner's requests.
+// On failure it returns nil and the reason to show the owner.
+func (h *bidCallbackHandler) ownedBid(ctx context.Context, owner *domain.User, bidID uuid.UUID) (*domain.Bid, *domain.Request, string) {
+	bid, err := h.bidRepo.GetByID(ctx, bidID)
+	if err != nil {
+		h.log.Error().Err(err).Str("bid_id", bidID.String()).Msg("Failed to get bid")
+		return nil, nil, "An internal error occurred."
+	}
+	if bid == nil {
+		return nil, nil, "This bid no longer exists."
+	}
+	req, err := h.requestRepo.GetByID(ctx, bid.RequestID)
+	if err != nil {
+		h.log.Error().Err(err).Str("request_id", bid.RequestID.String()).Msg("Failed to get request for bid")
+		return nil, nil, "An internal error occurred."
+	}
+	if req == nil || req.UserID != owner.ID {
+		h.log.Warn().Str("bid_id", bidID.String()).Msg("User tried to resolve a bid on someone else's request")
+		return nil, nil, "This bid is not on one of your requests."
+	}
+	if bid.Status != domain.BidStatusPending {
+		return nil, nil, "This bid has already been resolved."
+	}
+	return bid, req, ""
+}
+
+// answer stops the button spinner, showing text as a toast if given.
+func (h *bidCallbackHandler) answer(ctx context.Context, update *ports.BotUpdate, text string) error {
+	return h.bot.AnswerCallbackQuery(ctx, ports.AnswerCallbackParams{
+		CallbackQueryID: update.CallbackQueryID,
+		Text:            text,
+	})
+}
+
+// editMessage replaces the button message with plain text.
+func (h *bidCallbackHandler) editMessage(ctx context.Context, update *ports.BotUpdate, text string) error {
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:    update.ChatID,
+		MessageID: update.MessageID,
+		Text:      text,
+	})
+}
+
+// placeBid creates a pending bid and announces it on the bus.
+// It is shared by the "Place bid" button and the note step,
+// and returns the plain-text reply for the bidder.
+func placeBid(
+	ctx context.Context,
+	log zerolog.Logger,
+	requestRepo ports.RequestRepository,
+	bidRepo ports.BidRepository,
+	limits *services.LimitService,
+	transactor ports.Transactor,
+	bus ports.EventBus,
+	user *domain.User,
+	requestID uuid.UUID,
+	notes *string,
+) string {
+	log = log.With().Str("user_id", user.ID.String()).Str("request_id", requestID.String()).Logger()
+
+	req, err := requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get request for bid")
+		return "An internal error occurred."
+	}
+	if req == nil || req.Status != domain.RequestStatusOpen {
+		return "This offer is no longer available."
+	}
+	if req.UserID == user.ID {
+		return "You cannot bid on your own request."
+	}
+
+	bid := &domain.Bid{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		RequestID: req.ID,
+		Status:    domain.BidStatusPending,
+		Notes:     notes,
+	}
+	err = transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// The user's lock is held until the bid is saved
+		if err := limits.CheckTrade(ctx, user, req); err != nil {
+			return err
+		}
+		if err := bidRepo.Create(ctx, bid); err != nil {
+			return err
+		}
+		return events.Publish(ctx, bus, events.NewBidCreated(bid))
+	})
+	if problem := limitProblem(err); problem != "" {
+		return problem
+	}
+	if errors.Is(err, ports.ErrBidExists) {
+		return "You have already placed a bid on this offer."
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to save bid")
+		return "An internal error occurred. Please try again."
+	}
+	log.Info().Str("bid_id", bid.ID.String()).Msg("Bid placed")
+
+	return "✅ Your bid has been sent to the owner. We will notify you when they respond."
+}
+
+// formatOfferDetails renders a full MarkdownV2 description of an offer.
+func formatOfferDetails(currencies *domain.CurrencyRegistry, req *domain.Request) string {
+	var text strings.Builder
+	text.WriteString("💱 *Offer*\n\n")
+	text.WriteString(fmt.Sprintf("*Type:* %s\n", strings.ToUpper(string(req.Type))))
+	text.WriteString(fmt.Sprintf("*Amount:* %s %s\n",
+		messages.EscapeMarkdown(messages.FormatAmount(req.BaseAmount)), req.BaseCurrency))
+	text.WriteString(fmt.Sprintf("*Rate:* %s %s per %s\n",
+		messages.EscapeMarkdown(messages.FormatAmount(req.ExchangeRate)), req.QuoteCurrency, req.BaseCurrency))
+	text.WriteString(fmt.Sprintf("*Total:* %s %s\n",
+		messages.EscapeMarkdown(messages.FormatAmount(req.QuoteAmount(currencies).Amount)), req.QuoteCurrency))
+	return text.String()
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterState(NewBidNoteFlow)
+}
+
+// maxBidNoteLength is the longest note a bidder may attach, in characters.
+const maxBidNoteLength = 500
+
+// bidNoteFlow collects the optional note for a bid, then places it.
+type bidNoteFlow struct {
+	log         zerolog.Logger
+	userRepo    ports.UserRepository
+	requestRepo ports.RequestRepository
+	bidRepo     ports.BidRepository
+	limits      *services.LimitService
+	bot         ports.BotClientPort
+	transactor  ports.Transactor
+	bus         ports.EventBus
+}
+
+// NewBidNoteFlow creates the state handler for the bid note step.
+func NewBidNoteFlow(deps *customer.HandlerDeps) ports.StateHandler {
+	return &bidNoteFlow{
+		log:         deps.BaseLogger.With().Str("component", "bid_note_flow").Logger(),
+		userRepo:    deps.UserRepo,
+		requestRepo: deps.RequestRepo,
+		bidRepo:     deps.BidRepo,
+		limits:      deps.Limits,
+		bot:         deps.BotClient,
+		transactor:  deps.Transactor,
+		bus:         deps.Bus,
+	}
+}
+
+// States returns the user states owned by this flow.
+func (h *bidNoteFlow) States() []domain.UserState {
+	return []domain.UserState{domain.StateAwaitingBidNote}
+}
+
+// Handle takes the reply as the note and places the bid.
+func (h *bidNoteFlow) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	if update.Contact != nil || update.Photo != nil {
+		return h.sendMessage(ctx, update.ChatID, "Please reply with your note as text.")
+	}
+	note := strings.TrimSpace(update.Text)
+	if note == "" || utf8.RuneCountInString(note) > maxBidNoteLength {
+		return h.sendMessage(ctx, update.ChatID, "Please reply with a note of up to 500 characters.")
+	}
+
+	requestID, err := uuid.Parse(user.StateData[draftKeyBidRequest])
+	if err != nil {
+		log.Error().Err(err).Msg("Bid note state has no valid request ID")
+	}
+
+	// Leave the step first, whatever happens to the bid
+	user.State = domain.StateNone
+	user.StateData = map[string]string{}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		log.Error().Err(err).Msg("Failed to clear bid note state")
+		return h.sendMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+
+	if requestID == uuid.Nil {
+		return h.sendMessage(ctx, update.ChatID, "This offer is no longer available.")
+	}
+
+	reply := placeBid(ctx, h.log, h.requestRepo, h.bidRepo, h.limits, h.transactor, h.bus, user, requestID, &note)
+	return h.sendMessage(ctx, update.ChatID, reply)
+}
+
+// sendMessage is a helper to send a plain-text reply
+func (h *bidNoteFlow) sendMessage(ctx context.Context, chatID int64, message string) error {
+	msgParams := messages.NewBuilder(chatID).
+		WithText(message).
+		WithParseMode("").Build()
+	_, err := h.bot.SendMessage(ctx, msgParams)
+	return err
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCallback(NewCancelCallbackHandler)
+}
+
+// cancelCallbackHandler lets users withdraw their own requests, bids and unfunded trades.
+type cancelCallbackHandler struct {
+	log           zerolog.Logger
+	cancellations *services.CancellationService
+	bot           ports.BotClientPort
+}
+
+// NewCancelCallbackHandler creates a new handler for "cancel_" callbacks.
+func NewCancelCallbackHandler(deps *customer.HandlerDeps) ports.CallbackHandler {
+	return &cancelCallbackHandler{
+		log:           deps.BaseLogger.With().Str("component", "cancel_callback").Logger(),
+		cancellations: deps.Cancellations,
+		bot:           deps.BotClient,
+	}
+}
+
+// Prefix returns the prefix this handler is responsible for.
+func (h *cancelCallbackHandler) Prefix() string {
+	return "cancel_"
+}
+
+// Handle processes "cancel_<action>_<id>" where action is "req", "bid",
+// "tx" (ask to confirm), "txok" (confirmed) or "keep" (changed their mind).
+func (h *cancelCallbackHandler) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	// 1. Parse the callback data
+	parts := strings.Split(*update.CallbackData, "_")
+	if len(parts) != 3 {
+		log.Warn().Str("data", *update.CallbackData).Msg("Invalid cancel callback format")
+		return h.answer(ctx, update, "")
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		log.Warn().Err(err).Str("data", *update.CallbackData).Msg("Failed to parse UUID from callback")
+		return h.answer(ctx, update, "")
+	}
+
+	// 2. Route the action
+	switch parts[1] {
+	case "req":
+		return h.handleRequest(ctx, update, user, id)
+	case "bid":
+		return h.handleBid(ctx, update, user, id)
+	case "tx":
+		return h.handleAskTransaction(ctx, update, id)
+	case "txok":
+		return h.handleTransaction(ctx, update, user, id)
+	case "keep":
+		h.answer(ctx, update, "")
+		return h.editMessage(ctx, update, "The trade goes on.")
+	}
+
+	log.Warn().Str("data", *update.CallbackData).Msg("Unknown cancel callback")
+	return h.answer(ctx, update, "")
+}
+
+// handleRequest withdraws an open request.
+func (h *cancelCallbackHandler) handleRequest(ctx context.Context, update *ports.BotUpdate, user *domain.User, requestID uuid.UUID) error {
+	_, err := h.cancellations.CancelRequest(ctx, user.ID, requestID)
+	switch {
+	case errors.Is(err, services.ErrRequestNotFound), errors.Is(err, services.ErrNotOwner):
+		return h.answer(ctx, update, "This is not one of your requests.")
+	case errors.Is(err, ports.ErrRequestNotOpen):
+		return h.answer(ctx, update, "This request is no longer open. If it was matched, cancel the trade instead.")
+	case err != nil:
+		h.log.Error().Err(err).Str("request_id", requestID.String()).Msg("Failed to cancel request")
+		return h.answer(ctx, update, "An internal error occurred.")
+	}
+	h.answer(ctx, update, "Request withdrawn.")
+	return h.sendMessage(ctx, update.ChatID, "✖️ Your request was *withdrawn*\\. Any pending bids on it were cancelled\\.", nil)
+}
+
+// handleBid withdraws a pending bid.
+func (h *cancelCallbackHandler) handleBid(ctx context.Context, update *ports.BotUpdate, user *domain.User, bidID uuid.UUID) error {
+	_, err := h.cancellations.CancelBid(ctx, user.ID, bidID)
+	switch {
+	case errors.Is(err, services.ErrBidNotFound), errors.Is(err, services.ErrNotOwner):
+		return h.answer(ctx, update, "This is not one of your bids.")
+	case errors.Is(err, ports.ErrBidNotPending):
+		return h.answer(ctx, update, "This bid was already accepted or rejected.")
+	case err != nil:
+		h.log.Error().Err(err).Str("bid_id", bidID.String()).Msg("Failed to cancel bid")
+		return h.answer(ctx, update, "An internal error occurred.")
+	}
+	h.answer(ctx, update, "Bid withdrawn.")
+	return h.sendMessage(ctx, update.ChatID, "✖️ Your bid was *withdrawn*\\.", nil)
+}
+
+// handleAskTransaction asks before cancelling a trade, since it affects the other party.
+func (h *cancelCallbackHandler) handleAskTransaction(ctx context.Context, update *ports.BotUpdate, txID uuid.UUID) error {
+	h.answer(ctx, update, "")
+	return h.sendMessage(ctx, update.ChatID,
+		"Do you really want to *cancel this trade*? Only do this if you have not sent any money\\. The offer goes back to the order book\\.",
+		[][]ports.Button{{
+			{Text: "✖️ Yes, cancel", Data: "cancel_txok_" + txID.String()},
+			{Text: "◀️ No, keep it", Data: "cancel_keep_" + txID.String()},
+		}},
+	)
+}
+
+// handleTransaction cancels a trade nobody has paid into yet.
+func (h *cancelCallbackHandler) handleTransaction(ctx context.Context, update *ports.BotUpdate, user *domain.User, txID uuid.UUID) error {
+	h.answer(ctx, update, "")
+
+	_, err := h.cancellations.CancelTransaction(ctx, user.ID, txID)
+	switch {
+	case errors.Is(err, services.ErrTransactionNotFound), errors.Is(err, services.ErrNotOwner):
+		return h.editMessage(ctx, update, "This is not one of your trades.")
+	case errors.Is(err, services.ErrTransactionNotCancellable):
+		return h.editMessage(ctx, update, "This trade can no longer be cancelled because money has already been received. Use \"Report a problem\" if something is wrong.")
+	case err != nil:
+		h.log.Error().Err(err).Str("transaction_id", txID.String()).Msg("Failed to cancel transaction")
+		return h.editMessage(ctx, update, "An internal error occurred.")
+	}
+	return h.editMessage(ctx, update, "✖️ The trade was cancelled.")
+}
+
+// answer stops the button spinner, showing text as a toast if given.
+func (h *cancelCallbackHandler) answer(ctx context.Context, update *ports.BotUpdate, text string) error {
+	return h.bot.AnswerCallbackQuery(ctx, ports.AnswerCallbackParams{
+		CallbackQueryID: update.CallbackQueryID,
+		Text:            text,
+	})
+}
+
+// editMessage replaces the button message with plain text.
+func (h *cancelCallbackHandler) editMessage(ctx context.Context, update *ports.BotUpdate, text string) error {
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:    update.ChatID,
+		MessageID: update.MessageID,
+		Text:      text,
+	})
+}
+
+// sendMessage sends a MarkdownV2 message with optional inline buttons.
+func (h *cancelCallbackHandler) sendMessage(ctx context.Context, chatID int64, text string, buttons [][]ports.Button) error {
+	builder := messages.NewBuilder(chatID).WithText(text)
+	if buttons != nil {
+		builder = builder.WithInlineButtons(buttons)
+	}
+	_, err := h.bot.SendMessage(ctx, builder.Build())
+	return err
+}
+
+// cancelTradeButtonRow is the "cancel trade" button shown under an unfunded trade.
+func cancelTradeButtonRow(txID uuid.UUID) []ports.Button {
+	return []ports.Button{{Text: "✖️ Cancel trade", Data: "cancel_tx_" + txID.String()}}
+}
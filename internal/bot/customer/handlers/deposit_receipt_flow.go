@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterState(NewDepositReceiptFlow)
+}
+
+// depositReceiptFlow takes the receipt photo and queues it for moderator review.
+type depositReceiptFlow struct {
+	log      zerolog.Logger
+	userRepo ports.UserRepository
+	txRepo   ports.TransactionRepository
+	bot      ports.BotClientPort
+	queue    ports.ReceiptQueue
+}
+
+// NewDepositReceiptFlow creates the state handler for the receipt upload step.
+func NewDepositReceiptFlow(deps *customer.HandlerDeps) ports.StateHandler {
+	return &depositReceiptFlow{
+		log:      deps.BaseLogger.With().Str("component", "deposit_receipt_flow").Logger(),
+		userRepo: deps.UserRepo,
+		txRepo:   deps.TransactionRepo,
+		bot:      deps.BotClient,
+		queue:    deps.ReceiptQueue,
+	}
+}
+
+// States returns the user states owned by this flow.
+func (h *depositReceiptFlow) States() []domain.UserState {
+	return []domain.UserState{domain.StateAwaitingDepositReceipt}
+}
+
+// Handle publishes the photo to the receipt queue.
+func (h *depositReceiptFlow) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	if update.Photo == nil {
+		return h.sendMessage(ctx, update.ChatID, "Please upload a *photo* of your receipt, not text\\.")
+	}
+
+	rawTxID := user.StateData[draftKeyReceiptTx]
+
+	// Leave the step first, whatever happens to the receipt
+	user.State = domain.StateNone
+	user.StateData = map[string]string{}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		log.Error().Err(err).Msg("Failed to clear receipt upload state")
+		return h.sendMessage(ctx, update.ChatID, "An internal error occurred\\.")
+	}
+
+	txID, err := uuid.Parse(rawTxID)
+	if err != nil {
+		log.Error().Err(err).Msg("Receipt state has no valid transaction ID")
+		return h.sendMessage(ctx, update.ChatID, "We lost track of the trade for this receipt\\. Please start the upload again\\.")
+	}
+
+	// The trade may have moved on while we waited for the photo
+	tx, leg, reason := awaitedDeposit(ctx, h.log, h.txRepo, user, txID)
+	if tx == nil {
+		return h.sendMessage(ctx, update.ChatID, messages.EscapeMarkdown(reason))
+	}
+
+	log.Info().Str("transaction_id", tx.ID.String()).Str("leg", string(leg)).Msg("Received deposit receipt. Publishing to receipt queue...")
+	if _, err := h.queue.Publish(ctx, ports.DepositReceiptEvent{
+		TransactionID: tx.ID,
+		UserID:        user.ID,
+		Leg:           leg,
+		FileID:        update.Photo.FileID,
+	}); err != nil {
+		log.Error().Err(err).Msg("Failed to publish receipt to queue")
+		return h.sendMessage(ctx, update.ChatID, "We could not submit your receipt\\. Please try again later\\.")
+	}
+
+	return h.sendMessage(ctx, update.ChatID, "📨 Thank you\\! Your receipt has been submitted\\. We will notify you once a moderator has checked it\\.")
+}
+
+// sendMessage is a helper to send a MarkdownV2 reply
+func (h *depositReceiptFlow) sendMessage(ctx context.Context, chatID int64, text string) error {
+	_, err := h.bot.SendMessage(ctx, messages.NewBuilder(chatID).WithText(text).Build())
+	return err
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCallback(NewDisputeCallbackHandler)
+}
+
+// Keys used in user.StateData while a dispute statement is being collected.
+const (
+	draftKeyDisputeTx     = "dispute_tx_id"
+	draftKeyDisputeReason = "dispute_reason"
+)
+
+// disputeCallbackHandler starts and cancels the dispute flow.
+// The same button opens a dispute or, if one is already open, adds the user's side to it.
+// The statement and photo are handled by dispute_flow.go.
+type disputeCallbackHandler struct {
+	log         zerolog.Logger
+	userRepo    ports.UserRepository
+	txRepo      ports.TransactionRepository
+	disputeRepo ports.DisputeRepository
+	bot         ports.BotClientPort
+}
+
+// NewDisputeCallbackHandler creates a new handler for "dispute_" callbacks.
+func NewDisputeCallbackHandler(deps *customer.HandlerDeps) ports.CallbackHandler {
+	return &disputeCallbackHandler{
+		log:         deps.BaseLogger.With().Str("component", "dispute_callback").Logger(),
+		userRepo:    deps.UserRepo,
+		txRepo:      deps.TransactionRepo,
+		disputeRepo: deps.DisputeRepo,
+		bot:         deps.BotClient,
+	}
+}
+
+// Prefix returns the prefix this handler is responsible for.
+func (h *disputeCallbackHandler) Prefix() string {
+	return "dispute_"
+}
+
+// Handle processes "dispute_open_<txid>" and "dispute_abort_<txid>".
+func (h *disputeCallbackHandler) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	// 1. Parse the callback data
+	parts := strings.Split(*update.CallbackData, "_")
+	if len(parts) != 3 {
+		log.Warn().Str("data", *update.CallbackData).Msg("Invalid dispute callback format")
+		return h.answer(ctx, update, "")
+	}
+	txID, err := uuid.Parse(parts[2])
+	if err != nil {
+		log.Warn().Err(err).Str("data", *update.CallbackData).Msg("Failed to parse UUID from callback")
+		return h.answer(ctx, update, "")
+	}
+
+	// 2. Route the action
+	switch parts[1] {
+	case "open":
+		return h.handleOpen(ctx, update, user, txID)
+	case "abort":
+		return h.handleAbort(ctx, update, user)
+	}
+
+	log.Warn().Str("data", *update.CallbackData).Msg("Unknown dispute callback")
+	return h.answer(ctx, update, "")
+}
+
+// handleOpen moves the party into the statement step.
+func (h *disputeCallbackHandler) handleOpen(ctx context.Context, update *ports.BotUpdate, user *domain.User, txID uuid.UUID) error {
+	tx, dispute, reason := disputableTransaction(ctx, h.log, h.txRepo, h.disputeRepo, user, txID)
+	if tx == nil {
+		return h.answer(ctx, update, reason)
+	}
+	h.answer(ctx, update, "")
+
+	user.State = domain.StateAwaitingDisputeReason
+	user.StateData = map[string]string{draftKeyDisputeTx: tx.ID.String()}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to update user state for dispute")
+		return h.sendMessage(ctx, update.ChatID, "An internal error occurred\\.", nil)
+	}
+
+	prompt := "⚠️ *Open a dispute*\n\nPlease describe the problem in a few sentences\\. " +
+		"While the dispute is open, the trade is paused until a moderator decides\\."
+	if dispute != nil {
+		prompt = "⚠️ *Dispute on this trade*\n\nPlease tell us your side of the story in a few sentences\\."
+	}
+	return h.sendMessage(ctx, update.ChatID, prompt,
+		[][]ports.Button{{{Text: "✖️ Cancel", Data: "dispute_abort_" + tx.ID.String()}}},
+	)
+}
+
+// handleAbort leaves the dispute flow.
+func (h *disputeCallbackHandler) handleAbort(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	h.answer(ctx, update, "")
+
+	if isDisputeState(user.State) {
+		user.State = domain.StateNone
+		user.StateData = map[string]string{}
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to clear dispute state")
+			return h.editMessage(ctx, update, "An internal error occurred.")
+		}
+	}
+	return h.editMessage(ctx, update, "Dispute cancelled.")
+}
+
+// answer stops the button spinner, showing text as a toast if given.
+func (h *disputeCallbackHandler) answer(ctx context.Context, update *ports.BotUpdate, text string) error {
+	return h.bot.AnswerCallbackQuery(ctx, ports.AnswerCallbackParams{
+		CallbackQueryID: update.CallbackQueryID,
+		Text:            text,
+	})
+}
+
+// editMessage replaces the button message with plain text.
+func (h *disputeCallbackHandler) editMessage(ctx context.Context, update *ports.BotUpdate, text string) error {
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:    update.ChatID,
+		MessageID: update.MessageID,
+		Text:      text,
+	})
+}
+
+// sendMessage sends a MarkdownV2 message with optional inline buttons.
+func (h *disputeCallbackHandler) sendMessage(ctx context.Context, chatID int64, text string, buttons [][]ports.Button) error {
+	builder := messages.NewBuilder(chatID).WithText(text)
+	if buttons != nil {
+		builder = builder.WithInlineButtons(buttons)
+	}
+	_, err := h.bot.SendMessage(ctx, builder.Build())
+	return err
+}
+
+// disputeButtonRow is the "report a problem" button shown under a trade.
+func disputeButtonRow(txID uuid.UUID) []ports.Button {
+	return []ports.Button{{Text: "⚠️ Report a problem", Data: "dispute_open_" + txID.String()}}
+}
+
+// isDisputeState reports whether the state belongs to the dispute flow.
+func isDisputeState(state domain.UserState) bool {
+	return state == domain.StateAwaitingDisputeReason || state == domain.StateAwaitingDisputeEvidence
+}
+
+// disputableTransaction loads a transaction the user may dispute or add evidence to.
+// dispute is the open dispute, or nil if the user would be opening one.
+// On failure it returns a nil transaction and a plain-text reason for the user.
+func disputableTransaction(
+	ctx context.Context,
+	log zerolog.Logger,
+	txRepo ports.TransactionRepository,
+	disputeRepo ports.DisputeRepository,
+	user *domain.User,
+	txID uuid.UUID,
+) (*domain.Transaction, *domain.Dispute, string) {
+	tx, err := txRepo.GetByID(ctx, txID)
+	if err != nil {
+		log.Error().Err(err).Str("transaction_id", txID.String()).Msg("Failed to get transaction")
+		return nil, nil, "An internal error occurred."
+	}
+	if tx == nil {
+		return nil, nil, "This trade no longer exists."
+	}
+	if _, ok := legOf(tx, user.ID); !ok {
+		return nil, nil, "This is not one of your trades."
+	}
+
+	if tx.Status == domain.TxStatusDisputed {
+		dispute, err := disputeRepo.GetOpenByTransactionID(ctx, tx.ID)
+		if err != nil {
+			log.Error().Err(err).Str("transaction_id", txID.String()).Msg("Failed to get open dispute")
+			return nil, nil, "An internal error occurred."
+		}
+		if dispute == nil {
+			return nil, nil, "This dispute is already being handled by a moderator."
+		}
+		return tx, dispute, ""
+	}
+	if !services.CanTransition(tx.Status, domain.TxStatusDisputed) {
+		return nil, nil, "This trade can no longer be disputed."
+	}
+	return tx, nil, ""
+}
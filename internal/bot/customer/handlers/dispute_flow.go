@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterState(NewDisputeFlow)
+}
+
+// maxDisputeReasonLength is the longest dispute statement, in characters.
+// Statements of both parties share one moderator caption, which Telegram caps at 1024.
+const maxDisputeReasonLength = 300
+
+// disputeFlow collects the statement and the photo evidence,
+// then opens the dispute (or joins the open one) and queues the photo for moderators.
+type disputeFlow struct {
+	log         zerolog.Logger
+	userRepo    ports.UserRepository
+	txRepo      ports.TransactionRepository
+	disputeRepo ports.DisputeRepository
+	disputes    *services.DisputeService
+	transactor  ports.Transactor
+	bot         ports.BotClientPort
+	queue       ports.EvidenceQueue
+}
+
+// NewDisputeFlow creates the state handler for the dispute steps.
+func NewDisputeFlow(deps *customer.HandlerDeps) ports.StateHandler {
+	return &disputeFlow{
+		log:         deps.BaseLogger.With().Str("component", "dispute_flow").Logger(),
+		userRepo:    deps.UserRepo,
+		txRepo:      deps.TransactionRepo,
+		disputeRepo: deps.DisputeRepo,
+		disputes:    deps.Disputes,
+		transactor:  deps.Transactor,
+		bot:         deps.BotClient,
+		queue:       deps.EvidenceQueue,
+	}
+}
+
+// States returns the user states owned by this flow.
+func (h *disputeFlow) States() []domain.UserState {
+	return []domain.UserState{
+		domain.StateAwaitingDisputeReason,
+		domain.StateAwaitingDisputeEvidence,
+	}
+}
+
+// Handle routes the update to the current step.
+func (h *disputeFlow) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	if user.State == domain.StateAwaitingDisputeReason {
+		return h.handleReason(ctx, update, user)
+	}
+	return h.handleEvidence(ctx, update, user)
+}
+
+// handleReason stores the statement and asks for the photo.
+func (h *disputeFlow) handleReason(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	if update.Contact != nil || update.Photo != nil {
+		return h.sendMessage(ctx, update.ChatID, "Please describe the problem as text first\\.")
+	}
+	reason := strings.TrimSpace(update.Text)
+	if reason == "" || utf8.RuneCountInString(reason) > maxDisputeReasonLength {
+		return h.sendMessage(ctx, update.ChatID, "Please describe the problem in up to 300 characters\\.")
+	}
+
+	user.State = domain.StateAwaitingDisputeEvidence
+	user.StateData[draftKeyDisputeReason] = reason
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to save dispute reason")
+		return h.sendMessage(ctx, update.ChatID, "An internal error occurred\\.")
+	}
+
+	return h.sendMessage(ctx, update.ChatID,
+		"Now please send a *photo* that supports your case, for example a receipt or a bank statement\\.")
+}
+
+// handleEvidence takes the photo, opens or joins the dispute and queues the photo.
+func (h *disputeFlow) handleEvidence(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	if update.Photo == nil {
+		return h.sendMessage(ctx, update.ChatID, "Please upload a *photo* as evidence, not text\\.")
+	}
+
+	rawTxID := user.StateData[draftKeyDisputeTx]
+	reason := user.StateData[draftKeyDisputeReason]
+
+	txID, err := uuid.Parse(rawTxID)
+	if err != nil {
+		log.Error().Err(err).Msg("Dispute state has no valid transaction ID")
+		h.leaveFlow(ctx, log, user)
+		return h.sendMessage(ctx, update.ChatID, "We lost track of the trade you are disputing\\. Please open the dispute again\\.")
+	}
+
+	// The trade may have moved on while we waited for the photo
+	tx, dispute, problem := disputableTransaction(ctx, h.log, h.txRepo, h.disputeRepo, user, txID)
+	if tx == nil {
+		h.leaveFlow(ctx, log, user)
+		return h.sendMessage(ctx, update.ChatID, messages.EscapeMarkdown(problem))
+	}
+	leg, _ := legOf(tx, user.ID)
+	log = log.With().Str("transaction_id", tx.ID.String()).Logger()
+
+	// Open the dispute, save the evidence and leave the flow together, so a
+	// dispute never goes without the evidence that opened it
+	opened := dispute == nil
+	var evidence *domain.DisputeEvidence
+	err = h.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if opened {
+			var err error
+			if dispute, err = h.disputes.Open(ctx, tx.ID, user.ID, reason); err != nil {
+				return err
+			}
+		}
+		evidence = &domain.DisputeEvidence{
+			ID:        uuid.New(),
+			DisputeID: dispute.ID,
+			UserID:    user.ID,
+			Leg:       leg,
+			Statement: reason,
+			FileID:    update.Photo.FileID,
+		}
+		if err := h.disputeRepo.AddEvidence(ctx, evidence); err != nil {
+			return err
+		}
+		user.State = domain.StateNone
+		user.StateData = map[string]string{}
+		return h.userRepo.Update(ctx, user)
+	})
+	var illegal *services.IllegalTransitionError
+	switch {
+	case errors.As(err, &illegal), errors.Is(err, ports.ErrTransactionStatusChanged):
+		h.leaveFlow(ctx, log, user)
+		return h.sendMessage(ctx, update.ChatID, "This trade can no longer be disputed\\.")
+	case err != nil:
+		// Nothing was saved and the user is still in the flow, so the photo can be sent again
+		log.Error().Err(err).Bool("opening", opened).Msg("Failed to save dispute evidence")
+		user.State = domain.StateAwaitingDisputeEvidence
+		user.StateData = map[string]string{draftKeyDisputeTx: rawTxID, draftKeyDisputeReason: reason}
+		return h.sendMessage(ctx, update.ChatID, "We could not save your evidence\\. Please send the photo again in a moment\\.")
+	}
+
+	log.Info().Str("dispute_id", dispute.ID.String()).Msg("Received dispute evidence. Publishing to evidence queue...")
+	if _, err := h.queue.Publish(ctx, ports.DisputeEvidenceEvent{
+		DisputeID:  dispute.ID,
+		EvidenceID: evidence.ID,
+		FileID:     evidence.FileID,
+	}); err != nil {
+		log.Error().Err(err).Msg("Failed to publish evidence to queue")
+	}
+
+	if opened {
+		return h.sendMessage(ctx, update.ChatID,
+			"⚠️ Your dispute has been *opened*\\. The trade is paused and a moderator will review the case\\. We will notify you of the outcome\\.")
+	}
+	return h.sendMessage(ctx, update.ChatID,
+		"📨 Thank you\\! Your evidence has been added to the dispute\\. We will notify you of the outcome\\.")
+}
+
+// leaveFlow ends the dispute steps when the dispute cannot go ahead.
+func (h *disputeFlow) leaveFlow(ctx context.Context, log zerolog.Logger, user *domain.User) {
+	user.State = domain.StateNone
+	user.StateData = map[string]string{}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		log.Error().Err(err).Msg("Failed to clear dispute state")
+	}
+}
+
+// sendMessage is a helper to send a MarkdownV2 reply
+func (h *disputeFlow) sendMessage(ctx context.Context, chatID int64, text string) error {
+	_, err := h.bot.SendMessage(ctx, messages.NewBuilder(chatID).WithText(text).Build())
+	return err
+}
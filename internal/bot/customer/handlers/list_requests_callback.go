@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCallback(NewListRequestsCallbackHandler)
+}
+
+// listRequestsCallbackHandler handles paging and filter buttons of the /listrequests message.
+// Every action edits the same message in place.
+type listRequestsCallbackHandler struct {
+	log         zerolog.Logger
+	requestRepo ports.RequestRepository
+	bot         ports.BotClientPort
+}
+
+// NewListRequestsCallbackHandler creates a new handler for "list_" callbacks.
+func NewListRequestsCallbackHandler(deps *customer.HandlerDeps) ports.CallbackHandler {
+	return &listRequestsCallbackHandler{
+		log:         deps.BaseLogger.With().Str("component", "list_requests_callback").Logger(),
+		requestRepo: deps.RequestRepo,
+		bot:         deps.BotClient,
+	}
+}
+
+// Prefix returns the prefix this handler is responsible for.
+func (h *listRequestsCallbackHandler) Prefix() string {
+	return "list_"
+}
+
+// Handle processes "list_p_<query>" (show a page),
+// "list_fb_<query>" and "list_fq_<query>" (pick a base/quote currency).
+func (h *listRequestsCallbackHandler) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	// 1. Answer the callback to stop the spinner
+	h.bot.AnswerCallbackQuery(ctx, ports.AnswerCallbackParams{
+		CallbackQueryID: update.CallbackQueryID,
+	})
+
+	// 2. Parse the callback data
+	data := strings.TrimPrefix(*update.CallbackData, "list_")
+	action, encoded, ok := strings.Cut(data, "_")
+	if !ok {
+		log.Warn().Str("data", *update.CallbackData).Msg("Invalid list callback format")
+		return nil
+	}
+	q, err := decodeListQuery(encoded)
+	if err != nil {
+		log.Warn().Err(err).Str("data", *update.CallbackData).Msg("Invalid list query")
+		return nil
+	}
+
+	switch action {
+	case "p":
+		return h.showPage(ctx, update, q)
+	case "fb":
+		return h.showCurrencyPicker(ctx, update, q, true)
+	case "fq":
+		return h.showCurrencyPicker(ctx, update, q, false)
+	}
+
+	log.Warn().Str("data", *update.CallbackData).Msg("Unknown list callback")
+	return nil
+}
+
+// showPage re-renders the order book for the given query.
+func (h *listRequestsCallbackHandler) showPage(ctx context.Context, update *ports.BotUpdate, q listQuery) error {
+	text, buttons, err := renderRequestPage(ctx, h.requestRepo, q)
+	if err != nil {
+		h.log.Error().Err(err).Msg("Failed to render order book")
+		return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+			ChatID:    update.ChatID,
+			MessageID: update.MessageID,
+			Text:      "An internal error occurred.",
+		})
+	}
+
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:      update.ChatID,
+		MessageID:   update.MessageID,
+		Text:        text,
+		ParseMode:   "MarkdownV2",
+		ReplyMarkup: &ports.ReplyMarkup{IsInline: true, Buttons: buttons},
+	})
+}
+
+// showCurrencyPicker replaces the keyboard with the currencies currently on offer.
+func (h *listRequestsCallbackHandler) showCurrencyPicker(ctx context.Context, update *ports.BotUpdate, q listQuery, isBase bool) error {
+	base, quote, err := h.requestRepo.ListOpenCurrencies(ctx)
+	if err != nil {
+		h.log.Error().Err(err).Msg("Failed to list open currencies")
+		return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+			ChatID:    update.ChatID,
+			MessageID: update.MessageID,
+			Text:      "An internal error occurred.",
+		})
+	}
+
+	codes, title := quote, "Pick the *quote* currency"
+	if isBase {
+		codes, title = base, "Pick the *base* currency"
+	}
+
+	// "Any" clears this side of the filter
+	choice := q
+	if isBase {
+		choice.Filter.BaseCurrency = ""
+	} else {
+		choice.Filter.QuoteCurrency = ""
+	}
+	choices := []string{"list_p_" + choice.encode()}
+	labels := []string{"Any"}
+	for _, code := range codes {
+		if isBase {
+			choice.Filter.BaseCurrency = code
+		} else {
+			choice.Filter.QuoteCurrency = code
+		}
+		choices = append(choices, "list_p_"+choice.encode())
+		labels = append(labels, code)
+	}
+
+	// Lay the choices out in rows of 4
+	var buttons [][]ports.Button
+	var row []ports.Button
+	for i := range choices {
+		row = append(row, ports.Button{Text: labels[i], Data: choices[i]})
+		if len(row) == 4 || i == len(choices)-1 {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	buttons = append(buttons, []ports.Button{{Text: "◀️ Back", Data: "list_p_" + q.encode()}})
+
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:      update.ChatID,
+		MessageID:   update.MessageID,
+		Text:        "📊 *Open Requests*\n\n" + title + ":",
+		ParseMode:   "MarkdownV2",
+		ReplyMarkup: &ports.ReplyMarkup{IsInline: true, Buttons: buttons},
+	})
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCommand(NewListRequestsHandler)
+}
+
+// listPageSize is the number of offers shown on one page of the order book.
+const listPageSize = 5
+
+// listQuery is the current page and filter of an order book message.
+// It travels inside the callback data, so the browser needs no server-side state.
+type listQuery struct {
+	Page   int
+	Filter ports.RequestFilter
+}
+
+// encode packs the query as "<page>_<base>_<quote>_<type>", using "-" for "any".
+// The longest result ("999_EUR_IRR_sell") keeps us far below Telegram's 64-byte limit.
+func (q listQuery) encode() string {
+	return fmt.Sprintf("%d_%s_%s_%s",
+		q.Page,
+		orAny(q.Filter.BaseCurrency),
+		orAny(q.Filter.QuoteCurrency),
+		orAny(string(q.Filter.Type)),
+	)
+}
+
+// decodeListQuery is the reverse of listQuery.encode.
+func decodeListQuery(data string) (listQuery, error) {
+	parts := strings.Split(data, "_")
+	if len(parts) != 4 {
+		return listQuery{}, errors.New("invalid list query format")
+	}
+	page, err := strconv.Atoi(parts[0])
+	if err != nil || page < 0 {
+		return listQuery{}, errors.New("invalid page number")
+	}
+	reqType := domain.RequestType(fromAny(parts[3]))
+	if reqType != "" && reqType != domain.RequestTypeSell && reqType != domain.RequestTypeBuy {
+		return listQuery{}, errors.New("invalid request type")
+	}
+	return listQuery{
+		Page: page,
+		Filter: ports.RequestFilter{
+			BaseCurrency:  fromAny(parts[1]),
+			QuoteCurrency: fromAny(parts[2]),
+			Type:          reqType,
+		},
+	}, nil
+}
+
+func orAny(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func fromAny(s string) string {
+	if s == "-" {
+		return ""
+	}
+	return s
+}
+
+// listRequestsHandler is the plugin for the /listrequests command.
+// It sends the first page; list_requests_callback.go handles paging and filters.
+type listRequestsHandler struct {
+	log         zerolog.Logger
+	userRepo    ports.UserRepository
+	requestRepo ports.RequestRepository
+	bot         ports.BotClientPort
+}
+
+// NewListRequestsHandler creates a new handler for the /listrequests command.
+func NewListRequestsHandler(deps *customer.HandlerDeps) ports.CommandHandler {
+	return &listRequestsHandler{
+		log:         deps.BaseLogger.With().Str("component", "list_requests_handler").Logger(),
+		userRepo:    deps.UserRepo,
+		requestRepo: deps.RequestRepo,
+		bot:         deps.BotClient,
+	}
+}
+
+// Command returns the command string (without the "/")
+func (h *listRequestsHandler) Command() string {
+	return "listrequests"
+}
+
+// Handle sends the first, unfiltered page of open requests.
+func (h *listRequestsHandler) Handle(ctx context.Context, update *ports.BotUpdate) error {
+	log := h.log.With().Int64("user_id", update.UserID).Logger()
+
+	user, err := h.userRepo.GetByTelegramID(ctx, update.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get user from repository")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+	if user == nil {
+		msg := messages.NewBuilder(update.ChatID).
+			WithText("Please type /start to begin\\.").
+			Build()
+		_, err := h.bot.SendMessage(ctx, msg)
+		return err
+	}
+
+	text, buttons, err := renderRequestPage(ctx, h.requestRepo, listQuery{})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to render order book")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+
+	msg := messages.NewBuilder(update.ChatID).
+		WithText(text).
+		WithInlineButtons(buttons).
+		Build()
+	_, err = h.bot.SendMessage(ctx, msg)
+	return err
+}
+
+// sendErrorMessage is a helper to send a generic error
+func (h *listRequestsHandler) sendErrorMessage(ctx context.Context, chatID int64, message string) error {
+	msgParams := messages.NewBuilder(chatID).
+		WithText(message).
+		WithParseMode("").Build()
+	_, err := h.bot.SendMessage(ctx, msgParams)
+	return err
+}
+
+// renderRequestPage builds the MarkdownV2 text and inline keyboard for one page of the order book.
+// A page past the end is clamped to the last page.
+func renderRequestPage(ctx context.Context, repo ports.RequestRepository, q listQuery) (string, [][]ports.Button, error) {
+	requests, total, err := repo.ListOpen(ctx, q.Filter, listPageSize, q.Page*listPageSize)
+	if err != nil {
+		return "", nil, err
+	}
+	pages := (total + listPageSize - 1) / listPageSize
+	if pages > 0 && q.Page >= pages {
+		// The book shrank since this message was sent
+		q.Page = pages - 1
+		requests, total, err = repo.ListOpen(ctx, q.Filter, listPageSize, q.Page*listPageSize)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	var text strings.Builder
+	text.WriteString("📊 *Open Requests*\n")
+	text.WriteString(fmt.Sprintf("Base: *%s* · Quote: *%s* · Type: *%s*\n\n",
+		filterLabel(q.Filter.BaseCurrency),
+		filterLabel(q.Filter.QuoteCurrency),
+		filterLabel(strings.ToUpper(string(q.Filter.Type))),
+	))
+
+	var buttons [][]ports.Button
+	if total == 0 {
+		text.WriteString("No open requests match these filters\\.")
+	} else {
+		for i, req := range requests {
+			n := q.Page*listPageSize + i + 1
+			text.WriteString(fmt.Sprintf("*%d\\.* %s\n", n, formatRequestLine(req)))
+			buttons = append(buttons, []ports.Button{
+				{Text: fmt.Sprintf("💰 Bid on #%d", n), Data: "bid_view_" + req.ID.String()},
+			})
+		}
+		text.WriteString(fmt.Sprintf("\nPage %d of %d", q.Page+1, pages))
+	}
+
+	// Navigation row
+	var nav []ports.Button
+	if q.Page > 0 {
+		prev := q
+		prev.Page--
+		nav = append(nav, ports.Button{Text: "◀️ Prev", Data: "list_p_" + prev.encode()})
+	}
+	if q.Page+1 < pages {
+		next := q
+		next.Page++
+		nav = append(nav, ports.Button{Text: "Next ▶️", Data: "list_p_" + next.encode()})
+	}
+	if len(nav) > 0 {
+		buttons = append(buttons, nav)
+	}
+
+	// Filter row. Changing a filter always goes back to the first page.
+	first := q
+	first.Page = 0
+	typeToggle := first
+	switch q.Filter.Type {
+	case "":
+		typeToggle.Filter.Type = domain.RequestTypeSell
+	case domain.RequestTypeSell:
+		typeToggle.Filter.Type = domain.RequestTypeBuy
+	default:
+		typeToggle.Filter.Type = ""
+	}
+	buttons = append(buttons, []ports.Button{
+		{Text: "Base: " + filterLabel(q.Filter.BaseCurrency), Data: "list_fb_" + first.encode()},
+		{Text: "Quote: " + filterLabel(q.Filter.QuoteCurrency), Data: "list_fq_" + first.encode()},
+		{Text: "Type: " + filterLabel(strings.ToUpper(string(q.Filter.Type))), Data: "list_p_" + typeToggle.encode()},
+	})
+	if q.Filter != (ports.RequestFilter{}) {
+		buttons = append(buttons, []ports.Button{
+			{Text: "✖️ Clear filters", Data: "list_p_" + listQuery{}.encode()},
+		})
+	}
+
+	return text.String(), buttons, nil
+}
+
+// formatRequestLine renders one offer as a single MarkdownV2 line.
+func formatRequestLine(req *domain.Request) string {
+	return fmt.Sprintf("%s %s %s @ %s %s",
+		strings.ToUpper(string(req.Type)),
+		messages.EscapeMarkdown(messages.FormatAmount(req.BaseAmount)),
+		req.BaseCurrency,
+		messages.EscapeMarkdown(messages.FormatAmount(req.ExchangeRate)),
+		req.QuoteCurrency,
+	)
+}
+
+// filterLabel shows "any" for an empty filter field.
+func filterLabel(value string) string {
+	if value == "" {
+		return "any"
+	}
+	return value
+}
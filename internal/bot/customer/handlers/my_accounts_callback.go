@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCallback(NewMyAccountsCallbackHandler)
+}
+
+// myAccountsCallbackHandler handles the buttons of the /myaccounts message.
+type myAccountsCallbackHandler struct {
+	log      zerolog.Logger
+	userRepo ports.UserRepository
+	bankRepo ports.UserBankAccountRepository
+	bot      ports.BotClientPort
+}
+
+// NewMyAccountsCallbackHandler creates a new handler for "acct_" callbacks.
+func NewMyAccountsCallbackHandler(deps *customer.HandlerDeps) ports.CallbackHandler {
+	return &myAccountsCallbackHandler{
+		log:      deps.BaseLogger.With().Str("component", "my_accounts_callback").Logger(),
+		userRepo: deps.UserRepo,
+		bankRepo: deps.BankAccountRepo,
+		bot:      deps.BotClient,
+	}
+}
+
+// Prefix returns the prefix this handler is responsible for.
+func (h *myAccountsCallbackHandler) Prefix() string {
+	return "acct_"
+}
+
+// Handle processes "acct_add", "acct_list", "acct_cancel",
+// "acct_ren_<id>", "acct_del_<id>" and "acct_delok_<id>".
+func (h *myAccountsCallbackHandler) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	// 1. Answer the callback to stop the spinner
+	h.bot.AnswerCallbackQuery(ctx, ports.AnswerCallbackParams{
+		CallbackQueryID: update.CallbackQueryID,
+	})
+
+	if !user.VerificationStatus.IsVerified() {
+		log.Warn().Msg("Unverified user pressed an account button")
+		return h.editMessage(ctx, update, "Only verified accounts can manage payout accounts.")
+	}
+
+	// 2. Parse the callback data
+	data := strings.TrimPrefix(*update.CallbackData, "acct_")
+	switch data {
+	case "add":
+		return h.handleAdd(ctx, update, user)
+	case "list":
+		return h.showList(ctx, update, user)
+	case "cancel":
+		return h.handleCancel(ctx, update, user)
+	}
+
+	action, idStr, ok := strings.Cut(data, "_")
+	if !ok {
+		log.Warn().Str("data", *update.CallbackData).Msg("Invalid account callback format")
+		return nil
+	}
+	acctID, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn().Err(err).Str("data", *update.CallbackData).Msg("Failed to parse UUID from callback")
+		return nil
+	}
+
+	// Every per-account action starts with the ownership check
+	acct, err := h.bankRepo.GetByID(ctx, acctID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get bank account")
+		return h.editMessage(ctx, update, "An internal error occurred.")
+	}
+	if acct == nil || acct.UserID != user.ID {
+		log.Warn().Str("acct_id", acctID.String()).Msg("Account not found or not owned by user")
+		return h.showList(ctx, update, user)
+	}
+
+	switch action {
+	case "ren":
+		return h.handleRename(ctx, update, user, acct)
+	case "del":
+		return h.handleDelete(ctx, update, acct)
+	case "delok":
+		return h.handleDeleteConfirmed(ctx, update, user, acct)
+	}
+
+	log.Warn().Str("data", *update.CallbackData).Msg("Unknown account callback")
+	return nil
+}
+
+// handleAdd starts the add-account steps.
+func (h *myAccountsCallbackHandler) handleAdd(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	user.State = domain.StateAwaitingAccountName
+	user.StateData = map[string]string{}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to start add account flow")
+		return h.editMessage(ctx, update, "An internal error occurred.")
+	}
+
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:      update.ChatID,
+		MessageID:   update.MessageID,
+		Text:        "➕ *New Payout Account*\n\nWhat should we call this account? \\(e\\.g\\. `My N26`\\)",
+		ParseMode:   "MarkdownV2",
+		ReplyMarkup: cancelAccountMarkup(),
+	})
+}
+
+// handleRename asks for the new name of an account.
+func (h *myAccountsCallbackHandler) handleRename(ctx context.Context, update *ports.BotUpdate, user *domain.User, acct *domain.UserBankAccount) error {
+	user.State = domain.StateAwaitingAccountRename
+	user.StateData = map[string]string{draftKeyAccountID: acct.ID.String()}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to start rename account flow")
+		return h.editMessage(ctx, update, "An internal error occurred.")
+	}
+
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:    update.ChatID,
+		MessageID: update.MessageID,
+		Text: fmt.Sprintf("✏️ Reply with the new name for *%s*\\.",
+			messages.EscapeMarkdown(acct.AccountName)),
+		ParseMode:   "MarkdownV2",
+		ReplyMarkup: cancelAccountMarkup(),
+	})
+}
+
+// handleDelete asks for confirmation.
+func (h *myAccountsCallbackHandler) handleDelete(ctx context.Context, update *ports.BotUpdate, acct *domain.UserBankAccount) error {
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:    update.ChatID,
+		MessageID: update.MessageID,
+		Text: fmt.Sprintf("🗑 Delete *%s* \\(%s, `%s`\\)?",
+			messages.EscapeMarkdown(acct.AccountName),
+			acct.Currency,
+			messages.EscapeMarkdown(maskAccountDetails(acct.AccountDetails)),
+		),
+		ParseMode: "MarkdownV2",
+		ReplyMarkup: &ports.ReplyMarkup{
+			IsInline: true,
+			Buttons: [][]ports.Button{{
+				{Text: "🗑 Delete", Data: "acct_delok_" + acct.ID.String()},
+				{Text: "◀️ Back", Data: "acct_list"},
+			}},
+		},
+	})
+}
+
+// handleDeleteConfirmed deletes the account and shows the list again.
+func (h *myAccountsCallbackHandler) handleDeleteConfirmed(ctx context.Context, update *ports.BotUpdate, user *domain.User, acct *domain.UserBankAccount) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Str("acct_id", acct.ID.String()).Logger()
+
+	if err := h.bankRepo.Delete(ctx, acct.ID, user.ID); err != nil {
+		if errors.Is(err, ports.ErrBankAccountInUse) {
+			return h.editMessage(ctx, update, "This account is used by one of your trades and cannot be deleted. Use /myaccounts to go back.")
+		}
+		log.Error().Err(err).Msg("Failed to delete bank account")
+		return h.editMessage(ctx, update, "An internal error occurred.")
+	}
+	log.Info().Msg("Bank account deleted")
+
+	return h.showList(ctx, update, user)
+}
+
+// handleCancel leaves any account step and shows the list again.
+func (h *myAccountsCallbackHandler) handleCancel(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	if isAccountState(user.State) {
+		user.State = domain.StateNone
+		user.StateData = map[string]string{}
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to clear account flow state")
+			return h.editMessage(ctx, update, "An internal error occurred.")
+		}
+	}
+	return h.showList(ctx, update, user)
+}
+
+// showList re-renders the account list in place.
+func (h *myAccountsCallbackHandler) showList(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	accounts, err := h.bankRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to list bank accounts")
+		return h.editMessage(ctx, update, "An internal error occurred.")
+	}
+
+	text, buttons := renderAccountList(accounts)
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:      update.ChatID,
+		MessageID:   update.MessageID,
+		Text:        text,
+		ParseMode:   "MarkdownV2",
+		ReplyMarkup: &ports.ReplyMarkup{IsInline: true, Buttons: buttons},
+	})
+}
+
+// editMessage replaces the button message with plain text.
+func (h *myAccountsCallbackHandler) editMessage(ctx context.Context, update *ports.BotUpdate, text string) error {
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:    update.ChatID,
+		MessageID: update.MessageID,
+		Text:      text,
+	})
+}
+
+// cancelAccountMarkup is the Cancel button shown during the account steps.
+func cancelAccountMarkup() *ports.ReplyMarkup {
+	return &ports.ReplyMarkup{
+		IsInline: true,
+		Buttons:  [][]ports.Button{{{Text: "✖️ Cancel", Data: "acct_cancel"}}},
+	}
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterState(NewMyAccountsFlow)
+}
+
+// Keys used in user.StateData while an account is being added or renamed.
+const (
+	draftKeyAccountID       = "account_id"
+	draftKeyAccountName     = "account_name"
+	draftKeyAccountCurrency = "account_currency"
+	draftKeyAccountBank     = "account_bank"
+)
+
+// Length limits of the account fields, in characters.
+const (
+	maxAccountNameLength    = 50
+	maxAccountBankLength    = 100
+	maxAccountDetailsLength = 500
+)
+
+// myAccountsFlow is the FSM that collects the fields of a payout account.
+type myAccountsFlow struct {
+	log        zerolog.Logger
+	userRepo   ports.UserRepository
+	bankRepo   ports.UserBankAccountRepository
+	bot        ports.BotClientPort
+	currencies *domain.CurrencyRegistry
+}
+
+// NewMyAccountsFlow creates the state handler for the /myaccounts flow.
+func NewMyAccountsFlow(deps *customer.HandlerDeps) ports.StateHandler {
+	return &myAccountsFlow{
+		log:        deps.BaseLogger.With().Str("component", "my_accounts_flow").Logger(),
+		userRepo:   deps.UserRepo,
+		bankRepo:   deps.BankAccountRepo,
+		bot:        deps.BotClient,
+		currencies: deps.Cfg.Currencies,
+	}
+}
+
+// States returns the user states owned by this flow.
+func (h *myAccountsFlow) States() []domain.UserState {
+	return []domain.UserState{
+		domain.StateAwaitingAccountName,
+		domain.StateAwaitingAccountCurrency,
+		domain.StateAwaitingAccountBank,
+		domain.StateAwaitingAccountDetails,
+		domain.StateAwaitingAccountRename,
+	}
+}
+
+// isAccountState reports whether the user is in the middle of the /myaccounts flow.
+func isAccountState(state domain.UserState) bool {
+	for _, s := range (&myAccountsFlow{}).States() {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle routes the reply based on the user's state.
+func (h *myAccountsFlow) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	if update.Contact != nil || update.Photo != nil {
+		return h.sendErrorMessage(ctx, update.ChatID, "Please reply with text.")
+	}
+	if user.StateData == nil {
+		user.StateData = map[string]string{}
+	}
+	input := strings.TrimSpace(update.Text)
+
+	// --- THE STATE MACHINE ---
+	switch user.State {
+	case domain.StateAwaitingAccountName:
+		return h.handleName(ctx, update, user, input)
+	case domain.StateAwaitingAccountCurrency:
+		return h.handleCurrency(ctx, update, user, input)
+	case domain.StateAwaitingAccountBank:
+		return h.handleBank(ctx, update, user, input)
+	case domain.StateAwaitingAccountDetails:
+		return h.handleDetails(ctx, update, user, input)
+	case domain.StateAwaitingAccountRename:
+		return h.handleRename(ctx, update, user, input)
+	default:
+		h.log.Warn().Str("state", string(user.State)).Msg("Received text in unhandled state")
+		return nil
+	}
+}
+
+// handleName stores the display name of the new account.
+func (h *myAccountsFlow) handleName(ctx context.Context, update *ports.BotUpdate, user *domain.User, name string) error {
+	if name == "" || utf8.RuneCountInString(name) > maxAccountNameLength {
+		return h.sendErrorMessage(ctx, update.ChatID, fmt.Sprintf("Please reply with a name of up to %d characters.", maxAccountNameLength))
+	}
+
+	user.StateData[draftKeyAccountName] = name
+	user.State = domain.StateAwaitingAccountCurrency
+	if err := h.save(ctx, update, user); err != nil {
+		return err
+	}
+	return h.ask(ctx, update.ChatID, "Which currency is this account in\\?\n\nReply with one of: "+
+		messages.EscapeMarkdown(currencyCodes(h.currencies.Currencies())))
+}
+
+// handleCurrency stores the account currency.
+func (h *myAccountsFlow) handleCurrency(ctx context.Context, update *ports.BotUpdate, user *domain.User, input string) error {
+	code := strings.ToUpper(input)
+	if _, ok := h.currencies.Get(code); !ok {
+		return h.sendErrorMessage(ctx, update.ChatID, "Please reply with one of the currencies we support: "+currencyCodes(h.currencies.Currencies())+".")
+	}
+
+	user.StateData[draftKeyAccountCurrency] = code
+	user.State = domain.StateAwaitingAccountBank
+	if err := h.save(ctx, update, user); err != nil {
+		return err
+	}
+	return h.ask(ctx, update.ChatID, "What is the name of the *bank*\\?")
+}
+
+// handleBank stores the bank name.
+func (h *myAccountsFlow) handleBank(ctx context.Context, update *ports.BotUpdate, user *domain.User, bank string) error {
+	if bank == "" || utf8.RuneCountInString(bank) > maxAccountBankLength {
+		return h.sendErrorMessage(ctx, update.ChatID, fmt.Sprintf("Please reply with a bank name of up to %d characters.", maxAccountBankLength))
+	}
+
+	user.StateData[draftKeyAccountBank] = bank
+	user.State = domain.StateAwaitingAccountDetails
+	if err := h.save(ctx, update, user); err != nil {
+		return err
+	}
+	return h.ask(ctx, update.ChatID,
+		"Finally, reply with the *account details* we should pay into \\(IBAN, card or account number, holder name\\)\\.\n\n"+
+			"They are stored encrypted and only shown to the moderator making your payout\\.")
+}
+
+// handleDetails creates the account from the collected fields.
+func (h *myAccountsFlow) handleDetails(ctx context.Context, update *ports.BotUpdate, user *domain.User, details string) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	if details == "" || utf8.RuneCountInString(details) > maxAccountDetailsLength {
+		return h.sendErrorMessage(ctx, update.ChatID, fmt.Sprintf("Please reply with account details of up to %d characters.", maxAccountDetailsLength))
+	}
+	data := user.StateData
+	if data[draftKeyAccountName] == "" || data[draftKeyAccountCurrency] == "" || data[draftKeyAccountBank] == "" {
+		log.Error().Msg("Account draft is incomplete")
+		h.reset(ctx, user)
+		return h.sendErrorMessage(ctx, update.ChatID, "Your draft is incomplete. Please start again with /myaccounts.")
+	}
+
+	acct := &domain.UserBankAccount{
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		AccountName:    data[draftKeyAccountName],
+		Currency:       data[draftKeyAccountCurrency],
+		BankName:       data[draftKeyAccountBank],
+		AccountDetails: details,
+	}
+	if err := h.bankRepo.Create(ctx, acct); err != nil {
+		log.Error().Err(err).Msg("Failed to save bank account")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred. Please try again.")
+	}
+	log.Info().Str("acct_id", acct.ID.String()).Msg("Bank account added")
+
+	h.reset(ctx, user)
+	return h.sendList(ctx, update.ChatID, user, "✅ Account added\\.\n\n")
+}
+
+// handleRename saves the new name of an existing account.
+func (h *myAccountsFlow) handleRename(ctx context.Context, update *ports.BotUpdate, user *domain.User, name string) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	if name == "" || utf8.RuneCountInString(name) > maxAccountNameLength {
+		return h.sendErrorMessage(ctx, update.ChatID, fmt.Sprintf("Please reply with a name of up to %d characters.", maxAccountNameLength))
+	}
+
+	acctID, err := uuid.Parse(user.StateData[draftKeyAccountID])
+	h.reset(ctx, user)
+	if err != nil {
+		log.Error().Err(err).Msg("Rename state has no valid account ID")
+		return h.sendErrorMessage(ctx, update.ChatID, "Your draft is incomplete. Please start again with /myaccounts.")
+	}
+
+	acct, err := h.bankRepo.GetByID(ctx, acctID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get bank account")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+	if acct == nil || acct.UserID != user.ID {
+		return h.sendErrorMessage(ctx, update.ChatID, "This account no longer exists.")
+	}
+
+	acct.AccountName = name
+	if err := h.bankRepo.Update(ctx, acct); err != nil {
+		log.Error().Err(err).Str("acct_id", acct.ID.String()).Msg("Failed to rename bank account")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+	log.Info().Str("acct_id", acct.ID.String()).Msg("Bank account renamed")
+
+	return h.sendList(ctx, update.ChatID, user, "✅ Account renamed\\.\n\n")
+}
+
+// sendList sends the account list with a MarkdownV2 header.
+func (h *myAccountsFlow) sendList(ctx context.Context, chatID int64, user *domain.User, header string) error {
+	accounts, err := h.bankRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to list bank accounts")
+		return h.sendErrorMessage(ctx, chatID, "An internal error occurred.")
+	}
+
+	text, buttons := renderAccountList(accounts)
+	msg := messages.NewBuilder(chatID).
+		WithText(header + text).
+		WithInlineButtons(buttons).
+		Build()
+	_, err = h.bot.SendMessage(ctx, msg)
+	return err
+}
+
+// ask sends the next MarkdownV2 question with a Cancel button.
+func (h *myAccountsFlow) ask(ctx context.Context, chatID int64, question string) error {
+	msg := messages.NewBuilder(chatID).
+		WithText(question).
+		WithInlineButtons(cancelAccountMarkup().Buttons).
+		Build()
+	_, err := h.bot.SendMessage(ctx, msg)
+	return err
+}
+
+// save persists the user's progress through the flow.
+func (h *myAccountsFlow) save(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to update user")
+		h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+		return err
+	}
+	return nil
+}
+
+// reset leaves the flow. A stale state is harmless, so errors are only logged.
+func (h *myAccountsFlow) reset(ctx context.Context, user *domain.User) {
+	user.State = domain.StateNone
+	user.StateData = map[string]string{}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to clear account flow state")
+	}
+}
+
+// sendErrorMessage is a helper to send a generic error
+func (h *myAccountsFlow) sendErrorMessage(ctx context.Context, chatID int64, message string) error {
+	msgParams := messages.NewBuilder(chatID).
+		WithText(message).
+		WithParseMode("").Build()
+	_, err := h.bot.SendMessage(ctx, msgParams)
+	return err
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCommand(NewMyAccountsHandler)
+}
+
+// myAccountsHandler is the plugin for the /myaccounts command.
+// The buttons live in my_accounts_callback.go and the text steps in my_accounts_flow.go.
+type myAccountsHandler struct {
+	log      zerolog.Logger
+	userRepo ports.UserRepository
+	bankRepo ports.UserBankAccountRepository
+	bot      ports.BotClientPort
+}
+
+// NewMyAccountsHandler creates a new handler for the /myaccounts command.
+func NewMyAccountsHandler(deps *customer.HandlerDeps) ports.CommandHandler {
+	return &myAccountsHandler{
+		log:      deps.BaseLogger.With().Str("component", "my_accounts_handler").Logger(),
+		userRepo: deps.UserRepo,
+		bankRepo: deps.BankAccountRepo,
+		bot:      deps.BotClient,
+	}
+}
+
+// Command returns the command string (without the "/")
+func (h *myAccountsHandler) Command() string {
+	return "myaccounts"
+}
+
+// Handle lists the user's payout accounts.
+func (h *myAccountsHandler) Handle(ctx context.Context, update *ports.BotUpdate) error {
+	log := h.log.With().Int64("user_id", update.UserID).Logger()
+
+	user, err := h.userRepo.GetByTelegramID(ctx, update.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get user from repository")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+	if user == nil {
+		msg := messages.NewBuilder(update.ChatID).
+			WithText("Please type /start to begin\\.").
+			Build()
+		_, err := h.bot.SendMessage(ctx, msg)
+		return err
+	}
+	if !user.VerificationStatus.IsVerified() {
+		return h.sendErrorMessage(ctx, update.ChatID, "Only verified accounts can manage payout accounts.")
+	}
+
+	// Typing the command again leaves a half-finished account step
+	if isAccountState(user.State) {
+		user.State = domain.StateNone
+		user.StateData = map[string]string{}
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			log.Error().Err(err).Msg("Failed to reset account flow state")
+			return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+		}
+	}
+
+	accounts, err := h.bankRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list bank accounts")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+
+	text, buttons := renderAccountList(accounts)
+	msg := messages.NewBuilder(update.ChatID).
+		WithText(text).
+		WithInlineButtons(buttons).
+		Build()
+	_, err = h.bot.SendMessage(ctx, msg)
+	return err
+}
+
+// sendErrorMessage is a helper to send a generic error
+func (h *myAccountsHandler) sendErrorMessage(ctx context.Context, chatID int64, message string) error {
+	msgParams := messages.NewBuilder(chatID).
+		WithText(message).
+		WithParseMode("").Build()
+	_, err := h.bot.SendMessage(ctx, msgParams)
+	return err
+}
+
+// renderAccountList builds the MarkdownV2 list of accounts with their buttons.
+func renderAccountList(accounts []*domain.UserBankAccount) (string, [][]ports.Button) {
+	var text strings.Builder
+	text.WriteString("🏦 *Your Payout Accounts*\n\n")
+
+	var buttons [][]ports.Button
+	if len(accounts) == 0 {
+		text.WriteString("You have no payout accounts yet\\. We pay your side of a trade into one of them, so add one for each currency you receive\\.\n")
+	}
+	for i, acct := range accounts {
+		n := i + 1
+		text.WriteString(fmt.Sprintf("*%d\\.* %s · %s · %s\n    `%s`\n",
+			n,
+			messages.EscapeMarkdown(acct.AccountName),
+			acct.Currency,
+			messages.EscapeMarkdown(acct.BankName),
+			messages.EscapeMarkdown(maskAccountDetails(acct.AccountDetails)),
+		))
+		buttons = append(buttons, []ports.Button{
+			{Text: fmt.Sprintf("✏️ Rename #%d", n), Data: "acct_ren_" + acct.ID.String()},
+			{Text: fmt.Sprintf("🗑 Delete #%d", n), Data: "acct_del_" + acct.ID.String()},
+		})
+	}
+	buttons = append(buttons, []ports.Button{{Text: "➕ Add account", Data: "acct_add"}})
+
+	return text.String(), buttons
+}
+
+// maskAccountDetails hides all but the last 4 characters of the account details.
+func maskAccountDetails(details string) string {
+	runes := []rune(strings.TrimSpace(details))
+	if len(runes) <= 4 {
+		return "••••"
+	}
+	return "••••" + string(runes[len(runes)-4:])
+}
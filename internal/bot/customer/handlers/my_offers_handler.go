@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCommand(NewMyOffersHandler)
+}
+
+// myOffersHandler is the plugin for the /myoffers command.
+// It lists the user's open requests and pending bids with a withdraw button each;
+// the buttons are handled by cancel_callback.go.
+type myOffersHandler struct {
+	log         zerolog.Logger
+	userRepo    ports.UserRepository
+	requestRepo ports.RequestRepository
+	bidRepo     ports.BidRepository
+	bot         ports.BotClientPort
+}
+
+// NewMyOffersHandler creates a new handler for the /myoffers command.
+func NewMyOffersHandler(deps *customer.HandlerDeps) ports.CommandHandler {
+	return &myOffersHandler{
+		log:         deps.BaseLogger.With().Str("component", "my_offers_handler").Logger(),
+		userRepo:    deps.UserRepo,
+		requestRepo: deps.RequestRepo,
+		bidRepo:     deps.BidRepo,
+		bot:         deps.BotClient,
+	}
+}
+
+// Command returns the command string (without the "/")
+func (h *myOffersHandler) Command() string {
+	return "myoffers"
+}
+
+// Handle sends the user's open requests and pending bids.
+func (h *myOffersHandler) Handle(ctx context.Context, update *ports.BotUpdate) error {
+	log := h.log.With().Int64("user_id", update.UserID).Logger()
+
+	user, err := h.userRepo.GetByTelegramID(ctx, update.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get user from repository")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+	if user == nil {
+		msg := messages.NewBuilder(update.ChatID).
+			WithText("Please type /start to begin\\.").
+			Build()
+		_, err := h.bot.SendMessage(ctx, msg)
+		return err
+	}
+
+	requests, err := h.requestRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list requests")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+	bids, err := h.bidRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list bids")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+
+	var text strings.Builder
+	var buttons [][]ports.Button
+
+	text.WriteString("📋 *Your open requests*\n")
+	n := 0
+	for _, req := range requests {
+		if req.Status != domain.RequestStatusOpen {
+			continue
+		}
+		n++
+		text.WriteString(fmt.Sprintf("*%d\\.* %s\n", n, formatRequestLine(req)))
+		buttons = append(buttons, []ports.Button{
+			{Text: fmt.Sprintf("✖️ Withdraw request #%d", n), Data: "cancel_req_" + req.ID.String()},
+		})
+	}
+	if n == 0 {
+		text.WriteString("None\\. Use /newrequest to post one\\.\n")
+	}
+
+	text.WriteString("\n💰 *Your pending bids*\n")
+	m := 0
+	for _, bid := range bids {
+		if bid.Status != domain.BidStatusPending {
+			continue
+		}
+		req, err := h.requestRepo.GetByID(ctx, bid.RequestID)
+		if err != nil || req == nil {
+			log.Error().Err(err).Str("bid_id", bid.ID.String()).Msg("Failed to load request of bid")
+			continue
+		}
+		m++
+		text.WriteString(fmt.Sprintf("*%d\\.* %s\n", m, formatRequestLine(req)))
+		buttons = append(buttons, []ports.Button{
+			{Text: fmt.Sprintf("✖️ Withdraw bid #%d", m), Data: "cancel_bid_" + bid.ID.String()},
+		})
+	}
+	if m == 0 {
+		text.WriteString("None\\. Use /listrequests to find an offer\\.\n")
+	}
+
+	builder := messages.NewBuilder(update.ChatID).WithText(text.String())
+	if buttons != nil {
+		builder = builder.WithInlineButtons(buttons)
+	}
+	_, err = h.bot.SendMessage(ctx, builder.Build())
+	return err
+}
+
+// sendErrorMessage is a helper to send a generic error
+func (h *myOffersHandler) sendErrorMessage(ctx context.Context, chatID int64, message string) error {
+	msgParams := messages.NewBuilder(chatID).
+		WithText(message).
+		WithParseMode("").Build()
+	_, err := h.bot.SendMessage(ctx, msgParams)
+	return err
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCallback(NewNewRequestCallbackHandler)
+}
+
+// newRequestCallbackHandler handles the inline buttons of the /newrequest flow.
+type newRequestCallbackHandler struct {
+	log         zerolog.Logger
+	userRepo    ports.UserRepository
+	requestRepo ports.RequestRepository
+	bot         ports.BotClientPort
+	transactor  ports.Transactor
+	bus         ports.EventBus
+	currencies  *domain.CurrencyRegistry
+	limits      *services.LimitService
+}
+
+// NewNewRequestCallbackHandler creates a new handler for "newreq_" callbacks.
+func NewNewRequestCallbackHandler(deps *customer.HandlerDeps) ports.CallbackHandler {
+	return &newRequestCallbackHandler{
+		log:         deps.BaseLogger.With().Str("component", "new_request_callback").Logger(),
+		userRepo:    deps.UserRepo,
+		requestRepo: deps.RequestRepo,
+		bot:         deps.BotClient,
+		transactor:  deps.Transactor,
+		bus:         deps.Bus,
+		currencies:  deps.Cfg.Currencies,
+		limits:      deps.Limits,
+	}
+}
+
+// Prefix returns the prefix this handler is responsible for.
+func (h *newRequestCallbackHandler) Prefix() string {
+	return "newreq_"
+}
+
+// Handle processes "newreq_type_sell", "newreq_type_buy", "newreq_base_<code>",
+// "newreq_quote_<code>", "newreq_confirm" and "newreq_cancel".
+func (h *newRequestCallbackHandler) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	// 1. Answer the callback to stop the spinner
+	h.bot.AnswerCallbackQuery(ctx, ports.AnswerCallbackParams{
+		CallbackQueryID: update.CallbackQueryID,
+	})
+
+	if !user.VerificationStatus.IsVerified() {
+		log.Warn().Msg("Unverified user pressed a new request button")
+		return h.editMessage(ctx, update, "Only verified accounts can create exchange requests.")
+	}
+	if user.StateData == nil {
+		user.StateData = map[string]string{}
+	}
+
+	// 2. Parse the callback data
+	switch *update.CallbackData {
+	case "newreq_type_sell":
+		return h.handleType(ctx, update, user, domain.RequestTypeSell)
+	case "newreq_type_buy":
+		return h.handleType(ctx, update, user, domain.RequestTypeBuy)
+	case "newreq_confirm":
+		return h.handleConfirm(ctx, update, user)
+	case "newreq_cancel":
+		return h.handleCancel(ctx, update, user)
+	}
+	if code, ok := strings.CutPrefix(*update.CallbackData, "newreq_base_"); ok {
+		return h.handleBase(ctx, update, user, code)
+	}
+	if code, ok := strings.CutPrefix(*update.CallbackData, "newreq_quote_"); ok {
+		return h.handleQuote(ctx, update, user, code)
+	}
+
+	log.Warn().Str("data", *update.CallbackData).Msg("Unknown new request callback")
+	return nil
+}
+
+// handleType stores the side of the market and asks for the base currency.
+func (h *newRequestCallbackHandler) handleType(ctx context.Context, update *ports.BotUpdate, user *domain.User, reqType domain.RequestType) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	user.StateData = map[string]string{draftKeyType: string(reqType)}
+	user.State = domain.StateAwaitingRequestBaseCurrency
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		log.Error().Err(err).Msg("Failed to update user state for new request")
+		return h.editMessage(ctx, update, "An internal error occurred.")
+	}
+
+	h.editMessage(ctx, update, "📝 New "+string(reqType)+" request")
+	return sendBaseCurrencyQuestion(ctx, h.bot, h.currencies, update.ChatID, reqType)
+}
+
+// handleBase stores the base currency picked from the keyboard.
+func (h *newRequestCallbackHandler) handleBase(ctx context.Context, update *ports.BotUpdate, user *domain.User, code string) error {
+	if user.State != domain.StateAwaitingRequestBaseCurrency {
+		return h.editMessage(ctx, update, "This question has already been answered. Use /newrequest to start again.")
+	}
+	if problem := baseCurrencyProblem(h.currencies, code); problem != "" {
+		return h.editMessage(ctx, update, problem)
+	}
+
+	user.StateData[draftKeyBase] = code
+	user.State = domain.StateAwaitingRequestQuoteCurrency
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to save base currency")
+		return h.editMessage(ctx, update, "An internal error occurred.")
+	}
+
+	h.editMessage(ctx, update, "Currency: "+code)
+	return sendQuoteCurrencyQuestion(ctx, h.bot, h.currencies, update.ChatID, user)
+}
+
+// handleQuote stores the quote currency picked from the keyboard.
+func (h *newRequestCallbackHandler) handleQuote(ctx context.Context, update *ports.BotUpdate, user *domain.User, code string) error {
+	if user.State != domain.StateAwaitingRequestQuoteCurrency {
+		return h.editMessage(ctx, update, "This question has already been answered. Use /newrequest to start again.")
+	}
+	if problem := quoteCurrencyProblem(h.currencies, user.StateData[draftKeyBase], code); problem != "" {
+		return h.editMessage(ctx, update, problem)
+	}
+
+	user.StateData[draftKeyQuote] = code
+	user.State = domain.StateAwaitingRequestAmount
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to save quote currency")
+		return h.editMessage(ctx, update, "An internal error occurred.")
+	}
+
+	h.editMessage(ctx, update, "In exchange for: "+code)
+	return sendAmountQuestion(ctx, h.bot, update.ChatID, user)
+}
+
+// handleConfirm saves the drafted request and announces it on the bus.
+func (h *newRequestCallbackHandler) handleConfirm(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	if user.State != domain.StateAwaitingRequestConfirmation {
+		log.Warn().Str("state", string(user.State)).Msg("Confirm pressed outside of confirmation step")
+		return h.editMessage(ctx, update, "This request is no longer pending. Use /newrequest to start again.")
+	}
+
+	req, err := requestFromDraft(user)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid request draft at confirmation")
+		return h.editMessage(ctx, update, "Your draft is incomplete. Please start again with /newrequest.")
+	}
+	// The currency rules may have changed since the draft was started
+	if err := h.currencies.ValidateRequest(req); err != nil {
+		log.Warn().Err(err).Msg("Request draft no longer passes the currency rules")
+		return h.editMessage(ctx, update, "Your request no longer meets our currency rules. "+amountProblem(err))
+	}
+	req.ID = uuid.New()
+	req.UserID = user.ID
+
+	// The request, the cleared draft and the event are saved together,
+	// after a limit check that holds the user's lock until the commit
+	err = h.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := h.limits.CheckNewRequest(ctx, user, req); err != nil {
+			return err
+		}
+		user.State = domain.StateNone
+		user.StateData = map[string]string{}
+		if err := h.requestRepo.Create(ctx, req); err != nil {
+			return err
+		}
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return events.PublishKeyed(ctx, h.bus, req.ID.String(), events.RequestCreated{Request: events.SnapshotRequest(req)})
+	})
+	if problem := limitProblem(err); problem != "" {
+		return h.editMessage(ctx, update, problem)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to save new request")
+		return h.editMessage(ctx, update, "An internal error occurred. Please try again.")
+	}
+	log.Info().Str("request_id", req.ID.String()).Msg("New request created")
+
+	return h.editMessage(ctx, update, "✅ Your request has been published. We will notify you when someone bids on it.")
+}
+
+// handleCancel drops the draft.
+func (h *newRequestCallbackHandler) handleCancel(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	user.State = domain.StateNone
+	user.StateData = map[string]string{}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		log.Error().Err(err).Msg("Failed to clear request draft")
+		return h.editMessage(ctx, update, "An internal error occurred.")
+	}
+
+	log.Info().Msg("User cancelled new request")
+	return h.editMessage(ctx, update, "Request cancelled.")
+}
+
+// editMessage replaces the button message with plain text.
+func (h *newRequestCallbackHandler) editMessage(ctx context.Context, update *ports.BotUpdate, text string) error {
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:    update.ChatID,
+		MessageID: update.MessageID,
+		Text:      text,
+	})
+}
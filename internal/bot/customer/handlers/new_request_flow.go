@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterState(NewNewRequestFlow)
+}
+
+// Keys used in user.StateData while a request is being drafted.
+const (
+	draftKeyType   = "request_type"
+	draftKeyBase   = "base_currency"
+	draftKeyQuote  = "quote_currency"
+	draftKeyAmount = "base_amount"
+	draftKeyRate   = "exchange_rate"
+)
+
+// newRequestFlow is the FSM that collects the fields of a new request.
+type newRequestFlow struct {
+	log        zerolog.Logger
+	userRepo   ports.UserRepository
+	bot        ports.BotClientPort
+	currencies *domain.CurrencyRegistry
+}
+
+// NewNewRequestFlow creates the state handler for the /newrequest flow.
+func NewNewRequestFlow(deps *customer.HandlerDeps) ports.StateHandler {
+	return &newRequestFlow{
+		log:        deps.BaseLogger.With().Str("component", "new_request_flow").Logger(),
+		userRepo:   deps.UserRepo,
+		bot:        deps.BotClient,
+		currencies: deps.Cfg.Currencies,
+	}
+}
+
+// States returns the user states owned by this flow.
+func (h *newRequestFlow) States() []domain.UserState {
+	return []domain.UserState{
+		domain.StateAwaitingRequestBaseCurrency,
+		domain.StateAwaitingRequestQuoteCurrency,
+		domain.StateAwaitingRequestAmount,
+		domain.StateAwaitingRequestRate,
+		domain.StateAwaitingRequestConfirmation,
+	}
+}
+
+// Handle routes the reply based on the user's state.
+func (h *newRequestFlow) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	if update.Contact != nil || update.Photo != nil {
+		return h.sendErrorMessage(ctx, update.ChatID, "Please reply with text.")
+	}
+	if user.StateData == nil {
+		user.StateData = map[string]string{}
+	}
+
+	// --- THE STATE MACHINE ---
+	switch user.State {
+	case domain.StateAwaitingRequestBaseCurrency:
+		return h.handleBaseCurrency(ctx, update, user)
+	case domain.StateAwaitingRequestQuoteCurrency:
+		return h.handleQuoteCurrency(ctx, update, user)
+	case domain.StateAwaitingRequestAmount:
+		return h.handleAmount(ctx, update, user)
+	case domain.StateAwaitingRequestRate:
+		return h.handleRate(ctx, update, user)
+	case domain.StateAwaitingRequestConfirmation:
+		// The user should be pressing the buttons; show the summary again.
+		return sendRequestSummary(ctx, h.bot, h.currencies, update.ChatID, user)
+	default:
+		h.log.Warn().Str("state", string(user.State)).Msg("Received text in unhandled state")
+		return nil
+	}
+}
+
+// handleBaseCurrency stores a base currency typed instead of picked from the keyboard.
+func (h *newRequestFlow) handleBaseCurrency(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	code := strings.ToUpper(strings.TrimSpace(update.Text))
+	if problem := baseCurrencyProblem(h.currencies, code); problem != "" {
+		return h.sendErrorMessage(ctx, update.ChatID, problem)
+	}
+
+	user.StateData[draftKeyBase] = code
+	user.State = domain.StateAwaitingRequestQuoteCurrency
+	if err := h.save(ctx, update, user); err != nil {
+		return err
+	}
+	return sendQuoteCurrencyQuestion(ctx, h.bot, h.currencies, update.ChatID, user)
+}
+
+// handleQuoteCurrency stores a quote currency typed instead of picked from the keyboard.
+func (h *newRequestFlow) handleQuoteCurrency(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	code := strings.ToUpper(strings.TrimSpace(update.Text))
+	if problem := quoteCurrencyProblem(h.currencies, user.StateData[draftKeyBase], code); problem != "" {
+		return h.sendErrorMessage(ctx, update.ChatID, problem)
+	}
+
+	user.StateData[draftKeyQuote] = code
+	user.State = domain.StateAwaitingRequestAmount
+	if err := h.save(ctx, update, user); err != nil {
+		return err
+	}
+	return sendAmountQuestion(ctx, h.bot, update.ChatID, user)
+}
+
+// handleAmount stores the base amount.
+func (h *newRequestFlow) handleAmount(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	amount, err := parsePositiveNumber(update.Text)
+	if err != nil {
+		return h.sendErrorMessage(ctx, update.ChatID, "Please reply with a positive number, e.g. 1500.")
+	}
+	base := domain.Money{Amount: amount, Currency: user.StateData[draftKeyBase]}
+	if err := h.currencies.ValidateAmount(base); err != nil {
+		return h.sendErrorMessage(ctx, update.ChatID, amountProblem(err))
+	}
+
+	user.StateData[draftKeyAmount] = amount.String()
+	user.State = domain.StateAwaitingRequestRate
+	if err := h.save(ctx, update, user); err != nil {
+		return err
+	}
+
+	msg := messages.NewBuilder(update.ChatID).
+		WithText(fmt.Sprintf(
+			"What exchange rate are you asking for\\?\n\nReply with the amount of *%s* per 1 *%s*\\.",
+			user.StateData[draftKeyQuote], user.StateData[draftKeyBase],
+		)).
+		Build()
+	_, err = h.bot.SendMessage(ctx, msg)
+	return err
+}
+
+// handleRate stores the exchange rate and asks for confirmation.
+func (h *newRequestFlow) handleRate(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	rate, err := parsePositiveNumber(update.Text)
+	if err != nil {
+		return h.sendErrorMessage(ctx, update.ChatID, "Please reply with a positive number, e.g. 615000.")
+	}
+	if err := h.currencies.ValidateRate(rate); err != nil {
+		return h.sendErrorMessage(ctx, update.ChatID, amountProblem(err))
+	}
+	amount, _ := domain.ParseDecimal(user.StateData[draftKeyAmount])
+	draft := &domain.Request{
+		BaseCurrency:  user.StateData[draftKeyBase],
+		QuoteCurrency: user.StateData[draftKeyQuote],
+		BaseAmount:    amount,
+		ExchangeRate:  rate,
+	}
+	if err := h.currencies.ValidateAmount(draft.QuoteAmount(h.currencies)); err != nil {
+		return h.sendErrorMessage(ctx, update.ChatID, "The total at this rate is not allowed. "+amountProblem(err))
+	}
+
+	user.StateData[draftKeyRate] = rate.String()
+	user.State = domain.StateAwaitingRequestConfirmation
+	if err := h.save(ctx, update, user); err != nil {
+		return err
+	}
+
+	return sendRequestSummary(ctx, h.bot, h.currencies, update.ChatID, user)
+}
+
+// save persists the user's progress through the flow.
+func (h *newRequestFlow) save(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to update user")
+		h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+		return err
+	}
+	return nil
+}
+
+// sendErrorMessage is a helper to send a generic error
+func (h *newRequestFlow) sendErrorMessage(ctx context.Context, chatID int64, message string) error {
+	msgParams := messages.NewBuilder(chatID).
+		WithText(message).
+		WithParseMode("").Build()
+	_, err := h.bot.SendMessage(ctx, msgParams)
+	return err
+}
+
+// sendRequestSummary shows the drafted request with Confirm/Cancel buttons.
+func sendRequestSummary(ctx context.Context, bot ports.BotClientPort, currencies *domain.CurrencyRegistry, chatID int64, user *domain.User) error {
+	draft, err := requestFromDraft(user)
+	if err != nil {
+		msg := messages.NewBuilder(chatID).
+			WithText("Your draft is incomplete. Please start again with /newrequest.").
+			WithParseMode("").Build()
+		_, sendErr := bot.SendMessage(ctx, msg)
+		return sendErr
+	}
+
+	var text strings.Builder
+	text.WriteString("*Please confirm your request*\n\n")
+	text.WriteString(fmt.Sprintf("*Type:* %s\n", strings.ToUpper(string(draft.Type))))
+	text.WriteString(fmt.Sprintf("*Amount:* %s %s\n",
+		messages.EscapeMarkdown(messages.FormatAmount(draft.BaseAmount)), draft.BaseCurrency))
+	text.WriteString(fmt.Sprintf("*Rate:* %s %s per %s\n",
+		messages.EscapeMarkdown(messages.FormatAmount(draft.ExchangeRate)), draft.QuoteCurrency, draft.BaseCurrency))
+	text.WriteString(fmt.Sprintf("*Total:* %s %s\n",
+		messages.EscapeMarkdown(messages.FormatAmount(draft.QuoteAmount(currencies).Amount)), draft.QuoteCurrency))
+
+	msg := messages.NewBuilder(chatID).
+		WithText(text.String()).
+		WithInlineButtons([][]ports.Button{
+			{
+				{Text: "✅ Confirm", Data: "newreq_confirm"},
+				{Text: "✖️ Cancel", Data: "newreq_cancel"},
+			},
+		}).
+		Build()
+	_, err = bot.SendMessage(ctx, msg)
+	return err
+}
+
+// requestFromDraft rebuilds the request fields collected in user.StateData.
+// It does not assign an ID or owner.
+func requestFromDraft(user *domain.User) (*domain.Request, error) {
+	data := user.StateData
+	reqType := domain.RequestType(data[draftKeyType])
+	if reqType != domain.RequestTypeSell && reqType != domain.RequestTypeBuy {
+		return nil, errors.New("draft has no request type")
+	}
+	if data[draftKeyBase] == "" || data[draftKeyQuote] == "" {
+		return nil, errors.New("draft has no currencies")
+	}
+	amount, err := domain.ParseDecimal(data[draftKeyAmount])
+	if err != nil {
+		return nil, fmt.Errorf("draft has invalid amount: %w", err)
+	}
+	rate, err := domain.ParseDecimal(data[draftKeyRate])
+	if err != nil {
+		return nil, fmt.Errorf("draft has invalid rate: %w", err)
+	}
+
+	return &domain.Request{
+		Type:          reqType,
+		BaseCurrency:  data[draftKeyBase],
+		QuoteCurrency: data[draftKeyQuote],
+		BaseAmount:    amount,
+		ExchangeRate:  rate,
+		Status:        domain.RequestStatusOpen,
+	}, nil
+}
+
+// baseCurrencyProblem returns why code cannot be the base of a new request,
+// or "" if it can.
+func baseCurrencyProblem(currencies *domain.CurrencyRegistry, code string) string {
+	if _, ok := currencies.Get(code); !ok || len(currencies.QuoteCurrencies(code)) == 0 {
+		return "Please pick one of the currencies on the keyboard, e.g. " + currencyCodes(currencies.BaseCurrencies()) + "."
+	}
+	return ""
+}
+
+// quoteCurrencyProblem returns why code cannot be the quote of a new request
+// on the given base, or "" if it can.
+func quoteCurrencyProblem(currencies *domain.CurrencyRegistry, base, code string) string {
+	if code == base {
+		return "The two currencies must be different. Please choose another one."
+	}
+	if err := currencies.ValidatePair(base, code); err != nil {
+		return fmt.Sprintf("%s can be traded against %s. Please choose one of those.", base, currencyCodes(currencies.QuoteCurrencies(base)))
+	}
+	return ""
+}
+
+// amountProblem turns a validation error into text for the user.
+func amountProblem(err error) string {
+	var amountErr *domain.AmountError
+	if errors.As(err, &amountErr) {
+		return amountErr.Message
+	}
+	var rateErr *domain.RateError
+	if errors.As(err, &rateErr) {
+		return rateErr.Message
+	}
+	return "This currency is no longer supported. Please start again with /newrequest."
+}
+
+// limitProblem returns the text of a trading limit error, or "" for any other error.
+func limitProblem(err error) string {
+	var limitErr *domain.LimitError
+	if errors.As(err, &limitErr) {
+		return limitErr.Message
+	}
+	return ""
+}
+
+// currencyCodes lists the codes of currencies, e.g. "EUR, USD".
+func currencyCodes(currencies []domain.Currency) string {
+	codes := make([]string, 0, len(currencies))
+	for _, c := range currencies {
+		codes = append(codes, c.Code)
+	}
+	return strings.Join(codes, ", ")
+}
+
+// currencyButtons lays out one button per currency, 3 per row.
+// The callback data is prefix + the currency code.
+func currencyButtons(currencies []domain.Currency, prefix string) [][]ports.Button {
+	var buttons [][]ports.Button
+	var row []ports.Button
+	for i, c := range currencies {
+		row = append(row, ports.Button{Text: c.Code + " · " + c.Name, Data: prefix + c.Code})
+		if len(row) == 3 || i == len(currencies)-1 {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	return append(buttons, []ports.Button{{Text: "✖️ Cancel", Data: "newreq_cancel"}})
+}
+
+// sendBaseCurrencyQuestion asks which currency is sold or bought.
+func sendBaseCurrencyQuestion(ctx context.Context, bot ports.BotClientPort, currencies *domain.CurrencyRegistry, chatID int64, reqType domain.RequestType) error {
+	question := "Which currency do you want to *sell*\\?"
+	if reqType == domain.RequestTypeBuy {
+		question = "Which currency do you want to *buy*\\?"
+	}
+	msg := messages.NewBuilder(chatID).
+		WithText(question).
+		WithInlineButtons(currencyButtons(currencies.BaseCurrencies(), "newreq_base_")).
+		Build()
+	_, err := bot.SendMessage(ctx, msg)
+	return err
+}
+
+// sendQuoteCurrencyQuestion asks for the currency on the other side of the trade.
+func sendQuoteCurrencyQuestion(ctx context.Context, bot ports.BotClientPort, currencies *domain.CurrencyRegistry, chatID int64, user *domain.User) error {
+	question := "Which currency do you want to *receive* in exchange\\?"
+	if domain.RequestType(user.StateData[draftKeyType]) == domain.RequestTypeBuy {
+		question = "Which currency will you *pay with*\\?"
+	}
+	msg := messages.NewBuilder(chatID).
+		WithText(question).
+		WithInlineButtons(currencyButtons(currencies.QuoteCurrencies(user.StateData[draftKeyBase]), "newreq_quote_")).
+		Build()
+	_, err := bot.SendMessage(ctx, msg)
+	return err
+}
+
+// sendAmountQuestion asks how much of the base currency is traded.
+func sendAmountQuestion(ctx context.Context, bot ports.BotClientPort, chatID int64, user *domain.User) error {
+	verb := "selling"
+	if domain.RequestType(user.StateData[draftKeyType]) == domain.RequestTypeBuy {
+		verb = "buying"
+	}
+	msg := messages.NewBuilder(chatID).
+		WithText(fmt.Sprintf("How much *%s* are you %s\\?", user.StateData[draftKeyBase], verb)).
+		Build()
+	_, err := bot.SendMessage(ctx, msg)
+	return err
+}
+
+// parsePositiveNumber accepts user input like "1,500", "1500.25" or "۱۵۰۰".
+func parsePositiveNumber(input string) (domain.Decimal, error) {
+	value, err := domain.ParseAmount(input)
+	if err != nil {
+		return domain.Decimal{}, err
+	}
+	if !value.IsPositive() {
+		return domain.Decimal{}, errors.New("value must be positive")
+	}
+	return value, nil
+}
+
+// formatPayout renders the gross, fee and net lines of a payout as MarkdownV2.
+func formatPayout(p domain.Payout) string {
+	return fmt.Sprintf("*You receive:* %s\n*Fee:* %s\n*Net payout:* %s\n",
+		messages.FormatMoney(p.Gross), messages.FormatMoney(p.Fee), messages.FormatMoney(p.Net))
+}
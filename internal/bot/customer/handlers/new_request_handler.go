@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCommand(NewNewRequestHandler)
+}
+
+// newRequestHandler is the plugin for the /newrequest command.
+// It only opens the flow; the steps live in new_request_flow.go
+// and the buttons in new_request_callback.go.
+type newRequestHandler struct {
+	log      zerolog.Logger
+	userRepo ports.UserRepository
+	bot      ports.BotClientPort
+}
+
+// NewNewRequestHandler creates a new handler for the /newrequest command.
+func NewNewRequestHandler(deps *customer.HandlerDeps) ports.CommandHandler {
+	return &newRequestHandler{
+		log:      deps.BaseLogger.With().Str("component", "new_request_handler").Logger(),
+		userRepo: deps.UserRepo,
+		bot:      deps.BotClient,
+	}
+}
+
+// Command returns the command string (without the "/")
+func (h *newRequestHandler) Command() string {
+	return "newrequest"
+}
+
+// Handle checks the user may trade and asks which side of the market they are on.
+func (h *newRequestHandler) Handle(ctx context.Context, update *ports.BotUpdate) error {
+	log := h.log.With().Int64("user_id", update.UserID).Logger()
+
+	user, err := h.userRepo.GetByTelegramID(ctx, update.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get user from repository")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+	if user == nil {
+		msg := messages.NewBuilder(update.ChatID).
+			WithText("Please type /start to begin\\.").
+			Build()
+		_, err := h.bot.SendMessage(ctx, msg)
+		return err
+	}
+
+	if !user.VerificationStatus.IsVerified() {
+		log.Warn().Str("status", string(user.VerificationStatus)).Msg("Unverified user tried to create a request")
+		return h.sendErrorMessage(ctx, update.ChatID, "Only verified accounts can create exchange requests. Please complete registration and wait for approval.")
+	}
+
+	// Start from a clean draft, even if an older one was abandoned
+	user.State = domain.StateNone
+	user.StateData = map[string]string{}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		log.Error().Err(err).Msg("Failed to reset user state for new request")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+
+	log.Info().Msg("Starting new request flow")
+	msg := messages.NewBuilder(update.ChatID).
+		WithText("📝 *New Exchange Request*\n\nDo you want to *sell* or *buy* a currency?").
+		WithInlineButtons([][]ports.Button{
+			{
+				{Text: "📤 Sell", Data: "newreq_type_sell"},
+				{Text: "📥 Buy", Data: "newreq_type_buy"},
+			},
+			{
+				{Text: "✖️ Cancel", Data: "newreq_cancel"},
+			},
+		}).
+		Build()
+
+	_, err = h.bot.SendMessage(ctx, msg)
+	return err
+}
+
+// sendErrorMessage is a helper to send a generic error
+func (h *newRequestHandler) sendErrorMessage(ctx context.Context, chatID int64, message string) error {
+	msgParams := messages.NewBuilder(chatID).
+		WithText(message).
+		WithParseMode("").Build()
+	_, err := h.bot.SendMessage(ctx, msgParams)
+	return err
+}
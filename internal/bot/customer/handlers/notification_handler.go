@@ -3,18 +3,28 @@ package handlers
 import (
 	"AsaExchange/internal/bot/messages"
 	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
 	"AsaExchange/internal/core/ports"
 	"context"
+	"fmt"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
 // NotificationHandler listens for internal events (from the EventBus)
 // and sends messages to users via the Customer Bot.
 type NotificationHandler struct {
-	log        zerolog.Logger
-	custClient ports.BotClientPort
-	userRepo   ports.UserRepository // Added for fetching fresh user data if needed
+	log          zerolog.Logger
+	custClient   ports.BotClientPort
+	userRepo     ports.UserRepository // Added for fetching fresh user data if needed
+	requestRepo  ports.RequestRepository
+	txRepo       ports.TransactionRepository
+	platformRepo ports.PlatformAccountRepository
+	proofRepo    ports.PayoutProofRepository
+	currencies   *domain.CurrencyRegistry
+	intents      *startIntentDispatcher
 }
 
 // NewNotificationHandler creates a new handler for sending user notifications.
@@ -22,24 +32,32 @@ type NotificationHandler struct {
 func NewNotificationHandler(
 	custClient ports.BotClientPort,
 	userRepo ports.UserRepository,
+	requestRepo ports.RequestRepository,
+	txRepo ports.TransactionRepository,
+	platformRepo ports.PlatformAccountRepository,
+	proofRepo ports.PayoutProofRepository,
+	currencies *domain.CurrencyRegistry,
 	baseLogger *zerolog.Logger,
 ) *NotificationHandler {
+	log := baseLogger.With().Str("component", "notification_handler").Logger()
 	return &NotificationHandler{
-		log:        baseLogger.With().Str("component", "notification_handler").Logger(),
-		custClient: custClient,
-		userRepo:   userRepo,
+		log:          log,
+		custClient:   custClient,
+		userRepo:     userRepo,
+		requestRepo:  requestRepo,
+		txRepo:       txRepo,
+		platformRepo: platformRepo,
+		proofRepo:    proofRepo,
+		currencies:   currencies,
+		intents:      newStartIntentDispatcher(requestRepo, txRepo, currencies, custClient, log),
 	}
 }
 
-// HandleUserApproved is an EventHandler for the "user:approved" topic.
-func (h *NotificationHandler) HandleUserApproved(ctx context.Context, event ports.Event) error {
-	user, ok := event.Data.(*domain.User)
-	if !ok {
-		h.log.Error().Msg("Received invalid data for 'user:approved' event")
-		return nil // Don't retry
-	}
+// HandleUserApproved handles events.UserApproved.
+func (h *NotificationHandler) HandleUserApproved(ctx context.Context, event events.UserApproved) error {
+	user := event.UserRef
 
-	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+	log := h.log.With().Str("user_id", user.UserID.String()).Logger()
 	log.Info().Msg("Sending approval notification to user")
 
 	msg := messages.NewBuilder(user.TelegramID).
@@ -52,18 +70,45 @@ func (h *NotificationHandler) HandleUserApproved(ctx context.Context, event port
 		log.Error().Err(err).Msg("Failed to send approval notification")
 		return err
 	}
+
+	h.replayStartIntent(ctx, log, user.UserID)
 	return nil
 }
 
-// HandleUserRejected is an EventHandler for the "user:rejected" topic.
-func (h *NotificationHandler) HandleUserRejected(ctx context.Context, event ports.Event) error {
-	user, ok := event.Data.(*domain.User)
+// replayStartIntent opens the deep link the user followed before approval.
+// The link is claimed first, so it is replayed at most once, and only that
+// column is cleared, so a flow the user started meanwhile is kept.
+func (h *NotificationHandler) replayStartIntent(ctx context.Context, log zerolog.Logger, userID uuid.UUID) {
+	payload, err := h.userRepo.ClaimPendingStartPayload(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to claim start payload")
+		return
+	}
+	if payload == nil {
+		return
+	}
+	intent, ok := parseStartIntent(*payload)
 	if !ok {
-		h.log.Error().Msg("Received invalid data for 'user:rejected' event")
-		return nil // Don't retry
+		return
+	}
+
+	user, err := h.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		log.Error().Err(err).Msg("Failed to reload user for start payload replay")
+		return
+	}
+
+	log.Info().Str("intent", string(intent.Kind)).Msg("Replaying start payload after approval")
+	if _, err := h.intents.Dispatch(ctx, user.TelegramID, user, intent); err != nil {
+		log.Error().Err(err).Msg("Failed to replay start payload")
 	}
+}
+
+// HandleUserRejected handles events.UserRejected.
+func (h *NotificationHandler) HandleUserRejected(ctx context.Context, event events.UserRejected) error {
+	user := event.UserRef
 
-	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+	log := h.log.With().Str("user_id", user.UserID.String()).Logger()
 	log.Info().Msg("Sending rejection notification to user")
 
 	msg := messages.NewBuilder(user.TelegramID).
@@ -78,3 +123,477 @@ func (h *NotificationHandler) HandleUserRejected(ctx context.Context, event port
 	}
 	return nil
 }
+
+// HandleUserUpgraded handles events.UserUpgraded.
+func (h *NotificationHandler) HandleUserUpgraded(ctx context.Context, event events.UserUpgraded) error {
+	user := event.UserRef
+
+	log := h.log.With().Str("user_id", user.UserID.String()).Logger()
+	log.Info().Msg("Sending upgrade notification to user")
+
+	msg := messages.NewBuilder(user.TelegramID).
+		WithText(
+			"⭐️ Your account has been *upgraded to level 2*\\! Your higher trading limits apply from now on\\.",
+		).
+		Build()
+
+	if _, err := h.custClient.SendMessage(ctx, msg); err != nil {
+		log.Error().Err(err).Msg("Failed to send upgrade notification")
+		return err
+	}
+	return nil
+}
+
+// HandleUpgradeRejected handles events.UpgradeRejected.
+// The user keeps their level_1 account.
+func (h *NotificationHandler) HandleUpgradeRejected(ctx context.Context, event events.UpgradeRejected) error {
+	user := event.UserRef
+
+	log := h.log.With().Str("user_id", user.UserID.String()).Logger()
+	log.Info().Msg("Sending upgrade rejection notification to user")
+
+	msg := messages.NewBuilder(user.TelegramID).
+		WithText(
+			"Your level 2 upgrade was *rejected*\\. Your account stays at level 1 and you can keep trading as before\\. Type /upgrade to submit new documents\\.",
+		).
+		Build()
+
+	if _, err := h.custClient.SendMessage(ctx, msg); err != nil {
+		log.Error().Err(err).Msg("Failed to send upgrade rejection notification")
+		return err
+	}
+	return nil
+}
+
+// HandleBidCreated handles events.BidCreated.
+// It asks the request owner to accept or reject the bid.
+func (h *NotificationHandler) HandleBidCreated(ctx context.Context, event events.BidCreated) error {
+	log := h.log.With().Str("bid_id", event.BidID.String()).Logger()
+
+	req, owner, err := h.requestAndOwner(ctx, event.RequestID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load request owner for bid notification")
+		return err
+	}
+
+	log.Info().Str("owner_id", owner.ID.String()).Msg("Sending new bid notification to request owner")
+
+	text := "🔔 *New bid on your request*\n\n" + formatRequestLine(req) + "\n"
+	if event.Notes != "" {
+		text += fmt.Sprintf("\n*Note:* %s\n", messages.EscapeMarkdown(event.Notes))
+	}
+
+	msg := messages.NewBuilder(owner.TelegramID).
+		WithText(text).
+		WithInlineButtons([][]ports.Button{
+			{
+				{Text: "✅ Accept", Data: "bid_accept_" + event.BidID.String()},
+				{Text: "❌ Reject", Data: "bid_reject_" + event.BidID.String()},
+			},
+		}).
+		Build()
+
+	if _, err := h.custClient.SendMessage(ctx, msg); err != nil {
+		log.Error().Err(err).Msg("Failed to send new bid notification")
+		return err
+	}
+	return nil
+}
+
+// HandleBidAccepted handles events.BidAccepted.
+func (h *NotificationHandler) HandleBidAccepted(ctx context.Context, event events.BidAccepted) error {
+	return h.notifyBidder(ctx, event.Topic(), event.BidRef, "🎉 Your bid was *accepted*\\! A trade has been opened and we will send you deposit instructions shortly\\.")
+}
+
+// HandleBidRejected handles events.BidRejected.
+func (h *NotificationHandler) HandleBidRejected(ctx context.Context, event events.BidRejected) error {
+	return h.notifyBidder(ctx, event.Topic(), event.BidRef, "Your bid was *not accepted*\\. Use /listrequests to find another offer\\.")
+}
+
+// HandleBidCancelled handles events.BidCancelled.
+// The scheduler cancels bids left pending on requests that closed.
+func (h *NotificationHandler) HandleBidCancelled(ctx context.Context, event events.BidCancelled) error {
+	return h.notifyBidder(ctx, event.Topic(), event.BidRef, "Your bid was *cancelled* because the offer is no longer open\\. Use /listrequests to find another offer\\.")
+}
+
+// HandleBidWithdrawn handles events.BidWithdrawn.
+// It tells the request owner that a bidder took their bid back.
+func (h *NotificationHandler) HandleBidWithdrawn(ctx context.Context, event events.BidWithdrawn) error {
+	log := h.log.With().Str("bid_id", event.BidID.String()).Logger()
+
+	req, owner, err := h.requestAndOwner(ctx, event.RequestID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load request owner for bid notification")
+		return err
+	}
+
+	log.Info().Str("owner_id", owner.ID.String()).Msg("Sending bid withdrawal notification to request owner")
+	msg := messages.NewBuilder(owner.TelegramID).
+		WithText("↩️ A bidder *withdrew* their bid on your request\\.\n\n" + formatRequestLine(req)).
+		Build()
+	if _, err := h.custClient.SendMessage(ctx, msg); err != nil {
+		log.Error().Err(err).Msg("Failed to send bid withdrawal notification")
+		return err
+	}
+	return nil
+}
+
+// HandleRequestExpired handles events.RequestExpired.
+// It tells the owner their offer timed out.
+func (h *NotificationHandler) HandleRequestExpired(ctx context.Context, event events.RequestExpired) error {
+	req := &event.Request
+
+	log := h.log.With().Str("request_id", req.ID.String()).Logger()
+
+	owner, err := h.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load request owner")
+		return err
+	}
+	if owner == nil {
+		log.Warn().Msg("Request owner no longer exists, skipping notification")
+		return nil
+	}
+
+	log.Info().Str("owner_id", owner.ID.String()).Msg("Sending request expiry notification")
+	msg := messages.NewBuilder(owner.TelegramID).
+		WithText("⌛ Your offer *expired* without a match and was closed\\. Use /newrequest to post it again\\.\n\n" + formatRequestLine(req)).
+		Build()
+	if _, err := h.custClient.SendMessage(ctx, msg); err != nil {
+		log.Error().Err(err).Msg("Failed to send request expiry notification")
+		return err
+	}
+	return nil
+}
+
+// HandleRegistrationStalled handles events.RegistrationStalled.
+// It reminds the user to finish signing up.
+func (h *NotificationHandler) HandleRegistrationStalled(ctx context.Context, event events.RegistrationStalled) error {
+	user := event.UserRef
+
+	log := h.log.With().Str("user_id", user.UserID.String()).Logger()
+	log.Info().Msg("Sending registration reminder to user")
+
+	msg := messages.NewBuilder(user.TelegramID).
+		WithText("👋 You have not finished your registration yet\\. Type /start to continue where you left off\\.").
+		Build()
+	if _, err := h.custClient.SendMessage(ctx, msg); err != nil {
+		log.Error().Err(err).Msg("Failed to send registration reminder")
+		return err
+	}
+	return nil
+}
+
+// HandleTransactionCreated returns the handler of events.TransactionCreated
+// for one party. It sends that party their deposit instructions.
+func (h *NotificationHandler) HandleTransactionCreated(leg domain.TransactionLeg) events.Handler[events.TransactionCreated] {
+	return func(ctx context.Context, event events.TransactionCreated) error {
+		tx := &event.Transaction
+
+		log := h.log.With().Str("transaction_id", tx.ID.String()).Str("leg", string(leg)).Logger()
+
+		req, err := h.requestRepo.GetByID(ctx, tx.RequestID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to load request for deposit instructions")
+			return err
+		}
+		if req == nil {
+			log.Error().Msg("Request of transaction not found, skipping deposit instructions")
+			return nil
+		}
+
+		deposit := req.DepositFor(h.currencies, leg)
+		text := "🤝 *Trade opened*\n\n" + formatRequestLine(req) + "\n\n" +
+			formatPayout(tx.PayoutFor(h.currencies, req, leg)) + "\n" +
+			fmt.Sprintf("Your part: deposit *%s*\\. ", messages.FormatMoney(deposit)) +
+			h.depositAccountText(ctx, tx, leg) +
+			"Once you have paid, upload a photo of the receipt\\."
+		buttons := [][]ports.Button{
+			{{Text: "📤 Upload receipt", Data: "receipt_upload_" + tx.ID.String()}},
+			cancelTradeButtonRow(tx.ID),
+			disputeButtonRow(tx.ID),
+		}
+		return h.notifyParty(ctx, tx, leg, text, buttons)
+	}
+}
+
+// depositAccountText tells the party where to pay, as MarkdownV2.
+// Without a platform account the moderators send the details by hand.
+func (h *NotificationHandler) depositAccountText(ctx context.Context, tx *domain.Transaction, leg domain.TransactionLeg) string {
+	fallback := "Our team will send you the account details\\. "
+
+	acctID := tx.DepositAccountFor(leg)
+	if acctID == nil {
+		return fallback
+	}
+	acct, err := h.platformRepo.GetByID(ctx, *acctID)
+	if err != nil || acct == nil {
+		h.log.Error().Err(err).Str("acct_id", acctID.String()).Msg("Failed to load platform deposit account")
+		return fallback
+	}
+	return fmt.Sprintf("Please pay into:\n*Bank:* %s\n*Details:* `%s`\n\n",
+		messages.EscapeMarkdown(acct.BankName), messages.EscapeMarkdown(acct.AccountDetails))
+}
+
+// depositWaiting tells the party whose deposit arrived first that we wait for the other one.
+const depositWaiting = "✅ Your deposit has been *confirmed*\\. We are now waiting for the other party's deposit\\."
+
+// HandleSellerDepositReceived handles events.SellerDepositReceived.
+func (h *NotificationHandler) HandleSellerDepositReceived(ctx context.Context, event events.SellerDepositReceived) error {
+	return h.notifyParty(ctx, &event.Transaction, domain.LegSeller, depositWaiting, nil)
+}
+
+// HandleBuyerDepositReceived handles events.BuyerDepositReceived.
+func (h *NotificationHandler) HandleBuyerDepositReceived(ctx context.Context, event events.BuyerDepositReceived) error {
+	return h.notifyParty(ctx, &event.Transaction, domain.LegBuyer, depositWaiting, nil)
+}
+
+// HandlePayoutsDue returns the handler of events.PayoutsDue for one party:
+// both deposits are in.
+func (h *NotificationHandler) HandlePayoutsDue(leg domain.TransactionLeg) events.Handler[events.PayoutsDue] {
+	return func(ctx context.Context, event events.PayoutsDue) error {
+		both := "✅ Both deposits have been *confirmed*\\. Your payout is on its way\\."
+		return h.notifyParty(ctx, &event.Transaction, leg, both, nil)
+	}
+}
+
+// HandleDepositRejected handles events.DepositRejected.
+// It asks the party to upload a new receipt.
+func (h *NotificationHandler) HandleDepositRejected(ctx context.Context, rejection events.DepositRejected) error {
+	log := h.log.With().Str("transaction_id", rejection.TransactionID.String()).Str("user_id", rejection.UserID.String()).Logger()
+
+	user, err := h.userRepo.GetByID(ctx, rejection.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load user for receipt rejection")
+		return err
+	}
+	if user == nil {
+		log.Warn().Msg("User no longer exists, skipping notification")
+		return nil
+	}
+
+	log.Info().Msg("Sending receipt rejection to user")
+	msg := messages.NewBuilder(user.TelegramID).
+		WithText(fmt.Sprintf(
+			"❌ Your deposit receipt was *rejected*\\.\n*Reason:* %s\n\nPlease upload a new receipt\\.",
+			messages.EscapeMarkdown(rejection.Reason),
+		)).
+		WithInlineButtons([][]ports.Button{
+			{{Text: "📤 Upload receipt", Data: "receipt_upload_" + rejection.TransactionID.String()}},
+		}).
+		Build()
+	if _, err := h.custClient.SendMessage(ctx, msg); err != nil {
+		log.Error().Err(err).Msg("Failed to send receipt rejection notification")
+		return err
+	}
+	return nil
+}
+
+// HandlePayoutSent handles events.PayoutProofSent.
+// It forwards the moderator's proof of payment to the recipient.
+func (h *NotificationHandler) HandlePayoutSent(ctx context.Context, proof events.PayoutProofSent) error {
+	log := h.log.With().Str("transaction_id", proof.TransactionID.String()).Str("user_id", proof.UserID.String()).Logger()
+
+	user, err := h.userRepo.GetByID(ctx, proof.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load payout recipient")
+		return err
+	}
+	if user == nil {
+		log.Warn().Msg("Payout recipient no longer exists, skipping notification")
+		return nil
+	}
+
+	photo, err := h.proofRepo.GetByID(ctx, proof.ProofID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load payout proof")
+		return err
+	}
+	if photo == nil {
+		log.Warn().Str("proof_id", proof.ProofID.String()).Msg("Payout proof no longer exists, skipping notification")
+		return nil
+	}
+
+	log.Info().Msg("Sending payout proof to user")
+	if _, err := h.custClient.SendPhoto(ctx, ports.SendPhotoParams{
+		ChatID:    user.TelegramID,
+		File:      tgbotapi.FileBytes{Name: "payout-proof.jpg", Bytes: photo.Photo},
+		Caption:   "💸 Your payout has been *sent*\\! Here is the proof of payment\\.",
+		ParseMode: "MarkdownV2",
+	}); err != nil {
+		log.Error().Err(err).Msg("Failed to send payout proof")
+		return err
+	}
+	return nil
+}
+
+// HandleTransactionCompleted returns the handler of events.TransactionCompleted for one party.
+func (h *NotificationHandler) HandleTransactionCompleted(leg domain.TransactionLeg) events.Handler[events.TransactionCompleted] {
+	return func(ctx context.Context, event events.TransactionCompleted) error {
+		text := "🎉 Your trade is *complete*\\. Thank you for using the exchange\\!"
+		return h.notifyParty(ctx, &event.Transaction, leg, text, nil)
+	}
+}
+
+// HandleTransactionWithdrawn handles events.TransactionWithdrawn.
+// It tells the other party that the trade was cancelled before any deposit.
+func (h *NotificationHandler) HandleTransactionWithdrawn(ctx context.Context, withdrawal events.TransactionWithdrawn) error {
+	tx := &withdrawal.Transaction
+	other := domain.LegBuyer
+	if withdrawal.UserID == tx.BuyerUserID {
+		other = domain.LegSeller
+	}
+	text := "✖️ The other party *cancelled* your trade before any money was sent\\. " +
+		"You do not need to make a deposit\\. The offer is back in the order book, see /listrequests\\."
+	return h.notifyParty(ctx, tx, other, text, nil)
+}
+
+// HandleDisputeOpened handles events.DisputeOpened.
+// It asks the other party for their side of the story.
+func (h *NotificationHandler) HandleDisputeOpened(ctx context.Context, event events.DisputeOpened) error {
+	dispute := event.Dispute
+
+	tx, err := h.txRepo.GetByID(ctx, dispute.TransactionID)
+	if err != nil {
+		h.log.Error().Err(err).Str("dispute_id", dispute.ID.String()).Msg("Failed to load disputed transaction")
+		return err
+	}
+	if tx == nil {
+		h.log.Error().Str("dispute_id", dispute.ID.String()).Msg("Disputed transaction not found, skipping notification")
+		return nil
+	}
+
+	other := domain.LegBuyer
+	if dispute.OpenedByUserID == tx.BuyerUserID {
+		other = domain.LegSeller
+	}
+	text := "⚠️ The other party has opened a *dispute* on your trade\\. " +
+		"The trade is paused until a moderator decides\\.\n\n" +
+		fmt.Sprintf("*Their reason:* %s\n\n", messages.EscapeMarkdown(dispute.Reason)) +
+		"Please send us your side of the story\\."
+	buttons := [][]ports.Button{{{Text: "📎 Add my evidence", Data: "dispute_open_" + tx.ID.String()}}}
+	return h.notifyParty(ctx, tx, other, text, buttons)
+}
+
+// HandleDisputeResolved returns the handler of events.DisputeResolved for one
+// party. It tells that party what the moderator decided.
+func (h *NotificationHandler) HandleDisputeResolved(leg domain.TransactionLeg) events.Handler[events.DisputeResolved] {
+	return func(ctx context.Context, event events.DisputeResolved) error {
+		dispute := event.Dispute
+		if dispute.Resolution == nil {
+			h.log.Error().Str("dispute_id", dispute.ID.String()).Msg("Resolved dispute has no resolution")
+			return nil // Don't retry
+		}
+
+		tx, err := h.txRepo.GetByID(ctx, dispute.TransactionID)
+		if err != nil {
+			h.log.Error().Err(err).Str("dispute_id", dispute.ID.String()).Msg("Failed to load disputed transaction")
+			return err
+		}
+		if tx == nil {
+			h.log.Error().Str("dispute_id", dispute.ID.String()).Msg("Disputed transaction not found, skipping notification")
+			return nil
+		}
+
+		var text string
+		switch *dispute.Resolution {
+		case domain.DisputeResolutionRefund:
+			text = "⚖️ The dispute on your trade was resolved with a *refund*\\. The trade is cancelled and any deposit you made will be returned to you\\."
+		case domain.DisputeResolutionComplete:
+			text = "⚖️ The dispute on your trade was resolved and the trade was *completed*\\."
+		default:
+			text = "⚖️ The dispute on your trade was resolved and the trade was *cancelled*\\."
+		}
+		return h.notifyParty(ctx, tx, leg, text, nil)
+	}
+}
+
+// notifyParty sends a MarkdownV2 message to the seller or the buyer of a transaction.
+// Handlers that tell both parties are built per party and subscribed once
+// each, so a retry only re-sends the message that failed.
+func (h *NotificationHandler) notifyParty(
+	ctx context.Context,
+	tx *domain.Transaction,
+	leg domain.TransactionLeg,
+	text string,
+	buttons [][]ports.Button,
+) error {
+	userID := tx.BuyerUserID
+	if leg == domain.LegSeller {
+		userID = tx.SellerUserID
+	}
+	log := h.log.With().Str("transaction_id", tx.ID.String()).Str("leg", string(leg)).Logger()
+
+	user, err := h.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load transaction party")
+		return err
+	}
+	if user == nil {
+		log.Warn().Msg("Transaction party no longer exists, skipping notification")
+		return nil
+	}
+
+	builder := messages.NewBuilder(user.TelegramID).WithText(text)
+	if buttons != nil {
+		builder = builder.WithInlineButtons(buttons)
+	}
+	log.Info().Msg("Sending transaction notification")
+	if _, err := h.custClient.SendMessage(ctx, builder.Build()); err != nil {
+		log.Error().Err(err).Msg("Failed to send transaction notification")
+		return err
+	}
+	return nil
+}
+
+// notifyBidder tells the author of a bid what happened to it.
+func (h *NotificationHandler) notifyBidder(ctx context.Context, topic string, bid events.BidRef, outcome string) error {
+	log := h.log.With().Str("bid_id", bid.BidID.String()).Str("topic", topic).Logger()
+
+	bidder, err := h.userRepo.GetByID(ctx, bid.BidderID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load bidder")
+		return err
+	}
+	if bidder == nil {
+		log.Warn().Msg("Bidder no longer exists, skipping notification")
+		return nil
+	}
+	req, err := h.requestRepo.GetByID(ctx, bid.RequestID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load request for bid notification")
+		return err
+	}
+
+	text := outcome
+	if req != nil {
+		text += "\n\n" + formatRequestLine(req)
+	}
+
+	log.Info().Msg("Sending bid outcome to bidder")
+	msg := messages.NewBuilder(bidder.TelegramID).WithText(text).Build()
+	if _, err := h.custClient.SendMessage(ctx, msg); err != nil {
+		log.Error().Err(err).Msg("Failed to send bid outcome notification")
+		return err
+	}
+	return nil
+}
+
+// requestAndOwner loads a request together with the user who created it.
+func (h *NotificationHandler) requestAndOwner(ctx context.Context, requestID uuid.UUID) (*domain.Request, *domain.User, error) {
+	req, err := h.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if req == nil {
+		return nil, nil, fmt.Errorf("request %s not found", requestID)
+	}
+	owner, err := h.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if owner == nil {
+		return nil, nil, fmt.Errorf("owner of request %s not found", requestID)
+	}
+	return req, owner, nil
+}
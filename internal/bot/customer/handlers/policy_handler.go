@@ -5,7 +5,7 @@ import (
 	"AsaExchange/internal/bot/messages"
 	"AsaExchange/internal/core/domain"
 	"AsaExchange/internal/core/ports"
-	"AsaExchange/internal/shared/config"
+	"AsaExchange/internal/core/services"
 	"context"
 	"fmt"
 
@@ -21,19 +21,16 @@ type policyHandler struct {
 	log      zerolog.Logger
 	userRepo ports.UserRepository
 	bot      ports.BotClientPort
+	verifier *services.VerificationService
 }
 
 // NewPolicyHandler creates a new handler for policy callbacks
-func NewPolicyHandler(
-	cfg *config.Config,
-	userRepo ports.UserRepository,
-	bot ports.BotClientPort,
-	baseLogger *zerolog.Logger,
-) ports.CallbackHandler {
+func NewPolicyHandler(deps *customer.HandlerDeps) ports.CallbackHandler {
 	return &policyHandler{
-		log:      baseLogger.With().Str("component", "policy_handler").Logger(),
-		userRepo: userRepo,
-		bot:      bot,
+		log:      deps.BaseLogger.With().Str("component", "policy_handler").Logger(),
+		userRepo: deps.UserRepo,
+		bot:      deps.BotClient,
+		verifier: deps.Verifier,
 	}
 }
 
@@ -59,15 +56,31 @@ func (h *policyHandler) Handle(ctx context.Context, update *ports.BotUpdate, use
 	case "policy_accept":
 		log.Info().Msg("User accepted policy. Completing registration.")
 
-		// 1. Update user state
-		user.State = domain.StateNone // Registration is complete
+		// 1. Edit the original policy message to remove the buttons
+		h.bot.EditMessageText(ctx, ports.EditMessageParams{
+			ChatID:    update.ChatID,
+			MessageID: update.MessageID,
+			Text:      "You have accepted the terms of service.",
+		})
+
+		// 2. Run the verification strategy of the user's country.
 		// user.VerificationStatus is already 'pending'
-		if err := h.userRepo.Update(ctx, user); err != nil {
+		decision, err := h.verifier.Submit(ctx, user)
+		if err != nil {
 			log.Error().Err(err).Msg("Failed to update user state after policy accept")
 			return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
 		}
 
-		// 2. Send confirmation and remove inline keyboard
+		switch decision.Outcome {
+		case ports.VerificationApprove, ports.VerificationReject:
+			// The notification handler tells the user the verdict
+			return nil
+		case ports.VerificationNeedFields:
+			return sendVerificationPrompt(ctx, h.bot, update.ChatID, decision.Fields[0],
+				"One more step: we need a few more details to confirm your identity.")
+		}
+
+		// 3. Left for a moderator
 		msg := messages.NewBuilder(update.ChatID).
 			WithText(fmt.Sprintf(
 				"✅ *Registration Complete\\!*\n\nThank you, %s\\. Your account is now submitted and *pending admin verification*\\.\n\nWe will notify you as soon as you are approved to make transactions\\.",
@@ -75,14 +88,7 @@ func (h *policyHandler) Handle(ctx context.Context, update *ports.BotUpdate, use
 			)).
 			Build()
 
-		// 3. Edit the original policy message to remove the buttons
-		h.bot.EditMessageText(ctx, ports.EditMessageParams{
-			ChatID:    update.ChatID,
-			MessageID: update.MessageID,
-			Text:      "You have accepted the terms of service.",
-		})
-
-		_, err := h.bot.SendMessage(ctx, msg)
+		_, err = h.bot.SendMessage(ctx, msg)
 		return err
 	case "policy_decline":
 		log.Info().Msg("User declined policy. Resetting registration.")
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCallback(NewReceiptCallbackHandler)
+}
+
+// Keys used in user.StateData while a deposit receipt is being uploaded.
+const draftKeyReceiptTx = "receipt_tx_id"
+
+// receiptCallbackHandler starts and cancels the deposit receipt upload.
+// The photo itself is handled by deposit_receipt_flow.go.
+type receiptCallbackHandler struct {
+	log      zerolog.Logger
+	userRepo ports.UserRepository
+	txRepo   ports.TransactionRepository
+	bot      ports.BotClientPort
+}
+
+// NewReceiptCallbackHandler creates a new handler for "receipt_" callbacks.
+func NewReceiptCallbackHandler(deps *customer.HandlerDeps) ports.CallbackHandler {
+	return &receiptCallbackHandler{
+		log:      deps.BaseLogger.With().Str("component", "receipt_callback").Logger(),
+		userRepo: deps.UserRepo,
+		txRepo:   deps.TransactionRepo,
+		bot:      deps.BotClient,
+	}
+}
+
+// Prefix returns the prefix this handler is responsible for.
+func (h *receiptCallbackHandler) Prefix() string {
+	return "receipt_"
+}
+
+// Handle processes "receipt_upload_<txid>" and "receipt_abort_<txid>".
+func (h *receiptCallbackHandler) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	// 1. Parse the callback data
+	parts := strings.Split(*update.CallbackData, "_")
+	if len(parts) != 3 {
+		log.Warn().Str("data", *update.CallbackData).Msg("Invalid receipt callback format")
+		return h.answer(ctx, update, "")
+	}
+	txID, err := uuid.Parse(parts[2])
+	if err != nil {
+		log.Warn().Err(err).Str("data", *update.CallbackData).Msg("Failed to parse UUID from callback")
+		return h.answer(ctx, update, "")
+	}
+
+	// 2. Route the action
+	switch parts[1] {
+	case "upload":
+		return h.handleUpload(ctx, update, user, txID)
+	case "abort":
+		return h.handleAbort(ctx, update, user)
+	}
+
+	log.Warn().Str("data", *update.CallbackData).Msg("Unknown receipt callback")
+	return h.answer(ctx, update, "")
+}
+
+// handleUpload moves the party into the receipt upload step.
+func (h *receiptCallbackHandler) handleUpload(ctx context.Context, update *ports.BotUpdate, user *domain.User, txID uuid.UUID) error {
+	tx, _, reason := awaitedDeposit(ctx, h.log, h.txRepo, user, txID)
+	if tx == nil {
+		return h.answer(ctx, update, reason)
+	}
+	h.answer(ctx, update, "")
+
+	user.State = domain.StateAwaitingDepositReceipt
+	user.StateData = map[string]string{draftKeyReceiptTx: tx.ID.String()}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to update user state for receipt upload")
+		return h.sendMessage(ctx, update.ChatID, "An internal error occurred\\.", nil)
+	}
+
+	return h.sendMessage(ctx, update.ChatID,
+		"Please send a *photo* of your payment receipt\\.",
+		[][]ports.Button{{{Text: "✖️ Cancel", Data: "receipt_abort_" + tx.ID.String()}}},
+	)
+}
+
+// handleAbort leaves the upload step.
+func (h *receiptCallbackHandler) handleAbort(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	h.answer(ctx, update, "")
+
+	if user.State == domain.StateAwaitingDepositReceipt {
+		user.State = domain.StateNone
+		user.StateData = map[string]string{}
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to clear receipt upload state")
+			return h.editMessage(ctx, update, "An internal error occurred.")
+		}
+	}
+	return h.editMessage(ctx, update, "Receipt upload cancelled.")
+}
+
+// answer stops the button spinner, showing text as a toast if given.
+func (h *receiptCallbackHandler) answer(ctx context.Context, update *ports.BotUpdate, text string) error {
+	return h.bot.AnswerCallbackQuery(ctx, ports.AnswerCallbackParams{
+		CallbackQueryID: update.CallbackQueryID,
+		Text:            text,
+	})
+}
+
+// editMessage replaces the button message with plain text.
+func (h *receiptCallbackHandler) editMessage(ctx context.Context, update *ports.BotUpdate, text string) error {
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:    update.ChatID,
+		MessageID: update.MessageID,
+		Text:      text,
+	})
+}
+
+// sendMessage sends a MarkdownV2 message with optional inline buttons.
+func (h *receiptCallbackHandler) sendMessage(ctx context.Context, chatID int64, text string, buttons [][]ports.Button) error {
+	builder := messages.NewBuilder(chatID).WithText(text)
+	if buttons != nil {
+		builder = builder.WithInlineButtons(buttons)
+	}
+	_, err := h.bot.SendMessage(ctx, builder.Build())
+	return err
+}
+
+// legOf returns which side of the transaction the user is on.
+func legOf(tx *domain.Transaction, userID uuid.UUID) (domain.TransactionLeg, bool) {
+	switch userID {
+	case tx.SellerUserID:
+		return domain.LegSeller, true
+	case tx.BuyerUserID:
+		return domain.LegBuyer, true
+	}
+	return "", false
+}
+
+// awaitedDeposit loads a transaction and checks that we are still waiting for
+// the user's deposit on it. On failure it returns a nil transaction and a
+// plain-text reason for the user.
+func awaitedDeposit(
+	ctx context.Context,
+	log zerolog.Logger,
+	txRepo ports.TransactionRepository,
+	user *domain.User,
+	txID uuid.UUID,
+) (*domain.Transaction, domain.TransactionLeg, string) {
+	tx, err := txRepo.GetByID(ctx, txID)
+	if err != nil {
+		log.Error().Err(err).Str("transaction_id", txID.String()).Msg("Failed to get transaction")
+		return nil, "", "An internal error occurred."
+	}
+	if tx == nil {
+		return nil, "", "This trade no longer exists."
+	}
+	leg, ok := legOf(tx, user.ID)
+	if !ok {
+		return nil, "", "This is not one of your trades."
+	}
+	if _, ok := services.DepositTarget(tx.Status, leg); !ok {
+		return nil, "", "We are not waiting for a deposit from you on this trade."
+	}
+	return tx, leg, ""
+}
@@ -30,19 +30,13 @@ type registrationHandler struct {
 }
 
 // NewRegistrationHandler
-func NewRegistrationHandler(
-	cfg *config.Config,
-	userRepo ports.UserRepository,
-	bot ports.BotClientPort,
-	queue ports.VerificationQueue,
-	baseLogger *zerolog.Logger,
-) ports.MessageHandler {
+func NewRegistrationHandler(deps *customer.HandlerDeps) ports.MessageHandler {
 	return &registrationHandler{
-		log:               baseLogger.With().Str("component", "reg_handler").Logger(),
-		userRepo:          userRepo,
-		bot:               bot,
-		countryStrategies: cfg.Bot.Customer.CountryStrategies,
-		queue:             queue,
+		log:               deps.BaseLogger.With().Str("component", "reg_handler").Logger(),
+		userRepo:          deps.UserRepo,
+		bot:               deps.BotClient,
+		countryStrategies: deps.Cfg.Bot.Customer.CountryStrategies,
+		queue:             deps.Queue,
 	}
 }
 
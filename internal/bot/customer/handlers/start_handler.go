@@ -5,6 +5,7 @@ import (
 	"AsaExchange/internal/bot/messages"
 	"AsaExchange/internal/core/domain"
 	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
 	"AsaExchange/internal/shared/config"
 	"context"
 	"fmt"
@@ -22,21 +23,21 @@ type startHandler struct {
 	log               zerolog.Logger
 	userRepo          ports.UserRepository
 	bot               ports.BotClientPort
+	intents           *startIntentDispatcher
 	countryStrategies map[string]config.CountryConfig
+	verifier          *services.VerificationService
 }
 
 // NewStartHandler creates a new handler for the /start command.
-func NewStartHandler(
-	cfg *config.Config,
-	userRepo ports.UserRepository,
-	bot ports.BotClientPort,
-	baseLogger *zerolog.Logger,
-) ports.CommandHandler {
+func NewStartHandler(deps *customer.HandlerDeps) ports.CommandHandler {
+	log := deps.BaseLogger.With().Str("component", "start_handler").Logger()
 	return &startHandler{
-		log:               baseLogger.With().Str("component", "start_handler").Logger(),
-		userRepo:          userRepo,
-		bot:               bot,
-		countryStrategies: cfg.Bot.Customer.CountryStrategies,
+		log:               log,
+		userRepo:          deps.UserRepo,
+		bot:               deps.BotClient,
+		intents:           newStartIntentDispatcher(deps.RequestRepo, deps.TransactionRepo, deps.Cfg.Currencies, deps.BotClient, log),
+		countryStrategies: deps.Cfg.Bot.Customer.CountryStrategies,
+		verifier:          deps.Verifier,
 	}
 }
 
@@ -46,10 +47,17 @@ func (h *startHandler) Command() string {
 }
 
 // Handle processes the /start command with the new logic.
+// A deep-link payload (see start_intent.go) is acted on right away for
+// verified users and stashed until approval for everyone else.
 func (h *startHandler) Handle(ctx context.Context, update *ports.BotUpdate) error {
 	log := h.log.With().Int64("user_id", update.UserID).Logger()
 	ctx = log.WithContext(ctx)
 
+	intent, hasIntent := parseStartIntent(update.CommandArgs)
+	if update.CommandArgs != "" && !hasIntent {
+		log.Warn().Str("payload", update.CommandArgs).Msg("Ignoring unknown start payload")
+	}
+
 	user, err := h.userRepo.GetByTelegramID(ctx, update.UserID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get user from repository")
@@ -68,6 +76,13 @@ func (h *startHandler) Handle(ctx context.Context, update *ports.BotUpdate) erro
 			VerificationStatus: domain.VerificationPending,
 			State:              domain.StateAwaitingFirstName,
 		}
+		if hasIntent && intent.Kind == intentReferral {
+			h.applyReferral(ctx, log, newUser, intent.ID)
+		}
+		if hasIntent && intent.replayable() {
+			payload := intent.payload()
+			newUser.PendingStartPayload = &payload
+		}
 
 		if err := h.userRepo.Create(ctx, newUser); err != nil {
 			log.Error().Err(err).Msg("Failed to create new user")
@@ -76,6 +91,9 @@ func (h *startHandler) Handle(ctx context.Context, update *ports.BotUpdate) erro
 		log.Info().Str("user_id", newUser.ID.String()).Msg("New user created successfully")
 
 		text := "👋 Welcome to AsaExchange\\!\n\nTo use our service, you must first register an account\\.\n\n"
+		if newUser.PendingStartPayload != nil {
+			text += "We will take you to the link you opened once your account is approved\\.\n\n"
+		}
 		text += "Please reply with your *legal First Name* as it appears on your ID\\."
 		msg = messages.NewBuilder(update.ChatID).WithText(text).WithRemoveKeyboard().Build()
 
@@ -83,6 +101,19 @@ func (h *startHandler) Handle(ctx context.Context, update *ports.BotUpdate) erro
 		// --- CASE 2: EXISTING USER ---
 		log.Info().Str("user_id", user.ID.String()).Str("status", string(user.VerificationStatus)).Msg("Existing user found.")
 
+		if hasIntent && user.VerificationStatus.IsVerified() {
+			if handled, err := h.intents.Dispatch(ctx, update.ChatID, user, intent); handled {
+				return err
+			}
+		}
+		if hasIntent && intent.replayable() && !user.VerificationStatus.IsVerified() {
+			payload := intent.payload()
+			user.PendingStartPayload = &payload
+			if err := h.userRepo.Update(ctx, user); err != nil {
+				log.Error().Err(err).Msg("Failed to stash start payload")
+			}
+		}
+
 		var responseText string
 		switch user.VerificationStatus {
 		case domain.VerificationPending:
@@ -115,6 +146,11 @@ func (h *startHandler) Handle(ctx context.Context, update *ports.BotUpdate) erro
 				responseText = "Please upload a *single, clear photo* of your Government ID or Passport\\."
 			case domain.StateAwaitingPolicyApproval:
 				responseText = "Please review our terms of service and *accept or decline* the policy\\."
+			case domain.StateAwaitingVerificationField:
+				if field, ok := h.verifier.NextField(user); ok {
+					return sendVerificationPrompt(ctx, h.bot, update.ChatID, field, "")
+				}
+				responseText = "Your account is still *pending verification*\\. Please wait\\."
 			case domain.StateNone:
 				if user.FirstName != nil {
 					responseText = fmt.Sprintf(
@@ -146,7 +182,7 @@ func (h *startHandler) Handle(ctx context.Context, update *ports.BotUpdate) erro
 
 			responseText = "Your previous registration was rejected\\.\n\nYou may try again\\. Please reply with your *legal First Name*\\."
 
-		case domain.VerificationLevel1:
+		default: // Any verification level
 			responseText = fmt.Sprintf(
 				"👋 Welcome back, %s\\! Use the menu to get started\\.",
 				*user.FirstName,
@@ -162,6 +198,21 @@ func (h *startHandler) Handle(ctx context.Context, update *ports.BotUpdate) erro
 	return err
 }
 
+// applyReferral records the referrer of a new user if that user exists.
+func (h *startHandler) applyReferral(ctx context.Context, log zerolog.Logger, newUser *domain.User, referrerID uuid.UUID) {
+	referrer, err := h.userRepo.GetByID(ctx, referrerID)
+	if err != nil {
+		log.Error().Err(err).Str("referrer_id", referrerID.String()).Msg("Failed to look up referrer")
+		return
+	}
+	if referrer == nil {
+		log.Warn().Str("referrer_id", referrerID.String()).Msg("Ignoring referral from unknown user")
+		return
+	}
+	newUser.ReferredByUserID = &referrer.ID
+	log.Info().Str("referrer_id", referrerID.String()).Msg("New user was referred")
+}
+
 // sendErrorMessage is a helper to send a generic error
 func (h *startHandler) sendErrorMessage(ctx context.Context, chatID int64) error {
 	msgParams := messages.NewBuilder(chatID).
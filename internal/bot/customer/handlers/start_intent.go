@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// startIntentKind is what a t.me/<bot>?start=<kind>_<id> link asks for.
+type startIntentKind string
+
+const (
+	intentOffer       startIntentKind = "offer" // Open the bid flow of a request
+	intentReferral    startIntentKind = "ref"   // Record who invited the user; <id> is the referrer's user ID
+	intentTransaction startIntentKind = "tx"    // Show one of the user's trades
+)
+
+// startIntent is a parsed /start payload.
+type startIntent struct {
+	Kind startIntentKind
+	ID   uuid.UUID
+}
+
+// parseStartIntent parses a "<kind>_<uuid>" /start payload.
+func parseStartIntent(payload string) (startIntent, bool) {
+	kind, rawID, found := strings.Cut(strings.TrimSpace(payload), "_")
+	if !found {
+		return startIntent{}, false
+	}
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return startIntent{}, false
+	}
+	switch k := startIntentKind(kind); k {
+	case intentOffer, intentReferral, intentTransaction:
+		return startIntent{Kind: k, ID: id}, true
+	}
+	return startIntent{}, false
+}
+
+// payload is the inverse of parseStartIntent.
+func (i startIntent) payload() string {
+	return string(i.Kind) + "_" + i.ID.String()
+}
+
+// replayable reports whether the intent should wait for approval instead of
+// being dropped. Referrals are recorded at sign-up and never replayed.
+func (i startIntent) replayable() bool {
+	return i.Kind == intentOffer || i.Kind == intentTransaction
+}
+
+// startIntentDispatcher opens the screen a deep link points at.
+// It is shared by /start and the approval notification, which replays
+// links opened before the user was verified.
+type startIntentDispatcher struct {
+	log         zerolog.Logger
+	requestRepo ports.RequestRepository
+	txRepo      ports.TransactionRepository
+	currencies  *domain.CurrencyRegistry
+	bot         ports.BotClientPort
+}
+
+func newStartIntentDispatcher(
+	requestRepo ports.RequestRepository,
+	txRepo ports.TransactionRepository,
+	currencies *domain.CurrencyRegistry,
+	bot ports.BotClientPort,
+	log zerolog.Logger,
+) *startIntentDispatcher {
+	return &startIntentDispatcher{log: log, requestRepo: requestRepo, txRepo: txRepo, currencies: currencies, bot: bot}
+}
+
+// Dispatch sends the view for a verified user.
+// It returns false if the intent has no view (a referral).
+func (d *startIntentDispatcher) Dispatch(ctx context.Context, chatID int64, user *domain.User, intent startIntent) (bool, error) {
+	switch intent.Kind {
+	case intentOffer:
+		return true, d.sendOffer(ctx, chatID, user, intent.ID)
+	case intentTransaction:
+		return true, d.sendTransaction(ctx, chatID, user, intent.ID)
+	}
+	return false, nil
+}
+
+// sendOffer shows an offer with the bid options, like the order book's "Bid" button.
+func (d *startIntentDispatcher) sendOffer(ctx context.Context, chatID int64, user *domain.User, requestID uuid.UUID) error {
+	req, err := d.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		d.log.Error().Err(err).Str("request_id", requestID.String()).Msg("Failed to get request for deep link")
+		return d.sendPlain(ctx, chatID, "An internal error occurred.")
+	}
+	if req == nil || req.Status != domain.RequestStatusOpen {
+		return d.sendPlain(ctx, chatID, "This offer is no longer available. Use /listrequests to see open offers.")
+	}
+	if req.UserID == user.ID {
+		return d.sendPlain(ctx, chatID, "This is your own offer.")
+	}
+
+	_, err = d.bot.SendMessage(ctx, offerViewMessage(d.currencies, chatID, req))
+	return err
+}
+
+// sendTransaction shows the user's side of a trade.
+func (d *startIntentDispatcher) sendTransaction(ctx context.Context, chatID int64, user *domain.User, txID uuid.UUID) error {
+	log := d.log.With().Str("transaction_id", txID.String()).Logger()
+
+	tx, err := d.txRepo.GetByID(ctx, txID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get transaction for deep link")
+		return d.sendPlain(ctx, chatID, "An internal error occurred.")
+	}
+	if tx == nil {
+		return d.sendPlain(ctx, chatID, "This trade no longer exists.")
+	}
+	leg, ok := legOf(tx, user.ID)
+	if !ok {
+		return d.sendPlain(ctx, chatID, "This is not one of your trades.")
+	}
+	req, err := d.requestRepo.GetByID(ctx, tx.RequestID)
+	if err != nil || req == nil {
+		log.Error().Err(err).Msg("Failed to get request of transaction")
+		return d.sendPlain(ctx, chatID, "An internal error occurred.")
+	}
+
+	deposit := req.DepositFor(d.currencies, leg)
+	payout := tx.PayoutFor(d.currencies, req, leg)
+
+	var text strings.Builder
+	text.WriteString("📄 *Trade*\n\n" + formatRequestLine(req) + "\n\n")
+	text.WriteString(fmt.Sprintf("*Your side:* %s\n", leg))
+	text.WriteString(fmt.Sprintf("*You deposit:* %s\n", messages.FormatMoney(deposit)))
+	text.WriteString(formatPayout(payout))
+	text.WriteString(fmt.Sprintf("*Status:* %s", messages.EscapeMarkdown(strings.ReplaceAll(string(tx.Status), "_", " "))))
+
+	var buttons [][]ports.Button
+	if _, ok := services.DepositTarget(tx.Status, leg); ok {
+		buttons = append(buttons, []ports.Button{{Text: "📤 Upload receipt", Data: "receipt_upload_" + tx.ID.String()}})
+	}
+	if tx.Status == domain.TxStatusPendingDeposits {
+		buttons = append(buttons, cancelTradeButtonRow(tx.ID))
+	}
+	if services.CanTransition(tx.Status, domain.TxStatusDisputed) {
+		buttons = append(buttons, disputeButtonRow(tx.ID))
+	}
+
+	builder := messages.NewBuilder(chatID).WithText(text.String())
+	if buttons != nil {
+		builder = builder.WithInlineButtons(buttons)
+	}
+	_, err = d.bot.SendMessage(ctx, builder.Build())
+	return err
+}
+
+// sendPlain sends a plain-text message.
+func (d *startIntentDispatcher) sendPlain(ctx context.Context, chatID int64, text string) error {
+	_, err := d.bot.SendMessage(ctx, messages.NewBuilder(chatID).WithText(text).WithParseMode("").Build())
+	return err
+}
+
+// offerViewMessage shows an offer with the bid options.
+func offerViewMessage(currencies *domain.CurrencyRegistry, chatID int64, req *domain.Request) ports.SendMessageParams {
+	return messages.NewBuilder(chatID).
+		WithText(formatOfferDetails(currencies, req) + "\nYou can bid right away or attach a short note for the owner\\.").
+		WithInlineButtons([][]ports.Button{
+			{
+				{Text: "💰 Place bid", Data: "bid_place_" + req.ID.String()},
+				{Text: "📝 Bid with a note", Data: "bid_note_" + req.ID.String()},
+			},
+		}).
+		Build()
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCallback(NewUpgradeCallbackHandler)
+}
+
+// upgradeCallbackHandler handles the cancel button of the /upgrade flow.
+type upgradeCallbackHandler struct {
+	log      zerolog.Logger
+	userRepo ports.UserRepository
+	bot      ports.BotClientPort
+}
+
+// NewUpgradeCallbackHandler creates a new handler for "upgrade_" callbacks.
+func NewUpgradeCallbackHandler(deps *customer.HandlerDeps) ports.CallbackHandler {
+	return &upgradeCallbackHandler{
+		log:      deps.BaseLogger.With().Str("component", "upgrade_callback").Logger(),
+		userRepo: deps.UserRepo,
+		bot:      deps.BotClient,
+	}
+}
+
+// Prefix returns the prefix this handler is responsible for.
+func (h *upgradeCallbackHandler) Prefix() string {
+	return "upgrade_"
+}
+
+// Handle processes "upgrade_cancel".
+// Documents already queued stay in the upload channel but are never reviewed.
+func (h *upgradeCallbackHandler) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	h.bot.AnswerCallbackQuery(ctx, ports.AnswerCallbackParams{
+		CallbackQueryID: update.CallbackQueryID,
+	})
+
+	if *update.CallbackData != "upgrade_cancel" {
+		log.Warn().Str("data", *update.CallbackData).Msg("Unknown upgrade callback")
+		return nil
+	}
+	if user.State != domain.StateAwaitingAddressProof && user.State != domain.StateAwaitingSelfie {
+		return h.editMessage(ctx, update, "This upgrade is no longer in progress.")
+	}
+
+	user.State = domain.StateNone
+	user.StateData = map[string]string{}
+	user.AddressDocRef = nil
+	user.SelfieDocRef = nil
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		log.Error().Err(err).Msg("Failed to cancel upgrade flow")
+		return h.editMessage(ctx, update, "An internal error occurred.")
+	}
+
+	log.Info().Msg("User cancelled level 2 upgrade")
+	return h.editMessage(ctx, update, "Upgrade cancelled. You can start again any time with /upgrade.")
+}
+
+// editMessage replaces the button message with plain text.
+func (h *upgradeCallbackHandler) editMessage(ctx context.Context, update *ports.BotUpdate, text string) error {
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:    update.ChatID,
+		MessageID: update.MessageID,
+		Text:      text,
+	})
+}
+
+// upgradeCancelButtonRow lets the user leave the /upgrade flow.
+func upgradeCancelButtonRow() []ports.Button {
+	return []ports.Button{{Text: "✖️ Cancel", Data: "upgrade_cancel"}}
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterState(NewUpgradeFlow)
+}
+
+// upgradeFlow collects the level_2 documents and queues them for review.
+// Both photos travel the same VerificationQueue path as the sign-up ID photo.
+type upgradeFlow struct {
+	log      zerolog.Logger
+	userRepo ports.UserRepository
+	bot      ports.BotClientPort
+	queue    ports.VerificationQueue
+}
+
+// NewUpgradeFlow creates the state handler for the /upgrade steps.
+func NewUpgradeFlow(deps *customer.HandlerDeps) ports.StateHandler {
+	return &upgradeFlow{
+		log:      deps.BaseLogger.With().Str("component", "upgrade_flow").Logger(),
+		userRepo: deps.UserRepo,
+		bot:      deps.BotClient,
+		queue:    deps.Queue,
+	}
+}
+
+// States returns the user states owned by this flow.
+func (h *upgradeFlow) States() []domain.UserState {
+	return []domain.UserState{domain.StateAwaitingAddressProof, domain.StateAwaitingSelfie}
+}
+
+// Handle routes the reply to the current step.
+func (h *upgradeFlow) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	// The account may have changed since the flow started
+	if !user.CanRequestUpgrade() {
+		user.State = domain.StateNone
+		user.StateData = map[string]string{}
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			h.log.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to leave upgrade flow")
+		}
+		return h.sendMessage(ctx, update.ChatID, "Your account can no longer be upgraded\\.")
+	}
+	if update.Photo == nil {
+		return h.sendMessage(ctx, update.ChatID, "Please upload a *photo*, not text\\.")
+	}
+
+	switch user.State {
+	case domain.StateAwaitingAddressProof:
+		return h.handleAddressProof(ctx, update, user)
+	case domain.StateAwaitingSelfie:
+		return h.handleSelfie(ctx, update, user)
+	}
+	return nil
+}
+
+// handleAddressProof queues the proof of address and asks for the selfie.
+func (h *upgradeFlow) handleAddressProof(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	storageRef, err := h.publish(ctx, user, ports.VerificationDocAddress, update.Photo.FileID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to publish proof of address to verification queue")
+		return h.sendMessage(ctx, update.ChatID, "We could not submit your photo\\. Please try again\\.")
+	}
+
+	user.AddressDocRef = &storageRef
+	user.State = domain.StateAwaitingSelfie
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		log.Error().Err(err).Msg("Failed to save proof of address")
+		return h.sendMessage(ctx, update.ChatID, "An internal error occurred\\.")
+	}
+	log.Info().Str("storage_ref", storageRef).Msg("Proof of address submitted")
+
+	msg := messages.NewBuilder(update.ChatID).
+		WithText("Thank you\\. Now please upload a *selfie* of you holding your ID next to your face\\. Both must be clearly visible\\.").
+		WithInlineButtons([][]ports.Button{upgradeCancelButtonRow()}).
+		Build()
+	_, err = h.bot.SendMessage(ctx, msg)
+	return err
+}
+
+// handleSelfie queues the selfie, which puts the upgrade up for review.
+func (h *upgradeFlow) handleSelfie(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	storageRef, err := h.publish(ctx, user, ports.VerificationDocSelfie, update.Photo.FileID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to publish selfie to verification queue")
+		return h.sendMessage(ctx, update.ChatID, "We could not submit your photo\\. Please try again\\.")
+	}
+
+	now := time.Now()
+	user.SelfieDocRef = &storageRef
+	user.UpgradeRequestedAt = &now
+	user.State = domain.StateNone
+	user.StateData = map[string]string{}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		log.Error().Err(err).Msg("Failed to save selfie")
+		return h.sendMessage(ctx, update.ChatID, "An internal error occurred\\.")
+	}
+	log.Info().Str("storage_ref", storageRef).Msg("Level 2 upgrade submitted for review")
+
+	return h.sendMessage(ctx, update.ChatID,
+		"📨 Thank you\\! Your upgrade has been submitted and is *pending review*\\. You can keep trading at level 1 in the meantime\\.")
+}
+
+// publish sends one upgrade photo to the verification queue.
+func (h *upgradeFlow) publish(ctx context.Context, user *domain.User, document ports.VerificationDocument, fileID string) (string, error) {
+	var caption strings.Builder
+	caption.WriteString("Level 2 Upgrade\n")
+	caption.WriteString(fmt.Sprintf("UserID: %s\n", user.ID.String()))
+	if user.FirstName != nil {
+		caption.WriteString(fmt.Sprintf("First Name: %s\n", *user.FirstName))
+	}
+	if user.LastName != nil {
+		caption.WriteString(fmt.Sprintf("Last Name: %s\n", *user.LastName))
+	}
+
+	return h.queue.Publish(ctx, ports.NewVerificationEvent{
+		UserID:   user.ID,
+		Document: document,
+		FileID:   fileID,
+		Caption:  caption.String(),
+	})
+}
+
+// sendMessage is a helper to send a MarkdownV2 reply
+func (h *upgradeFlow) sendMessage(ctx context.Context, chatID int64, text string) error {
+	_, err := h.bot.SendMessage(ctx, messages.NewBuilder(chatID).WithText(text).Build())
+	return err
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterCommand(NewUpgradeHandler)
+}
+
+// upgradeHandler is the plugin for the /upgrade command.
+// It opens the level_2 flow; the steps live in upgrade_flow.go
+// and the cancel button in upgrade_callback.go.
+type upgradeHandler struct {
+	log      zerolog.Logger
+	userRepo ports.UserRepository
+	bot      ports.BotClientPort
+}
+
+// NewUpgradeHandler creates a new handler for the /upgrade command.
+func NewUpgradeHandler(deps *customer.HandlerDeps) ports.CommandHandler {
+	return &upgradeHandler{
+		log:      deps.BaseLogger.With().Str("component", "upgrade_handler").Logger(),
+		userRepo: deps.UserRepo,
+		bot:      deps.BotClient,
+	}
+}
+
+// Command returns the command string (without the "/")
+func (h *upgradeHandler) Command() string {
+	return "upgrade"
+}
+
+// Handle checks the user may upgrade and asks for the proof of address.
+func (h *upgradeHandler) Handle(ctx context.Context, update *ports.BotUpdate) error {
+	log := h.log.With().Int64("user_id", update.UserID).Logger()
+
+	user, err := h.userRepo.GetByTelegramID(ctx, update.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get user from repository")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+	if user == nil {
+		msg := messages.NewBuilder(update.ChatID).
+			WithText("Please type /start to begin\\.").
+			Build()
+		_, err := h.bot.SendMessage(ctx, msg)
+		return err
+	}
+
+	switch {
+	case user.VerificationStatus == domain.VerificationLevel2:
+		return h.sendErrorMessage(ctx, update.ChatID, "Your account is already at level 2.")
+	case user.UpgradeRequestedAt != nil:
+		return h.sendErrorMessage(ctx, update.ChatID, "Your upgrade is already under review. We will notify you once a moderator has checked it.")
+	case !user.CanRequestUpgrade():
+		return h.sendErrorMessage(ctx, update.ChatID, "Only verified accounts can upgrade. Please complete registration and wait for approval first.")
+	}
+
+	// Start from scratch, even if an older upgrade was abandoned
+	user.State = domain.StateAwaitingAddressProof
+	user.StateData = map[string]string{}
+	user.AddressDocRef = nil
+	user.SelfieDocRef = nil
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		log.Error().Err(err).Msg("Failed to start upgrade flow")
+		return h.sendErrorMessage(ctx, update.ChatID, "An internal error occurred.")
+	}
+
+	log.Info().Msg("Starting level 2 upgrade flow")
+	msg := messages.NewBuilder(update.ChatID).
+		WithText(
+			"⭐️ *Upgrade to Level 2*\n\n" +
+				"Level 2 comes with higher trading limits\\. We need two more photos:\n" +
+				"1\\. A *proof of address* issued in the last 3 months, such as a utility bill or bank statement\n" +
+				"2\\. A *selfie* of you holding the ID you registered with\n\n" +
+				"Please upload the *proof of address* now\\.",
+		).
+		WithInlineButtons([][]ports.Button{upgradeCancelButtonRow()}).
+		Build()
+	_, err = h.bot.SendMessage(ctx, msg)
+	return err
+}
+
+// sendErrorMessage is a helper to send a generic error
+func (h *upgradeHandler) sendErrorMessage(ctx context.Context, chatID int64, message string) error {
+	msgParams := messages.NewBuilder(chatID).
+		WithText(message).
+		WithParseMode("").Build()
+	_, err := h.bot.SendMessage(ctx, msgParams)
+	return err
+}
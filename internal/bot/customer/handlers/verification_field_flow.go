@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/customer"
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	customer.RegisterState(NewVerificationFieldFlow)
+}
+
+// verificationFieldFlow collects the extra details a verification strategy
+// asked for, one question at a time, and hands them back to the strategy.
+type verificationFieldFlow struct {
+	log      zerolog.Logger
+	bot      ports.BotClientPort
+	verifier *services.VerificationService
+}
+
+// NewVerificationFieldFlow creates the state handler for strategy questions.
+func NewVerificationFieldFlow(deps *customer.HandlerDeps) ports.StateHandler {
+	return &verificationFieldFlow{
+		log:      deps.BaseLogger.With().Str("component", "verification_field_flow").Logger(),
+		bot:      deps.BotClient,
+		verifier: deps.Verifier,
+	}
+}
+
+// States returns the user states owned by this flow.
+func (h *verificationFieldFlow) States() []domain.UserState {
+	return []domain.UserState{domain.StateAwaitingVerificationField}
+}
+
+// Handle stores the answer and asks the next question, or finishes the check.
+func (h *verificationFieldFlow) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	log := h.log.With().Str("user_id", user.ID.String()).Logger()
+
+	answer := strings.TrimSpace(update.Text)
+	if answer == "" || utf8.RuneCountInString(answer) > 100 {
+		field, _ := h.verifier.NextField(user)
+		return sendVerificationPrompt(ctx, h.bot, update.ChatID, field, "Please reply with text.")
+	}
+
+	decision, err := h.verifier.Answer(ctx, user, answer)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to process verification answer")
+		return h.sendMessage(ctx, update.ChatID, "An internal error occurred\\.")
+	}
+
+	switch decision.Outcome {
+	case ports.VerificationApprove, ports.VerificationReject:
+		// The notification handler tells the user the verdict
+		return nil
+	case ports.VerificationNeedFields:
+		return sendVerificationPrompt(ctx, h.bot, update.ChatID, decision.Fields[0], "Thank you.")
+	}
+
+	return h.sendMessage(ctx, update.ChatID,
+		"✅ *Registration Complete\\!*\n\nThank you\\. Your account is now submitted and *pending admin verification*\\.\n\nWe will notify you as soon as you are approved to make transactions\\.")
+}
+
+// sendMessage is a helper to send a MarkdownV2 reply
+func (h *verificationFieldFlow) sendMessage(ctx context.Context, chatID int64, text string) error {
+	_, err := h.bot.SendMessage(ctx, messages.NewBuilder(chatID).WithText(text).Build())
+	return err
+}
+
+// sendVerificationPrompt asks the user one strategy question, after an optional intro line.
+func sendVerificationPrompt(ctx context.Context, bot ports.BotClientPort, chatID int64, field ports.VerificationField, intro string) error {
+	text := field.Prompt
+	if intro != "" {
+		text = intro + "\n\n" + text
+	}
+	msg := messages.NewBuilder(chatID).
+		WithText(text).
+		WithParseMode("").
+		Build()
+	_, err := bot.SendMessage(ctx, msg)
+	return err
+}
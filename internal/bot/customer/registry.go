@@ -2,40 +2,53 @@ package customer
 
 import (
 	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
 	"AsaExchange/internal/shared/config"
 
 	"github.com/rs/zerolog"
 )
 
+// HandlerDeps bundles everything a customer handler constructor may need.
+// This allows us to pass dependencies from main.go without
+// changing every constructor when a new one is added.
+type HandlerDeps struct {
+	Cfg              *config.Config
+	UserRepo         ports.UserRepository
+	RequestRepo      ports.RequestRepository
+	BidRepo          ports.BidRepository
+	TransactionRepo  ports.TransactionRepository
+	BankAccountRepo  ports.UserBankAccountRepository
+	DisputeRepo      ports.DisputeRepository
+	PlatformAccounts *services.PlatformAccountService
+	Disputes         *services.DisputeService
+	Cancellations    *services.CancellationService
+	Fees             *services.FeeEngine
+	Limits           *services.LimitService
+	Verifier         *services.VerificationService
+	BotClient        ports.BotClientPort
+	Queue            ports.VerificationQueue
+	ReceiptQueue     ports.ReceiptQueue
+	EvidenceQueue    ports.EvidenceQueue
+	Transactor       ports.Transactor
+	Bus              ports.EventBus
+	BaseLogger       *zerolog.Logger
+}
+
 // --- Define types for handler "constructors" ---
-// This allows us to pass dependencies from main.go
-
-type CommandHandlerConstructor func(
-	cfg *config.Config,
-	userRepo ports.UserRepository,
-	botClient ports.BotClientPort,
-	baseLogger *zerolog.Logger,
-) ports.CommandHandler
-
-type CallbackHandlerConstructor func(
-	cfg *config.Config,
-	userRepo ports.UserRepository,
-	botClient ports.BotClientPort,
-	baseLogger *zerolog.Logger,
-) ports.CallbackHandler
-
-type MessageHandlerConstructor func(
-	cfg *config.Config,
-	userRepo ports.UserRepository,
-	botClient ports.BotClientPort,
-	queue ports.VerificationQueue,
-	baseLogger *zerolog.Logger,
-) ports.MessageHandler
+
+type CommandHandlerConstructor func(deps *HandlerDeps) ports.CommandHandler
+
+type CallbackHandlerConstructor func(deps *HandlerDeps) ports.CallbackHandler
+
+type MessageHandlerConstructor func(deps *HandlerDeps) ports.MessageHandler
+
+type StateHandlerConstructor func(deps *HandlerDeps) ports.StateHandler
 
 // --- Create the global registries ---
 var (
 	commandRegistry  []CommandHandlerConstructor
 	callbackRegistry []CallbackHandlerConstructor
+	stateRegistry    []StateHandlerConstructor
 	messageHandler   MessageHandlerConstructor
 )
 
@@ -49,6 +62,11 @@ func RegisterCallback(constructor CallbackHandlerConstructor) {
 	callbackRegistry = append(callbackRegistry, constructor)
 }
 
+// RegisterState is called by multi-step flow handlers in their init()
+func RegisterState(constructor StateHandlerConstructor) {
+	stateRegistry = append(stateRegistry, constructor)
+}
+
 // RegisterMessage (formerly RegisterText) is called by the message handler
 func RegisterMessage(constructor MessageHandlerConstructor) {
 	// We only allow one global message handler
@@ -57,33 +75,27 @@ func RegisterMessage(constructor MessageHandlerConstructor) {
 
 // RegisterAllHandlers is the single function called by main.go
 // It builds all registered handlers and passes them to the router.
-func RegisterAllHandlers(
-	cfg *config.Config,
-	router *CustomerRouter,
-	userRepo ports.UserRepository,
-	botClient ports.BotClientPort,
-	queue ports.VerificationQueue,
-	baseLogger *zerolog.Logger,
-) {
-	log := baseLogger.With().Str("component", "customer_registry").Logger()
+func RegisterAllHandlers(router *CustomerRouter, deps *HandlerDeps) {
+	log := deps.BaseLogger.With().Str("component", "customer_registry").Logger()
 
 	// Register all commands
 	for _, constructor := range commandRegistry {
-		handler := constructor(cfg, userRepo, botClient, baseLogger)
-		router.RegisterCommandHandler(handler)
+		router.RegisterCommandHandler(constructor(deps))
 	}
 
 	// Register all callbacks
 	for _, constructor := range callbackRegistry {
-		handler := constructor(cfg, userRepo, botClient, baseLogger)
-		router.RegisterCallbackHandler(handler)
+		router.RegisterCallbackHandler(constructor(deps))
+	}
+
+	// Register all state (flow) handlers
+	for _, constructor := range stateRegistry {
+		router.RegisterStateHandler(constructor(deps))
 	}
 
 	// Register the single message handler
 	if messageHandler != nil {
-		// Pass cfg to the constructor
-		handler := messageHandler(cfg, userRepo, botClient, queue, baseLogger)
-		router.SetMessageHandler(handler)
+		router.SetMessageHandler(messageHandler(deps))
 		log.Info().Msg("Registered main message handler")
 	}
 }
@@ -2,6 +2,7 @@ package customer
 
 import (
 	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
 	"AsaExchange/internal/core/ports"
 	"context"
 	"strings"
@@ -18,6 +19,7 @@ type CustomerRouter struct {
 	botClient        ports.BotClientPort
 	commandHandlers  map[string]ports.CommandHandler
 	callbackHandlers map[string]ports.CallbackHandler
+	stateHandlers    map[domain.UserState]ports.StateHandler
 	messageHandler   ports.MessageHandler
 }
 
@@ -33,6 +35,7 @@ func NewCustomerRouter(
 		botClient:        botClient,
 		commandHandlers:  make(map[string]ports.CommandHandler),
 		callbackHandlers: make(map[string]ports.CallbackHandler),
+		stateHandlers:    make(map[domain.UserState]ports.StateHandler),
 	}
 }
 
@@ -50,6 +53,14 @@ func (r *CustomerRouter) RegisterCallbackHandler(handler ports.CallbackHandler)
 	r.log.Info().Str("prefix", prefix).Msg("Registered new callback handler")
 }
 
+// RegisterStateHandler adds a flow "plugin" for each state it owns.
+func (r *CustomerRouter) RegisterStateHandler(handler ports.StateHandler) {
+	for _, state := range handler.States() {
+		r.stateHandlers[state] = handler
+		r.log.Info().Str("state", string(state)).Msg("Registered new state handler")
+	}
+}
+
 // SetTextHandler registers the single, global text handler
 func (r *CustomerRouter) SetMessageHandler(handler ports.MessageHandler) {
 	r.messageHandler = handler
@@ -120,7 +131,16 @@ func (r *CustomerRouter) HandleUpdate(ctx context.Context, update *tgbotapi.Upda
 		return
 	}
 
-	// 7. Route all other messages (Text, Contact, Photo)
+	// 7. Route messages for users inside a multi-step flow
+	if handler, ok := r.stateHandlers[user.State]; ok {
+		ctxLogger.Info().Str("state", string(user.State)).Msg("Routing message to state handler")
+		if err := handler.Handle(ctx, botUpdate, user); err != nil {
+			ctxLogger.Error().Err(err).Msg("State handler failed")
+		}
+		return
+	}
+
+	// 8. Route all other messages (Text, Contact, Photo)
 	if r.messageHandler != nil {
 		log := ctxLogger.With().Str("state", string(user.State)).Logger()
 		if botUpdate.Contact != nil {
@@ -177,13 +197,14 @@ func (r *CustomerRouter) parseUpdate(update *tgbotapi.Update) (*ports.BotUpdate,
 		}
 
 		return &ports.BotUpdate{
-			MessageID: msg.MessageID,
-			ChatID:    msg.Chat.ID,
-			UserID:    msg.From.ID,
-			Text:      msg.Text,
-			Command:   msg.Command(),
-			Contact:   contactInfo,
-			Photo:     photoInfo,
+			MessageID:   msg.MessageID,
+			ChatID:      msg.Chat.ID,
+			UserID:      msg.From.ID,
+			Text:        msg.Text,
+			Command:     msg.Command(),
+			CommandArgs: msg.CommandArguments(),
+			Contact:     contactInfo,
+			Photo:       photoInfo,
 		}, true
 	}
 
@@ -5,6 +5,7 @@ import (
 	"AsaExchange/internal/core/ports"
 	"context"
 	"testing"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/google/uuid"
@@ -50,6 +51,11 @@ func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) LockForUpdate(ctx context.Context, ids ...uuid.UUID) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) GetNextPendingUser(ctx context.Context) (*domain.User, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -58,6 +64,22 @@ func (m *MockUserRepository) GetNextPendingUser(ctx context.Context) (*domain.Us
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *MockUserRepository) ClaimStalledRegistrations(ctx context.Context, idleSince time.Time, limit int) ([]*domain.User, error) {
+	args := m.Called(ctx, idleSince, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) ClaimPendingStartPayload(ctx context.Context, id uuid.UUID) (*string, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*string), args.Error(1)
+}
+
 // MockCommandHandler
 type MockCommandHandler struct {
 	mock.Mock
@@ -110,6 +132,11 @@ func (m *MockBotClient) EditMessageCaption(ctx context.Context, params ports.Edi
 	return args.Error(0)
 }
 
+func (m *MockBotClient) DeleteMessage(ctx context.Context, chatID int64, messageID int) error {
+	args := m.Called(ctx, chatID, messageID)
+	return args.Error(0)
+}
+
 func (m *MockBotClient) AnswerCallbackQuery(ctx context.Context, params ports.AnswerCallbackParams) error {
 	args := m.Called(ctx, params)
 	return args.Error(0)
@@ -120,6 +147,14 @@ func (m *MockBotClient) SendPhoto(ctx context.Context, params ports.SendPhotoPar
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockBotClient) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	args := m.Called(ctx, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
 // MockMessageHandler is a mock "plugin" for text
 type MockMessageHandler struct {
 	mock.Mock
@@ -130,6 +165,20 @@ func (m *MockMessageHandler) Handle(ctx context.Context, update *ports.BotUpdate
 	return args.Error(0)
 }
 
+// MockStateHandler is a mock "plugin" for multi-step flows
+type MockStateHandler struct {
+	mock.Mock
+}
+
+func (m *MockStateHandler) States() []domain.UserState {
+	args := m.Called()
+	return args.Get(0).([]domain.UserState)
+}
+func (m *MockStateHandler) Handle(ctx context.Context, update *ports.BotUpdate, user *domain.User) error {
+	args := m.Called(ctx, update, user)
+	return args.Error(0)
+}
+
 // --- Tests ---
 
 func TestRouter_HandleUpdate_Command(t *testing.T) {
@@ -342,3 +391,76 @@ func TestRouter_HandleUpdate_UnhandledText(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 	mockBotClient.AssertExpectations(t)
 }
+
+func TestRouter_HandleUpdate_StateHandlerTakesPrecedence(t *testing.T) {
+	// Tests that a user inside a registered flow is routed to that flow,
+	// not to the global MessageHandler.
+
+	// 1. Setup
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockUserRepo := new(MockUserRepository)
+	mockBotClient := new(MockBotClient)
+	router := NewCustomerRouter(mockUserRepo, mockBotClient, &nopLogger)
+
+	messageHandler := new(MockMessageHandler)
+	router.SetMessageHandler(messageHandler)
+
+	stateHandler := new(MockStateHandler)
+	stateHandler.On("States").Return([]domain.UserState{domain.StateAwaitingRequestAmount})
+	router.RegisterStateHandler(stateHandler)
+
+	// 2. Create a fake User inside the flow
+	testUser := &domain.User{
+		ID:    uuid.New(),
+		State: domain.StateAwaitingRequestAmount,
+	}
+
+	// 3. Create a fake Telegram update
+	fakeUpdate := &tgbotapi.Update{
+		UpdateID: 123,
+		Message: &tgbotapi.Message{
+			MessageID: 456,
+			From:      &tgbotapi.User{ID: 789, UserName: "testuser"},
+			Chat:      &tgbotapi.Chat{ID: 1000},
+			Text:      "1500",
+		},
+	}
+
+	// 4. Define Expectations
+	mockUserRepo.On("GetByTelegramID", mock.Anything, int64(789)).Return(testUser, nil).Once()
+	stateHandler.On("Handle", mock.Anything, mock.AnythingOfType("*ports.BotUpdate"), testUser).Return(nil).Once()
+
+	// 5. Run the handler
+	router.HandleUpdate(ctx, fakeUpdate)
+
+	// 6. Assert
+	mockUserRepo.AssertExpectations(t)
+	stateHandler.AssertExpectations(t)
+	messageHandler.AssertNotCalled(t, "Handle", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRouter_ParseUpdate_CommandArgs(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	router := NewCustomerRouter(new(MockUserRepository), new(MockBotClient), &nopLogger)
+
+	fakeUpdate := &tgbotapi.Update{
+		Message: &tgbotapi.Message{
+			MessageID: 1,
+			From:      &tgbotapi.User{ID: 789},
+			Chat:      &tgbotapi.Chat{ID: 1000},
+			Text:      "/start offer_42",
+			Entities: []tgbotapi.MessageEntity{
+				{Type: "bot_command", Offset: 0, Length: 6},
+			},
+		},
+	}
+
+	update, ok := router.parseUpdate(fakeUpdate)
+	if !ok {
+		t.Fatal("parseUpdate rejected a command message")
+	}
+	if update.Command != "start" || update.CommandArgs != "offer_42" {
+		t.Errorf("Got command %q with args %q, want \"start\" with \"offer_42\"", update.Command, update.CommandArgs)
+	}
+}
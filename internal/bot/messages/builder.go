@@ -68,7 +68,7 @@ func (b *Builder) WithReplyButtons(buttonTexts []string, columns int) *Builder {
 
 	for i, text := range buttonTexts {
 		row = append(row, ports.Button{Text: text})
-		
+
 		// If we've reached the column limit, or it's the last button
 		if (i+1)%columns == 0 || i == len(buttonTexts)-1 {
 			rows = append(rows, row)
@@ -87,4 +87,4 @@ func (b *Builder) WithReplyButtons(buttonTexts []string, columns int) *Builder {
 // Build returns the final SendMessageParams struct.
 func (b *Builder) Build() ports.SendMessageParams {
 	return b.params
-}
\ No newline at end of file
+}
@@ -0,0 +1,29 @@
+package messages
+
+import (
+	"AsaExchange/internal/core/domain"
+	"strings"
+)
+
+// The backslash comes first: it is MarkdownV2's escape character itself.
+var markdownReplacer = strings.NewReplacer(
+	"\\", "\\\\",
+	"_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]", "(", "\\(", ")", "\\)",
+	"~", "\\~", "`", "\\`", ">", "\\>", "#", "\\#", "+", "\\+", "-", "\\-",
+	"=", "\\=", "|", "\\|", "{", "\\{", "}", "\\}", ".", "\\.", "!", "\\!",
+)
+
+// EscapeMarkdown escapes user-provided text so it is safe inside a MarkdownV2 message.
+func EscapeMarkdown(s string) string {
+	return markdownReplacer.Replace(s)
+}
+
+// FormatAmount renders a number without exponent or trailing zeros.
+func FormatAmount(value domain.Decimal) string {
+	return value.String()
+}
+
+// FormatMoney renders an amount and its currency as MarkdownV2.
+func FormatMoney(m domain.Money) string {
+	return EscapeMarkdown(FormatAmount(m.Amount)) + " " + m.Currency
+}
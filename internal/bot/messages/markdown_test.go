@@ -0,0 +1,40 @@
+package messages
+
+import (
+	"AsaExchange/internal/core/domain"
+	"testing"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"plain", "plain"},
+		{"1.5 EUR!", "1\\.5 EUR\\!"},
+		{`C:\new_file`, `C:\\new\_file`},
+		{`\*bold\*`, `\\\*bold\\\*`},
+	}
+	for _, c := range cases {
+		if got := EscapeMarkdown(c.input); got != c.want {
+			t.Errorf("EscapeMarkdown(%q) = %q, want %q", c.input, got, c.want)
+		}
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	cases := []struct {
+		amount string
+		want   string
+	}{
+		{"1500", "1500 EUR"},
+		{"1500.50", "1500\\.5 EUR"},
+		{"-2.25", "\\-2\\.25 EUR"},
+	}
+	for _, c := range cases {
+		m := domain.Money{Amount: domain.MustParseDecimal(c.amount), Currency: "EUR"}
+		if got := FormatMoney(m); got != c.want {
+			t.Errorf("FormatMoney(%s) = %q, want %q", c.amount, got, c.want)
+		}
+	}
+}
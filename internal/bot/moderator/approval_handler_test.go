@@ -0,0 +1,78 @@
+package moderator_test
+
+import (
+	"AsaExchange/internal/bot/moderator"
+	"AsaExchange/internal/bot/moderator/handlers"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+)
+
+type inlineTransactor struct{}
+
+func (inlineTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestApprovalHandler_Reject(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockUserRepo := new(moderator.MockUserRepository)
+	mockBotClient := new(moderator.MockBotClient)
+	mockBus := new(moderator.MockEventBus)
+
+	handler := handlers.NewApprovalHandler(&moderator.HandlerDeps{
+		UserRepo:   mockUserRepo,
+		BotClient:  mockBotClient,
+		Bus:        mockBus,
+		Transactor: inlineTransactor{},
+		BaseLogger: &nopLogger,
+	})
+
+	// 2. A pending user with a complete registration
+	firstName, lastName, phone := "Sara", "Ahmadi", "+491701234567"
+	user := &domain.User{
+		ID:                 uuid.New(),
+		TelegramID:         42,
+		FirstName:          &firstName,
+		LastName:           &lastName,
+		PhoneNumber:        &phone,
+		VerificationStatus: domain.VerificationPending,
+		State:              domain.StateNone,
+	}
+	adminUser := &domain.User{ID: uuid.New(), TelegramID: 789, IsModerator: true}
+	data := "approval_reject_" + user.ID.String()
+	update := &ports.BotUpdate{ChatID: 1000, MessageID: 456, CallbackQueryID: "cb_id_1", CallbackData: &data}
+
+	// 3. Define Expectations
+	mockBotClient.On("AnswerCallbackQuery", mock.Anything, mock.Anything).Return(nil).Once()
+	mockUserRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil).Once()
+	mockUserRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return u.VerificationStatus == domain.VerificationRejected &&
+			u.State == domain.StateAwaitingFirstName &&
+			u.FirstName == nil && u.LastName == nil && u.PhoneNumber == nil
+	})).Return(nil).Once()
+	mockBus.On("Publish", mock.Anything, events.TopicUserRejected, mock.AnythingOfType("events.UserRejected")).Return(nil).Once()
+	mockBotClient.On("EditMessageCaption", mock.Anything, ports.EditMessageCaptionParams{
+		ChatID:    1000,
+		MessageID: 456,
+		Caption:   "❌ User Rejected: Sara Ahmadi\nAdmin: 789",
+	}).Return(nil).Once()
+
+	// 4. Run the handler
+	if err := handler.Handle(ctx, update, adminUser); err != nil {
+		t.Fatalf("Handle returned an error: %v", err)
+	}
+
+	// 5. Assert expectations
+	mockUserRepo.AssertExpectations(t)
+	mockBus.AssertExpectations(t)
+	mockBotClient.AssertExpectations(t)
+}
@@ -3,8 +3,8 @@ package handlers
 import (
 	"AsaExchange/internal/bot/moderator"
 	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
 	"AsaExchange/internal/core/ports"
-	"AsaExchange/internal/shared/config"
 	"context"
 	"fmt"
 	"strings"
@@ -24,21 +24,17 @@ type approvalHandler struct {
 	userRepo ports.UserRepository
 	bot      ports.BotClientPort
 	bus      ports.EventBus
+	tx       ports.Transactor
 }
 
 // NewApprovalHandler
-func NewApprovalHandler(
-	cfg *config.Config,
-	userRepo ports.UserRepository,
-	bot ports.BotClientPort,
-	bus ports.EventBus,
-	baseLogger *zerolog.Logger,
-) ports.CallbackHandler {
+func NewApprovalHandler(deps *moderator.HandlerDeps) ports.CallbackHandler {
 	return &approvalHandler{
-		log:      baseLogger.With().Str("component", "approval_handler").Logger(),
-		userRepo: userRepo,
-		bot:      bot,
-		bus:      bus,
+		log:      deps.BaseLogger.With().Str("component", "approval_handler").Logger(),
+		userRepo: deps.UserRepo,
+		bot:      deps.BotClient,
+		bus:      deps.Bus,
+		tx:       deps.Transactor,
 	}
 }
 
@@ -61,7 +57,7 @@ func (h *approvalHandler) Handle(ctx context.Context, update *ports.BotUpdate, a
 		return nil
 	}
 
-	action := parts[1] // "accept" or "reject"
+	action := parts[1] // "accept" or "reject", "l2accept" or "l2reject" for upgrades
 	userID, err := uuid.Parse(parts[2])
 	if err != nil {
 		log.Error().Err(err).Str("user_id_str", parts[2]).Msg("Failed to parse UUID from callback")
@@ -81,27 +77,30 @@ func (h *approvalHandler) Handle(ctx context.Context, update *ports.BotUpdate, a
 		return h.editMessage(ctx, update, "Error: Could not find user.")
 	}
 
+	// A stale sign-up card must never reset an already verified user
+	if (action == "accept" || action == "reject") && user.VerificationStatus != domain.VerificationPending {
+		log.Warn().Str("status", string(user.VerificationStatus)).Msg("Sign-up decision on a user who is not pending")
+		return h.editMessage(ctx, update, "This user is no longer pending review.")
+	}
+
 	// 4. Process the action
+	// The name is taken first: a rejection clears it before the card is edited
+	name := userName(user)
 	switch action {
 	case "accept":
 		user.VerificationStatus = domain.VerificationLevel1
 		user.State = domain.StateNone // Registration complete
 
-		if err := h.userRepo.Update(ctx, user); err != nil {
+		// Publish an event instead of sending a message
+		if err := h.saveAndPublish(ctx, user, events.UserApproved{UserRef: events.NewUserRef(user)}); err != nil {
 			log.Error().Err(err).Msg("Failed to update user to 'level_1'")
 			return h.editMessage(ctx, update, "Error: Could not update user.")
 		}
 
 		log.Info().Msg("User approved")
 
-		// Publish an event instead of sending a message
-		if err := h.bus.Publish(ctx, "user:approved", user); err != nil {
-			log.Error().Err(err).Msg("Failed to publish 'user:approved' event")
-			// Don't fail the whole operation, just log the error
-		}
-
 		// Edit the admin's message
-		return h.editMessage(ctx, update, fmt.Sprintf("✅ User Approved: %s %s\nAdmin: %d", *user.FirstName, *user.LastName, adminUser.TelegramID))
+		return h.editMessage(ctx, update, fmt.Sprintf("✅ User Approved: %s\nAdmin: %d", name, adminUser.TelegramID))
 
 	case "reject":
 		// As per your request: reset them for re-registration
@@ -115,25 +114,87 @@ func (h *approvalHandler) Handle(ctx context.Context, update *ports.BotUpdate, a
 		user.LocationCountry = nil
 		user.VerificationStrategy = nil
 
-		if err := h.userRepo.Update(ctx, user); err != nil {
+		// Publish an event instead of sending a message
+		if err := h.saveAndPublish(ctx, user, events.UserRejected{UserRef: events.NewUserRef(user)}); err != nil {
 			log.Error().Err(err).Msg("Failed to update user to 'rejected'")
 			return h.editMessage(ctx, update, "Error: Could not update user.")
 		}
 
 		log.Info().Msg("User rejected")
 
-		// Publish an event instead of sending a message
-		if err := h.bus.Publish(ctx, "user:rejected", user); err != nil {
-			log.Error().Err(err).Msg("Failed to publish 'user:rejected' event")
-		}
-
 		// Edit the admin's message
-		return h.editMessage(ctx, update, fmt.Sprintf("❌ User Rejected: %s %s\nAdmin: %d", *user.FirstName, *user.LastName, adminUser.TelegramID))
+		return h.editMessage(ctx, update, fmt.Sprintf("❌ User Rejected: %s\nAdmin: %d", name, adminUser.TelegramID))
+
+	case "l2accept", "l2reject":
+		return h.handleUpgrade(ctx, update, adminUser, user, name, action == "l2accept", log)
 	}
 
 	return nil
 }
 
+// handleUpgrade decides a level_2 upgrade.
+// Unlike a sign-up rejection, a declined upgrade keeps the user at level_1
+// with all their registration data; only the upgrade documents are dropped.
+func (h *approvalHandler) handleUpgrade(
+	ctx context.Context,
+	update *ports.BotUpdate,
+	adminUser *domain.User,
+	user *domain.User,
+	name string,
+	approve bool,
+	log zerolog.Logger,
+) error {
+	if user.UpgradeRequestedAt == nil || user.VerificationStatus != domain.VerificationLevel1 {
+		log.Warn().Str("status", string(user.VerificationStatus)).Msg("Upgrade decision on a user without a pending upgrade")
+		return h.editMessage(ctx, update, "This upgrade is no longer pending.")
+	}
+
+	user.UpgradeRequestedAt = nil
+	var event events.Event = events.UserUpgraded{UserRef: events.NewUserRef(user)}
+	verdict := "✅ Upgrade Approved"
+	if approve {
+		user.VerificationStatus = domain.VerificationLevel2
+	} else {
+		user.AddressDocRef = nil
+		user.SelfieDocRef = nil
+		event, verdict = events.UpgradeRejected{UserRef: events.NewUserRef(user)}, "❌ Upgrade Rejected"
+	}
+
+	if err := h.saveAndPublish(ctx, user, event); err != nil {
+		log.Error().Err(err).Msg("Failed to save upgrade decision")
+		return h.editMessage(ctx, update, "Error: Could not update user.")
+	}
+	log.Info().Bool("approved", approve).Msg("Level 2 upgrade decided")
+
+	return h.editMessage(ctx, update, fmt.Sprintf("%s: %s\nAdmin: %d", verdict, name, adminUser.TelegramID))
+}
+
+// userName returns the user's full name for the review card.
+// Users rejected mid-registration may not have one.
+func userName(user *domain.User) string {
+	var parts []string
+	for _, part := range []*string{user.FirstName, user.LastName} {
+		if part != nil && *part != "" {
+			parts = append(parts, *part)
+		}
+	}
+	if len(parts) == 0 {
+		return user.ID.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// saveAndPublish saves the decision and its event in one transaction,
+// so a crash can never leave the user without their notification.
+func (h *approvalHandler) saveAndPublish(ctx context.Context, user *domain.User, event events.Event) error {
+	return h.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return events.Publish(ctx, h.bus, event)
+	})
+}
+
 // editMessage
 func (h *approvalHandler) editMessage(ctx context.Context, update *ports.BotUpdate, text string) error {
 	msg := ports.EditMessageCaptionParams{
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/moderator"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	moderator.RegisterCommand(NewDeadLettersHandler)
+	moderator.RegisterCommand(NewReplayDeadLetterHandler)
+}
+
+const (
+	deadLetterListLimit  = 20
+	deadLetterPayloadMax = 1500 // Keeps the reply well below Telegram's message limit
+)
+
+// deadLettersBase holds what the dead letter commands share.
+type deadLettersBase struct {
+	log         zerolog.Logger
+	deadLetters ports.DeadLetterRepository
+	replayer    ports.DeadLetterReplayer
+	bot         ports.BotClientPort
+}
+
+func newDeadLettersBase(deps *moderator.HandlerDeps, component string) deadLettersBase {
+	return deadLettersBase{
+		log:         deps.BaseLogger.With().Str("component", component).Logger(),
+		deadLetters: deps.DeadLetterRepo,
+		replayer:    deps.DeadLetters,
+		bot:         deps.BotClient,
+	}
+}
+
+// --- /deadletters ---
+
+// deadLettersHandler lists the pending dead letters, or shows one in full.
+type deadLettersHandler struct {
+	deadLettersBase
+}
+
+// NewDeadLettersHandler creates a new handler for the /deadletters command.
+func NewDeadLettersHandler(deps *moderator.HandlerDeps) ports.CommandHandler {
+	return &deadLettersHandler{newDeadLettersBase(deps, "dead_letters_handler")}
+}
+
+func (h *deadLettersHandler) Command() string {
+	return "deadletters"
+}
+
+func (h *deadLettersHandler) Handle(ctx context.Context, update *ports.BotUpdate) error {
+	if args := strings.TrimSpace(update.CommandArgs); args != "" {
+		id, err := strconv.ParseInt(args, 10, 64)
+		if err != nil {
+			return h.sendText(ctx, update.ChatID, "Usage: /deadletters [ID]")
+		}
+		return h.show(ctx, update.ChatID, id)
+	}
+
+	letters, err := h.deadLetters.ListPending(ctx, deadLetterListLimit)
+	if err != nil {
+		h.log.Error().Err(err).Msg("Failed to list dead letters")
+		return h.sendText(ctx, update.ChatID, "Error: Could not load dead letters.")
+	}
+	if len(letters) == 0 {
+		return h.sendText(ctx, update.ChatID, "No dead letters. Every event was handled.")
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("☠️ Dead letters (newest %d)\n\n", len(letters)))
+	for _, letter := range letters {
+		text.WriteString(fmt.Sprintf("#%d · %s → %s · %d attempts · %s\n%s\n\n",
+			letter.ID,
+			letter.Topic,
+			letter.Subscriber,
+			letter.Attempts,
+			letter.CreatedAt.Format("2006-01-02 15:04"),
+			letter.Error,
+		))
+	}
+	text.WriteString("Inspect with /deadletters <ID>, replay with /replay <ID>.")
+	return h.sendText(ctx, update.ChatID, text.String())
+}
+
+// show sends one dead letter with its payload.
+func (h *deadLettersHandler) show(ctx context.Context, chatID int64, id int64) error {
+	letter, err := h.deadLetters.GetByID(ctx, id)
+	if err != nil {
+		h.log.Error().Err(err).Int64("dead_letter_id", id).Msg("Failed to get dead letter")
+		return h.sendText(ctx, chatID, "Error: Could not load the dead letter.")
+	}
+	if letter == nil {
+		return h.sendText(ctx, chatID, "Error: Dead letter not found.")
+	}
+
+	status := "pending"
+	if letter.ReplayedAt != nil {
+		status = "replayed " + letter.ReplayedAt.Format("2006-01-02 15:04")
+	}
+	payload := string(letter.Payload)
+	if runes := []rune(payload); len(runes) > deadLetterPayloadMax {
+		payload = string(runes[:deadLetterPayloadMax]) + "…"
+	}
+	return h.sendText(ctx, chatID, fmt.Sprintf(
+		"☠️ Dead letter #%d\n\nTopic: %s\nSubscriber: %s\nAttempts: %d\nFailed: %s\nStatus: %s\nError: %s\n\nPayload:\n%s",
+		letter.ID,
+		letter.Topic,
+		letter.Subscriber,
+		letter.Attempts,
+		letter.CreatedAt.Format("2006-01-02 15:04"),
+		status,
+		letter.Error,
+		payload,
+	))
+}
+
+// --- /replay ---
+
+// replayDeadLetterHandler hands a dead letter to its subscriber once more.
+type replayDeadLetterHandler struct {
+	deadLettersBase
+}
+
+// NewReplayDeadLetterHandler creates a new handler for the /replay command.
+func NewReplayDeadLetterHandler(deps *moderator.HandlerDeps) ports.CommandHandler {
+	return &replayDeadLetterHandler{newDeadLettersBase(deps, "replay_dead_letter_handler")}
+}
+
+func (h *replayDeadLetterHandler) Command() string {
+	return "replay"
+}
+
+func (h *replayDeadLetterHandler) Handle(ctx context.Context, update *ports.BotUpdate) error {
+	id, err := strconv.ParseInt(strings.TrimSpace(update.CommandArgs), 10, 64)
+	if err != nil {
+		return h.sendText(ctx, update.ChatID, "Usage: /replay <ID>\nSee /deadletters for the IDs.")
+	}
+	log := h.log.With().Int64("dead_letter_id", id).Int64("admin_id", update.UserID).Logger()
+
+	err = h.replayer.Replay(ctx, id)
+	switch {
+	case errors.Is(err, ports.ErrDeadLetterNotFound):
+		return h.sendText(ctx, update.ChatID, "Error: Dead letter not found.")
+	case errors.Is(err, ports.ErrDeadLetterReplayed):
+		return h.sendText(ctx, update.ChatID, "This dead letter was already replayed, or is being replayed right now.")
+	case err != nil:
+		log.Warn().Err(err).Msg("Dead letter replay failed")
+		return h.sendText(ctx, update.ChatID, fmt.Sprintf("❌ Replay of #%d failed: %s\nIt stays in /deadletters.", id, err))
+	}
+	log.Info().Msg("Dead letter replayed by moderator")
+
+	return h.sendText(ctx, update.ChatID, fmt.Sprintf("✅ Replayed #%d.", id))
+}
+
+// sendText sends a plain-text reply.
+func (h *deadLettersBase) sendText(ctx context.Context, chatID int64, text string) error {
+	_, err := h.bot.SendMessage(ctx, ports.SendMessageParams{ChatID: chatID, Text: text})
+	return err
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/bot/moderator"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// init
+func init() {
+	moderator.RegisterCallback(NewDepositReviewHandler)
+}
+
+// receiptRejectionReasons are the reasons a moderator can pick when rejecting a receipt.
+// The code travels in the callback data; the text is shown to the customer.
+var receiptRejectionReasons = []struct {
+	Code string
+	Text string
+}{
+	{"blurry", "The photo is unreadable"},
+	{"amount", "The amount does not match the trade"},
+	{"account", "The payment was sent to the wrong account"},
+	{"missing", "We have not received this payment"},
+}
+
+// depositReviewHandler handles the buttons under a forwarded deposit receipt
+type depositReviewHandler struct {
+	log         zerolog.Logger
+	userRepo    ports.UserRepository
+	requestRepo ports.RequestRepository
+	txRepo      ports.TransactionRepository
+	currencies  *domain.CurrencyRegistry
+	txService   *services.TransactionService
+	bot         ports.BotClientPort
+	bus         ports.EventBus
+}
+
+// NewDepositReviewHandler
+func NewDepositReviewHandler(deps *moderator.HandlerDeps) ports.CallbackHandler {
+	return &depositReviewHandler{
+		log:         deps.BaseLogger.With().Str("component", "deposit_review_handler").Logger(),
+		userRepo:    deps.UserRepo,
+		requestRepo: deps.RequestRepo,
+		txRepo:      deps.TransactionRepo,
+		currencies:  deps.Cfg.Currencies,
+		txService:   deps.TxService,
+		bot:         deps.BotClient,
+		bus:         deps.Bus,
+	}
+}
+
+func (h *depositReviewHandler) Prefix() string {
+	return "deposit_"
+}
+
+// Handle processes "deposit_<action>_<txid>_<leg>[_<reason>]" where action is
+// "ok" (confirm), "no" (pick a reason), "rr" (reject with reason) or "back".
+func (h *depositReviewHandler) Handle(ctx context.Context, update *ports.BotUpdate, adminUser *domain.User) error {
+	log := h.log.With().Int64("admin_id", adminUser.TelegramID).Logger()
+
+	// 1. Answer the callback to stop the spinner
+	h.bot.AnswerCallbackQuery(ctx, ports.AnswerCallbackParams{
+		CallbackQueryID: update.CallbackQueryID,
+	})
+
+	// 2. Parse the callback data
+	parts := strings.Split(*update.CallbackData, "_")
+	if len(parts) < 4 {
+		log.Error().Str("data", *update.CallbackData).Msg("Invalid callback data format")
+		return nil
+	}
+	action := parts[1]
+	txID, err := uuid.Parse(parts[2])
+	if err != nil {
+		log.Error().Err(err).Str("tx_id_str", parts[2]).Msg("Failed to parse UUID from callback")
+		return nil
+	}
+	leg, ok := decodeLeg(parts[3])
+	if !ok {
+		log.Error().Str("data", *update.CallbackData).Msg("Invalid leg in callback data")
+		return nil
+	}
+
+	log = log.With().Str("transaction_id", txID.String()).Str("leg", string(leg)).Str("action", action).Logger()
+
+	// 3. Process the action
+	switch action {
+	case "ok":
+		return h.confirm(ctx, log, update, adminUser, txID, leg)
+	case "no":
+		return h.showReasons(ctx, update, txID, leg)
+	case "rr":
+		if len(parts) != 5 {
+			log.Error().Str("data", *update.CallbackData).Msg("Missing rejection reason")
+			return nil
+		}
+		return h.reject(ctx, log, update, adminUser, txID, leg, parts[4])
+	case "back":
+		return h.showReview(ctx, log, update, txID, leg)
+	}
+
+	log.Warn().Msg("Unknown deposit callback")
+	return nil
+}
+
+// confirm advances the transaction past this leg's deposit.
+// If the other leg was confirmed at the same moment, it retries once against the new status.
+func (h *depositReviewHandler) confirm(
+	ctx context.Context,
+	log zerolog.Logger,
+	update *ports.BotUpdate,
+	adminUser *domain.User,
+	txID uuid.UUID,
+	leg domain.TransactionLeg,
+) error {
+	for attempt := 0; ; attempt++ {
+		tx, err := h.txRepo.GetByID(ctx, txID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get transaction")
+			return h.editMessage(ctx, update, "Error: Could not load transaction.")
+		}
+		if tx == nil {
+			return h.editMessage(ctx, update, "Error: Transaction not found.")
+		}
+
+		to, ok := services.DepositTarget(tx.Status, leg)
+		if !ok {
+			return h.editMessage(ctx, update, fmt.Sprintf(
+				"ℹ️ The %s deposit is not awaited anymore.\nTransaction: %s\nStatus: %s", leg, tx.ID, tx.Status))
+		}
+
+		_, err = h.txService.Transition(ctx, tx.ID, to, &adminUser.ID)
+		if errors.Is(err, ports.ErrTransactionStatusChanged) && attempt == 0 {
+			log.Warn().Msg("Transaction moved during confirmation, retrying")
+			continue
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to confirm deposit")
+			return h.editMessage(ctx, update, "Error: Could not update transaction.")
+		}
+
+		log.Info().Str("status", string(to)).Msg("Deposit confirmed")
+		return h.editMessage(ctx, update, fmt.Sprintf(
+			"✅ %s Deposit Confirmed\nTransaction: %s\nStatus: %s\nAdmin: %d",
+			legTitle(leg), tx.ID, to, adminUser.TelegramID))
+	}
+}
+
+// showReasons swaps the review buttons for the list of rejection reasons.
+func (h *depositReviewHandler) showReasons(ctx context.Context, update *ports.BotUpdate, txID uuid.UUID, leg domain.TransactionLeg) error {
+	prefix := fmt.Sprintf("deposit_rr_%s_%s_", txID, encodeLeg(leg))
+	var buttons [][]ports.Button
+	for _, reason := range receiptRejectionReasons {
+		buttons = append(buttons, []ports.Button{{Text: reason.Text, Data: prefix + reason.Code}})
+	}
+	buttons = append(buttons, []ports.Button{
+		{Text: "◀️ Back", Data: fmt.Sprintf("deposit_back_%s_%s", txID, encodeLeg(leg))},
+	})
+
+	return h.bot.EditMessageCaption(ctx, ports.EditMessageCaptionParams{
+		ChatID:      update.ChatID,
+		MessageID:   update.MessageID,
+		Caption:     fmt.Sprintf("Why is this %s receipt rejected?\nTransaction: %s", leg, txID),
+		ParseMode:   "", // Plain text
+		ReplyMarkup: &ports.ReplyMarkup{IsInline: true, Buttons: buttons},
+	})
+}
+
+// showReview restores the original caption and buttons.
+func (h *depositReviewHandler) showReview(
+	ctx context.Context,
+	log zerolog.Logger,
+	update *ports.BotUpdate,
+	txID uuid.UUID,
+	leg domain.TransactionLeg,
+) error {
+	caption, err := depositReviewCaption(ctx, h.txRepo, h.requestRepo, h.userRepo, h.currencies, txID, leg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to build receipt review caption")
+		return h.editMessage(ctx, update, "Error: Could not load transaction.")
+	}
+	return h.bot.EditMessageCaption(ctx, ports.EditMessageCaptionParams{
+		ChatID:      update.ChatID,
+		MessageID:   update.MessageID,
+		Caption:     caption,
+		ParseMode:   "MarkdownV2",
+		ReplyMarkup: &ports.ReplyMarkup{IsInline: true, Buttons: depositReviewButtons(txID, leg)},
+	})
+}
+
+// reject tells the customer to upload a new receipt.
+// The transaction itself does not change. If the customer cannot be told,
+// the review buttons stay, so the moderator can try again.
+func (h *depositReviewHandler) reject(
+	ctx context.Context,
+	log zerolog.Logger,
+	update *ports.BotUpdate,
+	adminUser *domain.User,
+	txID uuid.UUID,
+	leg domain.TransactionLeg,
+	reasonCode string,
+) error {
+	reason := ""
+	for _, r := range receiptRejectionReasons {
+		if r.Code == reasonCode {
+			reason = r.Text
+		}
+	}
+	if reason == "" {
+		log.Error().Str("reason", reasonCode).Msg("Unknown rejection reason")
+		return nil
+	}
+
+	tx, err := h.txRepo.GetByID(ctx, txID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get transaction")
+		return h.editMessage(ctx, update, "Error: Could not load transaction.")
+	}
+	if tx == nil {
+		return h.editMessage(ctx, update, "Error: Transaction not found.")
+	}
+	if _, ok := services.DepositTarget(tx.Status, leg); !ok {
+		return h.editMessage(ctx, update, fmt.Sprintf(
+			"ℹ️ The %s deposit is not awaited anymore.\nTransaction: %s\nStatus: %s", leg, tx.ID, tx.Status))
+	}
+
+	userID := tx.BuyerUserID
+	if leg == domain.LegSeller {
+		userID = tx.SellerUserID
+	}
+
+	// Publish an event instead of sending a message
+	if err := events.Publish(ctx, h.bus, events.DepositRejected{
+		TransactionID: tx.ID,
+		UserID:        userID,
+		Leg:           leg,
+		Reason:        reason,
+	}); err != nil {
+		// The customer was not told, so keep the receipt under review
+		log.Error().Err(err).Msg("Failed to publish 'deposit:rejected' event")
+		return h.bot.EditMessageCaption(ctx, ports.EditMessageCaptionParams{
+			ChatID:      update.ChatID,
+			MessageID:   update.MessageID,
+			Caption:     fmt.Sprintf("Error: Could not notify the customer, the %s receipt is not rejected yet. Please try again.\nTransaction: %s", leg, tx.ID),
+			ParseMode:   "", // Plain text
+			ReplyMarkup: &ports.ReplyMarkup{IsInline: true, Buttons: depositReviewButtons(tx.ID, leg)},
+		})
+	}
+	log.Info().Str("reason", reasonCode).Msg("Deposit receipt rejected")
+
+	return h.editMessage(ctx, update, fmt.Sprintf(
+		"❌ %s Receipt Rejected\nTransaction: %s\nReason: %s\nAdmin: %d",
+		legTitle(leg), tx.ID, reason, adminUser.TelegramID))
+}
+
+// editMessage
+func (h *depositReviewHandler) editMessage(ctx context.Context, update *ports.BotUpdate, text string) error {
+	msg := ports.EditMessageCaptionParams{
+		ChatID:      update.ChatID,
+		MessageID:   update.MessageID,
+		Caption:     text,
+		ParseMode:   "",  // Plain text
+		ReplyMarkup: nil, // Remove buttons
+	}
+	return h.bot.EditMessageCaption(ctx, msg)
+}
+
+// depositReviewButtons are the moderator's choices under a receipt.
+func depositReviewButtons(txID uuid.UUID, leg domain.TransactionLeg) [][]ports.Button {
+	suffix := fmt.Sprintf("%s_%s", txID, encodeLeg(leg))
+	return [][]ports.Button{
+		{
+			{Text: "✅ Confirm deposit", Data: "deposit_ok_" + suffix},
+			{Text: "❌ Reject receipt", Data: "deposit_no_" + suffix},
+		},
+	}
+}
+
+// depositReviewCaption renders the MarkdownV2 caption of a receipt under review:
+// who paid, and what we expect to have received.
+func depositReviewCaption(
+	ctx context.Context,
+	txRepo ports.TransactionRepository,
+	requestRepo ports.RequestRepository,
+	userRepo ports.UserRepository,
+	currencies *domain.CurrencyRegistry,
+	txID uuid.UUID,
+	leg domain.TransactionLeg,
+) (string, error) {
+	tx, err := txRepo.GetByID(ctx, txID)
+	if err != nil {
+		return "", err
+	}
+	if tx == nil {
+		return "", fmt.Errorf("transaction %s not found", txID)
+	}
+	req, err := requestRepo.GetByID(ctx, tx.RequestID)
+	if err != nil {
+		return "", err
+	}
+	if req == nil {
+		return "", fmt.Errorf("request %s not found", tx.RequestID)
+	}
+
+	userID := tx.BuyerUserID
+	if leg == domain.LegSeller {
+		userID = tx.SellerUserID
+	}
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		return "", fmt.Errorf("user %s not found", userID)
+	}
+
+	deposit := req.DepositFor(currencies, leg)
+
+	var caption strings.Builder
+	caption.WriteString(fmt.Sprintf("*Deposit Receipt for Review*\nTransaction: `%s`\n\n", tx.ID))
+	caption.WriteString(fmt.Sprintf("*Leg:* %s\n", legTitle(leg)))
+	caption.WriteString(fmt.Sprintf("*User ID:* `%s`\n", user.ID))
+	if user.FirstName != nil && user.LastName != nil {
+		caption.WriteString(fmt.Sprintf("*Name:* %s %s\n", messages.EscapeMarkdown(*user.FirstName), messages.EscapeMarkdown(*user.LastName)))
+	}
+	caption.WriteString(fmt.Sprintf("*Expected:* %s %s\n",
+		messages.EscapeMarkdown(messages.FormatAmount(deposit.Amount)), messages.EscapeMarkdown(deposit.Currency)))
+	caption.WriteString(fmt.Sprintf("*Status:* %s\n", messages.EscapeMarkdown(string(tx.Status))))
+	return caption.String(), nil
+}
+
+// encodeLeg shortens a leg to one letter for callback data.
+func encodeLeg(leg domain.TransactionLeg) string {
+	if leg == domain.LegSeller {
+		return "s"
+	}
+	return "b"
+}
+
+// decodeLeg is the reverse of encodeLeg.
+func decodeLeg(code string) (domain.TransactionLeg, bool) {
+	switch code {
+	case "s":
+		return domain.LegSeller, true
+	case "b":
+		return domain.LegBuyer, true
+	}
+	return "", false
+}
+
+// legTitle is the capitalized leg name for captions.
+func legTitle(leg domain.TransactionLeg) string {
+	if leg == domain.LegSeller {
+		return "Seller"
+	}
+	return "Buyer"
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/moderator"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// init
+func init() {
+	moderator.RegisterCallback(NewDisputeCallbackHandler)
+}
+
+// disputeCallbackHandler handles the resolution buttons under a dispute card
+type disputeCallbackHandler struct {
+	log      zerolog.Logger
+	disputes *services.DisputeService
+	bot      ports.BotClientPort
+}
+
+// NewDisputeCallbackHandler
+func NewDisputeCallbackHandler(deps *moderator.HandlerDeps) ports.CallbackHandler {
+	return &disputeCallbackHandler{
+		log:      deps.BaseLogger.With().Str("component", "dispute_callback").Logger(),
+		disputes: deps.Disputes,
+		bot:      deps.BotClient,
+	}
+}
+
+func (h *disputeCallbackHandler) Prefix() string {
+	return "dispute_"
+}
+
+// Handle processes "dispute_<resolution>_<disputeid>" where resolution is
+// "refund", "complete" or "cancel".
+func (h *disputeCallbackHandler) Handle(ctx context.Context, update *ports.BotUpdate, adminUser *domain.User) error {
+	log := h.log.With().Int64("admin_id", adminUser.TelegramID).Logger()
+
+	// 1. Answer the callback to stop the spinner
+	h.bot.AnswerCallbackQuery(ctx, ports.AnswerCallbackParams{
+		CallbackQueryID: update.CallbackQueryID,
+	})
+
+	// 2. Parse the callback data
+	parts := strings.Split(*update.CallbackData, "_")
+	if len(parts) != 3 {
+		log.Error().Str("data", *update.CallbackData).Msg("Invalid callback data format")
+		return nil
+	}
+	resolution := domain.DisputeResolution(parts[1])
+	disputeID, err := uuid.Parse(parts[2])
+	if err != nil {
+		log.Error().Err(err).Str("dispute_id_str", parts[2]).Msg("Failed to parse UUID from callback")
+		return nil
+	}
+
+	log = log.With().Str("dispute_id", disputeID.String()).Str("resolution", string(resolution)).Logger()
+
+	// 3. Resolve
+	dispute, err := h.disputes.Resolve(ctx, disputeID, resolution, adminUser.ID)
+	switch {
+	case errors.Is(err, ports.ErrDisputeNotOpen):
+		return h.editMessage(ctx, update, fmt.Sprintf("ℹ️ This dispute was already resolved.\nDispute: %s", disputeID))
+	case errors.Is(err, services.ErrDisputeNotFound):
+		return h.editMessage(ctx, update, "Error: Dispute not found.")
+	case err != nil:
+		log.Error().Err(err).Msg("Failed to resolve dispute")
+		return h.editMessage(ctx, update, "Error: Could not resolve dispute.")
+	}
+
+	log.Info().Msg("Dispute resolved")
+	return h.editMessage(ctx, update, fmt.Sprintf(
+		"⚖️ Dispute Resolved: %s\nTransaction: %s\nAdmin: %d",
+		resolution, dispute.TransactionID, adminUser.TelegramID))
+}
+
+// editMessage replaces the card caption with plain text and removes the buttons
+func (h *disputeCallbackHandler) editMessage(ctx context.Context, update *ports.BotUpdate, text string) error {
+	msg := ports.EditMessageCaptionParams{
+		ChatID:      update.ChatID,
+		MessageID:   update.MessageID,
+		Caption:     text,
+		ParseMode:   "",  // Plain text
+		ReplyMarkup: nil, // Remove buttons
+	}
+	return h.bot.EditMessageCaption(ctx, msg)
+}
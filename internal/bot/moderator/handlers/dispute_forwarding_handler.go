@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/shared/config"
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// DisputeForwardingHandler listens to the evidence queue and posts dispute cards to the admin channel.
+// Every new piece of evidence gets its own card, which repeats the statements of both parties so far.
+type DisputeForwardingHandler struct {
+	log                  zerolog.Logger
+	userRepo             ports.UserRepository
+	disputeRepo          ports.DisputeRepository
+	bot                  ports.BotClientPort
+	adminReviewChannelID int64
+}
+
+// NewDisputeForwardingHandler creates a new handler for forwarding dispute evidence
+func NewDisputeForwardingHandler(
+	cfg *config.Config,
+	userRepo ports.UserRepository,
+	disputeRepo ports.DisputeRepository,
+	bot ports.BotClientPort,
+	baseLogger *zerolog.Logger,
+) *DisputeForwardingHandler {
+	return &DisputeForwardingHandler{
+		log:                  baseLogger.With().Str("component", "dispute_forwarding_handler").Logger(),
+		userRepo:             userRepo,
+		disputeRepo:          disputeRepo,
+		bot:                  bot,
+		adminReviewChannelID: cfg.Bot.Moderator.AdminReviewChannelID,
+	}
+}
+
+// HandleEvent is the method that will be subscribed to the EvidenceQueue
+func (h *DisputeForwardingHandler) HandleEvent(event ports.DisputeEvidenceEvent) error {
+	ctx := context.Background()
+	log := h.log.With().
+		Str("dispute_id", event.DisputeID.String()).
+		Str("evidence_id", event.EvidenceID.String()).
+		Logger()
+	log.Info().Msg("Processing new dispute evidence from queue")
+
+	dispute, err := h.disputeRepo.GetByID(ctx, event.DisputeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load dispute")
+		return err
+	}
+	if dispute == nil {
+		log.Error().Msg("Dispute not found, dropping evidence")
+		return nil
+	}
+
+	caption, err := disputeCaption(ctx, h.disputeRepo, h.userRepo, dispute, event.EvidenceID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to build dispute caption")
+		return err
+	}
+
+	photoParams := ports.SendPhotoParams{
+		ChatID:    h.adminReviewChannelID,
+		File:      tgbotapi.FileID(event.FileID),
+		Caption:   caption,
+		ParseMode: "MarkdownV2",
+	}
+	if dispute.Status == domain.DisputeStatusOpen {
+		photoParams.ReplyMarkup = &ports.ReplyMarkup{IsInline: true, Buttons: disputeResolutionButtons(dispute.ID)}
+	}
+
+	if _, err := h.bot.SendPhoto(ctx, photoParams); err != nil {
+		log.Error().Err(err).Msg("Failed to forward dispute evidence to admin channel")
+		return err
+	}
+
+	log.Info().Msg("Successfully forwarded dispute evidence to admins")
+	return nil
+}
+
+// disputeResolutionButtons are the moderator's choices under a dispute card.
+func disputeResolutionButtons(disputeID uuid.UUID) [][]ports.Button {
+	return [][]ports.Button{
+		{
+			{Text: "💸 Refund", Data: "dispute_refund_" + disputeID.String()},
+			{Text: "✅ Force complete", Data: "dispute_complete_" + disputeID.String()},
+		},
+		{
+			{Text: "✖️ Cancel trade", Data: "dispute_cancel_" + disputeID.String()},
+		},
+	}
+}
+
+// disputeCaption renders the MarkdownV2 caption of a dispute card: the latest
+// statement of each party. The photo of the card belongs to evidence 'photoOf'.
+// Statements are capped at 300 characters, so two fit in Telegram's caption limit.
+func disputeCaption(
+	ctx context.Context,
+	disputeRepo ports.DisputeRepository,
+	userRepo ports.UserRepository,
+	dispute *domain.Dispute,
+	photoOf uuid.UUID,
+) (string, error) {
+	evidence, err := disputeRepo.GetEvidence(ctx, dispute.ID)
+	if err != nil {
+		return "", err
+	}
+	latest := map[domain.TransactionLeg]*domain.DisputeEvidence{}
+	for _, ev := range evidence {
+		latest[ev.Leg] = ev // Oldest first, so the last one wins
+	}
+
+	var caption strings.Builder
+	caption.WriteString(fmt.Sprintf("*Dispute for Review*\nTransaction: `%s`\n", dispute.TransactionID))
+	for _, leg := range []domain.TransactionLeg{domain.LegSeller, domain.LegBuyer} {
+		ev, ok := latest[leg]
+		if !ok {
+			caption.WriteString(fmt.Sprintf("\n*%s:* _no statement yet_\n", legTitle(leg)))
+			continue
+		}
+		name := ""
+		if user, err := userRepo.GetByID(ctx, ev.UserID); err == nil && user != nil && user.FirstName != nil && user.LastName != nil {
+			name = fmt.Sprintf(" \\(%s %s\\)", messages.EscapeMarkdown(*user.FirstName), messages.EscapeMarkdown(*user.LastName))
+		}
+		var tags []string
+		if ev.UserID == dispute.OpenedByUserID {
+			tags = append(tags, "opened the dispute")
+		}
+		if ev.ID == photoOf {
+			tags = append(tags, "📷 photo below")
+		}
+		tag := ""
+		if len(tags) > 0 {
+			tag = " \\- " + strings.Join(tags, ", ")
+		}
+		caption.WriteString(fmt.Sprintf("\n*%s*%s%s\n%s\n", legTitle(leg), name, tag, messages.EscapeMarkdown(ev.Statement)))
+	}
+	caption.WriteString(fmt.Sprintf("\n*Status:* %s\n", messages.EscapeMarkdown(string(dispute.Status))))
+	return caption.String(), nil
+}
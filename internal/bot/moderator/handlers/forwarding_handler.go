@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
 	"AsaExchange/internal/core/ports"
 	"AsaExchange/internal/shared/config"
 	"context"
@@ -39,22 +41,9 @@ func NewForwardingHandler(
 // HandleEvent is the method that will be subscribed to the VerificationQueue
 func (h *ForwardingHandler) HandleEvent(event ports.NewVerificationEvent) error {
 	ctx := context.Background()
-	log := h.log.With().Str("user_id", event.UserID.String()).Logger()
+	log := h.log.With().Str("user_id", event.UserID.String()).Str("document", string(event.Document)).Logger()
 	log.Info().Msg("Processing new verification event from queue")
 
-	// 1. Build the inline buttons
-	approveData := fmt.Sprintf("approval_accept_%s", event.UserID.String())
-	rejectData := fmt.Sprintf("approval_reject_%s", event.UserID.String())
-
-	buttons := [][]ports.Button{
-		{
-			{Text: "✅ Approve", Data: approveData},
-			{Text: "❌ Reject", Data: rejectData},
-		},
-	}
-
-	// 2. Escape the caption for MarkdownV2
-	// The caption from the event is plain text. We re-format it for the admin.
 	user, err := h.userRepo.GetByID(ctx, event.UserID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get user for forwarding")
@@ -65,38 +54,52 @@ func (h *ForwardingHandler) HandleEvent(event ports.NewVerificationEvent) error
 		return fmt.Errorf("user %s not found", event.UserID)
 	}
 
+	// 1. Build the card for the document
+	// The caption from the event is plain text. We re-format it for the admin.
 	var caption strings.Builder
-	caption.WriteString(fmt.Sprintf("*User for Review*\nID: `%s`\n\n", user.ID.String()))
-	if user.FirstName != nil {
-		caption.WriteString(fmt.Sprintf("*First Name:* %s\n", escapeMarkdown(*user.FirstName)))
-	}
-	if user.LastName != nil {
-		caption.WriteString(fmt.Sprintf("*Last Name:* %s\n", escapeMarkdown(*user.LastName)))
-	}
-	if user.PhoneNumber != nil {
-		caption.WriteString(fmt.Sprintf("*Phone:* `%s`\n", escapeMarkdown(*user.PhoneNumber)))
-	}
-	if user.GovernmentID != nil {
-		caption.WriteString(fmt.Sprintf("*Gov ID:* `%s`\n", escapeMarkdown(*user.GovernmentID)))
-	}
-	if user.LocationCountry != nil {
-		countryTitle := *user.LocationCountry // Fallback to ISO code
-		if country, ok := h.countryStrategies[*user.LocationCountry]; ok {
-			countryTitle = country.Title
+	var buttons [][]ports.Button
+	switch event.Document {
+	case ports.VerificationDocAddress:
+		// The first of the two upgrade photos; the selfie card carries the buttons
+		caption.WriteString(fmt.Sprintf("*Level 2 Upgrade: Proof of Address*\nID: `%s`\n\n", user.ID.String()))
+		h.writeUserDetails(&caption, user)
+		caption.WriteString("\nThe selfie and the review buttons follow\\.")
+
+	case ports.VerificationDocSelfie:
+		caption.WriteString(fmt.Sprintf("*Level 2 Upgrade for Review*\nID: `%s`\n\n", user.ID.String()))
+		h.writeUserDetails(&caption, user)
+		caption.WriteString(fmt.Sprintf("*Current level:* %s\n", messages.EscapeMarkdown(string(user.VerificationStatus))))
+		caption.WriteString("\nThis is the selfie holding the ID\\. The proof of address was posted just above\\.")
+		buttons = [][]ports.Button{
+			{
+				{Text: "✅ Approve level 2", Data: fmt.Sprintf("approval_l2accept_%s", user.ID)},
+				{Text: "❌ Reject upgrade", Data: fmt.Sprintf("approval_l2reject_%s", user.ID)},
+			},
+		}
+
+	default:
+		caption.WriteString(fmt.Sprintf("*User for Review*\nID: `%s`\n\n", user.ID.String()))
+		h.writeUserDetails(&caption, user)
+		buttons = [][]ports.Button{
+			{
+				{Text: "✅ Approve", Data: fmt.Sprintf("approval_accept_%s", user.ID)},
+				{Text: "❌ Reject", Data: fmt.Sprintf("approval_reject_%s", user.ID)},
+			},
 		}
-		caption.WriteString(fmt.Sprintf("*Country:* %s\n", escapeMarkdown(countryTitle)))
 	}
 
-	// 3. Send the photo (using its FileID) to the *admin review channel*
+	// 2. Send the photo (using its FileID) to the *admin review channel*
 	photoParams := ports.SendPhotoParams{
 		ChatID:    h.adminReviewChannelID,
 		File:      tgbotapi.FileID(event.FileID),
 		Caption:   caption.String(),
 		ParseMode: "MarkdownV2",
-		ReplyMarkup: &ports.ReplyMarkup{
+	}
+	if buttons != nil {
+		photoParams.ReplyMarkup = &ports.ReplyMarkup{
 			IsInline: true,
 			Buttons:  buttons,
-		},
+		}
 	}
 
 	if _, err := h.bot.SendPhoto(ctx, photoParams); err != nil {
@@ -108,11 +111,25 @@ func (h *ForwardingHandler) HandleEvent(event ports.NewVerificationEvent) error
 	return nil
 }
 
-func escapeMarkdown(s string) string {
-	replacer := strings.NewReplacer(
-		"_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]", "(", "\\(", ")", "\\)",
-		"~", "\\~", "`", "\\`", ">", "\\>", "#", "\\#", "+", "\\+", "-", "\\-",
-		"=", "\\=", "|", "\\|", "{", "\\{", "}", "\\}", ".", "\\.", "!", "\\!",
-	)
-	return replacer.Replace(s)
+// writeUserDetails adds the user's registration data to a MarkdownV2 caption.
+func (h *ForwardingHandler) writeUserDetails(caption *strings.Builder, user *domain.User) {
+	if user.FirstName != nil {
+		caption.WriteString(fmt.Sprintf("*First Name:* %s\n", messages.EscapeMarkdown(*user.FirstName)))
+	}
+	if user.LastName != nil {
+		caption.WriteString(fmt.Sprintf("*Last Name:* %s\n", messages.EscapeMarkdown(*user.LastName)))
+	}
+	if user.PhoneNumber != nil {
+		caption.WriteString(fmt.Sprintf("*Phone:* `%s`\n", messages.EscapeMarkdown(*user.PhoneNumber)))
+	}
+	if user.GovernmentID != nil {
+		caption.WriteString(fmt.Sprintf("*Gov ID:* `%s`\n", messages.EscapeMarkdown(*user.GovernmentID)))
+	}
+	if user.LocationCountry != nil {
+		countryTitle := *user.LocationCountry // Fallback to ISO code
+		if country, ok := h.countryStrategies[*user.LocationCountry]; ok {
+			countryTitle = country.Title
+		}
+		caption.WriteString(fmt.Sprintf("*Country:* %s\n", messages.EscapeMarkdown(countryTitle)))
+	}
 }
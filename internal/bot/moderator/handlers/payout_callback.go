@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/bot/moderator"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// init
+func init() {
+	moderator.RegisterCallback(NewPayoutCallbackHandler)
+}
+
+// Keys used in the moderator's StateData while a payout proof is awaited.
+const (
+	stateKeyPayoutTx  = "payout_tx_id"
+	stateKeyPayoutLeg = "payout_leg"
+)
+
+// payoutCallbackHandler shows a moderator where to send a payout
+// and waits for the proof (see payout_proof_handler.go).
+type payoutCallbackHandler struct {
+	log         zerolog.Logger
+	userRepo    ports.UserRepository
+	requestRepo ports.RequestRepository
+	txRepo      ports.TransactionRepository
+	bankRepo    ports.UserBankAccountRepository
+	currencies  *domain.CurrencyRegistry
+	bot         ports.BotClientPort
+}
+
+// NewPayoutCallbackHandler
+func NewPayoutCallbackHandler(deps *moderator.HandlerDeps) ports.CallbackHandler {
+	return &payoutCallbackHandler{
+		log:         deps.BaseLogger.With().Str("component", "payout_callback").Logger(),
+		userRepo:    deps.UserRepo,
+		requestRepo: deps.RequestRepo,
+		txRepo:      deps.TransactionRepo,
+		bankRepo:    deps.BankAccountRepo,
+		currencies:  deps.Cfg.Currencies,
+		bot:         deps.BotClient,
+	}
+}
+
+func (h *payoutCallbackHandler) Prefix() string {
+	return "payout_"
+}
+
+// Handle processes "payout_show_<txid>_<leg>" and "payout_abort_<txid>_<leg>".
+func (h *payoutCallbackHandler) Handle(ctx context.Context, update *ports.BotUpdate, adminUser *domain.User) error {
+	log := h.log.With().Int64("admin_id", adminUser.TelegramID).Logger()
+
+	parts := strings.Split(*update.CallbackData, "_")
+	if len(parts) != 4 {
+		log.Error().Str("data", *update.CallbackData).Msg("Invalid callback data format")
+		return h.answer(ctx, update, "", false)
+	}
+	txID, err := uuid.Parse(parts[2])
+	if err != nil {
+		log.Error().Err(err).Str("tx_id_str", parts[2]).Msg("Failed to parse UUID from callback")
+		return h.answer(ctx, update, "", false)
+	}
+	leg, ok := decodeLeg(parts[3])
+	if !ok {
+		log.Error().Str("data", *update.CallbackData).Msg("Invalid leg in callback data")
+		return h.answer(ctx, update, "", false)
+	}
+
+	log = log.With().Str("transaction_id", txID.String()).Str("leg", string(leg)).Logger()
+
+	switch parts[1] {
+	case "show":
+		return h.show(ctx, log, update, adminUser, txID, leg)
+	case "abort":
+		return h.abort(ctx, log, update, adminUser)
+	}
+
+	log.Warn().Str("data", *update.CallbackData).Msg("Unknown payout callback")
+	return h.answer(ctx, update, "", false)
+}
+
+// show sends the decrypted destination account to the moderator in private
+// and waits for the proof photo.
+func (h *payoutCallbackHandler) show(
+	ctx context.Context,
+	log zerolog.Logger,
+	update *ports.BotUpdate,
+	adminUser *domain.User,
+	txID uuid.UUID,
+	leg domain.TransactionLeg,
+) error {
+	tx, err := h.txRepo.GetByID(ctx, txID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get transaction")
+		return h.answer(ctx, update, "Error: Could not load transaction.", true)
+	}
+	if tx == nil {
+		return h.answer(ctx, update, "Error: Transaction not found.", true)
+	}
+	if _, ok := services.PayoutTarget(tx.Status, leg); !ok {
+		return h.answer(ctx, update, fmt.Sprintf("The %s payout is not due (status: %s).", leg, tx.Status), true)
+	}
+
+	req, err := h.requestRepo.GetByID(ctx, tx.RequestID)
+	if err != nil || req == nil {
+		log.Error().Err(err).Msg("Failed to get request of transaction")
+		return h.answer(ctx, update, "Error: Could not load request.", true)
+	}
+	recipientID := tx.BuyerUserID
+	if leg == domain.LegSeller {
+		recipientID = tx.SellerUserID
+	}
+	recipient, err := h.userRepo.GetByID(ctx, recipientID)
+	if err != nil || recipient == nil {
+		log.Error().Err(err).Msg("Failed to get payout recipient")
+		return h.answer(ctx, update, "Error: Could not load recipient.", true)
+	}
+
+	payout := tx.PayoutFor(h.currencies, req, leg)
+	acct, err := h.payoutAccount(ctx, tx, leg, payout.Net.Currency)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to resolve payout account")
+		return h.answer(ctx, update, "Error: Could not load payout account.", true)
+	}
+	if acct == nil {
+		return h.answer(ctx, update, fmt.Sprintf("The %s has no %s payout account yet.", leg, payout.Net.Currency), true)
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("*Payout to %s*\nTransaction: `%s`\n\n", legTitle(leg), tx.ID))
+	text.WriteString(fmt.Sprintf("*Gross:* %s\n", messages.FormatMoney(payout.Gross)))
+	text.WriteString(fmt.Sprintf("*Fee:* %s\n", messages.FormatMoney(payout.Fee)))
+	text.WriteString(fmt.Sprintf("*Send:* %s\n", messages.FormatMoney(payout.Net)))
+	if recipient.FirstName != nil && recipient.LastName != nil {
+		text.WriteString(fmt.Sprintf("*Recipient:* %s %s\n", messages.EscapeMarkdown(*recipient.FirstName), messages.EscapeMarkdown(*recipient.LastName)))
+	}
+	text.WriteString(fmt.Sprintf("*Account:* %s\n", messages.EscapeMarkdown(acct.AccountName)))
+	text.WriteString(fmt.Sprintf("*Bank:* %s\n", messages.EscapeMarkdown(acct.BankName)))
+	text.WriteString(fmt.Sprintf("*Details:* `%s`\n\n", messages.EscapeMarkdown(acct.AccountDetails)))
+	text.WriteString("Send the payment, then reply here with a *photo* of the proof\\.")
+
+	// The details are only ever sent to the moderator's private chat
+	if _, err := h.bot.SendMessage(ctx, ports.SendMessageParams{
+		ChatID:    adminUser.TelegramID,
+		Text:      text.String(),
+		ParseMode: "MarkdownV2",
+		ReplyMarkup: &ports.ReplyMarkup{
+			IsInline: true,
+			Buttons: [][]ports.Button{{
+				{Text: "✖️ Cancel", Data: fmt.Sprintf("payout_abort_%s_%s", tx.ID, encodeLeg(leg))},
+			}},
+		},
+	}); err != nil {
+		log.Error().Err(err).Msg("Failed to send payout details to moderator")
+		return h.answer(ctx, update, "Please start a private chat with me first.", true)
+	}
+
+	adminUser.State = domain.StateAwaitingPayoutProof
+	adminUser.StateData = map[string]string{
+		stateKeyPayoutTx:  tx.ID.String(),
+		stateKeyPayoutLeg: string(leg),
+	}
+	if err := h.userRepo.Update(ctx, adminUser); err != nil {
+		log.Error().Err(err).Msg("Failed to set moderator payout state")
+		return h.answer(ctx, update, "Error: Could not update your state.", true)
+	}
+
+	log.Info().Msg("Sent payout details to moderator")
+	return h.answer(ctx, update, "Payout details sent to you in a private chat.", false)
+}
+
+// abort leaves the payout proof step.
+func (h *payoutCallbackHandler) abort(ctx context.Context, log zerolog.Logger, update *ports.BotUpdate, adminUser *domain.User) error {
+	h.answer(ctx, update, "", false)
+
+	if adminUser.State == domain.StateAwaitingPayoutProof {
+		adminUser.State = domain.StateNone
+		adminUser.StateData = map[string]string{}
+		if err := h.userRepo.Update(ctx, adminUser); err != nil {
+			log.Error().Err(err).Msg("Failed to clear moderator payout state")
+		}
+	}
+	return h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:    update.ChatID,
+		MessageID: update.MessageID,
+		Text:      "Payout cancelled.",
+	})
+}
+
+// payoutAccount returns the account a leg is paid out to.
+// Until one is recorded on the transaction, it falls back to the recipient's
+// newest account in the payout currency and records that one.
+func (h *payoutCallbackHandler) payoutAccount(
+	ctx context.Context,
+	tx *domain.Transaction,
+	leg domain.TransactionLeg,
+	currency string,
+) (*domain.UserBankAccount, error) {
+	accountID, recipientID := tx.BuyerPayoutAccountID, tx.BuyerUserID
+	if leg == domain.LegSeller {
+		accountID, recipientID = tx.SellerPayoutAccountID, tx.SellerUserID
+	}
+	if accountID != nil {
+		return h.bankRepo.GetByID(ctx, *accountID)
+	}
+
+	accounts, err := h.bankRepo.GetByUserID(ctx, recipientID)
+	if err != nil {
+		return nil, err
+	}
+	for _, acct := range accounts { // Newest first
+		if acct.Currency == currency {
+			if err := h.txRepo.SetPayoutAccount(ctx, tx.ID, leg, acct.ID); err != nil {
+				return nil, err
+			}
+			return acct, nil
+		}
+	}
+	return nil, nil
+}
+
+// answer stops the button spinner, showing text as a toast or alert if given.
+func (h *payoutCallbackHandler) answer(ctx context.Context, update *ports.BotUpdate, text string, alert bool) error {
+	return h.bot.AnswerCallbackQuery(ctx, ports.AnswerCallbackParams{
+		CallbackQueryID: update.CallbackQueryID,
+		Text:            text,
+		ShowAlert:       alert,
+	})
+}
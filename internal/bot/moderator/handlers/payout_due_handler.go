@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/shared/config"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// PayoutDueHandler posts a payout card to the admin channel once both deposits are in
+type PayoutDueHandler struct {
+	log                  zerolog.Logger
+	requestRepo          ports.RequestRepository
+	currencies           *domain.CurrencyRegistry
+	bot                  ports.BotClientPort
+	adminReviewChannelID int64
+}
+
+// NewPayoutDueHandler creates a new handler for announcing due payouts
+func NewPayoutDueHandler(
+	cfg *config.Config,
+	requestRepo ports.RequestRepository,
+	bot ports.BotClientPort,
+	baseLogger *zerolog.Logger,
+) *PayoutDueHandler {
+	return &PayoutDueHandler{
+		log:                  baseLogger.With().Str("component", "payout_due_handler").Logger(),
+		requestRepo:          requestRepo,
+		currencies:           cfg.Currencies,
+		bot:                  bot,
+		adminReviewChannelID: cfg.Bot.Moderator.AdminReviewChannelID,
+	}
+}
+
+// HandleEvent handles events.PayoutsDue.
+func (h *PayoutDueHandler) HandleEvent(ctx context.Context, event events.PayoutsDue) error {
+	tx := &event.Transaction
+
+	log := h.log.With().Str("transaction_id", tx.ID.String()).Logger()
+
+	req, err := h.requestRepo.GetByID(ctx, tx.RequestID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load request for payout card")
+		return err
+	}
+	if req == nil {
+		log.Error().Msg("Request of transaction not found, skipping payout card")
+		return nil
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("*Payouts Due*\nTransaction: `%s`\n\n", tx.ID))
+	for _, leg := range []domain.TransactionLeg{domain.LegSeller, domain.LegBuyer} {
+		payout := tx.PayoutFor(h.currencies, req, leg)
+		text.WriteString(fmt.Sprintf("*%s gets:* %s \\(gross %s, fee %s\\)\n", legTitle(leg),
+			messages.FormatMoney(payout.Net), messages.FormatMoney(payout.Gross), messages.FormatMoney(payout.Fee)))
+	}
+
+	msg := ports.SendMessageParams{
+		ChatID:    h.adminReviewChannelID,
+		Text:      text.String(),
+		ParseMode: "MarkdownV2",
+		ReplyMarkup: &ports.ReplyMarkup{
+			IsInline: true,
+			Buttons:  [][]ports.Button{payoutButtons(tx.ID)},
+		},
+	}
+	if _, err := h.bot.SendMessage(ctx, msg); err != nil {
+		log.Error().Err(err).Msg("Failed to post payout card")
+		return err
+	}
+
+	log.Info().Msg("Posted payout card to admins")
+	return nil
+}
+
+// payoutButtons open the payout flow of each leg.
+func payoutButtons(txID uuid.UUID) []ports.Button {
+	return []ports.Button{
+		{Text: "💸 Pay seller", Data: fmt.Sprintf("payout_show_%s_%s", txID, encodeLeg(domain.LegSeller))},
+		{Text: "💸 Pay buyer", Data: fmt.Sprintf("payout_show_%s_%s", txID, encodeLeg(domain.LegBuyer))},
+	}
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/moderator"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// init
+func init() {
+	moderator.RegisterMessage(NewPayoutProofHandler)
+}
+
+// payoutProofHandler is the moderator bot's message handler.
+// It takes the payout proof photo, marks the leg as paid and
+// forwards the proof to the recipient.
+type payoutProofHandler struct {
+	log        zerolog.Logger
+	userRepo   ports.UserRepository
+	txRepo     ports.TransactionRepository
+	txService  *services.TransactionService
+	proofRepo  ports.PayoutProofRepository
+	transactor ports.Transactor
+	bot        ports.BotClientPort
+	bus        ports.EventBus
+}
+
+// NewPayoutProofHandler
+func NewPayoutProofHandler(deps *moderator.HandlerDeps) ports.MessageHandler {
+	return &payoutProofHandler{
+		log:        deps.BaseLogger.With().Str("component", "payout_proof_handler").Logger(),
+		userRepo:   deps.UserRepo,
+		txRepo:     deps.TransactionRepo,
+		txService:  deps.TxService,
+		proofRepo:  deps.PayoutProofRepo,
+		transactor: deps.Transactor,
+		bot:        deps.BotClient,
+		bus:        deps.Bus,
+	}
+}
+
+func (h *payoutProofHandler) Handle(ctx context.Context, update *ports.BotUpdate, adminUser *domain.User) error {
+	log := h.log.With().Int64("admin_id", adminUser.TelegramID).Logger()
+
+	if adminUser.State != domain.StateAwaitingPayoutProof {
+		log.Debug().Msg("Ignoring moderator message outside of a flow")
+		return nil
+	}
+	if update.Photo == nil {
+		return h.reply(ctx, update.ChatID, "Please send the payout proof as a photo.")
+	}
+
+	txID, err := uuid.Parse(adminUser.StateData[stateKeyPayoutTx])
+	leg := domain.TransactionLeg(adminUser.StateData[stateKeyPayoutLeg])
+	if err != nil || (leg != domain.LegSeller && leg != domain.LegBuyer) {
+		log.Error().Err(err).Msg("Payout state has no valid transaction")
+		h.clearState(ctx, log, adminUser)
+		return h.reply(ctx, update.ChatID, "Error: Lost track of this payout. Please start again from the payout card.")
+	}
+	log = log.With().Str("transaction_id", txID.String()).Str("leg", string(leg)).Logger()
+
+	// 1. Fetch the proof now, so a failed download leaves the payout untouched
+	photo, err := h.bot.DownloadFile(ctx, update.Photo.FileID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to download payout proof")
+		return h.reply(ctx, update.ChatID, "Error: Could not read the photo. Please send it again.")
+	}
+
+	// 2. Mark the leg as paid and send the proof to the recipient.
+	// The second payout completes the trade.
+	tx, to, reason := h.markPaid(ctx, log, adminUser, txID, leg, photo)
+	h.clearState(ctx, log, adminUser)
+	if tx == nil {
+		return h.reply(ctx, update.ChatID, reason)
+	}
+
+	return h.reply(ctx, update.ChatID, fmt.Sprintf("✅ %s Payout Recorded\nTransaction: %s\nStatus: %s", legTitle(leg), tx.ID, to))
+}
+
+// markPaid moves the transaction past this leg's payout, stores the proof and
+// publishes it for the recipient in the same transaction, retrying once if the
+// other leg was recorded at the same moment.
+// On failure it returns a nil transaction and a reason for the moderator.
+func (h *payoutProofHandler) markPaid(
+	ctx context.Context,
+	log zerolog.Logger,
+	adminUser *domain.User,
+	txID uuid.UUID,
+	leg domain.TransactionLeg,
+	photo []byte,
+) (*domain.Transaction, domain.TransactionStatus, string) {
+	for attempt := 0; ; attempt++ {
+		tx, err := h.txRepo.GetByID(ctx, txID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get transaction")
+			return nil, "", "Error: Could not load transaction."
+		}
+		if tx == nil {
+			return nil, "", "Error: Transaction not found."
+		}
+
+		to, ok := services.PayoutTarget(tx.Status, leg)
+		if !ok {
+			return nil, "", fmt.Sprintf("The %s payout is not due anymore (status: %s).", leg, tx.Status)
+		}
+
+		var updated *domain.Transaction
+		err = h.transactor.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			updated, err = h.txService.Transition(ctx, tx.ID, to, &adminUser.ID)
+			if err != nil {
+				return err
+			}
+			proof := &domain.PayoutProof{ID: uuid.New(), TransactionID: updated.ID, Leg: leg, Photo: photo}
+			if err := h.proofRepo.Create(ctx, proof); err != nil {
+				return err
+			}
+			recipientID := updated.BuyerUserID
+			if leg == domain.LegSeller {
+				recipientID = updated.SellerUserID
+			}
+			return events.Publish(ctx, h.bus, events.PayoutProofSent{
+				TransactionID: updated.ID,
+				UserID:        recipientID,
+				Leg:           leg,
+				ProofID:       proof.ID,
+			})
+		})
+		if errors.Is(err, ports.ErrTransactionStatusChanged) && attempt == 0 {
+			log.Warn().Msg("Transaction moved during payout, retrying")
+			continue
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to record payout")
+			return nil, "", "Error: Could not record the payout, nothing was saved. Please start again from the payout card."
+		}
+
+		log.Info().Str("status", string(to)).Msg("Payout recorded")
+		return updated, to, ""
+	}
+}
+
+// clearState ends the payout proof step.
+func (h *payoutProofHandler) clearState(ctx context.Context, log zerolog.Logger, adminUser *domain.User) {
+	adminUser.State = domain.StateNone
+	adminUser.StateData = map[string]string{}
+	if err := h.userRepo.Update(ctx, adminUser); err != nil {
+		log.Error().Err(err).Msg("Failed to clear moderator payout state")
+	}
+}
+
+// reply sends a plain-text message to the moderator.
+func (h *payoutProofHandler) reply(ctx context.Context, chatID int64, text string) error {
+	_, err := h.bot.SendMessage(ctx, ports.SendMessageParams{ChatID: chatID, Text: text})
+	return err
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/bot/moderator"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	moderator.RegisterCommand(NewListPlatformAccountsHandler)
+	moderator.RegisterCommand(NewAddPlatformAccountHandler)
+	moderator.RegisterCommand(NewDeactivatePlatformAccountHandler)
+}
+
+const addPlatformAccountUsage = "Usage: /addplatformaccount <CUR> | <bank> | <account name> | <details>\n" +
+	"Example: /addplatformaccount EUR | N26 | Primary EUR | IBAN DE89 3704 0044 0532 0130 00"
+
+// platformAccountsBase holds what the three platform account commands share.
+type platformAccountsBase struct {
+	log          zerolog.Logger
+	platformRepo ports.PlatformAccountRepository
+	bot          ports.BotClientPort
+	currencies   *domain.CurrencyRegistry
+}
+
+func newPlatformAccountsBase(deps *moderator.HandlerDeps, component string) platformAccountsBase {
+	return platformAccountsBase{
+		log:          deps.BaseLogger.With().Str("component", component).Logger(),
+		platformRepo: deps.PlatformAcctRepo,
+		bot:          deps.BotClient,
+		currencies:   deps.Cfg.Currencies,
+	}
+}
+
+// --- /platformaccounts ---
+
+// listPlatformAccountsHandler lists every company receiving account.
+type listPlatformAccountsHandler struct {
+	platformAccountsBase
+}
+
+// NewListPlatformAccountsHandler creates a new handler for the /platformaccounts command.
+func NewListPlatformAccountsHandler(deps *moderator.HandlerDeps) ports.CommandHandler {
+	return &listPlatformAccountsHandler{newPlatformAccountsBase(deps, "list_platform_accounts_handler")}
+}
+
+func (h *listPlatformAccountsHandler) Command() string {
+	return "platformaccounts"
+}
+
+func (h *listPlatformAccountsHandler) Handle(ctx context.Context, update *ports.BotUpdate) error {
+	accounts, err := h.platformRepo.List(ctx)
+	if err != nil {
+		h.log.Error().Err(err).Msg("Failed to list platform accounts")
+		return h.sendText(ctx, update.ChatID, "Error: Could not load platform accounts.")
+	}
+	if len(accounts) == 0 {
+		return h.sendText(ctx, update.ChatID, "No platform accounts yet.\n\n"+addPlatformAccountUsage)
+	}
+
+	var text strings.Builder
+	text.WriteString("🏦 *Platform Accounts*\n\n")
+	for _, acct := range accounts {
+		status := "✅ active"
+		if !acct.IsActive {
+			status = "⏸ inactive"
+		}
+		text.WriteString(fmt.Sprintf("*%s* · %s · %s · %s\n",
+			acct.Currency,
+			messages.EscapeMarkdown(acct.AccountName),
+			messages.EscapeMarkdown(acct.BankName),
+			status,
+		))
+		text.WriteString(fmt.Sprintf("`%s`\n", messages.EscapeMarkdown(acct.AccountDetails)))
+		text.WriteString(fmt.Sprintf("ID: `%s`\n\n", acct.ID))
+	}
+	text.WriteString("Deactivate with /deactivateplatformaccount \\<ID\\>")
+
+	_, err = h.bot.SendMessage(ctx, ports.SendMessageParams{
+		ChatID:    update.ChatID,
+		Text:      text.String(),
+		ParseMode: "MarkdownV2",
+	})
+	return err
+}
+
+// --- /addplatformaccount ---
+
+// addPlatformAccountHandler creates an active, manually verified account.
+type addPlatformAccountHandler struct {
+	platformAccountsBase
+}
+
+// NewAddPlatformAccountHandler creates a new handler for the /addplatformaccount command.
+func NewAddPlatformAccountHandler(deps *moderator.HandlerDeps) ports.CommandHandler {
+	return &addPlatformAccountHandler{newPlatformAccountsBase(deps, "add_platform_account_handler")}
+}
+
+func (h *addPlatformAccountHandler) Command() string {
+	return "addplatformaccount"
+}
+
+func (h *addPlatformAccountHandler) Handle(ctx context.Context, update *ports.BotUpdate) error {
+	fields := strings.Split(update.CommandArgs, "|")
+	if len(fields) != 4 {
+		return h.sendText(ctx, update.ChatID, addPlatformAccountUsage)
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+		if fields[i] == "" {
+			return h.sendText(ctx, update.ChatID, addPlatformAccountUsage)
+		}
+	}
+	currency := strings.ToUpper(fields[0])
+	if _, ok := h.currencies.Get(currency); !ok {
+		return h.sendText(ctx, update.ChatID, "Error: The currency must be one of "+currencyCodes(h.currencies.Currencies())+".")
+	}
+
+	acct := &domain.PlatformAccount{
+		ID:                   uuid.New(),
+		Currency:             currency,
+		BankName:             fields[1],
+		AccountName:          fields[2],
+		AccountDetails:       fields[3],
+		VerificationStrategy: domain.VerificationStrategyManual,
+		IsActive:             true,
+	}
+	if err := h.platformRepo.Create(ctx, acct); err != nil {
+		h.log.Error().Err(err).Msg("Failed to create platform account")
+		return h.sendText(ctx, update.ChatID, "Error: Could not save the platform account.")
+	}
+	h.log.Info().Str("acct_id", acct.ID.String()).Str("currency", currency).Int64("admin_id", update.UserID).Msg("Platform account added")
+
+	return h.sendText(ctx, update.ChatID, fmt.Sprintf("✅ Added %s account \"%s\".\nID: %s", currency, acct.AccountName, acct.ID))
+}
+
+// --- /deactivateplatformaccount ---
+
+// deactivatePlatformAccountHandler stops an account from receiving new deposits.
+// Trades that already use it keep their instructions.
+type deactivatePlatformAccountHandler struct {
+	platformAccountsBase
+}
+
+// NewDeactivatePlatformAccountHandler creates a new handler for the /deactivateplatformaccount command.
+func NewDeactivatePlatformAccountHandler(deps *moderator.HandlerDeps) ports.CommandHandler {
+	return &deactivatePlatformAccountHandler{newPlatformAccountsBase(deps, "deactivate_platform_account_handler")}
+}
+
+func (h *deactivatePlatformAccountHandler) Command() string {
+	return "deactivateplatformaccount"
+}
+
+func (h *deactivatePlatformAccountHandler) Handle(ctx context.Context, update *ports.BotUpdate) error {
+	id, err := uuid.Parse(strings.TrimSpace(update.CommandArgs))
+	if err != nil {
+		return h.sendText(ctx, update.ChatID, "Usage: /deactivateplatformaccount <ID>\nSee /platformaccounts for the IDs.")
+	}
+	log := h.log.With().Str("acct_id", id.String()).Int64("admin_id", update.UserID).Logger()
+
+	acct, err := h.platformRepo.GetByID(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get platform account")
+		return h.sendText(ctx, update.ChatID, "Error: Could not load the platform account.")
+	}
+	if acct == nil {
+		return h.sendText(ctx, update.ChatID, "Error: Platform account not found.")
+	}
+	if !acct.IsActive {
+		return h.sendText(ctx, update.ChatID, "This account is already inactive.")
+	}
+
+	acct.IsActive = false
+	if err := h.platformRepo.Update(ctx, acct); err != nil {
+		log.Error().Err(err).Msg("Failed to deactivate platform account")
+		return h.sendText(ctx, update.ChatID, "Error: Could not deactivate the platform account.")
+	}
+	log.Info().Msg("Platform account deactivated")
+
+	return h.sendText(ctx, update.ChatID, fmt.Sprintf("⏸ Deactivated %s account \"%s\".", acct.Currency, acct.AccountName))
+}
+
+// sendText sends a plain-text reply.
+func (h *platformAccountsBase) sendText(ctx context.Context, chatID int64, text string) error {
+	_, err := h.bot.SendMessage(ctx, ports.SendMessageParams{ChatID: chatID, Text: text})
+	return err
+}
+
+// currencyCodes lists the codes of currencies, e.g. "EUR, USD".
+func currencyCodes(currencies []domain.Currency) string {
+	codes := make([]string, 0, len(currencies))
+	for _, c := range currencies {
+		codes = append(codes, c.Code)
+	}
+	return strings.Join(codes, ", ")
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"AsaExchange/internal/bot/messages"
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/shared/config"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// offerStartPrefix is the /start payload that opens an offer in the Customer Bot.
+const offerStartPrefix = "offer_"
+
+// PublicChannelHandler keeps one card per request in the public channel.
+// It posts the card when a request is created and edits it in place once
+// the request is matched, completed, cancelled or reopened.
+type PublicChannelHandler struct {
+	log             zerolog.Logger
+	requestRepo     ports.RequestRepository
+	currencies      *domain.CurrencyRegistry
+	bot             ports.BotClientPort
+	publicChannelID int64
+	customerBotName string
+}
+
+// NewPublicChannelHandler creates a new handler for the public offer channel.
+// customerBotName is the Customer Bot's username, used for the deep links.
+func NewPublicChannelHandler(
+	cfg *config.Config,
+	requestRepo ports.RequestRepository,
+	bot ports.BotClientPort,
+	customerBotName string,
+	baseLogger *zerolog.Logger,
+) *PublicChannelHandler {
+	return &PublicChannelHandler{
+		log:             baseLogger.With().Str("component", "public_channel_handler").Logger(),
+		requestRepo:     requestRepo,
+		currencies:      cfg.Currencies,
+		bot:             bot,
+		publicChannelID: cfg.Bot.Moderator.PublicChannelID,
+		customerBotName: customerBotName,
+	}
+}
+
+// HandleRequestCreated handles events.RequestCreated.
+// Request events are keyed by request ID, but the request is reloaded anyway:
+// a redelivered event finds its card already posted, and an offer closed in
+// the meantime needs no card at all. Later status events edit the card.
+func (h *PublicChannelHandler) HandleRequestCreated(ctx context.Context, event events.RequestCreated) error {
+	if h.publicChannelID == 0 {
+		return nil // No public channel configured
+	}
+
+	log := h.log.With().Str("request_id", event.Request.ID.String()).Logger()
+
+	req, err := h.requestRepo.GetByID(ctx, event.Request.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load request for offer card")
+		return err
+	}
+	if req == nil {
+		log.Warn().Msg("Request not found, no offer card to post")
+		return nil
+	}
+	if req.ChannelMessageID != nil {
+		log.Info().Msg("Offer card already posted")
+		return nil
+	}
+	if req.Status != domain.RequestStatusOpen {
+		log.Info().Str("status", string(req.Status)).Msg("Request closed before its card was posted, skipping")
+		return nil
+	}
+
+	msgID, err := h.bot.SendMessage(ctx, ports.SendMessageParams{
+		ChatID:      h.publicChannelID,
+		Text:        h.offerCard(req),
+		ParseMode:   "MarkdownV2",
+		ReplyMarkup: h.bidMarkup(req),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to post offer card")
+		return err
+	}
+
+	if err := h.requestRepo.SetChannelMessageID(ctx, req.ID, int64(msgID)); err != nil {
+		log.Error().Err(err).Int("message_id", msgID).Msg("Failed to save channel message ID")
+		// Take the card down before the retry posts another, so no card is
+		// left behind that nothing will ever edit
+		if !h.retireCard(ctx, msgID) {
+			// Retrying would post a second card next to the live one
+			return nil
+		}
+		return err
+	}
+	log.Info().Int("message_id", msgID).Msg("Posted offer card to public channel")
+	return nil
+}
+
+// HandleRequestMatched handles events.RequestMatched.
+// It edits the card so the channel never shows a stale offer.
+func (h *PublicChannelHandler) HandleRequestMatched(ctx context.Context, event events.RequestMatched) error {
+	return h.refreshCard(ctx, event.Topic(), event.Request.ID)
+}
+
+// HandleRequestCompleted handles events.RequestCompleted.
+func (h *PublicChannelHandler) HandleRequestCompleted(ctx context.Context, event events.RequestCompleted) error {
+	return h.refreshCard(ctx, event.Topic(), event.Request.ID)
+}
+
+// HandleRequestCancelled handles events.RequestCancelled.
+func (h *PublicChannelHandler) HandleRequestCancelled(ctx context.Context, event events.RequestCancelled) error {
+	return h.refreshCard(ctx, event.Topic(), event.Request.ID)
+}
+
+// HandleRequestReopened handles events.RequestReopened.
+// A request goes back to open when its trade is cancelled before any deposit,
+// so the card gets its bid button back.
+func (h *PublicChannelHandler) HandleRequestReopened(ctx context.Context, event events.RequestReopened) error {
+	return h.refreshCard(ctx, event.Topic(), event.Request.ID)
+}
+
+// refreshCard edits the card of a request to show its current status.
+func (h *PublicChannelHandler) refreshCard(ctx context.Context, topic string, requestID uuid.UUID) error {
+	if h.publicChannelID == 0 {
+		return nil
+	}
+
+	log := h.log.With().Str("request_id", requestID.String()).Str("topic", topic).Logger()
+
+	// Reload, so the card shows the latest status and knows its message ID
+	req, err := h.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load request for offer card")
+		return err
+	}
+	if req == nil || req.ChannelMessageID == nil {
+		log.Warn().Msg("Request has no offer card, nothing to edit")
+		return nil
+	}
+
+	// Only open offers keep the bid button
+	var markup *ports.ReplyMarkup
+	if req.Status == domain.RequestStatusOpen {
+		markup = h.bidMarkup(req)
+	}
+	if err := h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:      h.publicChannelID,
+		MessageID:   int(*req.ChannelMessageID),
+		Text:        h.offerCard(req),
+		ParseMode:   "MarkdownV2",
+		ReplyMarkup: markup,
+	}); err != nil {
+		log.Error().Err(err).Msg("Failed to edit offer card")
+		return err
+	}
+	log.Info().Str("status", string(req.Status)).Msg("Updated offer card in public channel")
+	return nil
+}
+
+// retireCard deletes a card whose message ID could not be saved. If Telegram
+// refuses, it strips the bid button instead. It reports whether the card is
+// out of the way.
+func (h *PublicChannelHandler) retireCard(ctx context.Context, msgID int) bool {
+	log := h.log.With().Int("message_id", msgID).Logger()
+
+	err := h.bot.DeleteMessage(ctx, h.publicChannelID, msgID)
+	if err == nil {
+		return true
+	}
+	log.Error().Err(err).Msg("Failed to delete unsaved offer card")
+
+	if err := h.bot.EditMessageText(ctx, ports.EditMessageParams{
+		ChatID:    h.publicChannelID,
+		MessageID: msgID,
+		Text:      "❌ *Offer withdrawn*",
+		ParseMode: "MarkdownV2",
+	}); err != nil {
+		log.Error().Err(err).Msg("Failed to withdraw unsaved offer card")
+		return false
+	}
+	return true
+}
+
+// bidMarkup is the deep-link button under an open offer card.
+func (h *PublicChannelHandler) bidMarkup(req *domain.Request) *ports.ReplyMarkup {
+	return &ports.ReplyMarkup{
+		IsInline: true,
+		Buttons: [][]ports.Button{{
+			{Text: "💰 Bid on this offer", URL: h.offerLink(req)},
+		}},
+	}
+}
+
+// offerCard renders the MarkdownV2 card of a request.
+func (h *PublicChannelHandler) offerCard(req *domain.Request) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("💱 *%s %s %s*\n\n",
+		strings.ToUpper(string(req.Type)),
+		messages.EscapeMarkdown(messages.FormatAmount(req.BaseAmount)),
+		req.BaseCurrency,
+	))
+	text.WriteString(fmt.Sprintf("*Rate:* %s %s per %s\n",
+		messages.EscapeMarkdown(messages.FormatAmount(req.ExchangeRate)), req.QuoteCurrency, req.BaseCurrency))
+	text.WriteString(fmt.Sprintf("*Total:* %s %s\n\n",
+		messages.EscapeMarkdown(messages.FormatAmount(req.QuoteAmount(h.currencies).Amount)), req.QuoteCurrency))
+	text.WriteString(offerStatusLine(req.Status))
+	return text.String()
+}
+
+// offerLink returns the deep link that opens the offer in the Customer Bot.
+func (h *PublicChannelHandler) offerLink(req *domain.Request) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s%s", h.customerBotName, offerStartPrefix, req.ID)
+}
+
+// offerStatusLine is the last line of a card.
+func offerStatusLine(status domain.RequestStatus) string {
+	switch status {
+	case domain.RequestStatusOpen:
+		return "🟢 *Open*"
+	case domain.RequestStatusMatched:
+		return "🤝 *Matched* \\- no longer taking bids"
+	case domain.RequestStatusCompleted:
+		return "✅ *Completed*"
+	case domain.RequestStatusCancelled:
+		return "❌ *Cancelled*"
+	}
+	return messages.EscapeMarkdown(string(status))
+}
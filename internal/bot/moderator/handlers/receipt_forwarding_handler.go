@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/shared/config"
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+)
+
+// ReceiptForwardingHandler listens to the receipt queue and forwards to the admin channel
+type ReceiptForwardingHandler struct {
+	log                  zerolog.Logger
+	userRepo             ports.UserRepository
+	requestRepo          ports.RequestRepository
+	txRepo               ports.TransactionRepository
+	currencies           *domain.CurrencyRegistry
+	bot                  ports.BotClientPort
+	adminReviewChannelID int64
+}
+
+// NewReceiptForwardingHandler creates a new handler for forwarding deposit receipts
+func NewReceiptForwardingHandler(
+	cfg *config.Config,
+	userRepo ports.UserRepository,
+	requestRepo ports.RequestRepository,
+	txRepo ports.TransactionRepository,
+	bot ports.BotClientPort,
+	baseLogger *zerolog.Logger,
+) *ReceiptForwardingHandler {
+	return &ReceiptForwardingHandler{
+		log:                  baseLogger.With().Str("component", "receipt_forwarding_handler").Logger(),
+		userRepo:             userRepo,
+		requestRepo:          requestRepo,
+		txRepo:               txRepo,
+		currencies:           cfg.Currencies,
+		bot:                  bot,
+		adminReviewChannelID: cfg.Bot.Moderator.AdminReviewChannelID,
+	}
+}
+
+// HandleEvent is the method that will be subscribed to the ReceiptQueue
+func (h *ReceiptForwardingHandler) HandleEvent(event ports.DepositReceiptEvent) error {
+	ctx := context.Background()
+	log := h.log.With().
+		Str("transaction_id", event.TransactionID.String()).
+		Str("leg", string(event.Leg)).
+		Logger()
+	log.Info().Msg("Processing new deposit receipt from queue")
+
+	caption, err := depositReviewCaption(ctx, h.txRepo, h.requestRepo, h.userRepo, h.currencies, event.TransactionID, event.Leg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to build receipt review caption")
+		return err
+	}
+
+	photoParams := ports.SendPhotoParams{
+		ChatID:    h.adminReviewChannelID,
+		File:      tgbotapi.FileID(event.FileID),
+		Caption:   caption,
+		ParseMode: "MarkdownV2",
+		ReplyMarkup: &ports.ReplyMarkup{
+			IsInline: true,
+			Buttons:  depositReviewButtons(event.TransactionID, event.Leg),
+		},
+	}
+
+	if _, err := h.bot.SendPhoto(ctx, photoParams); err != nil {
+		log.Error().Err(err).Msg("Failed to forward receipt to admin channel")
+		return err
+	}
+
+	log.Info().Msg("Successfully forwarded deposit receipt to admins")
+	return nil
+}
@@ -2,33 +2,39 @@ package moderator
 
 import (
 	"AsaExchange/internal/core/ports"
+	"AsaExchange/internal/core/services"
 	"AsaExchange/internal/shared/config"
 
 	"github.com/rs/zerolog"
 )
 
+// HandlerDeps bundles everything a moderator handler constructor may need,
+// just like customer.HandlerDeps.
+type HandlerDeps struct {
+	Cfg              *config.Config
+	UserRepo         ports.UserRepository
+	RequestRepo      ports.RequestRepository
+	TransactionRepo  ports.TransactionRepository
+	BankAccountRepo  ports.UserBankAccountRepository
+	PlatformAcctRepo ports.PlatformAccountRepository
+	DisputeRepo      ports.DisputeRepository
+	PayoutProofRepo  ports.PayoutProofRepository
+	TxService        *services.TransactionService
+	Disputes         *services.DisputeService
+	BotClient        ports.BotClientPort
+	Bus              ports.EventBus
+	Transactor       ports.Transactor
+	DeadLetterRepo   ports.DeadLetterRepository
+	DeadLetters      ports.DeadLetterReplayer
+	BaseLogger       *zerolog.Logger
+}
+
 // Define constructor types for moderator handlers
-type CommandHandlerConstructor func(
-	cfg *config.Config,
-	userRepo ports.UserRepository,
-	botClient ports.BotClientPort,
-	baseLogger *zerolog.Logger,
-) ports.CommandHandler
+type CommandHandlerConstructor func(deps *HandlerDeps) ports.CommandHandler
 
-type MessageHandlerConstructor func(
-	cfg *config.Config,
-	userRepo ports.UserRepository,
-	botClient ports.BotClientPort,
-	baseLogger *zerolog.Logger,
-) ports.MessageHandler
+type MessageHandlerConstructor func(deps *HandlerDeps) ports.MessageHandler
 
-type CallbackHandlerConstructor func(
-	cfg *config.Config,
-	userRepo ports.UserRepository,
-	botClient ports.BotClientPort,
-	bus ports.EventBus,
-	baseLogger *zerolog.Logger,
-) ports.CallbackHandler
+type CallbackHandlerConstructor func(deps *HandlerDeps) ports.CallbackHandler
 
 var (
 	commandRegistry  []CommandHandlerConstructor
@@ -46,31 +52,26 @@ func RegisterCallback(constructor CallbackHandlerConstructor) {
 	callbackRegistry = append(callbackRegistry, constructor)
 }
 
-func RegisterAllHandlers(
-	cfg *config.Config,
-	router *ModeratorRouter,
-	userRepo ports.UserRepository,
-	botClient ports.BotClientPort,
-	bus ports.EventBus,
-	baseLogger *zerolog.Logger,
-) {
-	log := baseLogger.With().Str("component", "moderator_registry").Logger()
+// RegisterMessage sets the single moderator message handler
+func RegisterMessage(constructor MessageHandlerConstructor) {
+	messageHandler = constructor
+}
+
+func RegisterAllHandlers(router *ModeratorRouter, deps *HandlerDeps) {
+	log := deps.BaseLogger.With().Str("component", "moderator_registry").Logger()
 	// Register all commands
 	for _, constructor := range commandRegistry {
-		handler := constructor(cfg, userRepo, botClient, baseLogger)
-		router.RegisterCommandHandler(handler)
+		router.RegisterCommandHandler(constructor(deps))
 	}
 
 	// Register the single message handler
 	if messageHandler != nil {
-		handler := messageHandler(cfg, userRepo, botClient, baseLogger)
-		router.SetMessageHandler(handler)
+		router.SetMessageHandler(messageHandler(deps))
 		log.Info().Msg("Registered main message handler")
 	}
 
 	// Register all callbacks
 	for _, constructor := range callbackRegistry {
-		handler := constructor(cfg, userRepo, botClient, bus, baseLogger)
-		router.RegisterCallbackHandler(handler)
+		router.RegisterCallbackHandler(constructor(deps))
 	}
 }
@@ -2,6 +2,7 @@ package moderator
 
 import (
 	// <-- NEW IMPORT
+	"AsaExchange/internal/core/events"
 	"AsaExchange/internal/core/ports"
 	"context"
 	"strings"
@@ -37,8 +38,8 @@ func NewModeratorRouter(
 	}
 
 	// Subscribe to the event bus topics
-	bus.Subscribe("telegram:mod:message", router.handleMessage)
-	bus.Subscribe("telegram:mod:callback_query", router.handleCallbackQuery)
+	events.Subscribe(bus, router.handleMessage)
+	events.Subscribe(bus, router.handleCallbackQuery)
 
 	return router
 }
@@ -65,9 +66,9 @@ func (r *ModeratorRouter) SetMessageHandler(handler ports.MessageHandler) {
 // --- END NEW METHOD ---
 
 // This method is called by the EventBus
-func (r *ModeratorRouter) handleMessage(ctx context.Context, event ports.Event) error {
-	update, ok := event.Data.(tgbotapi.Update)
-	if !ok || update.Message == nil {
+func (r *ModeratorRouter) handleMessage(ctx context.Context, event MessageReceived) error {
+	update := event.Update
+	if update.Message == nil {
 		r.log.Error().Msg("Received bad message event")
 		return nil // Don't retry
 	}
@@ -123,9 +124,9 @@ func (r *ModeratorRouter) handleMessage(ctx context.Context, event ports.Event)
 }
 
 // This method is called by the EventBus (UNCHANGED)
-func (r *ModeratorRouter) handleCallbackQuery(ctx context.Context, event ports.Event) error {
-	update, ok := event.Data.(tgbotapi.Update)
-	if !ok || update.CallbackQuery == nil {
+func (r *ModeratorRouter) handleCallbackQuery(ctx context.Context, event CallbackQueryReceived) error {
+	update := event.Update
+	if update.CallbackQuery == nil {
 		r.log.Error().Msg("Received bad callback_query event")
 		return nil // Don't retry
 	}
@@ -186,13 +187,20 @@ func (r *ModeratorRouter) parseUpdate(update tgbotapi.Update) (*ports.BotUpdate,
 
 	if update.Message != nil {
 		msg := update.Message
-		return &ports.BotUpdate{
-			MessageID: msg.MessageID,
-			ChatID:    msg.Chat.ID,
-			UserID:    msg.From.ID,
-			Text:      msg.Text,
-			Command:   msg.Command(),
-		}, true
+		botUpdate := &ports.BotUpdate{
+			MessageID:   msg.MessageID,
+			ChatID:      msg.Chat.ID,
+			UserID:      msg.From.ID,
+			Text:        msg.Text,
+			Command:     msg.Command(),
+			CommandArgs: msg.CommandArguments(),
+		}
+		// Moderators upload payout proofs
+		if len(msg.Photo) > 0 {
+			bestPhoto := msg.Photo[len(msg.Photo)-1]
+			botUpdate.Photo = &ports.PhotoInfo{FileID: bestPhoto.FileID, FileSize: bestPhoto.FileSize}
+		}
+		return botUpdate, true
 	}
 
 	// We ignore channel posts here
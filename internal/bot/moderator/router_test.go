@@ -5,6 +5,7 @@ import (
 	"AsaExchange/internal/core/ports"
 	"context"
 	"testing"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/google/uuid"
@@ -45,6 +46,10 @@ func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+func (m *MockUserRepository) LockForUpdate(ctx context.Context, ids ...uuid.UUID) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
 func (m *MockUserRepository) GetNextPendingUser(ctx context.Context) (*domain.User, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -52,6 +57,21 @@ func (m *MockUserRepository) GetNextPendingUser(ctx context.Context) (*domain.Us
 	}
 	return args.Get(0).(*domain.User), args.Error(1)
 }
+func (m *MockUserRepository) ClaimStalledRegistrations(ctx context.Context, idleSince time.Time, limit int) ([]*domain.User, error) {
+	args := m.Called(ctx, idleSince, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) ClaimPendingStartPayload(ctx context.Context, id uuid.UUID) (*string, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*string), args.Error(1)
+}
 
 // MockCommandHandler
 type MockCommandHandler struct {
@@ -102,6 +122,10 @@ func (m *MockBotClient) EditMessageCaption(ctx context.Context, params ports.Edi
 	args := m.Called(ctx, params)
 	return args.Error(0)
 }
+func (m *MockBotClient) DeleteMessage(ctx context.Context, chatID int64, messageID int) error {
+	args := m.Called(ctx, chatID, messageID)
+	return args.Error(0)
+}
 func (m *MockBotClient) AnswerCallbackQuery(ctx context.Context, params ports.AnswerCallbackParams) error {
 	args := m.Called(ctx, params)
 	return args.Error(0)
@@ -110,6 +134,13 @@ func (m *MockBotClient) SendPhoto(ctx context.Context, params ports.SendPhotoPar
 	args := m.Called(ctx, params)
 	return args.Int(0), args.Error(1)
 }
+func (m *MockBotClient) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	args := m.Called(ctx, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
 
 // MockEventBus
 type MockEventBus struct {
@@ -121,6 +152,10 @@ func (m *MockEventBus) Publish(ctx context.Context, topic string, data interface
 	args := m.Called(ctx, topic, data)
 	return args.Error(0)
 }
+func (m *MockEventBus) PublishKeyed(ctx context.Context, topic, key string, data interface{}) error {
+	args := m.Called(ctx, topic, key, data)
+	return args.Error(0)
+}
 func (m *MockEventBus) Subscribe(topic string, handler ports.EventHandler) {
 	m.Called(topic, handler)
 	if m.Handlers == nil {
@@ -128,6 +163,13 @@ func (m *MockEventBus) Subscribe(topic string, handler ports.EventHandler) {
 	}
 	m.Handlers[topic] = handler // Store the handler so we can call it
 }
+func (m *MockEventBus) SubscribeWithRetry(topic, name string, handler ports.EventHandler, policy ports.RetryPolicy) {
+	m.Called(topic, name, handler, policy)
+	if m.Handlers == nil {
+		m.Handlers = make(map[string]ports.EventHandler)
+	}
+	m.Handlers[topic] = handler
+}
 
 // --- Tests ---
 
@@ -174,7 +216,7 @@ func TestModeratorRouter_HandleUpdate_Command(t *testing.T) {
 	// 5. Run the handler
 	// We simulate the event bus calling the router's handler
 	handler := mockBus.Handlers["telegram:mod:message"]
-	err := handler(ctx, ports.Event{Topic: "telegram:mod:message", Data: fakeUpdate})
+	err := handler(ctx, ports.Event{Topic: TopicMessage, Data: MessageReceived{Update: fakeUpdate}})
 	if err != nil {
 		t.Fatalf("Handler returned an error: %v", err)
 	}
@@ -227,7 +269,7 @@ func TestModeratorRouter_CallbackRouting(t *testing.T) {
 
 	// 6. Run the handler
 	handler := mockBus.Handlers["telegram:mod:callback_query"]
-	err := handler(ctx, ports.Event{Topic: "telegram:mod:callback_query", Data: fakeUpdate})
+	err := handler(ctx, ports.Event{Topic: TopicCallbackQuery, Data: CallbackQueryReceived{Update: fakeUpdate}})
 	if err != nil {
 		t.Fatalf("Handler returned an error: %v", err)
 	}
@@ -1,6 +1,7 @@
 package moderator
 
 import (
+	"AsaExchange/internal/core/events"
 	"AsaExchange/internal/core/ports"
 	"AsaExchange/internal/shared/config"
 	"context"
@@ -153,12 +154,20 @@ func (s *ModeratorServer) startWebhook(ctx context.Context) error {
 }
 
 // publishUpdateToBus inspects the update and publishes it to the correct topic.
+// Updates sharing a partition key are handled in the order Telegram sent them;
+// the bus blocks here when its workers are saturated.
 func (s *ModeratorServer) publishUpdateToBus(ctx context.Context, update tgbotapi.Update) {
+	var event events.Event
 	if update.ChannelPost != nil {
-		s.bus.Publish(ctx, "telegram:mod:channel_post", update)
+		event = ChannelPostReceived{Update: update}
 	} else if update.Message != nil {
-		s.bus.Publish(ctx, "telegram:mod:message", update)
+		event = MessageReceived{Update: update}
 	} else if update.CallbackQuery != nil {
-		s.bus.Publish(ctx, "telegram:mod:callback_query", update)
+		event = CallbackQueryReceived{Update: update}
+	} else {
+		return
+	}
+	if err := events.PublishKeyed(ctx, s.bus, partitionKey(update), event); err != nil {
+		s.log.Error().Err(err).Int("update_id", update.UpdateID).Msg("Failed to publish update")
 	}
 }
@@ -0,0 +1,61 @@
+package moderator
+
+import (
+	"AsaExchange/internal/core/events"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Topics of the raw Moderator Bot updates. ModeratorServer publishes them,
+// the router and the channel queues subscribe. They are not durable: an
+// update is only worth handling while it is fresh.
+const (
+	TopicMessage       = "telegram:mod:message"
+	TopicCallbackQuery = "telegram:mod:callback_query"
+	TopicChannelPost   = "telegram:mod:channel_post"
+)
+
+var (
+	_ events.Event = MessageReceived{}
+	_ events.Event = CallbackQueryReceived{}
+	_ events.Event = ChannelPostReceived{}
+)
+
+// MessageReceived carries an update with a Message.
+type MessageReceived struct{ Update tgbotapi.Update }
+
+func (MessageReceived) Topic() string { return TopicMessage }
+func (MessageReceived) Version() int  { return 1 }
+
+// CallbackQueryReceived carries an update with a CallbackQuery.
+type CallbackQueryReceived struct{ Update tgbotapi.Update }
+
+func (CallbackQueryReceived) Topic() string { return TopicCallbackQuery }
+func (CallbackQueryReceived) Version() int  { return 1 }
+
+// ChannelPostReceived carries an update with a ChannelPost.
+type ChannelPostReceived struct{ Update tgbotapi.Update }
+
+func (ChannelPostReceived) Topic() string { return TopicChannelPost }
+func (ChannelPostReceived) Version() int  { return 1 }
+
+// partitionKey orders the updates that must not overtake each other: clicks
+// on the same card, so two moderators can't decide one case at once, and
+// everything else from the same sender or channel.
+func partitionKey(update tgbotapi.Update) string {
+	switch {
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil:
+		msg := update.CallbackQuery.Message
+		return fmt.Sprintf("card:%d:%d", msg.Chat.ID, msg.MessageID)
+	case update.CallbackQuery != nil:
+		return fmt.Sprintf("user:%d", update.CallbackQuery.From.ID)
+	case update.ChannelPost != nil:
+		return fmt.Sprintf("chat:%d", update.ChannelPost.Chat.ID)
+	case update.Message != nil && update.Message.From != nil:
+		return fmt.Sprintf("user:%d", update.Message.From.ID)
+	case update.Message != nil:
+		return fmt.Sprintf("chat:%d", update.Message.Chat.ID)
+	}
+	return ""
+}
@@ -0,0 +1,28 @@
+package moderator
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestPartitionKey(t *testing.T) {
+	card := &tgbotapi.Message{MessageID: 7, Chat: &tgbotapi.Chat{ID: -100}}
+	cases := []struct {
+		name   string
+		update tgbotapi.Update
+		want   string
+	}{
+		{"clicks on a card share its key", tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{From: &tgbotapi.User{ID: 1}, Message: card}}, "card:-100:7"},
+		{"messages are keyed by sender", tgbotapi.Update{Message: &tgbotapi.Message{From: &tgbotapi.User{ID: 1}, Chat: &tgbotapi.Chat{ID: 1}}}, "user:1"},
+		{"channel posts are keyed by channel", tgbotapi.Update{ChannelPost: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: -200}}}, "chat:-200"},
+		{"other updates are not ordered", tgbotapi.Update{}, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := partitionKey(c.update); got != c.want {
+				t.Errorf("partitionKey = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BidStatus is a custom type for our bid_status ENUM
+type BidStatus string
+
+const (
+	BidStatusPending   BidStatus = "pending"
+	BidStatusAccepted  BidStatus = "accepted"
+	BidStatusRejected  BidStatus = "rejected"
+	BidStatusCancelled BidStatus = "cancelled"
+)
+
+// Bid is a user's offer to take an open Request.
+type Bid struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	RequestID uuid.UUID
+	Status    BidStatus
+	Notes     *string // Nullable
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrUnknownCurrency is returned for a currency code that is not in the registry.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// ErrPairNotAllowed is returned when two currencies may not be traded against each other.
+var ErrPairNotAllowed = errors.New("currency pair is not allowed")
+
+var currencyCodeRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+const defaultCurrencyDecimals int32 = 2
+
+// Exchange rates are stored as NUMERIC(19, 8): 8 decimal places and 11 digits before the point.
+const (
+	rateDecimals      int32 = 8
+	rateIntegerDigits int32 = 11
+)
+
+// maxRate is the smallest rate with too many integer digits to be stored.
+var maxRate = NewDecimal(1, -rateIntegerDigits)
+
+// Currency is one supported currency and its trading rules.
+type Currency struct {
+	Code      string // ISO 4217 code, e.g. 'EUR'
+	Name      string // Display name, e.g. 'Euro'
+	Decimals  int32  // Decimal places amounts are settled in
+	MinAmount Decimal
+	MaxAmount Decimal // Zero means no maximum
+}
+
+// CurrencyPair is a market: offers trade Base and are priced in Quote.
+type CurrencyPair struct {
+	Base  string
+	Quote string
+}
+
+// AmountError is returned when an amount breaks the rules of its currency.
+// Message is safe to show to the user.
+type AmountError struct {
+	Money   Money
+	Message string
+}
+
+func (e *AmountError) Error() string {
+	return fmt.Sprintf("invalid amount %s: %s", e.Money, e.Message)
+}
+
+// RateError is returned when an exchange rate cannot be stored.
+// Message is safe to show to the user.
+type RateError struct {
+	Rate    Decimal
+	Message string
+}
+
+func (e *RateError) Error() string {
+	return fmt.Sprintf("invalid rate %s: %s", e.Rate, e.Message)
+}
+
+// CurrencyRegistry is the list of currencies and pairs the exchange supports.
+// It is built once from the config and read-only afterwards.
+type CurrencyRegistry struct {
+	currencies []Currency // In config order, which is the order keyboards show
+	byCode     map[string]Currency
+	pairs      []CurrencyPair
+}
+
+// NewCurrencyRegistry validates the currencies and pairs and builds a registry.
+func NewCurrencyRegistry(currencies []Currency, pairs []CurrencyPair) (*CurrencyRegistry, error) {
+	if len(currencies) == 0 {
+		return nil, errors.New("no currencies defined")
+	}
+	if len(pairs) == 0 {
+		return nil, errors.New("no currency pairs defined")
+	}
+
+	r := &CurrencyRegistry{byCode: make(map[string]Currency, len(currencies))}
+	for _, c := range currencies {
+		if !currencyCodeRegex.MatchString(c.Code) {
+			return nil, fmt.Errorf("currency code %q must be 3 upper-case letters", c.Code)
+		}
+		if _, dup := r.byCode[c.Code]; dup {
+			return nil, fmt.Errorf("currency %s is defined twice", c.Code)
+		}
+		if c.Name == "" {
+			return nil, fmt.Errorf("currency %s has no name", c.Code)
+		}
+		if c.Decimals < 0 || c.Decimals > 8 {
+			return nil, fmt.Errorf("currency %s: decimals must be between 0 and 8", c.Code)
+		}
+		if c.MinAmount.Sign() < 0 || c.MaxAmount.Sign() < 0 {
+			return nil, fmt.Errorf("currency %s: trade limits must not be negative", c.Code)
+		}
+		if !c.MaxAmount.IsZero() && c.MaxAmount.Cmp(c.MinAmount) < 0 {
+			return nil, fmt.Errorf("currency %s: max_amount is below min_amount", c.Code)
+		}
+		r.currencies = append(r.currencies, c)
+		r.byCode[c.Code] = c
+	}
+
+	seen := make(map[CurrencyPair]bool, len(pairs))
+	for _, p := range pairs {
+		for _, code := range []string{p.Base, p.Quote} {
+			if _, ok := r.byCode[code]; !ok {
+				return nil, fmt.Errorf("pair %s/%s: %w %q", p.Base, p.Quote, ErrUnknownCurrency, code)
+			}
+		}
+		if p.Base == p.Quote {
+			return nil, fmt.Errorf("pair %s/%s trades a currency against itself", p.Base, p.Quote)
+		}
+		if seen[p] {
+			return nil, fmt.Errorf("pair %s/%s is defined twice", p.Base, p.Quote)
+		}
+		seen[p] = true
+		r.pairs = append(r.pairs, p)
+	}
+	return r, nil
+}
+
+// Get returns the currency with the given code.
+func (r *CurrencyRegistry) Get(code string) (Currency, bool) {
+	c, ok := r.byCode[code]
+	return c, ok
+}
+
+// Decimals returns the number of decimal places amounts in a currency are
+// rounded to. Unknown currencies use defaultCurrencyDecimals.
+func (r *CurrencyRegistry) Decimals(code string) int32 {
+	if c, ok := r.byCode[code]; ok {
+		return c.Decimals
+	}
+	return defaultCurrencyDecimals
+}
+
+// Money returns amount in currency, rounded to the currency's decimal places.
+func (r *CurrencyRegistry) Money(amount Decimal, currency string) Money {
+	return Money{Amount: amount.Round(r.Decimals(currency)), Currency: currency}
+}
+
+// Currencies returns every supported currency in config order.
+func (r *CurrencyRegistry) Currencies() []Currency {
+	return append([]Currency(nil), r.currencies...)
+}
+
+// BaseCurrencies returns the currencies that are the base of at least one pair.
+func (r *CurrencyRegistry) BaseCurrencies() []Currency {
+	var out []Currency
+	for _, c := range r.currencies {
+		if len(r.QuoteCurrencies(c.Code)) > 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// QuoteCurrencies returns the currencies the given base may be priced in.
+func (r *CurrencyRegistry) QuoteCurrencies(base string) []Currency {
+	var out []Currency
+	for _, p := range r.pairs {
+		if p.Base == base {
+			out = append(out, r.byCode[p.Quote])
+		}
+	}
+	return out
+}
+
+// ValidatePair returns ErrUnknownCurrency or ErrPairNotAllowed unless base/quote is a listed pair.
+func (r *CurrencyRegistry) ValidatePair(base, quote string) error {
+	for _, code := range []string{base, quote} {
+		if _, ok := r.byCode[code]; !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+		}
+	}
+	for _, p := range r.pairs {
+		if p.Base == base && p.Quote == quote {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s/%s", ErrPairNotAllowed, base, quote)
+}
+
+// ValidateAmount checks an amount against the decimals and trade limits of its currency.
+// It returns *AmountError if a rule is broken.
+func (r *CurrencyRegistry) ValidateAmount(m Money) error {
+	c, ok := r.byCode[m.Currency]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownCurrency, m.Currency)
+	}
+	if !m.Amount.Round(c.Decimals).Equal(m.Amount) {
+		if c.Decimals == 0 {
+			return &AmountError{Money: m, Message: fmt.Sprintf("%s amounts must be whole numbers.", c.Code)}
+		}
+		return &AmountError{Money: m, Message: fmt.Sprintf("%s amounts can have at most %d decimal places.", c.Code, c.Decimals)}
+	}
+	if m.Amount.Cmp(c.MinAmount) < 0 {
+		return &AmountError{Money: m, Message: fmt.Sprintf("The minimum is %s %s.", c.MinAmount, c.Code)}
+	}
+	if !c.MaxAmount.IsZero() && m.Amount.Cmp(c.MaxAmount) > 0 {
+		return &AmountError{Money: m, Message: fmt.Sprintf("The maximum is %s %s.", c.MaxAmount, c.Code)}
+	}
+	return nil
+}
+
+// ValidateRate checks that an exchange rate fits the precision it is stored with.
+// It returns *RateError if it does not.
+func (r *CurrencyRegistry) ValidateRate(rate Decimal) error {
+	if !rate.Round(rateDecimals).Equal(rate) {
+		return &RateError{Rate: rate, Message: fmt.Sprintf("A rate can have at most %d decimal places.", rateDecimals)}
+	}
+	if rate.Cmp(maxRate) >= 0 {
+		return &RateError{Rate: rate, Message: fmt.Sprintf("A rate can have at most %d digits before the decimal point.", rateIntegerDigits)}
+	}
+	return nil
+}
+
+// ValidateRequest checks the pair of a request, its rate, its base amount and its quote total.
+func (r *CurrencyRegistry) ValidateRequest(req *Request) error {
+	if err := r.ValidatePair(req.BaseCurrency, req.QuoteCurrency); err != nil {
+		return err
+	}
+	if err := r.ValidateRate(req.ExchangeRate); err != nil {
+		return err
+	}
+	if err := r.ValidateAmount(Money{Amount: req.BaseAmount, Currency: req.BaseCurrency}); err != nil {
+		return err
+	}
+	return r.ValidateAmount(req.QuoteAmount(r))
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func testRegistry(t *testing.T) *CurrencyRegistry {
+	t.Helper()
+	r, err := NewCurrencyRegistry(
+		[]Currency{
+			{Code: "EUR", Name: "Euro", Decimals: 2, MinAmount: MustParseDecimal("50"), MaxAmount: MustParseDecimal("20000")},
+			{Code: "IRR", Name: "Iranian Rial", Decimals: 0, MinAmount: MustParseDecimal("10000000")},
+			{Code: "USD", Name: "US Dollar", Decimals: 2},
+		},
+		[]CurrencyPair{{Base: "EUR", Quote: "IRR"}, {Base: "EUR", Quote: "USD"}},
+	)
+	if err != nil {
+		t.Fatalf("NewCurrencyRegistry returned an error: %v", err)
+	}
+	return r
+}
+
+func TestNewCurrencyRegistry_RejectsBadConfig(t *testing.T) {
+	eur := Currency{Code: "EUR", Name: "Euro", Decimals: 2}
+	irr := Currency{Code: "IRR", Name: "Iranian Rial"}
+	cases := []struct {
+		name       string
+		currencies []Currency
+		pairs      []CurrencyPair
+	}{
+		{"unknown currency in pair", []Currency{eur}, []CurrencyPair{{Base: "EUR", Quote: "IRR"}}},
+		{"pair against itself", []Currency{eur}, []CurrencyPair{{Base: "EUR", Quote: "EUR"}}},
+		{"duplicate currency", []Currency{eur, eur, irr}, []CurrencyPair{{Base: "EUR", Quote: "IRR"}}},
+		{"lower-case code", []Currency{{Code: "eur", Name: "Euro"}, irr}, []CurrencyPair{{Base: "eur", Quote: "IRR"}}},
+		{"max below min", []Currency{{Code: "EUR", Name: "Euro", MinAmount: NewDecimalFromInt(10), MaxAmount: NewDecimalFromInt(5)}, irr}, []CurrencyPair{{Base: "EUR", Quote: "IRR"}}},
+		{"no pairs", []Currency{eur, irr}, nil},
+	}
+	for _, c := range cases {
+		if _, err := NewCurrencyRegistry(c.currencies, c.pairs); err == nil {
+			t.Errorf("%s: expected an error", c.name)
+		}
+	}
+}
+
+func TestCurrencyRegistry_Pairs(t *testing.T) {
+	r := testRegistry(t)
+
+	if bases := r.BaseCurrencies(); len(bases) != 1 || bases[0].Code != "EUR" {
+		t.Errorf("BaseCurrencies = %v, want only EUR", bases)
+	}
+	if quotes := r.QuoteCurrencies("EUR"); len(quotes) != 2 || quotes[0].Code != "IRR" || quotes[1].Code != "USD" {
+		t.Errorf("QuoteCurrencies(EUR) = %v, want IRR, USD in config order", quotes)
+	}
+	if err := r.ValidatePair("EUR", "IRR"); err != nil {
+		t.Errorf("EUR/IRR should be allowed: %v", err)
+	}
+	if err := r.ValidatePair("IRR", "EUR"); !errors.Is(err, ErrPairNotAllowed) {
+		t.Errorf("Expected ErrPairNotAllowed for IRR/EUR, got: %v", err)
+	}
+	if err := r.ValidatePair("EUR", "GBP"); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("Expected ErrUnknownCurrency for GBP, got: %v", err)
+	}
+}
+
+func TestCurrencyRegistry_ValidateAmount(t *testing.T) {
+	r := testRegistry(t)
+	cases := []struct {
+		amount, currency string
+		ok               bool
+	}{
+		{"50", "EUR", true},
+		{"20000", "EUR", true},
+		{"49.99", "EUR", false},    // Below the minimum
+		{"20000.01", "EUR", false}, // Above the maximum
+		{"100.005", "EUR", false},  // Too many decimals
+		{"15000000000", "IRR", true},
+		{"15000000.5", "IRR", false}, // The Rial has no decimals
+		{"0.01", "USD", true},        // No limits configured
+	}
+	for _, c := range cases {
+		err := r.ValidateAmount(Money{Amount: MustParseDecimal(c.amount), Currency: c.currency})
+		if c.ok && err != nil {
+			t.Errorf("%s %s: unexpected error %v", c.amount, c.currency, err)
+		}
+		var amountErr *AmountError
+		if !c.ok && !errors.As(err, &amountErr) {
+			t.Errorf("%s %s: expected *AmountError, got %v", c.amount, c.currency, err)
+		}
+	}
+}
+
+func TestCurrencyRegistry_ValidateRate(t *testing.T) {
+	r := testRegistry(t)
+	cases := []struct {
+		rate string
+		ok   bool
+	}{
+		{"615000.5", true},
+		{"0.00000001", true},
+		{"99999999999.99999999", true},
+		{"0.000000015", false},  // Too many decimals
+		{"100000000000", false}, // Too many integer digits
+		{"123456789012.5", false},
+	}
+	for _, c := range cases {
+		err := r.ValidateRate(MustParseDecimal(c.rate))
+		if c.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", c.rate, err)
+		}
+		var rateErr *RateError
+		if !c.ok && !errors.As(err, &rateErr) {
+			t.Errorf("%s: expected *RateError, got %v", c.rate, err)
+		}
+	}
+}
+
+func TestCurrencyRegistry_ValidateRequest_ChecksQuoteTotal(t *testing.T) {
+	r := testRegistry(t)
+	req := &Request{
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "IRR",
+		BaseAmount:    MustParseDecimal("100"),
+		ExchangeRate:  MustParseDecimal("60000"), // 6,000,000 IRR, below the Rial minimum
+	}
+	var amountErr *AmountError
+	if err := r.ValidateRequest(req); !errors.As(err, &amountErr) || amountErr.Money.Currency != "IRR" {
+		t.Errorf("Expected an IRR AmountError, got %v", err)
+	}
+
+	req.ExchangeRate = MustParseDecimal("615000")
+	if err := r.ValidateRequest(req); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
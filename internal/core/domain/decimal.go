@@ -0,0 +1,284 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidDecimal is returned when a string is not a decimal number.
+var ErrInvalidDecimal = errors.New("invalid decimal number")
+
+// Decimal is an exact base-10 number, used for every amount and rate.
+// float64 cannot hold Rial amounts in the billions with cents on the other leg,
+// so nothing that touches money may go through a float.
+//
+// The value is coef * 10^-scale. The zero value is 0 and ready to use.
+// Decimals are immutable: every operation returns a new value.
+type Decimal struct {
+	coef  *big.Int // nil means 0
+	scale int32    // Number of digits after the decimal point, never negative
+}
+
+var bigTen = big.NewInt(10)
+
+// NewDecimal returns coef * 10^-scale, e.g. NewDecimal(150, 2) is 1.50.
+func NewDecimal(coef int64, scale int32) Decimal {
+	if scale < 0 {
+		c := new(big.Int).Mul(big.NewInt(coef), pow10(-scale))
+		return Decimal{coef: c}
+	}
+	return Decimal{coef: big.NewInt(coef), scale: scale}
+}
+
+// NewDecimalFromInt returns the whole number n.
+func NewDecimalFromInt(n int64) Decimal {
+	return NewDecimal(n, 0)
+}
+
+// ParseDecimal parses the canonical form written by String and by Postgres:
+// an optional '-', ASCII digits and at most one '.'.
+// Use ParseAmount for text typed by users.
+func ParseDecimal(s string) (Decimal, error) {
+	if s == "" {
+		return Decimal{}, ErrInvalidDecimal
+	}
+	neg := false
+	if s[0] == '-' || s[0] == '+' {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	intPart, fracPart, hasPoint := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return Decimal{}, ErrInvalidDecimal
+	}
+	if hasPoint && fracPart == "" {
+		return Decimal{}, ErrInvalidDecimal
+	}
+	digits := intPart + fracPart
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return Decimal{}, ErrInvalidDecimal
+		}
+	}
+	coef, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, ErrInvalidDecimal
+	}
+	if neg {
+		coef.Neg(coef)
+	}
+	return Decimal{coef: coef, scale: int32(len(fracPart))}, nil
+}
+
+// MustParseDecimal is ParseDecimal for constants. It panics on invalid input.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(fmt.Sprintf("domain: MustParseDecimal(%q): %v", s, err))
+	}
+	return d
+}
+
+// amountReplacer turns what users type on Persian and Arabic keyboards into
+// the canonical form. Commas become ',' so ParseAmount can check their
+// grouping, other grouping separators are dropped and the Arabic decimal
+// separator becomes '.'.
+var amountReplacer = strings.NewReplacer(
+	// Persian digits
+	"۰", "0", "۱", "1", "۲", "2", "۳", "3", "۴", "4",
+	"۵", "5", "۶", "6", "۷", "7", "۸", "8", "۹", "9",
+	// Arabic-Indic digits
+	"٠", "0", "١", "1", "٢", "2", "٣", "3", "٤", "4",
+	"٥", "5", "٦", "6", "٧", "7", "٨", "8", "٩", "9",
+	// Separators
+	"٫", ".", // Arabic decimal separator
+	"٬", ",", // Arabic thousands separator
+	"،", ",", // Arabic comma
+	"_", "",
+	"'", "",
+	" ", "",
+	" ", "", // No-break space
+	" ", "", // Narrow no-break space
+)
+
+// groupedAmountRegex matches an amount whose commas separate groups of exactly
+// three digits before the decimal point.
+var groupedAmountRegex = regexp.MustCompile(`^[-+]?[0-9]{1,3}(,[0-9]{3})+(\.[0-9]+)?$`)
+
+// ParseAmount parses a number typed by a user, such as "1,500", "1 500.25"
+// or "۱۵۰۰٫۵". Commas are only accepted as thousands separators, so "1,5"
+// and "1.500,00" are rejected rather than read as the wrong amount.
+func ParseAmount(input string) (Decimal, error) {
+	s := amountReplacer.Replace(strings.TrimSpace(input))
+	if strings.Contains(s, ",") {
+		if !groupedAmountRegex.MatchString(s) {
+			return Decimal{}, ErrInvalidDecimal
+		}
+		s = strings.ReplaceAll(s, ",", "")
+	}
+	return ParseDecimal(s)
+}
+
+// pow10 returns 10^n as a new big.Int.
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// bigInt returns the coefficient, treating the zero value as 0.
+func (d Decimal) bigInt() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescale returns the coefficient of d at a scale of at least d.scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	c := d.bigInt()
+	if scale <= d.scale {
+		return new(big.Int).Set(c)
+	}
+	return new(big.Int).Mul(c, pow10(scale-d.scale))
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return Decimal{coef: new(big.Int).Add(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return Decimal{coef: new(big.Int).Sub(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Mul returns the exact product d * other. Round the result to the currency
+// it is in before showing or storing it.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.bigInt(), other.bigInt()), scale: d.scale + other.scale}
+}
+
+// Round returns d rounded half away from zero to the given number of decimal places.
+func (d Decimal) Round(places int32) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if d.scale <= places {
+		return d
+	}
+	divisor := pow10(d.scale - places)
+	q, r := new(big.Int).QuoRem(d.bigInt(), divisor, new(big.Int))
+	// Round up when the remainder is at least half the divisor
+	r.Abs(r).Mul(r, big.NewInt(2))
+	if r.Cmp(divisor) >= 0 {
+		if d.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return Decimal{coef: q, scale: places}
+}
+
+// Cmp compares d and other and returns -1, 0 or +1.
+func (d Decimal) Cmp(other Decimal) int {
+	scale := max(d.scale, other.scale)
+	return d.rescale(scale).Cmp(other.rescale(scale))
+}
+
+// Equal reports whether d and other are the same number, ignoring trailing zeros.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Sign returns -1, 0 or +1.
+func (d Decimal) Sign() int {
+	return d.bigInt().Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// IsPositive reports whether d is greater than 0.
+func (d Decimal) IsPositive() bool {
+	return d.Sign() > 0
+}
+
+// String renders d without exponent or trailing zeros, e.g. "1500.5".
+func (d Decimal) String() string {
+	s := d.StringFixed(d.scale)
+	if d.scale > 0 {
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimSuffix(s, ".")
+	}
+	return s
+}
+
+// StringFixed renders d rounded to exactly the given number of decimal places.
+func (d Decimal) StringFixed(places int32) string {
+	r := d.Round(places)
+	coef := r.rescale(places)
+	digits := new(big.Int).Abs(coef).String()
+	if places > 0 {
+		if pad := int(places) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(places)] + "." + digits[len(digits)-int(places):]
+	}
+	if coef.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalText implements encoding.TextMarshaler, so JSON carries the exact digits.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner. pgx hands NUMERIC columns over as text,
+// so no precision is lost on the way in.
+func (d *Decimal) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		*d = NewDecimalFromInt(v)
+		return nil
+	case nil:
+		return errors.New("cannot scan NULL into Decimal")
+	default:
+		return fmt.Errorf("cannot scan %T into Decimal", src)
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return fmt.Errorf("cannot scan %q into Decimal: %w", s, err)
+	}
+	*d = parsed
+	return nil
+}
+
+// Value implements driver.Valuer. The text form is exact, unlike a float.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
@@ -0,0 +1,139 @@
+package domain
+
+import "testing"
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1500", "1500", false},
+		{" 1,500.25 ", "1500.25", false},
+		{"1 500 000 000", "1500000000", false},
+		{"۱۵۰۰", "1500", false},      // Persian digits
+		{"١٬٥٠٠٫٥", "1500.5", false}, // Arabic-Indic digits and separators
+		{"۱۲۳۴۵۶۷۸۹۰۱۲", "123456789012", false},
+		{"0.00000001", "0.00000001", false},
+		{"-3", "-3", false},
+		{"", "", true},
+		{"1.", "", true},
+		{"1.2.3", "", true},
+		{"12abc", "", true},
+		{"1e5", "", true},
+		{"1,5", "", true},
+		{"1,50", "", true},
+		{"1.500,00", "", true},
+		{"1,500,00", "", true},
+		{",500", "", true},
+		{"1500,000", "", true},
+		{"1,500,000", "1500000", false},
+	}
+	for _, c := range cases {
+		got, err := ParseAmount(c.input)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("ParseAmount(%q) = %s, want an error", c.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseAmount(%q) returned an error: %v", c.input, err)
+			continue
+		}
+		if got.String() != c.want {
+			t.Errorf("ParseAmount(%q) = %s, want %s", c.input, got, c.want)
+		}
+	}
+}
+
+func TestDecimal_MulIsExact(t *testing.T) {
+	// 0.1 * 3 and billions of Rials are where floats go wrong
+	cases := []struct{ a, b, want string }{
+		{"0.1", "3", "0.3"},
+		{"1250.5", "615000", "769057500"},
+		{"99999999999.99999999", "1000000", "99999999999999999.99"},
+		{"-2.5", "0.4", "-1"},
+	}
+	for _, c := range cases {
+		got := MustParseDecimal(c.a).Mul(MustParseDecimal(c.b))
+		if got.String() != c.want {
+			t.Errorf("%s * %s = %s, want %s", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestDecimal_Round(t *testing.T) {
+	cases := []struct {
+		value  string
+		places int32
+		want   string
+	}{
+		{"1.005", 2, "1.01"},
+		{"1.004", 2, "1"},
+		{"2.5", 0, "3"},
+		{"-2.5", 0, "-3"},
+		{"1234.4999", 0, "1234"},
+		{"7", 2, "7"},
+	}
+	for _, c := range cases {
+		got := MustParseDecimal(c.value).Round(c.places)
+		if got.String() != c.want {
+			t.Errorf("Round(%s, %d) = %s, want %s", c.value, c.places, got, c.want)
+		}
+	}
+}
+
+func TestDecimal_StringFixed(t *testing.T) {
+	if got := MustParseDecimal("0.5").StringFixed(2); got != "0.50" {
+		t.Errorf("StringFixed = %s, want 0.50", got)
+	}
+	if got := MustParseDecimal("-0.004").StringFixed(2); got != "0.00" {
+		t.Errorf("StringFixed = %s, want 0.00", got)
+	}
+	if got := (Decimal{}).String(); got != "0" {
+		t.Errorf("Zero value = %s, want 0", got)
+	}
+}
+
+func TestDecimal_ScanValue(t *testing.T) {
+	in := MustParseDecimal("12345678901.12345678")
+	v, err := in.Value()
+	if err != nil {
+		t.Fatalf("Value returned an error: %v", err)
+	}
+
+	var out Decimal
+	if err := out.Scan(v); err != nil {
+		t.Fatalf("Scan returned an error: %v", err)
+	}
+	if !out.Equal(in) {
+		t.Errorf("Round trip mismatch: got %s, want %s", out, in)
+	}
+	if err := out.Scan(nil); err == nil {
+		t.Errorf("Scanning NULL should fail")
+	}
+}
+
+func TestRequest_QuoteAmount(t *testing.T) {
+	currencies := testRegistry(t)
+	req := &Request{
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "IRR",
+		BaseAmount:    MustParseDecimal("1250.55"),
+		ExchangeRate:  MustParseDecimal("615000.5"),
+	}
+	// 769,088,875.275 Rials, and the Rial has no minor unit
+	if got := req.QuoteAmount(currencies); got.Amount.String() != "769088875" || got.Currency != "IRR" {
+		t.Errorf("QuoteAmount = %s, want 769088875 IRR", got)
+	}
+
+	req.BaseCurrency, req.QuoteCurrency = "IRR", "EUR"
+	req.BaseAmount, req.ExchangeRate = MustParseDecimal("1000000"), MustParseDecimal("0.0000016")
+	if got := req.DepositFor(currencies, LegBuyer); got.Amount.String() != "1.6" {
+		t.Errorf("Buyer deposit = %s, want 1.6 EUR", got)
+	}
+	if got := req.PayoutFor(currencies, LegBuyer); got.Amount.String() != "1000000" || got.Currency != "IRR" {
+		t.Errorf("Buyer payout = %s, want 1000000 IRR", got)
+	}
+}
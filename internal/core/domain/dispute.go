@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DisputeStatus is a custom type for our dispute_status ENUM
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen     DisputeStatus = "open"     // Waiting for a moderator
+	DisputeStatusResolved DisputeStatus = "resolved" // A moderator decided the outcome
+)
+
+// DisputeResolution is how a moderator settled a dispute.
+type DisputeResolution string
+
+const (
+	DisputeResolutionRefund   DisputeResolution = "refund"   // Deposits are returned, the trade is cancelled
+	DisputeResolutionComplete DisputeResolution = "complete" // The trade is forced through
+	DisputeResolutionCancel   DisputeResolution = "cancel"   // The trade is cancelled without refunds
+)
+
+// Dispute is a complaint raised by one party of a transaction.
+// While it is open the transaction stays in TxStatusDisputed.
+type Dispute struct {
+	ID             uuid.UUID
+	TransactionID  uuid.UUID
+	OpenedByUserID uuid.UUID
+	Reason         string
+	Status         DisputeStatus
+
+	Resolution *DisputeResolution // Nullable, set once resolved
+	ResolvedBy *uuid.UUID         // Nullable, the moderator who resolved it
+	ResolvedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// DisputeEvidence is one statement and photo submitted by a party to a dispute.
+type DisputeEvidence struct {
+	ID        uuid.UUID
+	DisputeID uuid.UUID
+	UserID    uuid.UUID
+	Leg       TransactionLeg
+	Statement string
+	FileID    string // The Customer Bot's Telegram FileID of the photo
+	CreatedAt time.Time
+}
@@ -0,0 +1,69 @@
+package domain
+
+// FeeRule is one line of a fee schedule. Empty Base, Quote or Level match anything.
+// A leg is charged Percent plus half of SpreadPercent of its gross payout,
+// plus the Flat fee in the payout currency.
+type FeeRule struct {
+	Base          string                 `json:"base,omitempty"`
+	Quote         string                 `json:"quote,omitempty"`
+	Level         UserVerificationStatus `json:"level,omitempty"`
+	Percent       Decimal                `json:"percent"`
+	SpreadPercent Decimal                `json:"spread_percent"` // Split evenly between the two legs
+	Flat          map[string]Decimal     `json:"flat,omitempty"` // Keyed by currency code
+}
+
+// Matches reports whether the rule applies to the pair and the party's verification level.
+func (r FeeRule) Matches(base, quote string, level UserVerificationStatus) bool {
+	return (r.Base == "" || r.Base == base) &&
+		(r.Quote == "" || r.Quote == quote) &&
+		(r.Level == "" || r.Level == level)
+}
+
+// specificity ranks matching rules: a pair beats a level, both beat neither.
+func (r FeeRule) specificity() int {
+	n := 0
+	if r.Base != "" {
+		n += 2
+	}
+	if r.Quote != "" {
+		n += 2
+	}
+	if r.Level != "" {
+		n++
+	}
+	return n
+}
+
+// FeeSchedule is a versioned set of fee rules. Any change to the rules must
+// come with a new Version, so trades keep the schedule they were matched under.
+type FeeSchedule struct {
+	Version string    `json:"version"`
+	Default FeeRule   `json:"default"`
+	Rules   []FeeRule `json:"rules"`
+}
+
+// RuleFor returns the most specific rule for the pair and level, or the default.
+// Among equally specific rules the first one listed wins.
+func (s *FeeSchedule) RuleFor(base, quote string, level UserVerificationStatus) FeeRule {
+	best, bestScore := s.Default, -1
+	for _, r := range s.Rules {
+		if r.Matches(base, quote, level) && r.specificity() > bestScore {
+			best, bestScore = r, r.specificity()
+		}
+	}
+	return best
+}
+
+// Payout is what one party of a trade receives.
+type Payout struct {
+	Gross Money // What the trade is worth to the party
+	Fee   Money // What the platform keeps
+	Net   Money // What is actually paid out
+}
+
+// FeeQuote is the outcome of pricing a trade against a fee schedule.
+type FeeQuote struct {
+	ScheduleVersion string
+	Seller          Payout
+	Buyer           Payout
+}
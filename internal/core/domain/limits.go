@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CurrencyLimits caps how much of one currency a user may trade.
+// Amounts are counted in the base currency of each trade. Zero means no limit.
+type CurrencyLimits struct {
+	PerTransaction Decimal
+	Daily          Decimal // Rolling 24 hours
+	Monthly        Decimal // Rolling 30 days
+}
+
+// TradingLimits are the limits of one verification level.
+type TradingLimits struct {
+	Level           UserVerificationStatus
+	MaxOpenRequests int                       // Zero means no limit
+	Currencies      map[string]CurrencyLimits // Currencies not listed are not limited
+}
+
+// LimitError is returned when an action would break a trading limit.
+// Message is safe to show to the user.
+type LimitError struct {
+	Level   UserVerificationStatus
+	Message string
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("trading limit of %s reached: %s", e.Level, e.Message)
+}
+
+// LimitTable holds the trading limits of every verification level that may trade.
+// It is built once from the config and read-only afterwards.
+type LimitTable struct {
+	byLevel map[UserVerificationStatus]TradingLimits
+}
+
+// NewLimitTable validates the tiers and builds a limit table.
+func NewLimitTable(tiers []TradingLimits) (*LimitTable, error) {
+	if len(tiers) == 0 {
+		return nil, errors.New("no trading limits defined")
+	}
+	t := &LimitTable{byLevel: make(map[UserVerificationStatus]TradingLimits, len(tiers))}
+	for _, tier := range tiers {
+		if !tier.Level.IsVerified() {
+			return nil, fmt.Errorf("level %q is not a verification level", tier.Level)
+		}
+		if _, dup := t.byLevel[tier.Level]; dup {
+			return nil, fmt.Errorf("level %s is defined twice", tier.Level)
+		}
+		if tier.MaxOpenRequests < 0 {
+			return nil, fmt.Errorf("level %s: max_open_requests must not be negative", tier.Level)
+		}
+		for code, l := range tier.Currencies {
+			if l.PerTransaction.Sign() < 0 || l.Daily.Sign() < 0 || l.Monthly.Sign() < 0 {
+				return nil, fmt.Errorf("level %s: %s limits must not be negative", tier.Level, code)
+			}
+		}
+		t.byLevel[tier.Level] = tier
+	}
+	return t, nil
+}
+
+// For returns the limits of a level. ok is false if the level may not trade.
+func (t *LimitTable) For(level UserVerificationStatus) (TradingLimits, bool) {
+	l, ok := t.byLevel[level]
+	return l, ok
+}
+
+// CanTrade reports whether users of the level may trade at all.
+func (t *LimitTable) CanTrade(level UserVerificationStatus) bool {
+	_, ok := t.byLevel[level]
+	return ok
+}
+
+// Levels returns the levels that may trade.
+func (t *LimitTable) Levels() []UserVerificationStatus {
+	out := make([]UserVerificationStatus, 0, len(t.byLevel))
+	for level := range t.byLevel {
+		out = append(out, level)
+	}
+	return out
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "testing"
+
+func TestNewLimitTable_RejectsBadConfig(t *testing.T) {
+	cases := []struct {
+		name  string
+		tiers []TradingLimits
+	}{
+		{"no tiers", nil},
+		{"unverified level", []TradingLimits{{Level: VerificationPending}}},
+		{"duplicate level", []TradingLimits{{Level: VerificationLevel1}, {Level: VerificationLevel1}}},
+		{"negative open requests", []TradingLimits{{Level: VerificationLevel1, MaxOpenRequests: -1}}},
+		{"negative limit", []TradingLimits{{Level: VerificationLevel1, Currencies: map[string]CurrencyLimits{
+			"EUR": {Daily: NewDecimalFromInt(-1)},
+		}}}},
+	}
+	for _, c := range cases {
+		if _, err := NewLimitTable(c.tiers); err == nil {
+			t.Errorf("%s: expected an error", c.name)
+		}
+	}
+}
+
+func TestLimitTable_CanTrade(t *testing.T) {
+	table, err := NewLimitTable([]TradingLimits{{Level: VerificationLevel1}})
+	if err != nil {
+		t.Fatalf("NewLimitTable returned an error: %v", err)
+	}
+	if !table.CanTrade(VerificationLevel1) {
+		t.Error("level_1 should be able to trade")
+	}
+	// Verified, but without limits configured
+	if table.CanTrade("level_2") {
+		t.Error("level_2 should not be able to trade")
+	}
+	if table.CanTrade(VerificationPending) {
+		t.Error("pending users should not be able to trade")
+	}
+}
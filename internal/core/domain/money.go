@@ -0,0 +1,12 @@
+package domain
+
+// Money is an amount in a currency.
+type Money struct {
+	Amount   Decimal
+	Currency string // ISO code, e.g. 'EUR'
+}
+
+// String renders the money as "<amount> <currency>", e.g. "1500.5 EUR".
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlatformAccount is one of our company's receiving bank accounts.
+type PlatformAccount struct {
+	ID                   uuid.UUID
+	AccountName          string // e.g., "Primary Rial - Mellat"
+	Currency             string
+	BankName             string
+	AccountDetails       string // User-facing info (IBAN, card number)
+	VerificationStrategy string // e.g., 'manual', 'bank_api_mellat'
+	IsActive             bool
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// VerificationStrategyManual means moderators check by hand: deposits into a
+// platform account, or the documents of a new user.
+const VerificationStrategyManual = "manual"
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestType is a custom type for our request_type ENUM
+type RequestType string
+
+const (
+	RequestTypeSell RequestType = "sell"
+	RequestTypeBuy  RequestType = "buy"
+)
+
+// RequestStatus is a custom type for our request_status ENUM
+type RequestStatus string
+
+const (
+	RequestStatusOpen      RequestStatus = "open"
+	RequestStatusMatched   RequestStatus = "matched" // A bid was accepted
+	RequestStatusCompleted RequestStatus = "completed"
+	RequestStatusCancelled RequestStatus = "cancelled"
+)
+
+// Request is a marketplace offer (the "ad") created by a user.
+type Request struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	ChannelMessageID *int64 // Nullable, set once posted to the public channel
+	Type             RequestType
+	BaseCurrency     string // e.g., 'EUR'
+	QuoteCurrency    string // e.g., 'IRR'
+	BaseAmount       Decimal
+	ExchangeRate     Decimal // Units of QuoteCurrency per 1 BaseCurrency
+	Status           RequestStatus
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// QuoteAmount returns the total in the quote currency, BaseAmount * ExchangeRate,
+// rounded to the quote currency's decimal places.
+func (r *Request) QuoteAmount(currencies *CurrencyRegistry) Money {
+	return currencies.Money(r.BaseAmount.Mul(r.ExchangeRate), r.QuoteCurrency)
+}
+
+// DepositFor returns what the given party of a trade on this request has to pay in:
+// the seller deposits the base amount, the buyer the quote total.
+func (r *Request) DepositFor(currencies *CurrencyRegistry, leg TransactionLeg) Money {
+	if leg == LegSeller {
+		return Money{Amount: r.BaseAmount, Currency: r.BaseCurrency}
+	}
+	return r.QuoteAmount(currencies)
+}
+
+// PayoutFor returns what the given party of a trade on this request receives:
+// the seller gets the quote total, the buyer the base amount.
+func (r *Request) PayoutFor(currencies *CurrencyRegistry, leg TransactionLeg) Money {
+	if leg == LegSeller {
+		return r.QuoteAmount(currencies)
+	}
+	return Money{Amount: r.BaseAmount, Currency: r.BaseCurrency}
+}
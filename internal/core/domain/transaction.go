@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransactionStatus is a custom type for our transaction_status ENUM
+type TransactionStatus string
+
+const (
+	TxStatusPendingDeposits       TransactionStatus = "pending_deposits"        // Initial state
+	TxStatusSellerDepositReceived TransactionStatus = "seller_deposit_received" // Seller sent money to us
+	TxStatusBuyerDepositReceived  TransactionStatus = "buyer_deposit_received"  // Buyer sent money to us
+	TxStatusPendingPayouts        TransactionStatus = "pending_payouts"         // We received both
+	TxStatusSellerPayoutSent      TransactionStatus = "seller_payout_sent"      // We paid the seller
+	TxStatusBuyerPayoutSent       TransactionStatus = "buyer_payout_sent"       // We paid the buyer
+	TxStatusCompleted             TransactionStatus = "completed"               // Both payouts sent
+	TxStatusDisputed              TransactionStatus = "disputed"                // An issue was raised
+	TxStatusCancelled             TransactionStatus = "cancelled"               // Transaction was cancelled
+)
+
+// TransactionLeg identifies one party's side of a transaction.
+type TransactionLeg string
+
+const (
+	LegSeller TransactionLeg = "seller" // Deposits the base currency
+	LegBuyer  TransactionLeg = "buyer"  // Deposits the quote currency
+)
+
+// Transaction is the fulfillment ledger entry for a matched request/bid pair.
+type Transaction struct {
+	ID           uuid.UUID
+	RequestID    uuid.UUID
+	BidID        uuid.UUID
+	SellerUserID uuid.UUID
+	BuyerUserID  uuid.UUID
+	ModeratorID  *uuid.UUID // Nullable, the mod who handled it
+	Status       TransactionStatus
+
+	// Deposit Leg (Users paying IN to us)
+	PlatformDepositBaseAccountID  *uuid.UUID
+	PlatformDepositQuoteAccountID *uuid.UUID
+
+	// Payout Leg (Us paying OUT to users)
+	SellerPayoutAccountID *uuid.UUID
+	BuyerPayoutAccountID  *uuid.UUID
+
+	// Fees, fixed when the trade is matched. The seller is paid in the quote
+	// currency, the buyer in the base currency.
+	FeeScheduleVersion *string // Nullable, trades matched before fees existed have none
+	SellerFee          Decimal
+	SellerNet          Decimal
+	BuyerFee           Decimal
+	BuyerNet           Decimal
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// DepositAccountFor returns the platform account the given leg deposits into.
+// The seller pays the base currency, the buyer the quote currency.
+func (t *Transaction) DepositAccountFor(leg TransactionLeg) *uuid.UUID {
+	if leg == LegSeller {
+		return t.PlatformDepositBaseAccountID
+	}
+	return t.PlatformDepositQuoteAccountID
+}
+
+// ApplyFees stores a fee quote on the transaction.
+func (t *Transaction) ApplyFees(q *FeeQuote) {
+	version := q.ScheduleVersion
+	t.FeeScheduleVersion = &version
+	t.SellerFee, t.SellerNet = q.Seller.Fee.Amount, q.Seller.Net.Amount
+	t.BuyerFee, t.BuyerNet = q.Buyer.Fee.Amount, q.Buyer.Net.Amount
+}
+
+// PayoutFor returns what the given party receives, combining the gross payout
+// of the request with the fee fixed on this transaction.
+func (t *Transaction) PayoutFor(currencies *CurrencyRegistry, req *Request, leg TransactionLeg) Payout {
+	gross := req.PayoutFor(currencies, leg)
+	if t.FeeScheduleVersion == nil {
+		// Matched before fees existed
+		return Payout{Gross: gross, Fee: Money{Currency: gross.Currency}, Net: gross}
+	}
+	fee, net := t.BuyerFee, t.BuyerNet
+	if leg == LegSeller {
+		fee, net = t.SellerFee, t.SellerNet
+	}
+	return Payout{
+		Gross: gross,
+		Fee:   Money{Amount: fee, Currency: gross.Currency},
+		Net:   Money{Amount: net, Currency: gross.Currency},
+	}
+}
+
+// TransactionTransition is one entry in a transaction's status history.
+type TransactionTransition struct {
+	ID            uuid.UUID
+	TransactionID uuid.UUID
+	From          TransactionStatus
+	To            TransactionStatus
+	ModeratorID   *uuid.UUID // Nullable, set when a moderator made the move
+	CreatedAt     time.Time
+}
+
+// PayoutProof is the photo a moderator uploaded as proof of one payout.
+// It is stored once, so events only carry its ID.
+type PayoutProof struct {
+	ID            uuid.UUID
+	TransactionID uuid.UUID
+	Leg           TransactionLeg
+	Photo         []byte // JPEG bytes, downloaded from the Moderator Bot
+	CreatedAt     time.Time
+}
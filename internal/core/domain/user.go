@@ -12,9 +12,16 @@ type UserVerificationStatus string
 const (
 	VerificationPending  UserVerificationStatus = "pending"
 	VerificationLevel1   UserVerificationStatus = "level_1"
+	VerificationLevel2   UserVerificationStatus = "level_2" // level_1 plus proof of address and a selfie
 	VerificationRejected UserVerificationStatus = "rejected"
 )
 
+// IsVerified reports whether the status is an approved verification level.
+// Which levels may trade, and how much, is decided by the LimitTable.
+func (s UserVerificationStatus) IsVerified() bool {
+	return s != "" && s != VerificationPending && s != VerificationRejected
+}
+
 // UserState is a custom type for our state machine ENUM
 type UserState string
 
@@ -27,8 +34,54 @@ const (
 	StateAwaitingLocation       UserState = "awaiting_location"
 	StateAwaitingIdentityDoc    UserState = "awaiting_identity_doc"
 	StateAwaitingPolicyApproval UserState = "awaiting_policy_approval"
+
+	// --- /newrequest flow ---
+	StateAwaitingRequestBaseCurrency  UserState = "awaiting_request_base_currency"
+	StateAwaitingRequestQuoteCurrency UserState = "awaiting_request_quote_currency"
+	StateAwaitingRequestAmount        UserState = "awaiting_request_amount"
+	StateAwaitingRequestRate          UserState = "awaiting_request_rate"
+	StateAwaitingRequestConfirmation  UserState = "awaiting_request_confirmation"
+
+	// --- bid flow ---
+	StateAwaitingBidNote UserState = "awaiting_bid_note"
+
+	// --- deposit flow ---
+	StateAwaitingDepositReceipt UserState = "awaiting_deposit_receipt"
+
+	// --- /myaccounts flow ---
+	StateAwaitingAccountName     UserState = "awaiting_account_name"
+	StateAwaitingAccountCurrency UserState = "awaiting_account_currency"
+	StateAwaitingAccountBank     UserState = "awaiting_account_bank"
+	StateAwaitingAccountDetails  UserState = "awaiting_account_details"
+	StateAwaitingAccountRename   UserState = "awaiting_account_rename"
+
+	// --- moderator payout flow ---
+	StateAwaitingPayoutProof UserState = "awaiting_payout_proof"
+
+	// --- dispute flow ---
+	StateAwaitingDisputeReason   UserState = "awaiting_dispute_reason"
+	StateAwaitingDisputeEvidence UserState = "awaiting_dispute_evidence"
+
+	// --- /upgrade flow ---
+	StateAwaitingAddressProof UserState = "awaiting_address_proof"
+	StateAwaitingSelfie       UserState = "awaiting_selfie"
+
+	// --- verification strategy questions ---
+	StateAwaitingVerificationField UserState = "awaiting_verification_field"
 )
 
+// RegistrationStates are the steps of the sign-up flow, in order.
+var RegistrationStates = []UserState{
+	StateAwaitingFirstName,
+	StateAwaitingLastName,
+	StateAwaitingPhoneNumber,
+	StateAwaitingGovID,
+	StateAwaitingLocation,
+	StateAwaitingIdentityDoc,
+	StateAwaitingPolicyApproval,
+	StateAwaitingVerificationField,
+}
+
 // User represents a user in the system.
 type User struct {
 	ID                   uuid.UUID
@@ -40,9 +93,20 @@ type User struct {
 	LocationCountry      *string // Nullable
 	VerificationStatus   UserVerificationStatus
 	State                UserState
-	VerificationStrategy *string // Nullable
-	IdentityDocRef       *string // Nullable
+	StateData            map[string]string // Scratchpad for the current multi-step flow
+	VerificationStrategy *string           // Nullable
+	IdentityDocRef       *string           // Nullable
 	IsModerator          bool
+	ReferredByUserID     *uuid.UUID // Nullable, set from a ref_<code> start link
+	PendingStartPayload  *string    // Nullable, a deep link to replay after approval
+	AddressDocRef        *string    // Nullable, level_2 proof of address
+	SelfieDocRef         *string    // Nullable, level_2 selfie holding the ID
+	UpgradeRequestedAt   *time.Time // Nullable, set while a level_2 upgrade awaits review
 	CreatedAt            time.Time
 	UpdatedAt            time.Time
 }
+
+// CanRequestUpgrade reports whether the user may apply for level_2.
+func (u *User) CanRequestUpgrade() bool {
+	return u.VerificationStatus == VerificationLevel1 && u.UpgradeRequestedAt == nil
+}
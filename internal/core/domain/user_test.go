@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUser_CanRequestUpgrade(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		user User
+		want bool
+	}{
+		{"level 1", User{VerificationStatus: VerificationLevel1}, true},
+		{"level 1 under review", User{VerificationStatus: VerificationLevel1, UpgradeRequestedAt: &now}, false},
+		{"already level 2", User{VerificationStatus: VerificationLevel2}, false},
+		{"pending", User{VerificationStatus: VerificationPending}, false},
+		{"rejected", User{VerificationStatus: VerificationRejected}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.user.CanRequestUpgrade(); got != tt.want {
+			t.Errorf("%s: CanRequestUpgrade() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"AsaExchange/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+// Bid topics.
+const (
+	TopicBidCreated   = "bid:created"
+	TopicBidAccepted  = "bid:accepted"
+	TopicBidRejected  = "bid:rejected"
+	TopicBidCancelled = "bid:cancelled"
+	TopicBidWithdrawn = "bid:withdrawn"
+)
+
+func init() {
+	register[BidCreated]()
+	register[BidAccepted]()
+	register[BidRejected]()
+	register[BidCancelled]()
+	register[BidWithdrawn]()
+}
+
+// BidRef identifies the bid an event is about.
+type BidRef struct {
+	BidID     uuid.UUID
+	BidderID  uuid.UUID
+	RequestID uuid.UUID
+}
+
+// NewBidRef takes the reference of a bid.
+func NewBidRef(bid *domain.Bid) BidRef {
+	return BidRef{BidID: bid.ID, BidderID: bid.UserID, RequestID: bid.RequestID}
+}
+
+// BidCreated is published when a user bids on a request.
+type BidCreated struct {
+	BidRef
+	Notes string // Empty if the bidder left no note
+}
+
+// NewBidCreated snapshots a new bid.
+func NewBidCreated(bid *domain.Bid) BidCreated {
+	event := BidCreated{BidRef: NewBidRef(bid)}
+	if bid.Notes != nil {
+		event.Notes = *bid.Notes
+	}
+	return event
+}
+
+func (BidCreated) Topic() string { return TopicBidCreated }
+func (BidCreated) Version() int  { return 1 }
+
+// BidAccepted is published when the request owner accepts a bid.
+type BidAccepted struct{ BidRef }
+
+func (BidAccepted) Topic() string { return TopicBidAccepted }
+func (BidAccepted) Version() int  { return 1 }
+
+// BidRejected is published when the request owner rejects a bid, or accepts another one.
+type BidRejected struct{ BidRef }
+
+func (BidRejected) Topic() string { return TopicBidRejected }
+func (BidRejected) Version() int  { return 1 }
+
+// BidCancelled is published when a bid is cancelled because its request closed.
+type BidCancelled struct{ BidRef }
+
+func (BidCancelled) Topic() string { return TopicBidCancelled }
+func (BidCancelled) Version() int  { return 1 }
+
+// BidWithdrawn is published when a bidder takes their bid back.
+type BidWithdrawn struct{ BidRef }
+
+func (BidWithdrawn) Topic() string { return TopicBidWithdrawn }
+func (BidWithdrawn) Version() int  { return 1 }
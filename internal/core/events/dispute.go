@@ -0,0 +1,36 @@
+package events
+
+import "AsaExchange/internal/core/domain"
+
+// Dispute topics.
+const (
+	TopicDisputeOpened   = "dispute:opened"
+	TopicDisputeResolved = "dispute:resolved"
+)
+
+func init() {
+	register[DisputeOpened]()
+	register[DisputeResolved]()
+}
+
+// SnapshotDispute copies a dispute for an event.
+func SnapshotDispute(dispute *domain.Dispute) domain.Dispute {
+	snapshot := *dispute
+	snapshot.Resolution = clonePtr(dispute.Resolution)
+	snapshot.ResolvedBy = clonePtr(dispute.ResolvedBy)
+	snapshot.ResolvedAt = clonePtr(dispute.ResolvedAt)
+	return snapshot
+}
+
+// DisputeOpened is published when a party disputes a trade.
+type DisputeOpened struct{ Dispute domain.Dispute }
+
+func (DisputeOpened) Topic() string { return TopicDisputeOpened }
+func (DisputeOpened) Version() int  { return 1 }
+
+// DisputeResolved is published when a moderator decides a dispute.
+// Dispute.Resolution is always set.
+type DisputeResolved struct{ Dispute domain.Dispute }
+
+func (DisputeResolved) Topic() string { return TopicDisputeResolved }
+func (DisputeResolved) Version() int  { return 1 }
@@ -0,0 +1,131 @@
+// Package events defines the typed events published on the ports.EventBus.
+//
+// Every event type owns one topic and one schema version. Payloads are value
+// snapshots taken when the event is created, so neither the publisher nor
+// another subscriber can change what a handler sees. Subscribe with the
+// generic helpers: the handler's parameter type picks the topic, so a
+// mismatched payload is a compile error instead of a failed type assertion.
+package events
+
+import (
+	"AsaExchange/internal/core/ports"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrPayloadMismatch is returned to the bus when a topic carries a payload
+// other than its event type, which only happens with raw ports.EventBus.Publish.
+var ErrPayloadMismatch = errors.New("event payload does not match its topic")
+
+// Event is a payload of the event bus.
+type Event interface {
+	// Topic is the bus topic of the event type.
+	Topic() string
+	// Version is the schema version of the payload. Bump it when a field is
+	// removed or changes meaning, so stored events of the old shape are
+	// refused instead of misread.
+	Version() int
+}
+
+// Handler handles one event type.
+type Handler[E Event] func(ctx context.Context, event E) error
+
+// Publish sends an event on its topic. A nil event, as returned for an
+// unknown status by RequestStatusChanged and TransactionStatusChanged, is an error.
+func Publish(ctx context.Context, bus ports.EventBus, event Event) error {
+	if event == nil {
+		return errors.New("no event to publish")
+	}
+	return bus.Publish(ctx, event.Topic(), event)
+}
+
+// PublishKeyed sends an event on its topic. Events sharing key are handled
+// one at a time, in the order they were published.
+func PublishKeyed(ctx context.Context, bus ports.EventBus, key string, event Event) error {
+	if event == nil {
+		return errors.New("no event to publish")
+	}
+	return bus.PublishKeyed(ctx, event.Topic(), key, event)
+}
+
+// Subscribe registers handler for the topic of E.
+func Subscribe[E Event](bus ports.EventBus, handler Handler[E]) {
+	bus.Subscribe(TopicOf[E](), adapt(handler))
+}
+
+// SubscribeWithRetry registers handler for the topic of E, retrying it under
+// policy and dead-lettering the event under name once the attempts run out.
+func SubscribeWithRetry[E Event](bus ports.EventBus, name string, handler Handler[E], policy ports.RetryPolicy) {
+	bus.SubscribeWithRetry(TopicOf[E](), name, adapt(handler), policy)
+}
+
+// TopicOf returns the topic of event type E.
+func TopicOf[E Event]() string {
+	var zero E
+	return zero.Topic()
+}
+
+// adapt turns a typed handler into a ports.EventHandler.
+func adapt[E Event](handler Handler[E]) ports.EventHandler {
+	return func(ctx context.Context, event ports.Event) error {
+		typed, ok := event.Data.(E)
+		if !ok {
+			return fmt.Errorf("%w: %s carries %T, want %T", ErrPayloadMismatch, event.Topic, event.Data, typed)
+		}
+		return handler(ctx, typed)
+	}
+}
+
+// durable maps the topics of events that may be stored, e.g. in an outbox,
+// to their decoder. Events not listed here only live in memory.
+var durable = map[string]durableType{}
+
+type durableType struct {
+	version int
+	decode  func(data []byte) (Event, error)
+}
+
+// register makes E durable.
+func register[E Event]() {
+	var zero E
+	durable[zero.Topic()] = durableType{
+		version: zero.Version(),
+		decode: func(data []byte) (Event, error) {
+			var event E
+			if err := json.Unmarshal(data, &event); err != nil {
+				return nil, err
+			}
+			return event, nil
+		},
+	}
+}
+
+// IsDurable reports whether events on topic can be stored and decoded again.
+func IsDurable(topic string) bool {
+	_, ok := durable[topic]
+	return ok
+}
+
+// Decode restores a stored event from the JSON of its payload. Payloads
+// written under another schema version are refused.
+func Decode(topic string, version int, data []byte) (Event, error) {
+	t, ok := durable[topic]
+	if !ok {
+		return nil, fmt.Errorf("topic %q has no durable event type", topic)
+	}
+	if version != t.version {
+		return nil, fmt.Errorf("%s event has schema version %d, this build reads %d", topic, version, t.version)
+	}
+	return t.decode(data)
+}
+
+// clonePtr copies the value behind p, so a snapshot shares no memory with its source.
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
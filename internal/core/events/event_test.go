@@ -0,0 +1,148 @@
+package events
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// syncBus is a ports.EventBus that runs handlers synchronously.
+type syncBus struct {
+	handlers map[string][]ports.EventHandler
+	policies map[string]ports.RetryPolicy
+}
+
+func newSyncBus() *syncBus {
+	return &syncBus{handlers: map[string][]ports.EventHandler{}, policies: map[string]ports.RetryPolicy{}}
+}
+
+func (b *syncBus) Publish(ctx context.Context, topic string, data interface{}) error {
+	for _, handler := range b.handlers[topic] {
+		if err := handler(ctx, ports.Event{Topic: topic, Data: data}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *syncBus) PublishKeyed(ctx context.Context, topic, key string, data interface{}) error {
+	return b.Publish(ctx, topic, data)
+}
+
+func (b *syncBus) Subscribe(topic string, handler ports.EventHandler) {
+	b.handlers[topic] = append(b.handlers[topic], handler)
+}
+
+func (b *syncBus) SubscribeWithRetry(topic, name string, handler ports.EventHandler, policy ports.RetryPolicy) {
+	b.policies[name] = policy
+	b.Subscribe(topic, handler)
+}
+
+func TestSubscribe_DeliversTypedEvent(t *testing.T) {
+	bus := newSyncBus()
+	var got BidAccepted
+	Subscribe(bus, func(ctx context.Context, event BidAccepted) error {
+		got = event
+		return nil
+	})
+
+	want := BidAccepted{BidRef{BidID: uuid.New(), BidderID: uuid.New(), RequestID: uuid.New()}}
+	if err := Publish(t.Context(), bus, want); err != nil {
+		t.Fatalf("Publish returned an error: %v", err)
+	}
+	if got != want {
+		t.Errorf("Handler got %+v, want %+v", got, want)
+	}
+	if len(bus.handlers[TopicBidAccepted]) != 1 {
+		t.Errorf("Handler not registered on %s: %v", TopicBidAccepted, bus.handlers)
+	}
+}
+
+func TestSubscribeWithRetry_PassesNameAndPolicy(t *testing.T) {
+	bus := newSyncBus()
+	SubscribeWithRetry(bus, "notification", func(ctx context.Context, event UserApproved) error { return nil }, ports.DefaultRetryPolicy)
+
+	if bus.policies["notification"] != ports.DefaultRetryPolicy || len(bus.handlers[TopicUserApproved]) != 1 {
+		t.Errorf("Subscription mismatch: %v %v", bus.policies, bus.handlers)
+	}
+}
+
+func TestSubscribe_RejectsMismatchedPayload(t *testing.T) {
+	bus := newSyncBus()
+	called := false
+	Subscribe(bus, func(ctx context.Context, event UserApproved) error {
+		called = true
+		return nil
+	})
+
+	// Only a raw publish can put the wrong type on a topic
+	err := bus.Publish(t.Context(), TopicUserApproved, &domain.User{})
+	if !errors.Is(err, ErrPayloadMismatch) {
+		t.Errorf("Expected ErrPayloadMismatch, got: %v", err)
+	}
+	if called {
+		t.Error("Handler was called with a mismatched payload")
+	}
+}
+
+func TestPublish_RejectsNilEvent(t *testing.T) {
+	req := &domain.Request{Status: "unknown"}
+	if err := Publish(t.Context(), newSyncBus(), RequestStatusChanged(req)); err == nil {
+		t.Error("Expected an error for a status without an event")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	want := TransactionWithdrawn{
+		Transaction: domain.Transaction{ID: uuid.New(), Status: domain.TxStatusCancelled},
+		UserID:      uuid.New(),
+	}
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	got, err := Decode(want.Topic(), want.Version(), data)
+	if err != nil {
+		t.Fatalf("Decode returned an error: %v", err)
+	}
+	withdrawn, ok := got.(TransactionWithdrawn)
+	if !ok {
+		t.Fatalf("Decode returned %T, want TransactionWithdrawn", got)
+	}
+	if withdrawn.Transaction.ID != want.Transaction.ID || withdrawn.UserID != want.UserID {
+		t.Errorf("Decoded %+v, want %+v", withdrawn, want)
+	}
+
+	if _, err := Decode(want.Topic(), want.Version()+1, data); err == nil {
+		t.Error("Expected an error for another schema version")
+	}
+	if _, err := Decode("telegram:moderator:message", 1, data); err == nil {
+		t.Error("Expected an error for a topic without a durable event")
+	}
+}
+
+func TestStatusChanged_PicksTopic(t *testing.T) {
+	if got := RequestStatusChanged(&domain.Request{Status: domain.RequestStatusOpen}).Topic(); got != "request:open" {
+		t.Errorf("Reopened request topic = %s", got)
+	}
+	if got := TransactionStatusChanged(&domain.Transaction{Status: domain.TxStatusPendingPayouts}).Topic(); got != "transaction:pending_payouts" {
+		t.Errorf("Payouts due topic = %s", got)
+	}
+}
+
+func TestSnapshots_ShareNoMemory(t *testing.T) {
+	msgID := int64(7)
+	req := &domain.Request{ID: uuid.New(), ChannelMessageID: &msgID}
+	event := RequestCreated{Request: SnapshotRequest(req)}
+	*req.ChannelMessageID = 8
+	req.Status = domain.RequestStatusCancelled
+	if *event.Request.ChannelMessageID != 7 || event.Request.Status == domain.RequestStatusCancelled {
+		t.Errorf("Snapshot changed with its source: %+v", event.Request)
+	}
+}
@@ -0,0 +1,84 @@
+package events
+
+import "AsaExchange/internal/core/domain"
+
+// Request topics. The status topics are "request:<status>". Request events
+// are published keyed by request ID, so they are handled in order.
+const (
+	TopicRequestCreated   = "request:created"
+	TopicRequestExpired   = "request:expired"
+	TopicRequestReopened  = "request:" + string(domain.RequestStatusOpen)
+	TopicRequestMatched   = "request:" + string(domain.RequestStatusMatched)
+	TopicRequestCompleted = "request:" + string(domain.RequestStatusCompleted)
+	TopicRequestCancelled = "request:" + string(domain.RequestStatusCancelled)
+)
+
+func init() {
+	register[RequestCreated]()
+	register[RequestExpired]()
+	register[RequestReopened]()
+	register[RequestMatched]()
+	register[RequestCompleted]()
+	register[RequestCancelled]()
+}
+
+// SnapshotRequest copies a request for an event.
+func SnapshotRequest(req *domain.Request) domain.Request {
+	snapshot := *req
+	snapshot.ChannelMessageID = clonePtr(req.ChannelMessageID)
+	return snapshot
+}
+
+// RequestStatusChanged returns the event announcing the current status of req.
+func RequestStatusChanged(req *domain.Request) Event {
+	snapshot := SnapshotRequest(req)
+	switch req.Status {
+	case domain.RequestStatusOpen:
+		return RequestReopened{Request: snapshot}
+	case domain.RequestStatusMatched:
+		return RequestMatched{Request: snapshot}
+	case domain.RequestStatusCompleted:
+		return RequestCompleted{Request: snapshot}
+	case domain.RequestStatusCancelled:
+		return RequestCancelled{Request: snapshot}
+	}
+	return nil
+}
+
+// RequestCreated is published when a new offer enters the order book.
+type RequestCreated struct{ Request domain.Request }
+
+func (RequestCreated) Topic() string { return TopicRequestCreated }
+func (RequestCreated) Version() int  { return 1 }
+
+// RequestExpired is published when an offer timed out without a match.
+// A RequestCancelled follows it.
+type RequestExpired struct{ Request domain.Request }
+
+func (RequestExpired) Topic() string { return TopicRequestExpired }
+func (RequestExpired) Version() int  { return 1 }
+
+// RequestReopened is published when a request goes back to open, because its
+// trade was cancelled before any deposit.
+type RequestReopened struct{ Request domain.Request }
+
+func (RequestReopened) Topic() string { return TopicRequestReopened }
+func (RequestReopened) Version() int  { return 1 }
+
+// RequestMatched is published when a bid on the request is accepted.
+type RequestMatched struct{ Request domain.Request }
+
+func (RequestMatched) Topic() string { return TopicRequestMatched }
+func (RequestMatched) Version() int  { return 1 }
+
+// RequestCompleted is published when the trade of the request completes.
+type RequestCompleted struct{ Request domain.Request }
+
+func (RequestCompleted) Topic() string { return TopicRequestCompleted }
+func (RequestCompleted) Version() int  { return 1 }
+
+// RequestCancelled is published when a request is closed without a trade.
+type RequestCancelled struct{ Request domain.Request }
+
+func (RequestCancelled) Topic() string { return TopicRequestCancelled }
+func (RequestCancelled) Version() int  { return 1 }
@@ -0,0 +1,173 @@
+package events
+
+import (
+	"AsaExchange/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+// Transaction topics. The status topics are "transaction:<status>".
+const (
+	TopicTransactionCreated   = "transaction:created"
+	TopicTransactionWithdrawn = "transaction:withdrawn"
+	TopicDepositRejected      = "deposit:rejected"
+	TopicPayoutProofSent      = "payout:sent"
+
+	TopicDepositsPending       = "transaction:" + string(domain.TxStatusPendingDeposits)
+	TopicSellerDepositReceived = "transaction:" + string(domain.TxStatusSellerDepositReceived)
+	TopicBuyerDepositReceived  = "transaction:" + string(domain.TxStatusBuyerDepositReceived)
+	TopicPayoutsDue            = "transaction:" + string(domain.TxStatusPendingPayouts)
+	TopicSellerPayoutSent      = "transaction:" + string(domain.TxStatusSellerPayoutSent)
+	TopicBuyerPayoutSent       = "transaction:" + string(domain.TxStatusBuyerPayoutSent)
+	TopicTransactionCompleted  = "transaction:" + string(domain.TxStatusCompleted)
+	TopicTransactionDisputed   = "transaction:" + string(domain.TxStatusDisputed)
+	TopicTransactionCancelled  = "transaction:" + string(domain.TxStatusCancelled)
+)
+
+func init() {
+	register[TransactionCreated]()
+	register[TransactionWithdrawn]()
+	register[DepositRejected]()
+	register[PayoutProofSent]()
+	register[DepositsPending]()
+	register[SellerDepositReceived]()
+	register[BuyerDepositReceived]()
+	register[PayoutsDue]()
+	register[SellerPayoutSent]()
+	register[BuyerPayoutSent]()
+	register[TransactionCompleted]()
+	register[TransactionDisputed]()
+	register[TransactionCancelled]()
+}
+
+// SnapshotTransaction copies a transaction for an event.
+func SnapshotTransaction(tx *domain.Transaction) domain.Transaction {
+	snapshot := *tx
+	snapshot.ModeratorID = clonePtr(tx.ModeratorID)
+	snapshot.PlatformDepositBaseAccountID = clonePtr(tx.PlatformDepositBaseAccountID)
+	snapshot.PlatformDepositQuoteAccountID = clonePtr(tx.PlatformDepositQuoteAccountID)
+	snapshot.SellerPayoutAccountID = clonePtr(tx.SellerPayoutAccountID)
+	snapshot.BuyerPayoutAccountID = clonePtr(tx.BuyerPayoutAccountID)
+	snapshot.FeeScheduleVersion = clonePtr(tx.FeeScheduleVersion)
+	return snapshot
+}
+
+// TransactionStatusChanged returns the event announcing the current status of tx.
+func TransactionStatusChanged(tx *domain.Transaction) Event {
+	snapshot := SnapshotTransaction(tx)
+	switch tx.Status {
+	case domain.TxStatusPendingDeposits:
+		return DepositsPending{Transaction: snapshot}
+	case domain.TxStatusSellerDepositReceived:
+		return SellerDepositReceived{Transaction: snapshot}
+	case domain.TxStatusBuyerDepositReceived:
+		return BuyerDepositReceived{Transaction: snapshot}
+	case domain.TxStatusPendingPayouts:
+		return PayoutsDue{Transaction: snapshot}
+	case domain.TxStatusSellerPayoutSent:
+		return SellerPayoutSent{Transaction: snapshot}
+	case domain.TxStatusBuyerPayoutSent:
+		return BuyerPayoutSent{Transaction: snapshot}
+	case domain.TxStatusCompleted:
+		return TransactionCompleted{Transaction: snapshot}
+	case domain.TxStatusDisputed:
+		return TransactionDisputed{Transaction: snapshot}
+	case domain.TxStatusCancelled:
+		return TransactionCancelled{Transaction: snapshot}
+	}
+	return nil
+}
+
+// TransactionCreated is published when an accepted bid opens a trade.
+type TransactionCreated struct{ Transaction domain.Transaction }
+
+func (TransactionCreated) Topic() string { return TopicTransactionCreated }
+func (TransactionCreated) Version() int  { return 1 }
+
+// TransactionWithdrawn is published when a party cancels a trade before any
+// money arrived. A TransactionCancelled precedes it.
+type TransactionWithdrawn struct {
+	Transaction domain.Transaction
+	UserID      uuid.UUID // The party who cancelled
+}
+
+func (TransactionWithdrawn) Topic() string { return TopicTransactionWithdrawn }
+func (TransactionWithdrawn) Version() int  { return 1 }
+
+// DepositRejected is published when a moderator rejects a deposit receipt.
+type DepositRejected struct {
+	TransactionID uuid.UUID
+	UserID        uuid.UUID
+	Leg           domain.TransactionLeg
+	Reason        string
+}
+
+func (DepositRejected) Topic() string { return TopicDepositRejected }
+func (DepositRejected) Version() int  { return 1 }
+
+// PayoutProofSent is published when a moderator uploads the proof of a payout.
+// The photo is kept in the ports.PayoutProofRepository; the event only
+// points at it, so no image bytes end up in the outbox or dead letters.
+type PayoutProofSent struct {
+	TransactionID uuid.UUID
+	UserID        uuid.UUID // The recipient
+	Leg           domain.TransactionLeg
+	ProofID       uuid.UUID
+}
+
+func (PayoutProofSent) Topic() string { return TopicPayoutProofSent }
+func (PayoutProofSent) Version() int  { return 2 }
+
+// DepositsPending is published when a trade waits for deposits again.
+type DepositsPending struct{ Transaction domain.Transaction }
+
+func (DepositsPending) Topic() string { return TopicDepositsPending }
+func (DepositsPending) Version() int  { return 1 }
+
+// SellerDepositReceived is published when the seller's deposit is confirmed first.
+type SellerDepositReceived struct{ Transaction domain.Transaction }
+
+func (SellerDepositReceived) Topic() string { return TopicSellerDepositReceived }
+func (SellerDepositReceived) Version() int  { return 1 }
+
+// BuyerDepositReceived is published when the buyer's deposit is confirmed first.
+type BuyerDepositReceived struct{ Transaction domain.Transaction }
+
+func (BuyerDepositReceived) Topic() string { return TopicBuyerDepositReceived }
+func (BuyerDepositReceived) Version() int  { return 1 }
+
+// PayoutsDue is published when both deposits are confirmed.
+type PayoutsDue struct{ Transaction domain.Transaction }
+
+func (PayoutsDue) Topic() string { return TopicPayoutsDue }
+func (PayoutsDue) Version() int  { return 1 }
+
+// SellerPayoutSent is published when the seller was paid first.
+type SellerPayoutSent struct{ Transaction domain.Transaction }
+
+func (SellerPayoutSent) Topic() string { return TopicSellerPayoutSent }
+func (SellerPayoutSent) Version() int  { return 1 }
+
+// BuyerPayoutSent is published when the buyer was paid first.
+type BuyerPayoutSent struct{ Transaction domain.Transaction }
+
+func (BuyerPayoutSent) Topic() string { return TopicBuyerPayoutSent }
+func (BuyerPayoutSent) Version() int  { return 1 }
+
+// TransactionCompleted is published when both payouts are sent.
+type TransactionCompleted struct{ Transaction domain.Transaction }
+
+func (TransactionCompleted) Topic() string { return TopicTransactionCompleted }
+func (TransactionCompleted) Version() int  { return 1 }
+
+// TransactionDisputed is published when a party opens a dispute.
+type TransactionDisputed struct{ Transaction domain.Transaction }
+
+func (TransactionDisputed) Topic() string { return TopicTransactionDisputed }
+func (TransactionDisputed) Version() int  { return 1 }
+
+// TransactionCancelled is published when a trade is called off.
+type TransactionCancelled struct{ Transaction domain.Transaction }
+
+func (TransactionCancelled) Topic() string { return TopicTransactionCancelled }
+func (TransactionCancelled) Version() int  { return 1 }
@@ -0,0 +1,66 @@
+package events
+
+import (
+	"AsaExchange/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+// User topics.
+const (
+	TopicUserApproved        = "user:approved"
+	TopicUserRejected        = "user:rejected"
+	TopicUserUpgraded        = "user:upgraded"
+	TopicUpgradeRejected     = "user:upgrade_rejected"
+	TopicRegistrationStalled = "user:registration_stalled"
+)
+
+func init() {
+	register[UserApproved]()
+	register[UserRejected]()
+	register[UserUpgraded]()
+	register[UpgradeRejected]()
+	register[RegistrationStalled]()
+}
+
+// UserRef identifies the user an event is about. User events carry no other
+// personal data; subscribers that need more load the user.
+type UserRef struct {
+	UserID     uuid.UUID
+	TelegramID int64
+}
+
+// NewUserRef takes the reference of a user.
+func NewUserRef(user *domain.User) UserRef {
+	return UserRef{UserID: user.ID, TelegramID: user.TelegramID}
+}
+
+// UserApproved is published when a user passes verification and reaches level_1.
+type UserApproved struct{ UserRef }
+
+func (UserApproved) Topic() string { return TopicUserApproved }
+func (UserApproved) Version() int  { return 1 }
+
+// UserRejected is published when verification fails and the registration starts over.
+type UserRejected struct{ UserRef }
+
+func (UserRejected) Topic() string { return TopicUserRejected }
+func (UserRejected) Version() int  { return 1 }
+
+// UserUpgraded is published when a moderator approves a level_2 upgrade.
+type UserUpgraded struct{ UserRef }
+
+func (UserUpgraded) Topic() string { return TopicUserUpgraded }
+func (UserUpgraded) Version() int  { return 1 }
+
+// UpgradeRejected is published when a moderator rejects a level_2 upgrade.
+type UpgradeRejected struct{ UserRef }
+
+func (UpgradeRejected) Topic() string { return TopicUpgradeRejected }
+func (UpgradeRejected) Version() int  { return 1 }
+
+// RegistrationStalled is published when a user stopped halfway through registration.
+type RegistrationStalled struct{ UserRef }
+
+func (RegistrationStalled) Topic() string { return TopicRegistrationStalled }
+func (RegistrationStalled) Version() int  { return 1 }
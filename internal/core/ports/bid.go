@@ -0,0 +1,68 @@
+package ports
+
+import (
+	"AsaExchange/internal/core/domain"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrBidExists is returned when a user bids twice on the same request.
+	ErrBidExists = errors.New("user already placed a bid on this request")
+
+	// ErrBidNotPending is returned when accepting a bid that was already resolved.
+	ErrBidNotPending = errors.New("bid is no longer pending")
+
+	// ErrBidStatusChanged is returned when a bid left the expected status
+	// before the update reached it.
+	ErrBidStatusChanged = errors.New("bid status changed concurrently")
+
+	// ErrRequestNotOpen is returned when matching a request that is no longer open.
+	ErrRequestNotOpen = errors.New("request is no longer open")
+)
+
+// BidRepository defines the persistence operations for Bids.
+type BidRepository interface {
+	// Create saves a new bid to the database.
+	// It returns ErrBidExists if the user already bid on the request.
+	Create(ctx context.Context, bid *domain.Bid) error
+
+	// GetByID finds a bid by its UUID.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Bid, error)
+
+	// GetByRequestID finds all bids placed on a request, oldest first.
+	GetByRequestID(ctx context.Context, requestID uuid.UUID) ([]*domain.Bid, error)
+
+	// GetByUserID finds all bids placed by a user, newest first.
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Bid, error)
+
+	// Update saves the mutable fields (status, notes) of a bid.
+	Update(ctx context.Context, bid *domain.Bid) error
+
+	// TransitionStatus saves bid.Status only if the stored status is still 'from'.
+	// It returns ErrBidStatusChanged otherwise.
+	TransitionStatus(ctx context.Context, bid *domain.Bid, from domain.BidStatus) error
+
+	// CancelPending cancels a bid that is still pending.
+	// It returns ErrBidNotPending if the bid was resolved first.
+	CancelPending(ctx context.Context, bid *domain.Bid) error
+
+	// RejectPending rejects a bid that is still pending.
+	// It returns ErrBidNotPending if the bid was accepted or withdrawn first.
+	RejectPending(ctx context.Context, bid *domain.Bid) error
+
+	// CancelPendingByRequest cancels every pending bid on a request and returns them.
+	CancelPendingByRequest(ctx context.Context, requestID uuid.UUID) ([]*domain.Bid, error)
+
+	// CancelPendingOnClosedRequests cancels every pending bid whose request is
+	// no longer open and returns the cancelled bids.
+	CancelPendingOnClosedRequests(ctx context.Context) ([]*domain.Bid, error)
+
+	// Accept matches a pending bid with its open request in a single database transaction:
+	// the bid becomes accepted, every other pending bid on the request is rejected,
+	// the request becomes matched and tx is inserted.
+	// It returns the bids that were rejected along the way.
+	Accept(ctx context.Context, bidID uuid.UUID, tx *domain.Transaction) (rejected []*domain.Bid, err error)
+}
@@ -59,7 +59,7 @@ type AnswerCallbackParams struct {
 // SendPhotoParams holds options for sending a photo.
 type SendPhotoParams struct {
 	ChatID      int64
-	File        interface{} // FileID or FilePath
+	File        interface{} // FileID, FilePath or FileBytes
 	Caption     string
 	ParseMode   string
 	ReplyMarkup *ReplyMarkup // For inline keyboards
@@ -82,11 +82,17 @@ type BotClientPort interface {
 	SendMessage(ctx context.Context, params SendMessageParams) (messageID int, err error)
 	SetMenuCommands(ctx context.Context, chatID int64, isAdmin bool) error
 	// EditMessageText allows us to change the text of an existing message.
+	// Edits that leave the message as it was succeed, like the caption edit.
 	EditMessageText(ctx context.Context, params EditMessageParams) error
 	EditMessageCaption(ctx context.Context, params EditMessageCaptionParams) error
+	// DeleteMessage removes a message the bot sent.
+	DeleteMessage(ctx context.Context, chatID int64, messageID int) error
 
 	AnswerCallbackQuery(ctx context.Context, params AnswerCallbackParams) error
 	SendPhoto(ctx context.Context, params SendPhotoParams) (messageID int, err error)
+	// DownloadFile fetches a file this bot received.
+	// File IDs only work with the bot that received them, so this is how a file crosses bots.
+	DownloadFile(ctx context.Context, fileID string) ([]byte, error)
 }
 
 // --- Bot Handler Port (Inbound) ---
@@ -98,6 +104,7 @@ type BotUpdate struct {
 	UserID          int64
 	Text            string
 	Command         string
+	CommandArgs     string // Everything after the command, e.g. the /start payload
 	CallbackQueryID string
 	CallbackData    *string
 	Contact         *ContactInfo
@@ -126,3 +133,13 @@ type MessageHandler interface {
 	// Handle processes the message, using the user's state to route logic.
 	Handle(ctx context.Context, update *BotUpdate, user *domain.User) error
 }
+
+// StateHandler defines the "plugin" interface for a multi-step flow.
+// The router sends it every non-command message from a user
+// whose state is one of the states it owns.
+type StateHandler interface {
+	// States returns the user states this handler is responsible for.
+	States() []domain.UserState
+	// Handle processes the message for a user in one of those states.
+	Handle(ctx context.Context, update *BotUpdate, user *domain.User) error
+}
@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrDeadLetterNotFound is returned when replaying an unknown dead letter.
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+	// ErrDeadLetterReplayed is returned when replaying a dead letter twice.
+	ErrDeadLetterReplayed = errors.New("dead letter was already replayed")
+)
+
+// DeadLetter is an event one subscriber kept failing on.
+type DeadLetter struct {
+	ID         int64
+	Topic      string
+	Subscriber string // The name given to EventBus.SubscribeWithRetry
+	Payload    []byte // JSON of the event data
+	Error      string // The last handler error
+	Attempts   int
+	CreatedAt  time.Time
+	ReplayedAt *time.Time
+}
+
+// DeadLetterRepository stores events whose handlers ran out of retries.
+type DeadLetterRepository interface {
+	Add(ctx context.Context, letter *DeadLetter) error
+	GetByID(ctx context.Context, id int64) (*DeadLetter, error)
+
+	// ListPending returns the newest dead letters not replayed yet.
+	ListPending(ctx context.Context, limit int) ([]*DeadLetter, error)
+
+	// ClaimReplay marks a pending dead letter replayed before its subscriber
+	// runs, so two replays cannot both run it. It returns
+	// ErrDeadLetterReplayed if the letter was claimed already.
+	ClaimReplay(ctx context.Context, id int64) error
+
+	// ReleaseReplay makes a claimed dead letter pending again, after its
+	// replay failed.
+	ReleaseReplay(ctx context.Context, id int64) error
+}
+
+// DeadLetterReplayer hands a dead letter to its subscriber once more.
+type DeadLetterReplayer interface {
+	// Replay claims the letter and runs the subscriber on the stored event.
+	// A failure leaves the letter pending.
+	Replay(ctx context.Context, id int64) error
+}
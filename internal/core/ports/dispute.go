@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"AsaExchange/internal/core/domain"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrDisputeExists is returned when a transaction already has an open dispute.
+	ErrDisputeExists = errors.New("transaction already has an open dispute")
+
+	// ErrDisputeNotOpen is returned when resolving a dispute that was already resolved.
+	ErrDisputeNotOpen = errors.New("dispute is no longer open")
+)
+
+// DisputeRepository defines the persistence operations for Disputes.
+type DisputeRepository interface {
+	// Create saves a new dispute.
+	// It returns ErrDisputeExists if the transaction already has an open one.
+	Create(ctx context.Context, dispute *domain.Dispute) error
+
+	// GetByID finds a dispute by its UUID.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Dispute, error)
+
+	// GetOpenByTransactionID finds the open dispute of a transaction, if any.
+	GetOpenByTransactionID(ctx context.Context, transactionID uuid.UUID) (*domain.Dispute, error)
+
+	// Resolve records the outcome of an open dispute (Resolution, ResolvedBy).
+	// It returns ErrDisputeNotOpen if someone else resolved it first.
+	Resolve(ctx context.Context, dispute *domain.Dispute) error
+
+	// AddEvidence saves a party's statement and photo.
+	AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error
+
+	// GetEvidence returns all evidence of a dispute, oldest first.
+	GetEvidence(ctx context.Context, disputeID uuid.UUID) ([]*domain.DisputeEvidence, error)
+}
@@ -1,6 +1,13 @@
 package ports
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrBusClosed is returned by Publish once the event bus is draining.
+var ErrBusClosed = errors.New("event bus is shut down")
 
 // Event is a generic wrapper for any event payload
 type Event struct {
@@ -16,6 +23,60 @@ type EventBus interface {
 	// Publish sends an event to all subscribers of a topic
 	Publish(ctx context.Context, topic string, data interface{}) error
 
+	// PublishKeyed sends an event like Publish, but events sharing a key are
+	// handled one at a time, in publish order. Different keys run in parallel
+	// on a bounded pool; when it is saturated PublishKeyed blocks until there
+	// is room or ctx is done. A handler publishing with the context it was
+	// given never blocks, so it cannot wait on its own queue. An empty key
+	// behaves like Publish.
+	PublishKeyed(ctx context.Context, topic, key string, data interface{}) error
+
 	// Subscribe registers a handler for a specific topic
 	Subscribe(topic string, handler EventHandler)
+
+	// SubscribeWithRetry registers a handler whose failures are retried under
+	// policy. Events still failing after the last attempt are dead-lettered
+	// under name, which must be unique per topic.
+	SubscribeWithRetry(topic, name string, handler EventHandler, policy RetryPolicy)
+}
+
+// RetryPolicy says how often a failing handler is retried before its event is
+// dead-lettered. The zero value tries once.
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts in total, including the first
+	BaseDelay   time.Duration // Delay before the first retry, doubled after each one
+	MaxDelay    time.Duration // Upper bound of a single delay
+}
+
+// DefaultRetryPolicy rides out short Telegram or database outages.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// EventDispatcher delivers stored events to the subscribers of an EventBus.
+type EventDispatcher interface {
+	// Run delivers events until ctx is done. Start it once every handler has
+	// subscribed, or replayed events find nobody to deliver to.
+	Run(ctx context.Context)
+}
+
+// EventDrainer shuts an EventBus down.
+type EventDrainer interface {
+	// Drain stops accepting publishes and waits until every handler in flight,
+	// queued keyed events included, has finished or ctx is done. Handlers
+	// still running then see their context cancelled. It returns what was
+	// left undelivered.
+	Drain(ctx context.Context) []UndeliveredEvent
+}
+
+// UndeliveredEvent is an event one subscriber had not finished handling when
+// the bus shut down.
+type UndeliveredEvent struct {
+	Topic      string
+	Subscriber string // Empty for plain Subscribe
+	Key        string // Partition key, empty if the event was not keyed
+	Started    bool   // False if it was still queued behind its key
+	Stored     bool   // True if it is kept in the outbox and delivered again on the next start
 }
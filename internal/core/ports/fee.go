@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"AsaExchange/internal/core/domain"
+	"context"
+	"errors"
+)
+
+// ErrFeeScheduleChanged is returned when a schedule version is registered again
+// with different rules. Changed rules need a new version.
+var ErrFeeScheduleChanged = errors.New("fee schedule version is already registered with different rules")
+
+// FeeScheduleRepository keeps every fee schedule version that trades were priced with.
+type FeeScheduleRepository interface {
+	// Register records a schedule version. Registering the same version with
+	// the same rules again is a no-op; different rules return ErrFeeScheduleChanged.
+	Register(ctx context.Context, schedule *domain.FeeSchedule) error
+
+	// GetByVersion finds a registered schedule.
+	GetByVersion(ctx context.Context, version string) (*domain.FeeSchedule, error)
+}
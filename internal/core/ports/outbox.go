@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Transactor runs work in one database transaction.
+type Transactor interface {
+	// WithinTx runs fn in a transaction. Repositories and the event bus called
+	// with the ctx passed to fn join it; an error from fn rolls everything back.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// OutboxEvent is an event waiting in the outbox until its subscribers have seen it.
+type OutboxEvent struct {
+	ID        int64
+	Topic     string
+	Key       string // Partition key, empty if the event is not ordered
+	Payload   []byte // JSON of the event data
+	CreatedAt time.Time
+}
+
+// OutboxRepository stores events next to the state changes they announce,
+// so an event is never lost once its change is committed.
+type OutboxRepository interface {
+	// Add stores an event under a partition key, which may be empty.
+	// Inside Transactor.WithinTx it commits with the transaction.
+	Add(ctx context.Context, topic, key string, payload []byte) error
+
+	// Claim leases up to limit undelivered events, oldest first.
+	// Events whose lease ran out, because their dispatcher died, are claimed again.
+	// An event is not claimed while an older event with its key is leased or
+	// being claimed elsewhere.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*OutboxEvent, error)
+
+	// MarkDelivered records that every subscriber has seen the event.
+	MarkDelivered(ctx context.Context, id int64) error
+
+	// DeleteDelivered removes events delivered before the given time.
+	DeleteDelivered(ctx context.Context, before time.Time) (int64, error)
+
+	// Listen calls notify each time new events are committed, until ctx is done.
+	Listen(ctx context.Context, notify func()) error
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"AsaExchange/internal/core/domain"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// PayoutProofRepository stores payout proof photos. The recipient is served
+// by the Customer Bot, which cannot read the Moderator Bot's file IDs, so the
+// photo itself is kept until it has been forwarded.
+type PayoutProofRepository interface {
+	// Create saves a new proof.
+	Create(ctx context.Context, proof *domain.PayoutProof) error
+
+	// GetByID returns nil if no proof has the ID.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.PayoutProof, error)
+}
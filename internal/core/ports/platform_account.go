@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"AsaExchange/internal/core/domain"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// PlatformAccountRepository defines persistence for the company's receiving accounts.
+type PlatformAccountRepository interface {
+	// Create saves a new platform account.
+	Create(ctx context.Context, acct *domain.PlatformAccount) error
+
+	// GetByID finds a platform account by its UUID.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.PlatformAccount, error)
+
+	// GetActiveByCurrency finds all active accounts for a currency.
+	GetActiveByCurrency(ctx context.Context, currency string) ([]*domain.PlatformAccount, error)
+
+	// List finds all platform accounts, active ones first.
+	List(ctx context.Context) ([]*domain.PlatformAccount, error)
+
+	// Update saves all mutable fields of a platform account.
+	Update(ctx context.Context, acct *domain.PlatformAccount) error
+}
@@ -1,17 +1,28 @@
 package ports
 
 import (
+	"AsaExchange/internal/core/domain"
 	"context"
 
 	"github.com/google/uuid"
 )
 
+// VerificationDocument says which document a verification photo shows.
+type VerificationDocument string
+
+const (
+	VerificationDocIdentity VerificationDocument = "identity" // Sign-up, reviewed for level_1
+	VerificationDocAddress  VerificationDocument = "address"  // First level_2 upgrade document
+	VerificationDocSelfie   VerificationDocument = "selfie"   // Last level_2 upgrade document, opens the review
+)
+
 // NewVerificationEvent holds the data for a new user pending review.
 // This is the "payload" our queue will transmit.
 type NewVerificationEvent struct {
-	UserID  uuid.UUID
-	FileID  string // The Telegram FileID of the photo
-	Caption string // The formatted text (Name, GovID, etc.)
+	UserID   uuid.UUID
+	Document VerificationDocument // Empty means VerificationDocIdentity
+	FileID   string               // The Telegram FileID of the photo
+	Caption  string               // The formatted text (Name, GovID, etc.)
 }
 
 // VerificationQueue is the abstract interface for our "notifier."
@@ -25,3 +36,39 @@ type VerificationQueue interface {
 	// and passing them to the handler function.
 	Subscribe(ctx context.Context, handler func(event NewVerificationEvent) error)
 }
+
+// DepositReceiptEvent holds a deposit receipt uploaded by one party of a transaction.
+type DepositReceiptEvent struct {
+	TransactionID uuid.UUID
+	UserID        uuid.UUID
+	Leg           domain.TransactionLeg
+	FileID        string // The Telegram FileID of the photo
+}
+
+// ReceiptQueue carries deposit receipts from the Customer Bot to the moderators.
+// It works exactly like VerificationQueue.
+type ReceiptQueue interface {
+	// Publish is called by the Customer Bot (deposit receipt flow)
+	Publish(ctx context.Context, event DepositReceiptEvent) (storageRef string, err error)
+
+	// Subscribe is called by the Moderator Bot on startup.
+	Subscribe(ctx context.Context, handler func(event DepositReceiptEvent) error)
+}
+
+// DisputeEvidenceEvent points at one piece of dispute evidence.
+// The statement stays in the database; only the photo travels through the queue.
+type DisputeEvidenceEvent struct {
+	DisputeID  uuid.UUID
+	EvidenceID uuid.UUID
+	FileID     string // The Telegram FileID of the photo
+}
+
+// EvidenceQueue carries dispute evidence from the Customer Bot to the moderators.
+// It works exactly like ReceiptQueue.
+type EvidenceQueue interface {
+	// Publish is called by the Customer Bot (dispute flow)
+	Publish(ctx context.Context, event DisputeEvidenceEvent) (storageRef string, err error)
+
+	// Subscribe is called by the Moderator Bot on startup.
+	Subscribe(ctx context.Context, handler func(event DisputeEvidenceEvent) error)
+}
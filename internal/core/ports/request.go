@@ -0,0 +1,60 @@
+package ports
+
+import (
+	"AsaExchange/internal/core/domain"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrRequestStatusChanged is returned when a request left the expected status
+// before a status change could be saved.
+var ErrRequestStatusChanged = errors.New("request status changed concurrently")
+
+// RequestFilter narrows down a listing of open requests.
+// Empty fields match anything.
+type RequestFilter struct {
+	BaseCurrency  string
+	QuoteCurrency string
+	Type          domain.RequestType
+}
+
+// RequestRepository defines the persistence operations for marketplace Requests.
+type RequestRepository interface {
+	// Create saves a new request to the database.
+	Create(ctx context.Context, req *domain.Request) error
+
+	// GetByID finds a request by its UUID.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Request, error)
+
+	// GetByUserID finds all requests created by a user, newest first.
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Request, error)
+
+	// Update saves the mutable fields (status, channel message) of a request.
+	Update(ctx context.Context, req *domain.Request) error
+
+	// TransitionStatus saves req.Status only if the stored status is still 'from'.
+	// It returns ErrRequestStatusChanged otherwise.
+	TransitionStatus(ctx context.Context, req *domain.Request, from domain.RequestStatus) error
+
+	// SetChannelMessageID records the public channel post of a request
+	// without touching its status.
+	SetChannelMessageID(ctx context.Context, id uuid.UUID, messageID int64) error
+
+	// ListOpen returns one page of open requests matching the filter, oldest first,
+	// together with the total number of matches.
+	ListOpen(ctx context.Context, filter RequestFilter, limit, offset int) ([]*domain.Request, int, error)
+
+	// ExpireOpen cancels up to limit requests that have been open since before
+	// the cutoff, oldest first, and returns them. A request reopened by a
+	// cancelled trade counts from its reopening, not its creation.
+	ExpireOpen(ctx context.Context, openedBefore time.Time, limit int) ([]*domain.Request, error)
+
+	// ListOpenCurrencies returns the distinct base and quote currencies of all open requests.
+	ListOpenCurrencies(ctx context.Context) (base []string, quote []string, err error)
+
+	// CountOpenByUser returns how many open requests a user has.
+	CountOpenByUser(ctx context.Context, userID uuid.UUID) (int, error)
+}
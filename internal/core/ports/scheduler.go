@@ -0,0 +1,18 @@
+package ports
+
+import "context"
+
+// Job is a periodic background task run by the scheduler.
+type Job interface {
+	// Name identifies the job in logs and in the cross-replica lock.
+	Name() string
+	// Run does one pass of work. It must be safe to run again after a failure.
+	Run(ctx context.Context) error
+}
+
+// JobLocker makes sure only one replica runs a job at a time.
+type JobLocker interface {
+	// TryLock takes the lock for the named job without waiting.
+	// ok is false if another replica holds it. unlock must be called when ok is true.
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
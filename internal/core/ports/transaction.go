@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"AsaExchange/internal/core/domain"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrTransactionStatusChanged is returned when a transaction left the expected
+// status before a transition could be saved (someone else moved it first).
+var ErrTransactionStatusChanged = errors.New("transaction status changed concurrently")
+
+// TransactionRepository defines the persistence operations for Transactions.
+type TransactionRepository interface {
+	// Create saves a new transaction to the database.
+	Create(ctx context.Context, tx *domain.Transaction) error
+
+	// GetByID finds a transaction by its UUID.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Transaction, error)
+
+	// GetByUserID finds all transactions where the user is the seller or the buyer.
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Transaction, error)
+
+	// Update saves the mutable fields (status, moderator, accounts) of a transaction.
+	Update(ctx context.Context, tx *domain.Transaction) error
+
+	// Transition moves tx from 'from' to tx.Status and appends a history entry, atomically.
+	// If moderatorID is set it also becomes the transaction's moderator.
+	// It returns ErrTransactionStatusChanged if the stored status is no longer 'from'.
+	Transition(ctx context.Context, tx *domain.Transaction, from domain.TransactionStatus, moderatorID *uuid.UUID) error
+
+	// SetPayoutAccount records the user bank account a leg is paid out to.
+	SetPayoutAccount(ctx context.Context, txID uuid.UUID, leg domain.TransactionLeg, accountID uuid.UUID) error
+
+	// GetHistory returns the status history of a transaction, oldest first.
+	GetHistory(ctx context.Context, transactionID uuid.UUID) ([]*domain.TransactionTransition, error)
+
+	// TradedVolume sums the base amounts of the user's trades in the given base
+	// currency that were matched since the cutoff. Cancelled trades do not count.
+	TradedVolume(ctx context.Context, userID uuid.UUID, baseCurrency string, since time.Time) (domain.Decimal, error)
+}
@@ -3,6 +3,7 @@ package ports
 import (
 	"AsaExchange/internal/core/domain"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -21,6 +22,21 @@ type UserRepository interface {
 	Update(ctx context.Context, user *domain.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
 
+	// LockForUpdate locks the rows of the given users until the surrounding
+	// transaction ends, in id order so that two lockers cannot deadlock.
+	// Outside a transaction the lock is released right away.
+	LockForUpdate(ctx context.Context, ids ...uuid.UUID) error
+
 	// GetNextPendingUser finds the oldest user in 'pending' status.
 	GetNextPendingUser(ctx context.Context) (*domain.User, error)
+
+	// ClaimStalledRegistrations marks up to limit users who have sat in a
+	// registration step since before idleSince as nudged, and returns them.
+	// A user is only ever claimed once.
+	ClaimStalledRegistrations(ctx context.Context, idleSince time.Time, limit int) ([]*domain.User, error)
+
+	// ClaimPendingStartPayload clears the deep link stashed for a user and
+	// returns it, leaving the rest of the row alone. Only one caller gets a
+	// given link; the others get nil.
+	ClaimPendingStartPayload(ctx context.Context, id uuid.UUID) (*string, error)
 }
@@ -3,15 +3,30 @@ package ports
 import (
 	"AsaExchange/internal/core/domain"
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 )
 
+// ErrBankAccountInUse is returned when deleting an account a transaction still pays out to.
+var ErrBankAccountInUse = errors.New("bank account is used by a transaction")
+
 // UserBankAccountRepository defines persistence for user bank accounts.
 type UserBankAccountRepository interface {
 	// Create saves a new bank account for a user.
 	Create(ctx context.Context, acct *domain.UserBankAccount) error
 
+	// GetByID finds a bank account by its UUID.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.UserBankAccount, error)
+
 	// GetByUserID finds all bank accounts for a given user.
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.UserBankAccount, error)
+
+	// Update saves the name, currency, bank and details of an account.
+	// Only an account owned by acct.UserID is changed.
+	Update(ctx context.Context, acct *domain.UserBankAccount) error
+
+	// Delete removes an account if it is owned by userID.
+	// It returns ErrBankAccountInUse if a transaction still references it.
+	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 }
@@ -0,0 +1,47 @@
+package ports
+
+import (
+	"AsaExchange/internal/core/domain"
+	"context"
+)
+
+// VerificationOutcome is what a verification strategy decided about a sign-up.
+type VerificationOutcome string
+
+const (
+	VerificationApprove    VerificationOutcome = "approve"     // Verified, the user becomes level_1
+	VerificationReject     VerificationOutcome = "reject"      // Failed, the user must register again
+	VerificationNeedFields VerificationOutcome = "need_fields" // Ask the user for Fields, then verify again
+	VerificationManual     VerificationOutcome = "manual"      // Leave it to a moderator
+)
+
+// VerificationField is an extra detail a strategy needs from the user.
+type VerificationField struct {
+	Key    string // Sent back to the strategy in VerificationInput.Fields
+	Prompt string // Question shown to the user, plain text
+}
+
+// VerificationDecision is the result of one verification attempt.
+type VerificationDecision struct {
+	Outcome VerificationOutcome
+	Reason  string              // Why, for the logs and the moderators
+	Fields  []VerificationField // Only for VerificationNeedFields
+}
+
+// VerificationInput is what a strategy checks.
+type VerificationInput struct {
+	User   *domain.User
+	Fields map[string]string // Answers to fields asked for earlier, keyed by VerificationField.Key
+}
+
+// VerificationStrategy checks the identity of a new user, usually for one country.
+// The strategy name is the `strategy` of the country in the config.
+type VerificationStrategy interface {
+	// Name is the key the strategy is registered under, e.g. "manual".
+	Name() string
+
+	// Verify decides on a user who has finished registration.
+	// An error means the check could not be done, not that it failed;
+	// callers fall back to manual review.
+	Verify(ctx context.Context, input VerificationInput) (*VerificationDecision, error)
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+var (
+	// ErrNotOwner is returned when a user tries to cancel something that is not theirs.
+	ErrNotOwner = errors.New("this does not belong to the user")
+
+	// ErrBidNotFound is returned when the bid to cancel does not exist.
+	ErrBidNotFound = errors.New("bid not found")
+
+	// ErrTransactionNotCancellable is returned once money was received for a
+	// transaction, or it already ended.
+	ErrTransactionNotCancellable = errors.New("transaction can no longer be cancelled")
+)
+
+// CancellationService lets users withdraw their own requests, bids and unfunded trades.
+// Every cancel publishes an event, so counterparties and channel cards stay in sync.
+type CancellationService struct {
+	requestRepo ports.RequestRepository
+	bidRepo     ports.BidRepository
+	txRepo      ports.TransactionRepository
+	requests    *RequestService
+	txService   *TransactionService
+	transactor  ports.Transactor
+	bus         ports.EventBus
+	log         zerolog.Logger
+}
+
+// NewCancellationService creates a new cancellation service.
+func NewCancellationService(
+	requestRepo ports.RequestRepository,
+	bidRepo ports.BidRepository,
+	txRepo ports.TransactionRepository,
+	requests *RequestService,
+	txService *TransactionService,
+	transactor ports.Transactor,
+	bus ports.EventBus,
+	baseLogger *zerolog.Logger,
+) *CancellationService {
+	return &CancellationService{
+		requestRepo: requestRepo,
+		bidRepo:     bidRepo,
+		txRepo:      txRepo,
+		requests:    requests,
+		txService:   txService,
+		transactor:  transactor,
+		bus:         bus,
+		log:         baseLogger.With().Str("component", "cancellation_service").Logger(),
+	}
+}
+
+// CancelRequest withdraws an open request and cancels its pending bids.
+// It publishes "request:cancelled" and one "bid:cancelled" per bid, all in
+// one transaction with the changes.
+// It returns ports.ErrRequestNotOpen if the request was matched or closed.
+func (s *CancellationService) CancelRequest(ctx context.Context, userID, requestID uuid.UUID) (*domain.Request, error) {
+	log := s.log.With().Str("user_id", userID.String()).Str("request_id", requestID.String()).Logger()
+
+	req, err := s.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load request")
+		return nil, err
+	}
+	if req == nil {
+		return nil, ErrRequestNotFound
+	}
+	if req.UserID != userID {
+		return nil, ErrNotOwner
+	}
+
+	var bids []*domain.Bid
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		req, err = s.requests.Transition(ctx, requestID, domain.RequestStatusOpen, domain.RequestStatusCancelled)
+		if errors.Is(err, ports.ErrRequestStatusChanged) {
+			return ports.ErrRequestNotOpen
+		}
+		if err != nil {
+			return err
+		}
+
+		bids, err = s.bidRepo.CancelPendingByRequest(ctx, requestID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to cancel bids of cancelled request")
+			return err
+		}
+		for _, bid := range bids {
+			if err := events.Publish(ctx, s.bus, events.BidCancelled{BidRef: events.NewBidRef(bid)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Int("bids", len(bids)).Msg("Request cancelled by its owner")
+	return req, nil
+}
+
+// CancelBid withdraws a pending bid and publishes "bid:withdrawn".
+// It returns ports.ErrBidNotPending if the bid was already accepted or rejected.
+func (s *CancellationService) CancelBid(ctx context.Context, userID, bidID uuid.UUID) (*domain.Bid, error) {
+	log := s.log.With().Str("user_id", userID.String()).Str("bid_id", bidID.String()).Logger()
+
+	bid, err := s.bidRepo.GetByID(ctx, bidID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load bid")
+		return nil, err
+	}
+	if bid == nil {
+		return nil, ErrBidNotFound
+	}
+	if bid.UserID != userID {
+		return nil, ErrNotOwner
+	}
+	if bid.Status != domain.BidStatusPending {
+		return nil, ports.ErrBidNotPending
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.bidRepo.CancelPending(ctx, bid); err != nil {
+			return err
+		}
+		return events.Publish(ctx, s.bus, events.BidWithdrawn{BidRef: events.NewBidRef(bid)})
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msg("Bid withdrawn by its author")
+	return bid, nil
+}
+
+// CancelTransaction cancels a trade nobody has paid into yet and reopens its request.
+// It publishes "transaction:withdrawn" and, through the other services,
+// "transaction:cancelled" and "request:open", all in one transaction with the changes.
+// It returns ErrTransactionNotCancellable once a deposit was received.
+func (s *CancellationService) CancelTransaction(ctx context.Context, userID, txID uuid.UUID) (*domain.Transaction, error) {
+	log := s.log.With().Str("user_id", userID.String()).Str("transaction_id", txID.String()).Logger()
+
+	tx, err := s.txRepo.GetByID(ctx, txID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load transaction")
+		return nil, err
+	}
+	if tx == nil {
+		return nil, ErrTransactionNotFound
+	}
+	if tx.SellerUserID != userID && tx.BuyerUserID != userID {
+		return nil, ErrNotOwner
+	}
+	if tx.Status != domain.TxStatusPendingDeposits {
+		return nil, ErrTransactionNotCancellable
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		tx, err = s.txService.Transition(ctx, txID, domain.TxStatusCancelled, nil)
+		if errors.Is(err, ports.ErrTransactionStatusChanged) {
+			// A deposit was confirmed in the meantime
+			return ErrTransactionNotCancellable
+		}
+		if err != nil {
+			return err
+		}
+
+		// The accepted bid goes with the trade
+		bid, err := s.bidRepo.GetByID(ctx, tx.BidID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to load bid of cancelled transaction")
+			return err
+		}
+		if bid == nil {
+			return ErrBidNotFound
+		}
+		bid.Status = domain.BidStatusCancelled
+		if err := s.bidRepo.TransitionStatus(ctx, bid, domain.BidStatusAccepted); err != nil {
+			log.Error().Err(err).Msg("Failed to cancel bid of cancelled transaction")
+			return err
+		}
+
+		// Put the offer back in the order book
+		if _, err := s.requests.Transition(ctx, tx.RequestID, domain.RequestStatusMatched, domain.RequestStatusOpen); err != nil {
+			log.Error().Err(err).Msg("Failed to reopen request of cancelled transaction")
+			return err
+		}
+
+		// Same key as the status events, so it follows transaction:cancelled
+		withdrawn := events.TransactionWithdrawn{Transaction: events.SnapshotTransaction(tx), UserID: userID}
+		return events.PublishKeyed(ctx, s.bus, tx.ID.String(), withdrawn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msg("Transaction cancelled by a party")
+	return tx, nil
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mocks ---
+
+// MockBidRepository
+type MockBidRepository struct {
+	mock.Mock
+}
+
+var _ ports.BidRepository = (*MockBidRepository)(nil)
+
+func (m *MockBidRepository) Create(ctx context.Context, bid *domain.Bid) error {
+	args := m.Called(ctx, bid)
+	return args.Error(0)
+}
+func (m *MockBidRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Bid, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Bid), args.Error(1)
+}
+func (m *MockBidRepository) GetByRequestID(ctx context.Context, requestID uuid.UUID) ([]*domain.Bid, error) {
+	args := m.Called(ctx, requestID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Bid), args.Error(1)
+}
+func (m *MockBidRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Bid, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Bid), args.Error(1)
+}
+func (m *MockBidRepository) Update(ctx context.Context, bid *domain.Bid) error {
+	args := m.Called(ctx, bid)
+	return args.Error(0)
+}
+func (m *MockBidRepository) TransitionStatus(ctx context.Context, bid *domain.Bid, from domain.BidStatus) error {
+	args := m.Called(ctx, bid, from)
+	return args.Error(0)
+}
+func (m *MockBidRepository) CancelPending(ctx context.Context, bid *domain.Bid) error {
+	args := m.Called(ctx, bid)
+	return args.Error(0)
+}
+func (m *MockBidRepository) RejectPending(ctx context.Context, bid *domain.Bid) error {
+	args := m.Called(ctx, bid)
+	return args.Error(0)
+}
+func (m *MockBidRepository) CancelPendingByRequest(ctx context.Context, requestID uuid.UUID) ([]*domain.Bid, error) {
+	args := m.Called(ctx, requestID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Bid), args.Error(1)
+}
+func (m *MockBidRepository) CancelPendingOnClosedRequests(ctx context.Context) ([]*domain.Bid, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Bid), args.Error(1)
+}
+func (m *MockBidRepository) Accept(ctx context.Context, bidID uuid.UUID, tx *domain.Transaction) ([]*domain.Bid, error) {
+	args := m.Called(ctx, bidID, tx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Bid), args.Error(1)
+}
+
+// cancellationFixture wires a CancellationService to fresh mocks.
+type cancellationFixture struct {
+	requestRepo *MockRequestRepository
+	bidRepo     *MockBidRepository
+	txRepo      *MockTransactionRepository
+	bus         *MockEventBus
+	svc         *CancellationService
+}
+
+func newCancellationFixture() *cancellationFixture {
+	nopLogger := zerolog.Nop()
+	f := &cancellationFixture{
+		requestRepo: new(MockRequestRepository),
+		bidRepo:     new(MockBidRepository),
+		txRepo:      new(MockTransactionRepository),
+		bus:         new(MockEventBus),
+	}
+	f.svc = NewCancellationService(
+		f.requestRepo,
+		f.bidRepo,
+		f.txRepo,
+		NewRequestService(f.requestRepo, inlineTransactor{}, f.bus, &nopLogger),
+		NewTransactionService(f.txRepo, inlineTransactor{}, f.bus, &nopLogger),
+		inlineTransactor{},
+		f.bus,
+		&nopLogger,
+	)
+	return f
+}
+
+// --- Tests ---
+
+func TestCancellationService_CancelRequest_CancelsBids(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	f := newCancellationFixture()
+
+	owner := uuid.New()
+	req := &domain.Request{ID: uuid.New(), UserID: owner, Status: domain.RequestStatusOpen}
+	bid := &domain.Bid{ID: uuid.New(), RequestID: req.ID, Status: domain.BidStatusCancelled}
+
+	// 2. Define Expectations
+	f.requestRepo.On("GetByID", mock.Anything, req.ID).Return(req, nil).Twice()
+	f.requestRepo.On("TransitionStatus", mock.Anything, req, domain.RequestStatusOpen).Return(nil).Once()
+	f.bus.On("PublishKeyed", mock.Anything, "request:cancelled", req.ID.String(), mock.AnythingOfType("events.RequestCancelled")).Return(nil).Once()
+	f.bidRepo.On("CancelPendingByRequest", mock.Anything, req.ID).Return([]*domain.Bid{bid}, nil).Once()
+	f.bus.On("Publish", mock.Anything, "bid:cancelled", events.BidCancelled{BidRef: events.NewBidRef(bid)}).Return(nil).Once()
+
+	// 3. Run
+	if _, err := f.svc.CancelRequest(ctx, owner, req.ID); err != nil {
+		t.Fatalf("CancelRequest returned an error: %v", err)
+	}
+
+	// 4. Assert
+	if req.Status != domain.RequestStatusCancelled {
+		t.Errorf("Status mismatch: got %s", req.Status)
+	}
+	f.requestRepo.AssertExpectations(t)
+	f.bidRepo.AssertExpectations(t)
+	f.bus.AssertExpectations(t)
+}
+
+func TestCancellationService_CancelRequest_NotOwner(t *testing.T) {
+	ctx := context.Background()
+	f := newCancellationFixture()
+
+	req := &domain.Request{ID: uuid.New(), UserID: uuid.New(), Status: domain.RequestStatusOpen}
+	f.requestRepo.On("GetByID", mock.Anything, req.ID).Return(req, nil).Once()
+
+	if _, err := f.svc.CancelRequest(ctx, uuid.New(), req.ID); !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("Expected ErrNotOwner, got: %v", err)
+	}
+	f.requestRepo.AssertNotCalled(t, "TransitionStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCancellationService_CancelBid_AlreadyAccepted(t *testing.T) {
+	ctx := context.Background()
+	f := newCancellationFixture()
+
+	bidder := uuid.New()
+	bid := &domain.Bid{ID: uuid.New(), UserID: bidder, Status: domain.BidStatusAccepted}
+	f.bidRepo.On("GetByID", mock.Anything, bid.ID).Return(bid, nil).Once()
+
+	if _, err := f.svc.CancelBid(ctx, bidder, bid.ID); !errors.Is(err, ports.ErrBidNotPending) {
+		t.Fatalf("Expected ErrBidNotPending, got: %v", err)
+	}
+	f.bidRepo.AssertNotCalled(t, "CancelPending", mock.Anything, mock.Anything)
+}
+
+func TestCancellationService_CancelTransaction_ReopensRequest(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	f := newCancellationFixture()
+
+	req := &domain.Request{ID: uuid.New(), Status: domain.RequestStatusMatched}
+	bid := &domain.Bid{ID: uuid.New(), RequestID: req.ID, Status: domain.BidStatusAccepted}
+	tx := &domain.Transaction{
+		ID:           uuid.New(),
+		RequestID:    req.ID,
+		BidID:        bid.ID,
+		SellerUserID: uuid.New(),
+		BuyerUserID:  uuid.New(),
+		Status:       domain.TxStatusPendingDeposits,
+	}
+
+	// 2. Define Expectations
+	f.txRepo.On("GetByID", mock.Anything, tx.ID).Return(tx, nil).Twice()
+	f.txRepo.On("Transition", mock.Anything, tx, domain.TxStatusPendingDeposits, (*uuid.UUID)(nil)).Return(nil).Once()
+	f.bus.On("PublishKeyed", mock.Anything, "transaction:cancelled", tx.ID.String(), mock.AnythingOfType("events.TransactionCancelled")).Return(nil).Once()
+	f.bidRepo.On("GetByID", mock.Anything, bid.ID).Return(bid, nil).Once()
+	f.bidRepo.On("TransitionStatus", mock.Anything, bid, domain.BidStatusAccepted).Return(nil).Once()
+	f.requestRepo.On("GetByID", mock.Anything, req.ID).Return(req, nil).Once()
+	f.requestRepo.On("TransitionStatus", mock.Anything, req, domain.RequestStatusMatched).Return(nil).Once()
+	f.bus.On("PublishKeyed", mock.Anything, "request:open", req.ID.String(), mock.AnythingOfType("events.RequestReopened")).Return(nil).Once()
+	f.bus.On("PublishKeyed", mock.Anything, "transaction:withdrawn", tx.ID.String(), mock.AnythingOfType("events.TransactionWithdrawn")).Return(nil).Once()
+
+	// 3. Run
+	if _, err := f.svc.CancelTransaction(ctx, tx.BuyerUserID, tx.ID); err != nil {
+		t.Fatalf("CancelTransaction returned an error: %v", err)
+	}
+
+	// 4. Assert
+	if tx.Status != domain.TxStatusCancelled || req.Status != domain.RequestStatusOpen || bid.Status != domain.BidStatusCancelled {
+		t.Errorf("Cascade mismatch: tx=%s request=%s bid=%s", tx.Status, req.Status, bid.Status)
+	}
+	f.txRepo.AssertExpectations(t)
+	f.bidRepo.AssertExpectations(t)
+	f.requestRepo.AssertExpectations(t)
+	f.bus.AssertExpectations(t)
+}
+
+func TestCancellationService_CancelTransaction_BidChanged(t *testing.T) {
+	ctx := context.Background()
+	f := newCancellationFixture()
+
+	bid := &domain.Bid{ID: uuid.New(), Status: domain.BidStatusAccepted}
+	tx := &domain.Transaction{ID: uuid.New(), RequestID: uuid.New(), BidID: bid.ID, SellerUserID: uuid.New(), BuyerUserID: uuid.New(), Status: domain.TxStatusPendingDeposits}
+	f.txRepo.On("GetByID", mock.Anything, tx.ID).Return(tx, nil).Twice()
+	f.txRepo.On("Transition", mock.Anything, tx, domain.TxStatusPendingDeposits, (*uuid.UUID)(nil)).Return(nil).Once()
+	f.bus.On("PublishKeyed", mock.Anything, "transaction:cancelled", tx.ID.String(), mock.AnythingOfType("events.TransactionCancelled")).Return(nil).Once()
+	f.bidRepo.On("GetByID", mock.Anything, bid.ID).Return(bid, nil).Once()
+	f.bidRepo.On("TransitionStatus", mock.Anything, bid, domain.BidStatusAccepted).Return(ports.ErrBidStatusChanged).Once()
+
+	// The error rolls the whole cancel back instead of being logged away
+	if _, err := f.svc.CancelTransaction(ctx, tx.SellerUserID, tx.ID); !errors.Is(err, ports.ErrBidStatusChanged) {
+		t.Fatalf("Expected ErrBidStatusChanged, got: %v", err)
+	}
+	f.requestRepo.AssertNotCalled(t, "TransitionStatus", mock.Anything, mock.Anything, mock.Anything)
+	f.bus.AssertNotCalled(t, "PublishKeyed", mock.Anything, "transaction:withdrawn", mock.Anything, mock.Anything)
+}
+
+func TestCancellationService_CancelTransaction_Funded(t *testing.T) {
+	ctx := context.Background()
+	f := newCancellationFixture()
+
+	tx := &domain.Transaction{ID: uuid.New(), SellerUserID: uuid.New(), BuyerUserID: uuid.New(), Status: domain.TxStatusSellerDepositReceived}
+	f.txRepo.On("GetByID", mock.Anything, tx.ID).Return(tx, nil).Once()
+
+	if _, err := f.svc.CancelTransaction(ctx, tx.SellerUserID, tx.ID); !errors.Is(err, ErrTransactionNotCancellable) {
+		t.Fatalf("Expected ErrTransactionNotCancellable, got: %v", err)
+	}
+	f.txRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	f.bus.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// cleanupBatchSize caps how many rows one job pass touches.
+// Anything left over is picked up on the next tick.
+const cleanupBatchSize = 100
+
+// ExpireRequestsJob cancels open requests nobody matched within the TTL.
+// For each one it publishes "request:expired" (to tell the owner) and
+// "request:cancelled" (to close the channel card), in the same transaction.
+type ExpireRequestsJob struct {
+	repo       ports.RequestRepository
+	transactor ports.Transactor
+	bus        ports.EventBus
+	ttl        time.Duration
+	log        zerolog.Logger
+}
+
+// NewExpireRequestsJob creates a new request expiry job.
+func NewExpireRequestsJob(
+	repo ports.RequestRepository,
+	transactor ports.Transactor,
+	bus ports.EventBus,
+	ttl time.Duration,
+	baseLogger *zerolog.Logger,
+) *ExpireRequestsJob {
+	return &ExpireRequestsJob{
+		repo:       repo,
+		transactor: transactor,
+		bus:        bus,
+		ttl:        ttl,
+		log:        baseLogger.With().Str("component", "expire_requests_job").Logger(),
+	}
+}
+
+// Name identifies the job.
+func (j *ExpireRequestsJob) Name() string {
+	return "expire_requests"
+}
+
+// Run expires one batch of requests.
+func (j *ExpireRequestsJob) Run(ctx context.Context) error {
+	return j.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		expired, err := j.repo.ExpireOpen(ctx, time.Now().Add(-j.ttl), cleanupBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, req := range expired {
+			if err := events.PublishKeyed(ctx, j.bus, req.ID.String(), events.RequestExpired{Request: events.SnapshotRequest(req)}); err != nil {
+				return err
+			}
+			if err := events.PublishKeyed(ctx, j.bus, req.ID.String(), events.RequestCancelled{Request: events.SnapshotRequest(req)}); err != nil {
+				return err
+			}
+			j.log.Info().Str("request_id", req.ID.String()).Msg("Request expired")
+		}
+		return nil
+	})
+}
+
+// CancelStaleBidsJob cancels pending bids left behind on closed requests
+// and publishes "bid:cancelled" for each one in the same transaction.
+type CancelStaleBidsJob struct {
+	repo       ports.BidRepository
+	transactor ports.Transactor
+	bus        ports.EventBus
+	log        zerolog.Logger
+}
+
+// NewCancelStaleBidsJob creates a new stale bid cleanup job.
+func NewCancelStaleBidsJob(repo ports.BidRepository, transactor ports.Transactor, bus ports.EventBus, baseLogger *zerolog.Logger) *CancelStaleBidsJob {
+	return &CancelStaleBidsJob{
+		repo:       repo,
+		transactor: transactor,
+		bus:        bus,
+		log:        baseLogger.With().Str("component", "cancel_stale_bids_job").Logger(),
+	}
+}
+
+// Name identifies the job.
+func (j *CancelStaleBidsJob) Name() string {
+	return "cancel_stale_bids"
+}
+
+// Run cancels every stale bid.
+func (j *CancelStaleBidsJob) Run(ctx context.Context) error {
+	return j.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		cancelled, err := j.repo.CancelPendingOnClosedRequests(ctx)
+		if err != nil {
+			return err
+		}
+		for _, bid := range cancelled {
+			if err := events.Publish(ctx, j.bus, events.BidCancelled{BidRef: events.NewBidRef(bid)}); err != nil {
+				return err
+			}
+			j.log.Info().Str("bid_id", bid.ID.String()).Msg("Stale bid cancelled")
+		}
+		return nil
+	})
+}
+
+// RegistrationNudgeJob finds users who stopped halfway through sign-up
+// and publishes "user:registration_stalled" once per user. The claim and
+// the events are saved in one transaction.
+type RegistrationNudgeJob struct {
+	repo       ports.UserRepository
+	transactor ports.Transactor
+	bus        ports.EventBus
+	after      time.Duration
+	log        zerolog.Logger
+}
+
+// NewRegistrationNudgeJob creates a new registration reminder job.
+func NewRegistrationNudgeJob(
+	repo ports.UserRepository,
+	transactor ports.Transactor,
+	bus ports.EventBus,
+	after time.Duration,
+	baseLogger *zerolog.Logger,
+) *RegistrationNudgeJob {
+	return &RegistrationNudgeJob{
+		repo:       repo,
+		transactor: transactor,
+		bus:        bus,
+		after:      after,
+		log:        baseLogger.With().Str("component", "registration_nudge_job").Logger(),
+	}
+}
+
+// Name identifies the job.
+func (j *RegistrationNudgeJob) Name() string {
+	return "registration_nudge"
+}
+
+// Run claims one batch of stalled users.
+func (j *RegistrationNudgeJob) Run(ctx context.Context) error {
+	return j.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		users, err := j.repo.ClaimStalledRegistrations(ctx, time.Now().Add(-j.after), cleanupBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if err := events.Publish(ctx, j.bus, events.RegistrationStalled{UserRef: events.NewUserRef(user)}); err != nil {
+				return err
+			}
+			j.log.Info().Str("user_id", user.ID.String()).Str("state", string(user.State)).Msg("Registration stalled")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExpireRequestsJob_Run(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockRepo := new(MockRequestRepository)
+	mockBus := new(MockEventBus)
+	job := NewExpireRequestsJob(mockRepo, inlineTransactor{}, mockBus, time.Hour, &nopLogger)
+
+	req := &domain.Request{ID: uuid.New(), Status: domain.RequestStatusCancelled}
+
+	// 2. Define Expectations
+	mockRepo.On("ExpireOpen", mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+		return time.Since(cutoff) >= time.Hour
+	}), cleanupBatchSize).Return([]*domain.Request{req}, nil).Once()
+	mockBus.On("PublishKeyed", mock.Anything, "request:expired", req.ID.String(), mock.AnythingOfType("events.RequestExpired")).Return(nil).Once()
+	mockBus.On("PublishKeyed", mock.Anything, "request:cancelled", req.ID.String(), mock.AnythingOfType("events.RequestCancelled")).Return(nil).Once()
+
+	// 3. Run
+	if err := job.Run(ctx); err != nil {
+		t.Fatalf("Run returned an error: %v", err)
+	}
+
+	// 4. Assert
+	mockRepo.AssertExpectations(t)
+	mockBus.AssertExpectations(t)
+}
+
+func TestExpireRequestsJob_Run_NothingExpired(t *testing.T) {
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockRepo := new(MockRequestRepository)
+	mockBus := new(MockEventBus)
+	job := NewExpireRequestsJob(mockRepo, inlineTransactor{}, mockBus, time.Hour, &nopLogger)
+
+	mockRepo.On("ExpireOpen", mock.Anything, mock.Anything, cleanupBatchSize).Return(nil, nil).Once()
+
+	if err := job.Run(ctx); err != nil {
+		t.Fatalf("Run returned an error: %v", err)
+	}
+	mockBus.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
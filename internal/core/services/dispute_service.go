@@ -0,0 +1,179 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ErrDisputeNotFound is returned when the dispute to resolve does not exist.
+var ErrDisputeNotFound = errors.New("dispute not found")
+
+// disputeOutcomes maps each resolution to the final status of the transaction.
+// A refund and a cancellation both end the trade; they differ in what the
+// moderators do with the deposits.
+var disputeOutcomes = map[domain.DisputeResolution]domain.TransactionStatus{
+	domain.DisputeResolutionRefund:   domain.TxStatusCancelled,
+	domain.DisputeResolutionComplete: domain.TxStatusCompleted,
+	domain.DisputeResolutionCancel:   domain.TxStatusCancelled,
+}
+
+// DisputeService opens and resolves disputes.
+// The transaction moves through TransactionService, so its history stays complete.
+type DisputeService struct {
+	repo       ports.DisputeRepository
+	bidRepo    ports.BidRepository
+	txService  *TransactionService
+	requests   *RequestService
+	transactor ports.Transactor
+	bus        ports.EventBus
+	log        zerolog.Logger
+}
+
+// NewDisputeService creates a new dispute service.
+func NewDisputeService(
+	repo ports.DisputeRepository,
+	bidRepo ports.BidRepository,
+	txService *TransactionService,
+	requests *RequestService,
+	transactor ports.Transactor,
+	bus ports.EventBus,
+	baseLogger *zerolog.Logger,
+) *DisputeService {
+	return &DisputeService{
+		repo:       repo,
+		bidRepo:    bidRepo,
+		txService:  txService,
+		requests:   requests,
+		transactor: transactor,
+		bus:        bus,
+		log:        baseLogger.With().Str("component", "dispute_service").Logger(),
+	}
+}
+
+// Open freezes the transaction in "disputed" and records the dispute.
+// The caller must make sure userID is a party of the transaction.
+// On success it publishes "dispute:opened" with the new dispute, in one
+// transaction with the status change and the dispute.
+func (s *DisputeService) Open(ctx context.Context, txID, userID uuid.UUID, reason string) (*domain.Dispute, error) {
+	log := s.log.With().Str("transaction_id", txID.String()).Str("user_id", userID.String()).Logger()
+
+	dispute := &domain.Dispute{
+		ID:             uuid.New(),
+		TransactionID:  txID,
+		OpenedByUserID: userID,
+		Reason:         reason,
+		Status:         domain.DisputeStatusOpen,
+	}
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.txService.Transition(ctx, txID, domain.TxStatusDisputed, nil); err != nil {
+			return err
+		}
+		if err := s.repo.Create(ctx, dispute); err != nil {
+			log.Error().Err(err).Msg("Failed to save dispute")
+			return err
+		}
+		return events.Publish(ctx, s.bus, events.DisputeOpened{Dispute: events.SnapshotDispute(dispute)})
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Str("dispute_id", dispute.ID.String()).Msg("Dispute opened")
+	return dispute, nil
+}
+
+// Resolve settles an open dispute and moves the transaction to its final status.
+// A refund or cancellation also cancels the accepted bid and closes the
+// request, so the offer does not linger as matched.
+// It returns ports.ErrDisputeNotOpen if the dispute was already resolved.
+// On success it publishes "dispute:resolved" with the resolved dispute, in
+// one transaction with all the changes.
+func (s *DisputeService) Resolve(
+	ctx context.Context,
+	disputeID uuid.UUID,
+	resolution domain.DisputeResolution,
+	moderatorID uuid.UUID,
+) (*domain.Dispute, error) {
+	log := s.log.With().Str("dispute_id", disputeID.String()).Str("resolution", string(resolution)).Logger()
+
+	to, ok := disputeOutcomes[resolution]
+	if !ok {
+		return nil, fmt.Errorf("unknown dispute resolution %q", resolution)
+	}
+
+	dispute, err := s.repo.GetByID(ctx, disputeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load dispute")
+		return nil, err
+	}
+	if dispute == nil {
+		return nil, ErrDisputeNotFound
+	}
+	if dispute.Status != domain.DisputeStatusOpen {
+		return nil, ports.ErrDisputeNotOpen
+	}
+
+	dispute.Resolution = &resolution
+	dispute.ResolvedBy = &moderatorID
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// Move the trade first: if another moderator got there, this fails and
+		// the dispute stays untouched.
+		tx, err := s.txService.Transition(ctx, dispute.TransactionID, to, &moderatorID)
+		if err != nil {
+			var illegal *IllegalTransitionError
+			if errors.As(err, &illegal) || errors.Is(err, ports.ErrTransactionStatusChanged) {
+				return ports.ErrDisputeNotOpen
+			}
+			return err
+		}
+		if to == domain.TxStatusCancelled {
+			if err := s.closeTrade(ctx, tx); err != nil {
+				return err
+			}
+		}
+
+		if err := s.repo.Resolve(ctx, dispute); err != nil {
+			log.Error().Err(err).Msg("Failed to close dispute")
+			return err
+		}
+		return events.Publish(ctx, s.bus, events.DisputeResolved{Dispute: events.SnapshotDispute(dispute)})
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msg("Dispute resolved")
+	return dispute, nil
+}
+
+// closeTrade cancels the accepted bid of a cancelled transaction and closes
+// its request. A disputed offer does not go back to the order book; its owner
+// can post it again.
+func (s *DisputeService) closeTrade(ctx context.Context, tx *domain.Transaction) error {
+	log := s.log.With().Str("transaction_id", tx.ID.String()).Logger()
+
+	bid, err := s.bidRepo.GetByID(ctx, tx.BidID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load bid of cancelled transaction")
+		return err
+	}
+	if bid == nil {
+		return ErrBidNotFound
+	}
+	bid.Status = domain.BidStatusCancelled
+	if err := s.bidRepo.TransitionStatus(ctx, bid, domain.BidStatusAccepted); err != nil {
+		log.Error().Err(err).Msg("Failed to cancel bid of cancelled transaction")
+		return err
+	}
+
+	if _, err := s.requests.Transition(ctx, tx.RequestID, domain.RequestStatusMatched, domain.RequestStatusCancelled); err != nil {
+		log.Error().Err(err).Msg("Failed to close request of cancelled transaction")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mocks ---
+
+// MockDisputeRepository
+type MockDisputeRepository struct {
+	mock.Mock
+}
+
+var _ ports.DisputeRepository = (*MockDisputeRepository)(nil)
+
+func (m *MockDisputeRepository) Create(ctx context.Context, dispute *domain.Dispute) error {
+	args := m.Called(ctx, dispute)
+	return args.Error(0)
+}
+func (m *MockDisputeRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Dispute, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Dispute), args.Error(1)
+}
+func (m *MockDisputeRepository) GetOpenByTransactionID(ctx context.Context, transactionID uuid.UUID) (*domain.Dispute, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Dispute), args.Error(1)
+}
+func (m *MockDisputeRepository) Resolve(ctx context.Context, dispute *domain.Dispute) error {
+	args := m.Called(ctx, dispute)
+	return args.Error(0)
+}
+func (m *MockDisputeRepository) AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	args := m.Called(ctx, evidence)
+	return args.Error(0)
+}
+func (m *MockDisputeRepository) GetEvidence(ctx context.Context, disputeID uuid.UUID) ([]*domain.DisputeEvidence, error) {
+	args := m.Called(ctx, disputeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.DisputeEvidence), args.Error(1)
+}
+
+// --- Tests ---
+
+type disputeFixture struct {
+	repo        *MockDisputeRepository
+	bidRepo     *MockBidRepository
+	requestRepo *MockRequestRepository
+	txRepo      *MockTransactionRepository
+	bus         *MockEventBus
+	svc         *DisputeService
+}
+
+func newDisputeFixture() *disputeFixture {
+	nopLogger := zerolog.Nop()
+	f := &disputeFixture{
+		repo:        new(MockDisputeRepository),
+		bidRepo:     new(MockBidRepository),
+		requestRepo: new(MockRequestRepository),
+		txRepo:      new(MockTransactionRepository),
+		bus:         new(MockEventBus),
+	}
+	f.svc = NewDisputeService(
+		f.repo,
+		f.bidRepo,
+		NewTransactionService(f.txRepo, inlineTransactor{}, f.bus, &nopLogger),
+		NewRequestService(f.requestRepo, inlineTransactor{}, f.bus, &nopLogger),
+		inlineTransactor{},
+		f.bus,
+		&nopLogger,
+	)
+	return f
+}
+
+func TestDisputeService_Open(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	f := newDisputeFixture()
+
+	tx := &domain.Transaction{ID: uuid.New(), Status: domain.TxStatusSellerDepositReceived}
+	userID := uuid.New()
+
+	// 2. Define Expectations
+	f.txRepo.On("GetByID", mock.Anything, tx.ID).Return(tx, nil).Once()
+	f.txRepo.On("Transition", mock.Anything, tx, domain.TxStatusSellerDepositReceived, (*uuid.UUID)(nil)).Return(nil).Once()
+	f.bus.On("PublishKeyed", mock.Anything, "transaction:disputed", tx.ID.String(), mock.AnythingOfType("events.TransactionDisputed")).Return(nil).Once()
+	f.repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Dispute")).Return(nil).Once()
+	f.bus.On("Publish", mock.Anything, "dispute:opened", mock.AnythingOfType("events.DisputeOpened")).Return(nil).Once()
+
+	// 3. Run
+	dispute, err := f.svc.Open(ctx, tx.ID, userID, "Wrong amount")
+	if err != nil {
+		t.Fatalf("Open returned an error: %v", err)
+	}
+
+	// 4. Assert
+	if tx.Status != domain.TxStatusDisputed {
+		t.Errorf("Transaction status mismatch: got %s", tx.Status)
+	}
+	if dispute.OpenedByUserID != userID || dispute.Status != domain.DisputeStatusOpen {
+		t.Errorf("Dispute mismatch: %+v", dispute)
+	}
+	f.txRepo.AssertExpectations(t)
+	f.repo.AssertExpectations(t)
+	f.bus.AssertExpectations(t)
+}
+
+func TestDisputeService_Open_CreateFailure(t *testing.T) {
+	ctx := context.Background()
+	f := newDisputeFixture()
+
+	tx := &domain.Transaction{ID: uuid.New(), Status: domain.TxStatusSellerDepositReceived}
+	f.txRepo.On("GetByID", mock.Anything, tx.ID).Return(tx, nil).Once()
+	f.txRepo.On("Transition", mock.Anything, tx, domain.TxStatusSellerDepositReceived, (*uuid.UUID)(nil)).Return(nil).Once()
+	f.bus.On("PublishKeyed", mock.Anything, "transaction:disputed", tx.ID.String(), mock.AnythingOfType("events.TransactionDisputed")).Return(nil).Once()
+	f.repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Dispute")).Return(errors.New("db down")).Once()
+
+	// The error rolls the status change back with the dispute
+	if _, err := f.svc.Open(ctx, tx.ID, uuid.New(), "Wrong amount"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	f.bus.AssertNotCalled(t, "Publish", mock.Anything, "dispute:opened", mock.Anything)
+}
+
+func TestDisputeService_Resolve(t *testing.T) {
+	cases := []struct {
+		resolution domain.DisputeResolution
+		want       domain.TransactionStatus
+	}{
+		{domain.DisputeResolutionRefund, domain.TxStatusCancelled},
+		{domain.DisputeResolutionComplete, domain.TxStatusCompleted},
+		{domain.DisputeResolutionCancel, domain.TxStatusCancelled},
+	}
+	for _, c := range cases {
+		t.Run(string(c.resolution), func(t *testing.T) {
+			ctx := context.Background()
+			f := newDisputeFixture()
+
+			req := &domain.Request{ID: uuid.New(), Status: domain.RequestStatusMatched}
+			bid := &domain.Bid{ID: uuid.New(), RequestID: req.ID, Status: domain.BidStatusAccepted}
+			tx := &domain.Transaction{ID: uuid.New(), RequestID: req.ID, BidID: bid.ID, Status: domain.TxStatusDisputed}
+			dispute := &domain.Dispute{ID: uuid.New(), TransactionID: tx.ID, Status: domain.DisputeStatusOpen}
+			modID := uuid.New()
+
+			f.repo.On("GetByID", mock.Anything, dispute.ID).Return(dispute, nil).Once()
+			f.txRepo.On("GetByID", mock.Anything, tx.ID).Return(tx, nil).Once()
+			f.txRepo.On("Transition", mock.Anything, tx, domain.TxStatusDisputed, &modID).Return(nil).Once()
+			f.bus.On("PublishKeyed", mock.Anything, "transaction:"+string(c.want), tx.ID.String(), mock.Anything).Return(nil).Once()
+			if c.want == domain.TxStatusCancelled {
+				// The trade is gone, so its bid and request close with it
+				f.bidRepo.On("GetByID", mock.Anything, bid.ID).Return(bid, nil).Once()
+				f.bidRepo.On("TransitionStatus", mock.Anything, bid, domain.BidStatusAccepted).Return(nil).Once()
+				f.requestRepo.On("GetByID", mock.Anything, req.ID).Return(req, nil).Once()
+				f.requestRepo.On("TransitionStatus", mock.Anything, req, domain.RequestStatusMatched).Return(nil).Once()
+				f.bus.On("PublishKeyed", mock.Anything, "request:cancelled", req.ID.String(), mock.Anything).Return(nil).Once()
+			}
+			f.repo.On("Resolve", mock.Anything, dispute).Return(nil).Once()
+			f.bus.On("Publish", mock.Anything, "dispute:resolved", mock.AnythingOfType("events.DisputeResolved")).Return(nil).Once()
+
+			if _, err := f.svc.Resolve(ctx, dispute.ID, c.resolution, modID); err != nil {
+				t.Fatalf("Resolve returned an error: %v", err)
+			}
+			if tx.Status != c.want {
+				t.Errorf("Transaction status mismatch: got %s, want %s", tx.Status, c.want)
+			}
+			if dispute.Resolution == nil || *dispute.Resolution != c.resolution {
+				t.Errorf("Resolution not recorded: %v", dispute.Resolution)
+			}
+			if c.want == domain.TxStatusCancelled && (req.Status != domain.RequestStatusCancelled || bid.Status != domain.BidStatusCancelled) {
+				t.Errorf("Cascade mismatch: request=%s bid=%s", req.Status, bid.Status)
+			}
+			f.txRepo.AssertExpectations(t)
+			f.repo.AssertExpectations(t)
+			f.bidRepo.AssertExpectations(t)
+			f.requestRepo.AssertExpectations(t)
+			f.bus.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDisputeService_Resolve_AlreadyResolved(t *testing.T) {
+	ctx := context.Background()
+	f := newDisputeFixture()
+
+	dispute := &domain.Dispute{ID: uuid.New(), TransactionID: uuid.New(), Status: domain.DisputeStatusResolved}
+	f.repo.On("GetByID", mock.Anything, dispute.ID).Return(dispute, nil).Once()
+
+	if _, err := f.svc.Resolve(ctx, dispute.ID, domain.DisputeResolutionRefund, uuid.New()); !errors.Is(err, ports.ErrDisputeNotOpen) {
+		t.Fatalf("Expected ErrDisputeNotOpen, got: %v", err)
+	}
+	f.txRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	f.bus.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	onePercent = domain.NewDecimal(1, 2) // 0.01
+	oneHalf    = domain.NewDecimal(5, 1) // 0.5
+)
+
+// FeeEngine prices trades against the configured fee schedule.
+// The outcome is stored on the transaction, so later schedule changes
+// never touch trades that were already matched.
+type FeeEngine struct {
+	schedule   *domain.FeeSchedule
+	currencies *domain.CurrencyRegistry
+	repo       ports.FeeScheduleRepository
+	log        zerolog.Logger
+}
+
+// NewFeeEngine creates a new fee engine for the given schedule.
+// Fees are rounded to the decimals of currencies.
+func NewFeeEngine(
+	schedule *domain.FeeSchedule,
+	currencies *domain.CurrencyRegistry,
+	repo ports.FeeScheduleRepository,
+	baseLogger *zerolog.Logger,
+) *FeeEngine {
+	return &FeeEngine{
+		schedule:   schedule,
+		currencies: currencies,
+		repo:       repo,
+		log:        baseLogger.With().Str("component", "fee_engine").Logger(),
+	}
+}
+
+// Register records the schedule version, so every version trades refer to is kept.
+// Call it once at startup. It returns ports.ErrFeeScheduleChanged if the
+// configured rules differ from an earlier registration of the same version.
+func (e *FeeEngine) Register(ctx context.Context) error {
+	if err := e.repo.Register(ctx, e.schedule); err != nil {
+		return err
+	}
+	e.log.Info().Str("version", e.schedule.Version).Int("rules", len(e.schedule.Rules)).Msg("Fee schedule registered")
+	return nil
+}
+
+// Quote computes what each party of a trade on req receives after fees.
+// Each party pays the fee of their own verification level.
+func (e *FeeEngine) Quote(
+	req *domain.Request,
+	sellerLevel domain.UserVerificationStatus,
+	buyerLevel domain.UserVerificationStatus,
+) *domain.FeeQuote {
+	sellerRule := e.schedule.RuleFor(req.BaseCurrency, req.QuoteCurrency, sellerLevel)
+	buyerRule := e.schedule.RuleFor(req.BaseCurrency, req.QuoteCurrency, buyerLevel)
+	return &domain.FeeQuote{
+		ScheduleVersion: e.schedule.Version,
+		Seller:          e.legPayout(sellerRule, req.PayoutFor(e.currencies, domain.LegSeller)),
+		Buyer:           e.legPayout(buyerRule, req.PayoutFor(e.currencies, domain.LegBuyer)),
+	}
+}
+
+// legPayout applies one fee rule to a gross payout.
+// The fee is rounded to the currency and never exceeds the gross amount.
+func (e *FeeEngine) legPayout(rule domain.FeeRule, gross domain.Money) domain.Payout {
+	percent := rule.Percent.Add(rule.SpreadPercent.Mul(oneHalf))
+	fee := gross.Amount.Mul(percent).Mul(onePercent)
+	if flat, ok := rule.Flat[gross.Currency]; ok {
+		fee = fee.Add(flat)
+	}
+	fee = fee.Round(e.currencies.Decimals(gross.Currency))
+	if fee.Cmp(gross.Amount) > 0 {
+		fee = gross.Amount
+	}
+	return domain.Payout{
+		Gross: gross,
+		Fee:   domain.Money{Amount: fee, Currency: gross.Currency},
+		Net:   domain.Money{Amount: gross.Amount.Sub(fee), Currency: gross.Currency},
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mocks ---
+
+// MockFeeScheduleRepository
+type MockFeeScheduleRepository struct {
+	mock.Mock
+}
+
+var _ ports.FeeScheduleRepository = (*MockFeeScheduleRepository)(nil)
+
+func (m *MockFeeScheduleRepository) Register(ctx context.Context, schedule *domain.FeeSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+func (m *MockFeeScheduleRepository) GetByVersion(ctx context.Context, version string) (*domain.FeeSchedule, error) {
+	args := m.Called(ctx, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FeeSchedule), args.Error(1)
+}
+
+// --- Tests ---
+
+func testCurrencies(t *testing.T) *domain.CurrencyRegistry {
+	t.Helper()
+	currencies, err := domain.NewCurrencyRegistry(
+		[]domain.Currency{
+			{Code: "EUR", Name: "Euro", Decimals: 2},
+			{Code: "IRR", Name: "Iranian Rial", Decimals: 0},
+			{Code: "USD", Name: "US Dollar", Decimals: 2},
+		},
+		[]domain.CurrencyPair{{Base: "EUR", Quote: "IRR"}, {Base: "EUR", Quote: "USD"}},
+	)
+	if err != nil {
+		t.Fatalf("NewCurrencyRegistry returned an error: %v", err)
+	}
+	return currencies
+}
+
+func testFeeSchedule() *domain.FeeSchedule {
+	return &domain.FeeSchedule{
+		Version: "v1",
+		Default: domain.FeeRule{Percent: domain.MustParseDecimal("1")},
+		Rules: []domain.FeeRule{
+			{
+				Base:          "EUR",
+				Quote:         "IRR",
+				Percent:       domain.MustParseDecimal("0.4"),
+				SpreadPercent: domain.MustParseDecimal("0.2"),
+				Flat:          map[string]domain.Decimal{"EUR": domain.MustParseDecimal("1")},
+			},
+			{Level: domain.VerificationLevel1, Percent: domain.MustParseDecimal("0.5")},
+		},
+	}
+}
+
+func TestFeeEngine_Quote(t *testing.T) {
+	// 1. Setup
+	nopLogger := zerolog.Nop()
+	engine := NewFeeEngine(testFeeSchedule(), testCurrencies(t), new(MockFeeScheduleRepository), &nopLogger)
+
+	req := &domain.Request{
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "IRR",
+		BaseAmount:    domain.MustParseDecimal("1000"),
+		ExchangeRate:  domain.MustParseDecimal("615271.1"),
+	}
+
+	// 2. Run
+	q := engine.Quote(req, domain.VerificationLevel1, domain.VerificationLevel1)
+
+	// 3. Assert: the pair rule beats the level rule, 0.4% + half of 0.2% per leg
+	if q.ScheduleVersion != "v1" {
+		t.Errorf("ScheduleVersion: got %q, want v1", q.ScheduleVersion)
+	}
+	// Seller receives 615271100 IRR; 0.5% is 3076355.5, rounded to whole Rials
+	checkPayout(t, "seller", q.Seller, "615271100", "3076356", "612194744", "IRR")
+	// Buyer receives 1000 EUR; 0.5% is 5, plus the 1 EUR flat fee
+	checkPayout(t, "buyer", q.Buyer, "1000", "6", "994", "EUR")
+}
+
+func TestFeeEngine_Quote_PerLevel(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	engine := NewFeeEngine(testFeeSchedule(), testCurrencies(t), new(MockFeeScheduleRepository), &nopLogger)
+
+	req := &domain.Request{
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "USD",
+		BaseAmount:    domain.MustParseDecimal("100"),
+		ExchangeRate:  domain.MustParseDecimal("1.0833"),
+	}
+
+	q := engine.Quote(req, domain.VerificationLevel1, domain.VerificationPending)
+
+	// The seller gets the level_1 rate of 0.5%, the buyer the 1% default
+	checkPayout(t, "seller", q.Seller, "108.33", "0.54", "107.79", "USD")
+	checkPayout(t, "buyer", q.Buyer, "100", "1", "99", "EUR")
+}
+
+func TestFeeEngine_Quote_FeeNeverExceedsGross(t *testing.T) {
+	nopLogger := zerolog.Nop()
+	schedule := &domain.FeeSchedule{
+		Version: "v1",
+		Default: domain.FeeRule{Flat: map[string]domain.Decimal{"EUR": domain.MustParseDecimal("50")}},
+	}
+	engine := NewFeeEngine(schedule, testCurrencies(t), new(MockFeeScheduleRepository), &nopLogger)
+
+	req := &domain.Request{
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "USD",
+		BaseAmount:    domain.MustParseDecimal("20"),
+		ExchangeRate:  domain.MustParseDecimal("1"),
+	}
+
+	q := engine.Quote(req, domain.VerificationLevel1, domain.VerificationLevel1)
+
+	checkPayout(t, "buyer", q.Buyer, "20", "20", "0", "EUR")
+	checkPayout(t, "seller", q.Seller, "20", "0", "20", "USD")
+}
+
+func TestFeeEngine_Register(t *testing.T) {
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockRepo := new(MockFeeScheduleRepository)
+	schedule := testFeeSchedule()
+	engine := NewFeeEngine(schedule, testCurrencies(t), mockRepo, &nopLogger)
+
+	mockRepo.On("Register", mock.Anything, schedule).Return(ports.ErrFeeScheduleChanged).Once()
+
+	if err := engine.Register(ctx); err != ports.ErrFeeScheduleChanged {
+		t.Errorf("Register: got %v, want ErrFeeScheduleChanged", err)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func checkPayout(t *testing.T, name string, p domain.Payout, gross, fee, net, currency string) {
+	t.Helper()
+	for _, c := range []struct {
+		field string
+		got   domain.Money
+		want  string
+	}{{"gross", p.Gross, gross}, {"fee", p.Fee, fee}, {"net", p.Net, net}} {
+		if !c.got.Amount.Equal(domain.MustParseDecimal(c.want)) || c.got.Currency != currency {
+			t.Errorf("%s %s: got %s, want %s %s", name, c.field, c.got, c.want, currency)
+		}
+	}
+}
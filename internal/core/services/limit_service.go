@@ -0,0 +1,160 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	dailyLimitWindow   = 24 * time.Hour
+	monthlyLimitWindow = 30 * 24 * time.Hour
+)
+
+// LimitService enforces the trading limits of each verification level.
+// Every check returns *domain.LimitError when a limit is hit;
+// any other error means the check itself failed.
+//
+// Every check locks the user it checks until the surrounding transaction
+// ends. Run the check and the save it guards in one transaction, so two
+// concurrent checks of a user cannot both pass on the same volume.
+type LimitService struct {
+	limits      *domain.LimitTable
+	userRepo    ports.UserRepository
+	requestRepo ports.RequestRepository
+	txRepo      ports.TransactionRepository
+	log         zerolog.Logger
+}
+
+// NewLimitService creates a new trading limit service.
+func NewLimitService(
+	limits *domain.LimitTable,
+	userRepo ports.UserRepository,
+	requestRepo ports.RequestRepository,
+	txRepo ports.TransactionRepository,
+	baseLogger *zerolog.Logger,
+) *LimitService {
+	return &LimitService{
+		limits:      limits,
+		userRepo:    userRepo,
+		requestRepo: requestRepo,
+		txRepo:      txRepo,
+		log:         baseLogger.With().Str("component", "limit_service").Logger(),
+	}
+}
+
+// CheckNewRequest checks that user may publish req:
+// the open request count, the size of the request and the remaining volume.
+func (s *LimitService) CheckNewRequest(ctx context.Context, user *domain.User, req *domain.Request) error {
+	if err := s.Lock(ctx, user); err != nil {
+		return err
+	}
+	limits, err := s.limitsOf(user)
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxOpenRequests > 0 {
+		open, err := s.requestRepo.CountOpenByUser(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if open >= limits.MaxOpenRequests {
+			return s.reject(user, fmt.Sprintf(
+				"You already have %d open requests, the most your verification level allows. Cancel one or wait for it to be matched.",
+				open))
+		}
+	}
+	return s.checkAmount(ctx, user, limits, req)
+}
+
+// CheckTrade checks that user may take part in a trade on req, as bidder or owner.
+func (s *LimitService) CheckTrade(ctx context.Context, user *domain.User, req *domain.Request) error {
+	if err := s.Lock(ctx, user); err != nil {
+		return err
+	}
+	limits, err := s.limitsOf(user)
+	if err != nil {
+		return err
+	}
+	return s.checkAmount(ctx, user, limits, req)
+}
+
+// Lock takes the limit locks of users for the surrounding transaction.
+// A caller checking several users locks them all at once first, so that
+// two transactions never wait on each other.
+func (s *LimitService) Lock(ctx context.Context, users ...*domain.User) error {
+	ids := make([]uuid.UUID, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	return s.userRepo.LockForUpdate(ctx, ids...)
+}
+
+// limitsOf returns the limits of the user's level, or a LimitError if the level may not trade.
+func (s *LimitService) limitsOf(user *domain.User) (domain.TradingLimits, error) {
+	limits, ok := s.limits.For(user.VerificationStatus)
+	if !ok {
+		return limits, s.reject(user, "Trading is not available at your verification level.")
+	}
+	return limits, nil
+}
+
+// checkAmount checks the base amount of req against the per-transaction
+// maximum and the rolling volumes of its currency.
+func (s *LimitService) checkAmount(ctx context.Context, user *domain.User, limits domain.TradingLimits, req *domain.Request) error {
+	currencyLimits, ok := limits.Currencies[req.BaseCurrency]
+	if !ok {
+		return nil
+	}
+	amount := req.BaseAmount
+
+	if perTx := currencyLimits.PerTransaction; !perTx.IsZero() && amount.Cmp(perTx) > 0 {
+		return s.reject(user, fmt.Sprintf(
+			"The most you can trade at once at your verification level is %s %s.", perTx, req.BaseCurrency))
+	}
+
+	windows := []struct {
+		name   string
+		limit  domain.Decimal
+		window time.Duration
+	}{
+		{"daily", currencyLimits.Daily, dailyLimitWindow},
+		{"30-day", currencyLimits.Monthly, monthlyLimitWindow},
+	}
+	now := time.Now()
+	for _, w := range windows {
+		if w.limit.IsZero() {
+			continue
+		}
+		used, err := s.txRepo.TradedVolume(ctx, user.ID, req.BaseCurrency, now.Add(-w.window))
+		if err != nil {
+			return err
+		}
+		if used.Add(amount).Cmp(w.limit) > 0 {
+			left := w.limit.Sub(used)
+			if left.Sign() < 0 {
+				left = domain.Decimal{}
+			}
+			return s.reject(user, fmt.Sprintf(
+				"This trade would take you over your %s limit of %s %s. You can still trade %s %s.",
+				w.name, w.limit, req.BaseCurrency, left, req.BaseCurrency))
+		}
+	}
+	return nil
+}
+
+// reject logs and builds a LimitError.
+func (s *LimitService) reject(user *domain.User, message string) error {
+	s.log.Info().
+		Str("user_id", user.ID.String()).
+		Str("level", string(user.VerificationStatus)).
+		Str("reason", message).
+		Msg("Trading limit hit")
+	return &domain.LimitError{Level: user.VerificationStatus, Message: message}
+}
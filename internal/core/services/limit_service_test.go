@@ -0,0 +1,163 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+)
+
+func testLimitTable(t *testing.T) *domain.LimitTable {
+	t.Helper()
+	table, err := domain.NewLimitTable([]domain.TradingLimits{{
+		Level:           domain.VerificationLevel1,
+		MaxOpenRequests: 2,
+		Currencies: map[string]domain.CurrencyLimits{
+			"EUR": {
+				PerTransaction: domain.MustParseDecimal("5000"),
+				Daily:          domain.MustParseDecimal("8000"),
+				Monthly:        domain.MustParseDecimal("20000"),
+			},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("NewLimitTable returned an error: %v", err)
+	}
+	return table
+}
+
+func eurRequest(amount string) *domain.Request {
+	return &domain.Request{
+		ID:            uuid.New(),
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "IRR",
+		BaseAmount:    domain.MustParseDecimal(amount),
+		ExchangeRate:  domain.MustParseDecimal("615000"),
+	}
+}
+
+// limitMessage returns the message of a LimitError, failing the test on any other error.
+func limitMessage(t *testing.T, err error) string {
+	t.Helper()
+	var limitErr *domain.LimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("Expected a LimitError, got: %v", err)
+	}
+	return limitErr.Message
+}
+
+// lockingUserRepo returns a user repository whose locks always succeed.
+func lockingUserRepo() *MockUserRepository {
+	repo := new(MockUserRepository)
+	repo.On("LockForUpdate", mock.Anything, mock.Anything).Return(nil)
+	return repo
+}
+
+func TestLimitService_CheckNewRequest(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockReqRepo := new(MockRequestRepository)
+	mockTxRepo := new(MockTransactionRepository)
+	svc := NewLimitService(testLimitTable(t), lockingUserRepo(), mockReqRepo, mockTxRepo, &nopLogger)
+
+	user := &domain.User{ID: uuid.New(), VerificationStatus: domain.VerificationLevel1}
+	mockTxRepo.On("TradedVolume", mock.Anything, user.ID, "EUR", mock.Anything).Return(domain.MustParseDecimal("3000"), nil)
+
+	// 2. Within all limits
+	mockReqRepo.On("CountOpenByUser", mock.Anything, user.ID).Return(1, nil).Once()
+	if err := svc.CheckNewRequest(ctx, user, eurRequest("4000")); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	// 3. Too many open requests
+	mockReqRepo.On("CountOpenByUser", mock.Anything, user.ID).Return(2, nil).Once()
+	msg := limitMessage(t, svc.CheckNewRequest(ctx, user, eurRequest("100")))
+	if !strings.Contains(msg, "2 open requests") {
+		t.Errorf("Unexpected message: %q", msg)
+	}
+
+	mockReqRepo.AssertExpectations(t)
+}
+
+func TestLimitService_CheckTrade(t *testing.T) {
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockTxRepo := new(MockTransactionRepository)
+	svc := NewLimitService(testLimitTable(t), lockingUserRepo(), new(MockRequestRepository), mockTxRepo, &nopLogger)
+
+	user := &domain.User{ID: uuid.New(), VerificationStatus: domain.VerificationLevel1}
+	mockTxRepo.On("TradedVolume", mock.Anything, user.ID, "EUR", mock.Anything).Return(domain.MustParseDecimal("6000"), nil)
+
+	// Above the per-transaction maximum; the volume is not even looked at
+	msg := limitMessage(t, svc.CheckTrade(ctx, user, eurRequest("5000.01")))
+	if !strings.Contains(msg, "5000 EUR") {
+		t.Errorf("Unexpected per-transaction message: %q", msg)
+	}
+
+	// 6000 already traded today, 2000 left
+	msg = limitMessage(t, svc.CheckTrade(ctx, user, eurRequest("2500")))
+	if !strings.Contains(msg, "daily limit of 8000 EUR") || !strings.Contains(msg, "still trade 2000 EUR") {
+		t.Errorf("Unexpected daily message: %q", msg)
+	}
+	if err := svc.CheckTrade(ctx, user, eurRequest("2000")); err != nil {
+		t.Errorf("Expected exactly the remaining volume to pass, got: %v", err)
+	}
+
+	// Currencies without limits are not checked
+	unlimited := &domain.Request{BaseCurrency: "USD", QuoteCurrency: "IRR", BaseAmount: domain.MustParseDecimal("1000000")}
+	if err := svc.CheckTrade(ctx, user, unlimited); err != nil {
+		t.Errorf("Expected unlisted currency to pass, got: %v", err)
+	}
+}
+
+func TestLimitService_CheckTrade_LevelWithoutLimits(t *testing.T) {
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	svc := NewLimitService(testLimitTable(t), lockingUserRepo(), new(MockRequestRepository), new(MockTransactionRepository), &nopLogger)
+
+	user := &domain.User{ID: uuid.New(), VerificationStatus: "level_9"}
+	msg := limitMessage(t, svc.CheckTrade(ctx, user, eurRequest("10")))
+	if !strings.Contains(msg, "not available") {
+		t.Errorf("Unexpected message: %q", msg)
+	}
+}
+
+func TestLimitService_CheckTrade_RepoError(t *testing.T) {
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockTxRepo := new(MockTransactionRepository)
+	svc := NewLimitService(testLimitTable(t), lockingUserRepo(), new(MockRequestRepository), mockTxRepo, &nopLogger)
+
+	user := &domain.User{ID: uuid.New(), VerificationStatus: domain.VerificationLevel1}
+	dbErr := errors.New("db down")
+	mockTxRepo.On("TradedVolume", mock.Anything, user.ID, "EUR", mock.Anything).Return(domain.Decimal{}, dbErr)
+
+	if err := svc.CheckTrade(ctx, user, eurRequest("10")); !errors.Is(err, dbErr) {
+		t.Errorf("Expected the repository error, got: %v", err)
+	}
+}
+
+func TestLimitService_CheckTrade_LocksUser(t *testing.T) {
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockUserRepo := new(MockUserRepository)
+	mockTxRepo := new(MockTransactionRepository)
+	svc := NewLimitService(testLimitTable(t), mockUserRepo, new(MockRequestRepository), mockTxRepo, &nopLogger)
+
+	user := &domain.User{ID: uuid.New(), VerificationStatus: domain.VerificationLevel1}
+	lockErr := errors.New("lock timeout")
+	mockUserRepo.On("LockForUpdate", mock.Anything, []uuid.UUID{user.ID}).Return(lockErr).Once()
+
+	// Without the lock the volume is not read at all
+	if err := svc.CheckTrade(ctx, user, eurRequest("10")); !errors.Is(err, lockErr) {
+		t.Errorf("Expected the lock error, got: %v", err)
+	}
+	mockTxRepo.AssertNotCalled(t, "TradedVolume", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertExpectations(t)
+}
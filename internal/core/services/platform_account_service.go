@@ -0,0 +1,48 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// PlatformAccountService picks the company account a party deposits into.
+type PlatformAccountService struct {
+	repo ports.PlatformAccountRepository
+	log  zerolog.Logger
+
+	mu   sync.Mutex
+	next map[string]int // Rotation cursor per currency
+}
+
+// NewPlatformAccountService creates a new platform account picker.
+func NewPlatformAccountService(repo ports.PlatformAccountRepository, baseLogger *zerolog.Logger) *PlatformAccountService {
+	return &PlatformAccountService{
+		repo: repo,
+		log:  baseLogger.With().Str("component", "platform_account_service").Logger(),
+		next: make(map[string]int),
+	}
+}
+
+// PickDepositAccount returns an active account for the currency, rotating
+// round-robin when several are active. It returns nil if there is none.
+func (s *PlatformAccountService) PickDepositAccount(ctx context.Context, currency string) (*domain.PlatformAccount, error) {
+	accounts, err := s.repo.GetActiveByCurrency(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		s.log.Warn().Str("currency", currency).Msg("No active platform account for currency")
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	i := s.next[currency] % len(accounts)
+	s.next[currency] = i + 1
+	s.mu.Unlock()
+
+	return accounts[i], nil
+}
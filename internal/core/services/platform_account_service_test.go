@@ -0,0 +1,96 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mocks ---
+
+// MockPlatformAccountRepository
+type MockPlatformAccountRepository struct {
+	mock.Mock
+}
+
+var _ ports.PlatformAccountRepository = (*MockPlatformAccountRepository)(nil)
+
+func (m *MockPlatformAccountRepository) Create(ctx context.Context, acct *domain.PlatformAccount) error {
+	args := m.Called(ctx, acct)
+	return args.Error(0)
+}
+func (m *MockPlatformAccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PlatformAccount, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PlatformAccount), args.Error(1)
+}
+func (m *MockPlatformAccountRepository) GetActiveByCurrency(ctx context.Context, currency string) ([]*domain.PlatformAccount, error) {
+	args := m.Called(ctx, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PlatformAccount), args.Error(1)
+}
+func (m *MockPlatformAccountRepository) List(ctx context.Context) ([]*domain.PlatformAccount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PlatformAccount), args.Error(1)
+}
+func (m *MockPlatformAccountRepository) Update(ctx context.Context, acct *domain.PlatformAccount) error {
+	args := m.Called(ctx, acct)
+	return args.Error(0)
+}
+
+// --- Tests ---
+
+func TestPlatformAccountService_PickDepositAccount_Rotates(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockRepo := new(MockPlatformAccountRepository)
+	svc := NewPlatformAccountService(mockRepo, &nopLogger)
+
+	a := &domain.PlatformAccount{ID: uuid.New(), Currency: "EUR", IsActive: true}
+	b := &domain.PlatformAccount{ID: uuid.New(), Currency: "EUR", IsActive: true}
+	c := &domain.PlatformAccount{ID: uuid.New(), Currency: "IRR", IsActive: true}
+	mockRepo.On("GetActiveByCurrency", mock.Anything, "EUR").Return([]*domain.PlatformAccount{a, b}, nil)
+	mockRepo.On("GetActiveByCurrency", mock.Anything, "IRR").Return([]*domain.PlatformAccount{c}, nil)
+
+	// 2. Run & Assert: EUR alternates, IRR keeps its own cursor
+	want := []*domain.PlatformAccount{a, b, a}
+	for i, w := range want {
+		got, err := svc.PickDepositAccount(ctx, "EUR")
+		if err != nil {
+			t.Fatalf("PickDepositAccount returned an error: %v", err)
+		}
+		if got != w {
+			t.Errorf("Pick %d: got %v, want %v", i, got.ID, w.ID)
+		}
+	}
+	if got, _ := svc.PickDepositAccount(ctx, "IRR"); got != c {
+		t.Errorf("IRR pick mismatch: got %v", got)
+	}
+}
+
+func TestPlatformAccountService_PickDepositAccount_None(t *testing.T) {
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockRepo := new(MockPlatformAccountRepository)
+	svc := NewPlatformAccountService(mockRepo, &nopLogger)
+
+	mockRepo.On("GetActiveByCurrency", mock.Anything, "USD").Return(nil, nil).Once()
+
+	got, err := svc.PickDepositAccount(ctx, "USD")
+	if err != nil || got != nil {
+		t.Fatalf("Expected (nil, nil), got (%v, %v)", got, err)
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ErrRequestNotFound is returned when the request to change does not exist.
+var ErrRequestNotFound = errors.New("request not found")
+
+// RequestService owns request status changes: it closes requests once their
+// trade settles and moves them for cancellations.
+type RequestService struct {
+	repo       ports.RequestRepository
+	transactor ports.Transactor
+	bus        ports.EventBus
+	log        zerolog.Logger
+}
+
+// NewRequestService creates a new request lifecycle service.
+func NewRequestService(
+	repo ports.RequestRepository,
+	transactor ports.Transactor,
+	bus ports.EventBus,
+	baseLogger *zerolog.Logger,
+) *RequestService {
+	return &RequestService{
+		repo:       repo,
+		transactor: transactor,
+		bus:        bus,
+		log:        baseLogger.With().Str("component", "request_service").Logger(),
+	}
+}
+
+// SetStatus saves a new request status together with its "request:<status>"
+// event, which carries the updated request.
+func (s *RequestService) SetStatus(ctx context.Context, id uuid.UUID, to domain.RequestStatus) (*domain.Request, error) {
+	log := s.log.With().Str("request_id", id.String()).Str("to", string(to)).Logger()
+
+	req, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load request")
+		return nil, err
+	}
+	if req == nil {
+		return nil, ErrRequestNotFound
+	}
+	if req.Status == to {
+		return req, nil
+	}
+
+	req.Status = to
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, req); err != nil {
+			return err
+		}
+		return events.PublishKeyed(ctx, s.bus, req.ID.String(), events.RequestStatusChanged(req))
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msg("Request status changed")
+	return req, nil
+}
+
+// Transition moves a request from one status to another, saving its
+// "request:<status>" event with it. It returns
+// ports.ErrRequestStatusChanged if the request is no longer in 'from'.
+func (s *RequestService) Transition(ctx context.Context, id uuid.UUID, from, to domain.RequestStatus) (*domain.Request, error) {
+	log := s.log.With().Str("request_id", id.String()).Str("from", string(from)).Str("to", string(to)).Logger()
+
+	req, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load request")
+		return nil, err
+	}
+	if req == nil {
+		return nil, ErrRequestNotFound
+	}
+	if req.Status != from {
+		return nil, ports.ErrRequestStatusChanged
+	}
+
+	req.Status = to
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.TransitionStatus(ctx, req, from); err != nil {
+			return err
+		}
+		return events.PublishKeyed(ctx, s.bus, req.ID.String(), events.RequestStatusChanged(req))
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msg("Request status changed")
+	return req, nil
+}
+
+// HandleTransactionCompleted handles events.TransactionCompleted.
+// A completed trade completes its request.
+func (s *RequestService) HandleTransactionCompleted(ctx context.Context, event events.TransactionCompleted) error {
+	_, err := s.SetStatus(ctx, event.Transaction.RequestID, domain.RequestStatusCompleted)
+	return err
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mocks ---
+
+// MockRequestRepository
+type MockRequestRepository struct {
+	mock.Mock
+}
+
+var _ ports.RequestRepository = (*MockRequestRepository)(nil)
+
+func (m *MockRequestRepository) Create(ctx context.Context, req *domain.Request) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+func (m *MockRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Request, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Request), args.Error(1)
+}
+func (m *MockRequestRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Request, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Request), args.Error(1)
+}
+func (m *MockRequestRepository) Update(ctx context.Context, req *domain.Request) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+func (m *MockRequestRepository) TransitionStatus(ctx context.Context, req *domain.Request, from domain.RequestStatus) error {
+	args := m.Called(ctx, req, from)
+	return args.Error(0)
+}
+func (m *MockRequestRepository) SetChannelMessageID(ctx context.Context, id uuid.UUID, messageID int64) error {
+	args := m.Called(ctx, id, messageID)
+	return args.Error(0)
+}
+func (m *MockRequestRepository) ListOpen(ctx context.Context, filter ports.RequestFilter, limit, offset int) ([]*domain.Request, int, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*domain.Request), args.Int(1), args.Error(2)
+}
+func (m *MockRequestRepository) ExpireOpen(ctx context.Context, openedBefore time.Time, limit int) ([]*domain.Request, error) {
+	args := m.Called(ctx, openedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Request), args.Error(1)
+}
+func (m *MockRequestRepository) ListOpenCurrencies(ctx context.Context) ([]string, []string, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]string), args.Get(1).([]string), args.Error(2)
+}
+func (m *MockRequestRepository) CountOpenByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+// --- Tests ---
+
+func TestRequestService_HandleTransactionCompleted(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockRepo := new(MockRequestRepository)
+	mockBus := new(MockEventBus)
+	svc := NewRequestService(mockRepo, inlineTransactor{}, mockBus, &nopLogger)
+
+	req := &domain.Request{ID: uuid.New(), Status: domain.RequestStatusMatched}
+	tx := &domain.Transaction{ID: uuid.New(), RequestID: req.ID, Status: domain.TxStatusCompleted}
+
+	// 2. Define Expectations
+	mockRepo.On("GetByID", mock.Anything, req.ID).Return(req, nil).Once()
+	mockRepo.On("Update", mock.Anything, req).Return(nil).Once()
+	mockBus.On("PublishKeyed", mock.Anything, "request:completed", req.ID.String(), mock.AnythingOfType("events.RequestCompleted")).Return(nil).Once()
+
+	// 3. Run
+	if err := svc.HandleTransactionCompleted(ctx, events.TransactionCompleted{Transaction: *tx}); err != nil {
+		t.Fatalf("HandleTransactionCompleted returned an error: %v", err)
+	}
+
+	// 4. Assert
+	if req.Status != domain.RequestStatusCompleted {
+		t.Errorf("Status mismatch: got %s", req.Status)
+	}
+	mockRepo.AssertExpectations(t)
+	mockBus.AssertExpectations(t)
+}
+
+func TestRequestService_SetStatus_Unchanged(t *testing.T) {
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockRepo := new(MockRequestRepository)
+	mockBus := new(MockEventBus)
+	svc := NewRequestService(mockRepo, inlineTransactor{}, mockBus, &nopLogger)
+
+	req := &domain.Request{ID: uuid.New(), Status: domain.RequestStatusCompleted}
+	mockRepo.On("GetByID", mock.Anything, req.ID).Return(req, nil).Once()
+
+	if _, err := svc.SetStatus(ctx, req.ID, domain.RequestStatusCompleted); err != nil {
+		t.Fatalf("SetStatus returned an error: %v", err)
+	}
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockBus.AssertNotCalled(t, "PublishKeyed", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ErrTransactionNotFound is returned when the transaction to move does not exist.
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// ErrTransactionDisputed is returned when something other than a moderator
+// tries to move a disputed transaction. Disputes freeze the trade until resolved.
+var ErrTransactionDisputed = errors.New("transaction is frozen by a dispute")
+
+// IllegalTransitionError is returned when a move is not in the transition table.
+type IllegalTransitionError struct {
+	From domain.TransactionStatus
+	To   domain.TransactionStatus
+}
+
+func (e *IllegalTransitionError) Error() string {
+	return fmt.Sprintf("illegal transaction transition: %s -> %s", e.From, e.To)
+}
+
+// transactionTransitions is the two-leg escrow lifecycle.
+// A status that is not a key here is final.
+var transactionTransitions = map[domain.TransactionStatus][]domain.TransactionStatus{
+	domain.TxStatusPendingDeposits: {
+		domain.TxStatusSellerDepositReceived,
+		domain.TxStatusBuyerDepositReceived,
+		domain.TxStatusDisputed,
+		domain.TxStatusCancelled,
+	},
+	domain.TxStatusSellerDepositReceived: {
+		domain.TxStatusPendingPayouts, // The buyer's deposit arrived too
+		domain.TxStatusDisputed,
+		domain.TxStatusCancelled,
+	},
+	domain.TxStatusBuyerDepositReceived: {
+		domain.TxStatusPendingPayouts, // The seller's deposit arrived too
+		domain.TxStatusDisputed,
+		domain.TxStatusCancelled,
+	},
+	domain.TxStatusPendingPayouts: {
+		domain.TxStatusSellerPayoutSent,
+		domain.TxStatusBuyerPayoutSent,
+		domain.TxStatusDisputed,
+	},
+	domain.TxStatusSellerPayoutSent: {
+		domain.TxStatusCompleted, // The buyer was paid too
+		domain.TxStatusDisputed,
+	},
+	domain.TxStatusBuyerPayoutSent: {
+		domain.TxStatusCompleted, // The seller was paid too
+		domain.TxStatusDisputed,
+	},
+	domain.TxStatusDisputed: {
+		domain.TxStatusCompleted,
+		domain.TxStatusCancelled,
+	},
+}
+
+// CanTransition reports whether the lifecycle allows moving from one status to another.
+func CanTransition(from, to domain.TransactionStatus) bool {
+	for _, next := range transactionTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// DepositTarget returns the status a transaction moves to once the deposit of
+// the given leg is confirmed. ok is false if that deposit is not awaited in 'from'.
+func DepositTarget(from domain.TransactionStatus, leg domain.TransactionLeg) (to domain.TransactionStatus, ok bool) {
+	switch {
+	case from == domain.TxStatusPendingDeposits && leg == domain.LegSeller:
+		return domain.TxStatusSellerDepositReceived, true
+	case from == domain.TxStatusPendingDeposits && leg == domain.LegBuyer:
+		return domain.TxStatusBuyerDepositReceived, true
+	case from == domain.TxStatusBuyerDepositReceived && leg == domain.LegSeller,
+		from == domain.TxStatusSellerDepositReceived && leg == domain.LegBuyer:
+		return domain.TxStatusPendingPayouts, true
+	}
+	return "", false
+}
+
+// PayoutTarget returns the status a transaction moves to once the payout of
+// the given leg is sent. The second payout completes the trade.
+// ok is false if that payout is not due in 'from'.
+func PayoutTarget(from domain.TransactionStatus, leg domain.TransactionLeg) (to domain.TransactionStatus, ok bool) {
+	switch {
+	case from == domain.TxStatusPendingPayouts && leg == domain.LegSeller:
+		return domain.TxStatusSellerPayoutSent, true
+	case from == domain.TxStatusPendingPayouts && leg == domain.LegBuyer:
+		return domain.TxStatusBuyerPayoutSent, true
+	case from == domain.TxStatusBuyerPayoutSent && leg == domain.LegSeller,
+		from == domain.TxStatusSellerPayoutSent && leg == domain.LegBuyer:
+		return domain.TxStatusCompleted, true
+	}
+	return "", false
+}
+
+// TransactionService owns the transaction lifecycle.
+// Every status change in the application goes through Transition.
+type TransactionService struct {
+	repo       ports.TransactionRepository
+	transactor ports.Transactor
+	bus        ports.EventBus
+	log        zerolog.Logger
+}
+
+// NewTransactionService creates a new transaction lifecycle service.
+func NewTransactionService(
+	repo ports.TransactionRepository,
+	transactor ports.Transactor,
+	bus ports.EventBus,
+	baseLogger *zerolog.Logger,
+) *TransactionService {
+	return &TransactionService{
+		repo:       repo,
+		transactor: transactor,
+		bus:        bus,
+		log:        baseLogger.With().Str("component", "transaction_service").Logger(),
+	}
+}
+
+// Transition moves a transaction to a new status.
+// moderatorID is the moderator making the move, or nil for system and customer moves.
+// It returns *IllegalTransitionError for moves outside the lifecycle,
+// ErrTransactionDisputed for system moves out of a dispute and
+// ports.ErrTransactionStatusChanged if another move won the race.
+// The new status and its "transaction:<status>" event, carrying the updated
+// transaction, are saved in one database transaction.
+func (s *TransactionService) Transition(
+	ctx context.Context,
+	txID uuid.UUID,
+	to domain.TransactionStatus,
+	moderatorID *uuid.UUID,
+) (*domain.Transaction, error) {
+	log := s.log.With().Str("transaction_id", txID.String()).Str("to", string(to)).Logger()
+
+	tx, err := s.repo.GetByID(ctx, txID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load transaction")
+		return nil, err
+	}
+	if tx == nil {
+		return nil, ErrTransactionNotFound
+	}
+
+	from := tx.Status
+	if !CanTransition(from, to) {
+		log.Warn().Str("from", string(from)).Msg("Rejected illegal transaction transition")
+		return nil, &IllegalTransitionError{From: from, To: to}
+	}
+	if from == domain.TxStatusDisputed && moderatorID == nil {
+		log.Warn().Msg("Rejected system transition of a disputed transaction")
+		return nil, ErrTransactionDisputed
+	}
+
+	tx.Status = to
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Transition(ctx, tx, from, moderatorID); err != nil {
+			return err
+		}
+		// Keyed, so subscribers see the steps of one transaction in order
+		return events.PublishKeyed(ctx, s.bus, tx.ID.String(), events.TransactionStatusChanged(tx))
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Str("from", string(from)).Msg("Transaction status changed")
+	return tx, nil
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mocks ---
+
+// MockTransactionRepository
+type MockTransactionRepository struct {
+	mock.Mock
+}
+
+var _ ports.TransactionRepository = (*MockTransactionRepository)(nil)
+
+func (m *MockTransactionRepository) Create(ctx context.Context, tx *domain.Transaction) error {
+	args := m.Called(ctx, tx)
+	return args.Error(0)
+}
+func (m *MockTransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Transaction, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Transaction), args.Error(1)
+}
+func (m *MockTransactionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Transaction, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Transaction), args.Error(1)
+}
+func (m *MockTransactionRepository) Update(ctx context.Context, tx *domain.Transaction) error {
+	args := m.Called(ctx, tx)
+	return args.Error(0)
+}
+func (m *MockTransactionRepository) Transition(ctx context.Context, tx *domain.Transaction, from domain.TransactionStatus, moderatorID *uuid.UUID) error {
+	args := m.Called(ctx, tx, from, moderatorID)
+	return args.Error(0)
+}
+func (m *MockTransactionRepository) SetPayoutAccount(ctx context.Context, txID uuid.UUID, leg domain.TransactionLeg, accountID uuid.UUID) error {
+	args := m.Called(ctx, txID, leg, accountID)
+	return args.Error(0)
+}
+func (m *MockTransactionRepository) GetHistory(ctx context.Context, transactionID uuid.UUID) ([]*domain.TransactionTransition, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TransactionTransition), args.Error(1)
+}
+func (m *MockTransactionRepository) TradedVolume(ctx context.Context, userID uuid.UUID, baseCurrency string, since time.Time) (domain.Decimal, error) {
+	args := m.Called(ctx, userID, baseCurrency, since)
+	return args.Get(0).(domain.Decimal), args.Error(1)
+}
+
+// MockEventBus
+type MockEventBus struct {
+	mock.Mock
+}
+
+func (m *MockEventBus) Publish(ctx context.Context, topic string, data interface{}) error {
+	args := m.Called(ctx, topic, data)
+	return args.Error(0)
+}
+func (m *MockEventBus) PublishKeyed(ctx context.Context, topic, key string, data interface{}) error {
+	args := m.Called(ctx, topic, key, data)
+	return args.Error(0)
+}
+func (m *MockEventBus) Subscribe(topic string, handler ports.EventHandler) {
+	m.Called(topic, handler)
+}
+func (m *MockEventBus) SubscribeWithRetry(topic, name string, handler ports.EventHandler, policy ports.RetryPolicy) {
+	m.Called(topic, name, handler, policy)
+}
+
+// --- Tests ---
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		from, to domain.TransactionStatus
+		want     bool
+	}{
+		{domain.TxStatusPendingDeposits, domain.TxStatusSellerDepositReceived, true},
+		{domain.TxStatusSellerDepositReceived, domain.TxStatusPendingPayouts, true},
+		{domain.TxStatusBuyerPayoutSent, domain.TxStatusCompleted, true},
+		{domain.TxStatusDisputed, domain.TxStatusCancelled, true},
+		{domain.TxStatusPendingDeposits, domain.TxStatusCompleted, false},
+		{domain.TxStatusPendingPayouts, domain.TxStatusCancelled, false},
+		{domain.TxStatusCompleted, domain.TxStatusDisputed, false},
+		{domain.TxStatusCancelled, domain.TxStatusPendingDeposits, false},
+	}
+	for _, c := range cases {
+		if got := CanTransition(c.from, c.to); got != c.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", c.from, c.to, got, c.want)
+		}
+	}
+}
+
+func TestDepositTarget(t *testing.T) {
+	cases := []struct {
+		from   domain.TransactionStatus
+		leg    domain.TransactionLeg
+		want   domain.TransactionStatus
+		wantOK bool
+	}{
+		{domain.TxStatusPendingDeposits, domain.LegSeller, domain.TxStatusSellerDepositReceived, true},
+		{domain.TxStatusPendingDeposits, domain.LegBuyer, domain.TxStatusBuyerDepositReceived, true},
+		{domain.TxStatusSellerDepositReceived, domain.LegBuyer, domain.TxStatusPendingPayouts, true},
+		{domain.TxStatusBuyerDepositReceived, domain.LegSeller, domain.TxStatusPendingPayouts, true},
+		{domain.TxStatusSellerDepositReceived, domain.LegSeller, "", false}, // Already confirmed
+		{domain.TxStatusPendingPayouts, domain.LegBuyer, "", false},
+		{domain.TxStatusDisputed, domain.LegSeller, "", false},
+	}
+	for _, c := range cases {
+		got, ok := DepositTarget(c.from, c.leg)
+		if got != c.want || ok != c.wantOK {
+			t.Errorf("DepositTarget(%s, %s) = (%s, %v), want (%s, %v)", c.from, c.leg, got, ok, c.want, c.wantOK)
+		}
+		if ok && !CanTransition(c.from, got) {
+			t.Errorf("DepositTarget(%s, %s) returned %s, which the lifecycle forbids", c.from, c.leg, got)
+		}
+	}
+}
+
+func TestPayoutTarget(t *testing.T) {
+	cases := []struct {
+		from   domain.TransactionStatus
+		leg    domain.TransactionLeg
+		want   domain.TransactionStatus
+		wantOK bool
+	}{
+		{domain.TxStatusPendingPayouts, domain.LegSeller, domain.TxStatusSellerPayoutSent, true},
+		{domain.TxStatusPendingPayouts, domain.LegBuyer, domain.TxStatusBuyerPayoutSent, true},
+		{domain.TxStatusSellerPayoutSent, domain.LegBuyer, domain.TxStatusCompleted, true},
+		{domain.TxStatusBuyerPayoutSent, domain.LegSeller, domain.TxStatusCompleted, true},
+		{domain.TxStatusBuyerPayoutSent, domain.LegBuyer, "", false}, // Already paid
+		{domain.TxStatusSellerDepositReceived, domain.LegSeller, "", false},
+		{domain.TxStatusCompleted, domain.LegBuyer, "", false},
+	}
+	for _, c := range cases {
+		got, ok := PayoutTarget(c.from, c.leg)
+		if got != c.want || ok != c.wantOK {
+			t.Errorf("PayoutTarget(%s, %s) = (%s, %v), want (%s, %v)", c.from, c.leg, got, ok, c.want, c.wantOK)
+		}
+		if ok && !CanTransition(c.from, got) {
+			t.Errorf("PayoutTarget(%s, %s) returned %s, which the lifecycle forbids", c.from, c.leg, got)
+		}
+	}
+}
+
+func TestTransactionService_Transition_Success(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockRepo := new(MockTransactionRepository)
+	mockBus := new(MockEventBus)
+	svc := NewTransactionService(mockRepo, inlineTransactor{}, mockBus, &nopLogger)
+
+	tx := &domain.Transaction{ID: uuid.New(), Status: domain.TxStatusPendingDeposits}
+	modID := uuid.New()
+
+	// 2. Define Expectations
+	mockRepo.On("GetByID", mock.Anything, tx.ID).Return(tx, nil).Once()
+	mockRepo.On("Transition", mock.Anything, tx, domain.TxStatusPendingDeposits, &modID).Return(nil).Once()
+	mockBus.On("PublishKeyed", mock.Anything, "transaction:seller_deposit_received", tx.ID.String(), mock.AnythingOfType("events.SellerDepositReceived")).Return(nil).Once()
+
+	// 3. Run
+	updated, err := svc.Transition(ctx, tx.ID, domain.TxStatusSellerDepositReceived, &modID)
+	if err != nil {
+		t.Fatalf("Transition returned an error: %v", err)
+	}
+
+	// 4. Assert
+	if updated.Status != domain.TxStatusSellerDepositReceived {
+		t.Errorf("Status mismatch: got %s", updated.Status)
+	}
+	mockRepo.AssertExpectations(t)
+	mockBus.AssertExpectations(t)
+}
+
+func TestTransactionService_Transition_PublishFailure(t *testing.T) {
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockRepo := new(MockTransactionRepository)
+	mockBus := new(MockEventBus)
+	svc := NewTransactionService(mockRepo, inlineTransactor{}, mockBus, &nopLogger)
+
+	tx := &domain.Transaction{ID: uuid.New(), Status: domain.TxStatusPendingDeposits}
+	outboxErr := errors.New("outbox unavailable")
+	mockRepo.On("GetByID", mock.Anything, tx.ID).Return(tx, nil).Once()
+	mockRepo.On("Transition", mock.Anything, tx, domain.TxStatusPendingDeposits, (*uuid.UUID)(nil)).Return(nil).Once()
+	mockBus.On("PublishKeyed", mock.Anything, "transaction:cancelled", tx.ID.String(), mock.Anything).Return(outboxErr).Once()
+
+	// The event is part of the move, so losing it undoes the move
+	if _, err := svc.Transition(ctx, tx.ID, domain.TxStatusCancelled, nil); !errors.Is(err, outboxErr) {
+		t.Fatalf("Expected the publish error, got: %v", err)
+	}
+}
+
+func TestTransactionService_Transition_Illegal(t *testing.T) {
+	// 1. Setup
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockRepo := new(MockTransactionRepository)
+	mockBus := new(MockEventBus)
+	svc := NewTransactionService(mockRepo, inlineTransactor{}, mockBus, &nopLogger)
+
+	tx := &domain.Transaction{ID: uuid.New(), Status: domain.TxStatusPendingDeposits}
+	mockRepo.On("GetByID", mock.Anything, tx.ID).Return(tx, nil).Once()
+
+	// 2. Run
+	_, err := svc.Transition(ctx, tx.ID, domain.TxStatusCompleted, nil)
+
+	// 3. Assert
+	var illegal *IllegalTransitionError
+	if !errors.As(err, &illegal) {
+		t.Fatalf("Expected IllegalTransitionError, got: %v", err)
+	}
+	if illegal.From != domain.TxStatusPendingDeposits || illegal.To != domain.TxStatusCompleted {
+		t.Errorf("Error fields mismatch: %+v", illegal)
+	}
+	mockRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockBus.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransactionService_Transition_NotFound(t *testing.T) {
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockRepo := new(MockTransactionRepository)
+	mockBus := new(MockEventBus)
+	svc := NewTransactionService(mockRepo, inlineTransactor{}, mockBus, &nopLogger)
+
+	id := uuid.New()
+	mockRepo.On("GetByID", mock.Anything, id).Return(nil, nil).Once()
+
+	if _, err := svc.Transition(ctx, id, domain.TxStatusCancelled, nil); !errors.Is(err, ErrTransactionNotFound) {
+		t.Fatalf("Expected ErrTransactionNotFound, got: %v", err)
+	}
+}
+
+func TestTransactionService_Transition_DisputedNeedsModerator(t *testing.T) {
+	ctx := context.Background()
+	nopLogger := zerolog.Nop()
+	mockRepo := new(MockTransactionRepository)
+	mockBus := new(MockEventBus)
+	svc := NewTransactionService(mockRepo, inlineTransactor{}, mockBus, &nopLogger)
+
+	tx := &domain.Transaction{ID: uuid.New(), Status: domain.TxStatusDisputed}
+	mockRepo.On("GetByID", mock.Anything, tx.ID).Return(tx, nil).Once()
+
+	if _, err := svc.Transition(ctx, tx.ID, domain.TxStatusCompleted, nil); !errors.Is(err, ErrTransactionDisputed) {
+		t.Fatalf("Expected ErrTransactionDisputed, got: %v", err)
+	}
+	mockRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
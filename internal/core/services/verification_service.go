@@ -0,0 +1,255 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// StateData keys of the questions a strategy asked.
+const (
+	verifyFieldsKey       = "verify_fields"  // Comma separated field keys, in order
+	verifyPromptKeyPrefix = "verify_prompt_" // + field key
+	verifyAnswerKeyPrefix = "verify_answer_" // + field key
+)
+
+// manualStrategy defers every decision to a moderator. It is always registered
+// and is also used for users whose strategy is unknown.
+type manualStrategy struct{}
+
+func (manualStrategy) Name() string { return domain.VerificationStrategyManual }
+
+func (manualStrategy) Verify(ctx context.Context, input ports.VerificationInput) (*ports.VerificationDecision, error) {
+	return &ports.VerificationDecision{Outcome: ports.VerificationManual, Reason: "manual review"}, nil
+}
+
+// VerificationService runs the verification strategy of each user's country
+// and applies its decision. Strategies are keyed by the name used in the
+// country_strategies config, so a country-specific check only needs a new
+// strategy, not changes to the registration flow.
+type VerificationService struct {
+	strategies map[string]ports.VerificationStrategy
+	userRepo   ports.UserRepository
+	tx         ports.Transactor
+	bus        ports.EventBus
+	log        zerolog.Logger
+}
+
+// NewVerificationService creates a verification service with only the manual strategy.
+func NewVerificationService(
+	userRepo ports.UserRepository,
+	tx ports.Transactor,
+	bus ports.EventBus,
+	baseLogger *zerolog.Logger,
+) *VerificationService {
+	return &VerificationService{
+		strategies: map[string]ports.VerificationStrategy{domain.VerificationStrategyManual: manualStrategy{}},
+		userRepo:   userRepo,
+		tx:         tx,
+		bus:        bus,
+		log:        baseLogger.With().Str("component", "verification_service").Logger(),
+	}
+}
+
+// Register adds a strategy. Names must be unique.
+func (s *VerificationService) Register(strategy ports.VerificationStrategy) error {
+	name := strategy.Name()
+	if _, dup := s.strategies[name]; dup {
+		return fmt.Errorf("verification strategy %q is registered twice", name)
+	}
+	s.strategies[name] = strategy
+	s.log.Info().Str("strategy", name).Msg("Verification strategy registered")
+	return nil
+}
+
+// Has reports whether a strategy is registered under name.
+func (s *VerificationService) Has(name string) bool {
+	_, ok := s.strategies[name]
+	return ok
+}
+
+// Submit verifies a user who has finished registration and applies the decision:
+// approved and rejected users are saved and announced on "user:approved" and
+// "user:rejected", users with open questions move to StateAwaitingVerificationField,
+// everyone else stays pending for a moderator.
+func (s *VerificationService) Submit(ctx context.Context, user *domain.User) (*ports.VerificationDecision, error) {
+	return s.verify(ctx, user, map[string]string{})
+}
+
+// NextField returns the question the user has to answer next, if any.
+func (s *VerificationService) NextField(user *domain.User) (ports.VerificationField, bool) {
+	for _, key := range strings.Split(user.StateData[verifyFieldsKey], ",") {
+		if key == "" {
+			continue
+		}
+		if _, answered := user.StateData[verifyAnswerKeyPrefix+key]; !answered {
+			return ports.VerificationField{Key: key, Prompt: user.StateData[verifyPromptKeyPrefix+key]}, true
+		}
+	}
+	return ports.VerificationField{}, false
+}
+
+// Answer stores the answer to NextField. Once every question is answered the
+// user is verified again, like Submit; until then the decision lists the open fields.
+func (s *VerificationService) Answer(ctx context.Context, user *domain.User, value string) (*ports.VerificationDecision, error) {
+	if user.StateData == nil {
+		user.StateData = map[string]string{}
+	}
+	field, ok := s.NextField(user)
+	if !ok {
+		return s.verify(ctx, user, s.answers(user))
+	}
+	user.StateData[verifyAnswerKeyPrefix+field.Key] = value
+
+	if _, more := s.NextField(user); more {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+		return &ports.VerificationDecision{Outcome: ports.VerificationNeedFields, Fields: s.openFields(user)}, nil
+	}
+	return s.verify(ctx, user, s.answers(user))
+}
+
+// verify runs the user's strategy with the given answers and applies the decision.
+func (s *VerificationService) verify(ctx context.Context, user *domain.User, answers map[string]string) (*ports.VerificationDecision, error) {
+	strategy := s.strategyFor(user)
+	log := s.log.With().Str("user_id", user.ID.String()).Str("strategy", strategy.Name()).Logger()
+
+	decision, err := strategy.Verify(ctx, ports.VerificationInput{User: user, Fields: answers})
+	if err != nil {
+		// The check could not be done; a moderator decides instead
+		log.Error().Err(err).Msg("Verification strategy failed, falling back to manual review")
+		decision = &ports.VerificationDecision{Outcome: ports.VerificationManual, Reason: "strategy failed: " + err.Error()}
+	}
+	if decision.Outcome == ports.VerificationNeedFields {
+		if reason := s.invalidFields(decision.Fields, answers); reason != "" {
+			log.Warn().Str("reason", reason).Msg("Strategy asked for unusable fields, falling back to manual review")
+			decision = &ports.VerificationDecision{Outcome: ports.VerificationManual, Reason: reason}
+		}
+	}
+	log = log.With().Str("outcome", string(decision.Outcome)).Str("reason", decision.Reason).Logger()
+
+	switch decision.Outcome {
+	case ports.VerificationApprove:
+		user.VerificationStatus = domain.VerificationLevel1
+		user.State = domain.StateNone
+		user.StateData = map[string]string{}
+		if err := s.saveAndPublish(ctx, user, events.UserApproved{UserRef: events.NewUserRef(user)}); err != nil {
+			return nil, err
+		}
+		log.Info().Msg("User approved by verification strategy")
+
+	case ports.VerificationReject:
+		// Same reset as a moderator rejection: the user registers again
+		user.VerificationStatus = domain.VerificationRejected
+		user.State = domain.StateAwaitingFirstName
+		user.StateData = map[string]string{}
+		user.FirstName = nil
+		user.LastName = nil
+		user.PhoneNumber = nil
+		user.GovernmentID = nil
+		user.IdentityDocRef = nil
+		user.LocationCountry = nil
+		user.VerificationStrategy = nil
+		if err := s.saveAndPublish(ctx, user, events.UserRejected{UserRef: events.NewUserRef(user)}); err != nil {
+			return nil, err
+		}
+		log.Info().Msg("User rejected by verification strategy")
+
+	case ports.VerificationNeedFields:
+		keys := make([]string, 0, len(decision.Fields))
+		for _, f := range decision.Fields {
+			keys = append(keys, f.Key)
+		}
+		user.State = domain.StateAwaitingVerificationField
+		user.StateData = map[string]string{verifyFieldsKey: strings.Join(keys, ",")}
+		for _, f := range decision.Fields {
+			user.StateData[verifyPromptKeyPrefix+f.Key] = f.Prompt
+		}
+		// Earlier answers are kept, the strategy may need them again
+		for key, value := range answers {
+			user.StateData[verifyAnswerKeyPrefix+key] = value
+		}
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+		log.Info().Strs("fields", keys).Msg("Verification strategy needs more details")
+
+	default:
+		decision.Outcome = ports.VerificationManual
+		user.State = domain.StateNone
+		user.StateData = map[string]string{}
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+		log.Info().Msg("User left for manual review")
+	}
+	return decision, nil
+}
+
+// saveAndPublish saves the verdict and its event in one transaction.
+func (s *VerificationService) saveAndPublish(ctx context.Context, user *domain.User, event events.Event) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return events.Publish(ctx, s.bus, event)
+	})
+}
+
+// strategyFor returns the strategy of the user's country, or manual if it is unknown.
+func (s *VerificationService) strategyFor(user *domain.User) ports.VerificationStrategy {
+	if user.VerificationStrategy == nil {
+		return s.strategies[domain.VerificationStrategyManual]
+	}
+	strategy, ok := s.strategies[*user.VerificationStrategy]
+	if !ok {
+		s.log.Warn().Str("strategy", *user.VerificationStrategy).Msg("Unknown verification strategy, using manual review")
+		return s.strategies[domain.VerificationStrategyManual]
+	}
+	return strategy
+}
+
+// invalidFields explains why fields cannot be asked, or returns "".
+// Asking again for an answered field would loop forever.
+func (s *VerificationService) invalidFields(fields []ports.VerificationField, answers map[string]string) string {
+	if len(fields) == 0 {
+		return "strategy asked for no fields"
+	}
+	for _, f := range fields {
+		if f.Key == "" || strings.Contains(f.Key, ",") {
+			return fmt.Sprintf("invalid field key %q", f.Key)
+		}
+		if _, answered := answers[f.Key]; answered {
+			return fmt.Sprintf("strategy asked for %q again", f.Key)
+		}
+	}
+	return ""
+}
+
+// answers collects the answers stored in the user's StateData.
+func (s *VerificationService) answers(user *domain.User) map[string]string {
+	answers := map[string]string{}
+	for key, value := range user.StateData {
+		if field, ok := strings.CutPrefix(key, verifyAnswerKeyPrefix); ok {
+			answers[field] = value
+		}
+	}
+	return answers
+}
+
+// openFields lists the questions that are still unanswered.
+func (s *VerificationService) openFields(user *domain.User) []ports.VerificationField {
+	var open []ports.VerificationField
+	for _, key := range strings.Split(user.StateData[verifyFieldsKey], ",") {
+		if _, answered := user.StateData[verifyAnswerKeyPrefix+key]; key != "" && !answered {
+			open = append(open, ports.VerificationField{Key: key, Prompt: user.StateData[verifyPromptKeyPrefix+key]})
+		}
+	}
+	return open
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"AsaExchange/internal/core/domain"
+	"AsaExchange/internal/core/events"
+	"AsaExchange/internal/core/ports"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mocks ---
+
+// MockUserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+var _ ports.UserRepository = (*MockUserRepository)(nil)
+
+func (m *MockUserRepository) Create(ctx context.Context, user *domain.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+func (m *MockUserRepository) GetByTelegramID(ctx context.Context, telegramID int64) (*domain.User, error) {
+	args := m.Called(ctx, telegramID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+func (m *MockUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+func (m *MockUserRepository) Update(ctx context.Context, user *domain.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+func (m *MockUserRepository) LockForUpdate(ctx context.Context, ids ...uuid.UUID) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+func (m *MockUserRepository) GetNextPendingUser(ctx context.Context) (*domain.User, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+func (m *MockUserRepository) ClaimStalledRegistrations(ctx context.Context, idleSince time.Time, limit int) ([]*domain.User, error) {
+	args := m.Called(ctx, idleSince, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) ClaimPendingStartPayload(ctx context.Context, id uuid.UUID) (*string, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*string), args.Error(1)
+}
+
+// MockVerificationStrategy
+type MockVerificationStrategy struct {
+	mock.Mock
+}
+
+var _ ports.VerificationStrategy = (*MockVerificationStrategy)(nil)
+
+func (m *MockVerificationStrategy) Name() string {
+	return "registry"
+}
+func (m *MockVerificationStrategy) Verify(ctx context.Context, input ports.VerificationInput) (*ports.VerificationDecision, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ports.VerificationDecision), args.Error(1)
+}
+
+// inlineTransactor runs the work without a database.
+type inlineTransactor struct{}
+
+func (inlineTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// newVerificationTest builds a service with MockVerificationStrategy registered
+// and a pending user who picked it.
+func newVerificationTest(t *testing.T) (*VerificationService, *MockVerificationStrategy, *MockUserRepository, *MockEventBus, *domain.User) {
+	nopLogger := zerolog.Nop()
+	userRepo := new(MockUserRepository)
+	bus := new(MockEventBus)
+	strategy := new(MockVerificationStrategy)
+
+	svc := NewVerificationService(userRepo, inlineTransactor{}, bus, &nopLogger)
+	if err := svc.Register(strategy); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	name, firstName := strategy.Name(), "Sara"
+	user := &domain.User{
+		ID:                   uuid.New(),
+		FirstName:            &firstName,
+		State:                domain.StateAwaitingPolicyApproval,
+		VerificationStatus:   domain.VerificationPending,
+		VerificationStrategy: &name,
+		StateData:            map[string]string{},
+	}
+	return svc, strategy, userRepo, bus, user
+}
+
+// --- Tests ---
+
+func TestVerificationService_Register_Duplicate(t *testing.T) {
+	svc, strategy, _, _, _ := newVerificationTest(t)
+
+	if err := svc.Register(strategy); err == nil {
+		t.Error("expected an error for a duplicate strategy")
+	}
+	if !svc.Has("registry") || !svc.Has(domain.VerificationStrategyManual) {
+		t.Error("expected both the registry and the manual strategy to be registered")
+	}
+}
+
+func TestVerificationService_Submit_Approve(t *testing.T) {
+	// 1. Setup
+	svc, strategy, userRepo, bus, user := newVerificationTest(t)
+	ctx := context.Background()
+
+	strategy.On("Verify", ctx, mock.Anything).Return(&ports.VerificationDecision{Outcome: ports.VerificationApprove}, nil).Once()
+	userRepo.On("Update", ctx, user).Return(nil).Once()
+	bus.On("Publish", ctx, "user:approved", events.UserApproved{UserRef: events.NewUserRef(user)}).Return(nil).Once()
+
+	// 2. Run
+	decision, err := svc.Submit(ctx, user)
+
+	// 3. Verify
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if decision.Outcome != ports.VerificationApprove {
+		t.Errorf("Outcome mismatch: got %s", decision.Outcome)
+	}
+	if user.VerificationStatus != domain.VerificationLevel1 || user.State != domain.StateNone {
+		t.Errorf("User not approved: status %s, state %s", user.VerificationStatus, user.State)
+	}
+	strategy.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+	bus.AssertExpectations(t)
+}
+
+func TestVerificationService_Submit_Reject(t *testing.T) {
+	svc, strategy, userRepo, bus, user := newVerificationTest(t)
+	ctx := context.Background()
+
+	strategy.On("Verify", ctx, mock.Anything).Return(&ports.VerificationDecision{Outcome: ports.VerificationReject}, nil).Once()
+	userRepo.On("Update", ctx, user).Return(nil).Once()
+	bus.On("Publish", ctx, "user:rejected", events.UserRejected{UserRef: events.NewUserRef(user)}).Return(nil).Once()
+
+	if _, err := svc.Submit(ctx, user); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if user.VerificationStatus != domain.VerificationRejected || user.State != domain.StateAwaitingFirstName {
+		t.Errorf("User not reset: status %s, state %s", user.VerificationStatus, user.State)
+	}
+	if user.FirstName != nil || user.VerificationStrategy != nil {
+		t.Error("Registration details were not cleared")
+	}
+	bus.AssertExpectations(t)
+}
+
+func TestVerificationService_Submit_StrategyErrorFallsBackToManual(t *testing.T) {
+	svc, strategy, userRepo, bus, user := newVerificationTest(t)
+	ctx := context.Background()
+
+	strategy.On("Verify", ctx, mock.Anything).Return(nil, errors.New("registry down")).Once()
+	userRepo.On("Update", ctx, user).Return(nil).Once()
+
+	decision, err := svc.Submit(ctx, user)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if decision.Outcome != ports.VerificationManual {
+		t.Errorf("Outcome mismatch: got %s, want manual", decision.Outcome)
+	}
+	if user.VerificationStatus != domain.VerificationPending || user.State != domain.StateNone {
+		t.Errorf("User should wait for a moderator: status %s, state %s", user.VerificationStatus, user.State)
+	}
+	bus.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVerificationService_Submit_UnknownStrategyIsManual(t *testing.T) {
+	svc, strategy, userRepo, _, user := newVerificationTest(t)
+	ctx := context.Background()
+
+	unknown := "retired_registry"
+	user.VerificationStrategy = &unknown
+	userRepo.On("Update", ctx, user).Return(nil).Once()
+
+	decision, err := svc.Submit(ctx, user)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if decision.Outcome != ports.VerificationManual {
+		t.Errorf("Outcome mismatch: got %s, want manual", decision.Outcome)
+	}
+	strategy.AssertNotCalled(t, "Verify", mock.Anything, mock.Anything)
+}
+
+func TestVerificationService_NeedFields_AnswerAndVerifyAgain(t *testing.T) {
+	// 1. Setup: the strategy asks for two fields, then approves
+	svc, strategy, userRepo, bus, user := newVerificationTest(t)
+	ctx := context.Background()
+
+	fields := []ports.VerificationField{
+		{Key: "birth_date", Prompt: "Date of birth?"},
+		{Key: "father_name", Prompt: "Father's name?"},
+	}
+	strategy.On("Verify", ctx, mock.MatchedBy(func(in ports.VerificationInput) bool { return len(in.Fields) == 0 })).
+		Return(&ports.VerificationDecision{Outcome: ports.VerificationNeedFields, Fields: fields}, nil).Once()
+	strategy.On("Verify", ctx, mock.MatchedBy(func(in ports.VerificationInput) bool {
+		return in.Fields["birth_date"] == "1990-03-21" && in.Fields["father_name"] == "Ali"
+	})).Return(&ports.VerificationDecision{Outcome: ports.VerificationApprove}, nil).Once()
+	userRepo.On("Update", ctx, user).Return(nil)
+	bus.On("Publish", ctx, "user:approved", events.UserApproved{UserRef: events.NewUserRef(user)}).Return(nil).Once()
+
+	// 2. Submit asks the first question
+	decision, err := svc.Submit(ctx, user)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if decision.Outcome != ports.VerificationNeedFields || user.State != domain.StateAwaitingVerificationField {
+		t.Fatalf("Expected questions: outcome %s, state %s", decision.Outcome, user.State)
+	}
+	if field, ok := svc.NextField(user); !ok || field.Key != "birth_date" || field.Prompt != "Date of birth?" {
+		t.Fatalf("NextField mismatch: got %+v, %v", field, ok)
+	}
+
+	// 3. The first answer moves to the second question
+	decision, err = svc.Answer(ctx, user, "1990-03-21")
+	if err != nil {
+		t.Fatalf("Answer failed: %v", err)
+	}
+	if decision.Outcome != ports.VerificationNeedFields || decision.Fields[0].Key != "father_name" {
+		t.Fatalf("Expected the second question, got %+v", decision)
+	}
+
+	// 4. The last answer verifies again
+	decision, err = svc.Answer(ctx, user, "Ali")
+	if err != nil {
+		t.Fatalf("Answer failed: %v", err)
+	}
+	if decision.Outcome != ports.VerificationApprove || user.VerificationStatus != domain.VerificationLevel1 {
+		t.Errorf("Expected approval: outcome %s, status %s", decision.Outcome, user.VerificationStatus)
+	}
+	if len(user.StateData) != 0 {
+		t.Errorf("Answers were not cleared: %v", user.StateData)
+	}
+	strategy.AssertExpectations(t)
+	bus.AssertExpectations(t)
+}
+
+func TestVerificationService_NeedFields_RepeatedFieldIsManual(t *testing.T) {
+	svc, strategy, userRepo, _, user := newVerificationTest(t)
+	ctx := context.Background()
+
+	ask := &ports.VerificationDecision{
+		Outcome: ports.VerificationNeedFields,
+		Fields:  []ports.VerificationField{{Key: "birth_date", Prompt: "Date of birth?"}},
+	}
+	strategy.On("Verify", ctx, mock.Anything).Return(ask, nil).Twice()
+	userRepo.On("Update", ctx, user).Return(nil)
+
+	if _, err := svc.Submit(ctx, user); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	decision, err := svc.Answer(ctx, user, "1990-03-21")
+	if err != nil {
+		t.Fatalf("Answer failed: %v", err)
+	}
+	if decision.Outcome != ports.VerificationManual || user.State != domain.StateNone {
+		t.Errorf("A repeated question should go to manual review: outcome %s, state %s", decision.Outcome, user.State)
+	}
+	strategy.AssertExpectations(t)
+}
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"AsaExchange/internal/core/domain"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -57,11 +60,88 @@ type PostgresConfig struct {
 	URL      string `mapstructure:"url"`
 }
 
+type SchedulerConfig struct {
+	Interval               time.Duration `mapstructure:"interval"`                 // How often every job runs
+	RequestTTL             time.Duration `mapstructure:"request_ttl"`              // Requests open longer than this expire
+	RegistrationNudgeAfter time.Duration `mapstructure:"registration_nudge_after"` // Idle time before a registration reminder
+}
+
+type CurrencyConfig struct {
+	Code      string `mapstructure:"code"`
+	Name      string `mapstructure:"name"`
+	Decimals  int32  `mapstructure:"decimals"`
+	MinAmount string `mapstructure:"min_amount"` // Decimal strings, so large Rial limits stay exact
+	MaxAmount string `mapstructure:"max_amount"` // Empty or "0" means no maximum
+}
+
+type CurrencyPairConfig struct {
+	Base  string `mapstructure:"base"`
+	Quote string `mapstructure:"quote"`
+}
+
+type MarketConfig struct {
+	Currencies []CurrencyConfig     `mapstructure:"currencies"`
+	Pairs      []CurrencyPairConfig `mapstructure:"pairs"`
+}
+
+type FeeRuleConfig struct {
+	Base          string            `mapstructure:"base"`  // Empty matches any currency
+	Quote         string            `mapstructure:"quote"` // Empty matches any currency
+	Level         string            `mapstructure:"level"` // Verification level, empty matches any
+	Percent       string            `mapstructure:"percent"`
+	SpreadPercent string            `mapstructure:"spread_percent"`
+	Flat          map[string]string `mapstructure:"flat"` // Flat fee per payout currency
+}
+
+type FeesConfig struct {
+	Version string          `mapstructure:"version"` // Bump on every change to the rules
+	Default FeeRuleConfig   `mapstructure:"default"`
+	Rules   []FeeRuleConfig `mapstructure:"rules"`
+}
+
+type CurrencyLimitConfig struct {
+	Code           string `mapstructure:"code"`
+	PerTransaction string `mapstructure:"per_transaction"` // Empty or "0" means no limit
+	Daily          string `mapstructure:"daily"`           // Rolling 24 hours
+	Monthly        string `mapstructure:"monthly"`         // Rolling 30 days
+}
+
+type TradingLimitConfig struct {
+	Level           string                `mapstructure:"level"`
+	MaxOpenRequests int                   `mapstructure:"max_open_requests"` // 0 means no limit
+	Currencies      []CurrencyLimitConfig `mapstructure:"currencies"`
+}
+
+type RegistryConfig struct {
+	Strategy string        `mapstructure:"strategy"` // Name countries refer to in country_strategies
+	URL      string        `mapstructure:"url"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
+
+type VerificationConfig struct {
+	Registries []RegistryConfig `mapstructure:"registries"` // Government registry strategies
+}
+
 type Config struct {
-	AppEnv        string         `mapstructure:"app_env"`
-	EncryptionKey string         `mapstructure:"encryption_key"`
-	Postgres      PostgresConfig `mapstructure:"postgres"`
-	Bot           BotConfig      `mapstructure:"bot"`
+	AppEnv        string               `mapstructure:"app_env"`
+	EncryptionKey string               `mapstructure:"encryption_key"`
+	Postgres      PostgresConfig       `mapstructure:"postgres"`
+	Bot           BotConfig            `mapstructure:"bot"`
+	Scheduler     SchedulerConfig      `mapstructure:"scheduler"`
+	Market        MarketConfig         `mapstructure:"market"`
+	Fees          FeesConfig           `mapstructure:"fees"`
+	Limits        []TradingLimitConfig `mapstructure:"limits"`
+	Verification  VerificationConfig   `mapstructure:"verification"`
+
+	// ShutdownTimeout bounds how long in-flight events may finish after SIGTERM
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+
+	// Currencies is built from Market by Load
+	Currencies *domain.CurrencyRegistry `mapstructure:"-"`
+	// FeeSchedule is built from Fees by Load
+	FeeSchedule *domain.FeeSchedule `mapstructure:"-"`
+	// TradingLimits is built from Limits by Load
+	TradingLimits *domain.LimitTable `mapstructure:"-"`
 }
 
 // findProjectRoot
@@ -117,6 +197,10 @@ func Load() (*Config, error) {
 	v.SetDefault("bot.customer.connection.polling.worker_pool_size", 5)
 	v.SetDefault("bot.moderator.connection.mode", "polling")
 	v.SetDefault("bot.moderator.connection.polling.worker_pool_size", 1)
+	v.SetDefault("scheduler.interval", "5m")
+	v.SetDefault("scheduler.request_ttl", "168h")
+	v.SetDefault("scheduler.registration_nudge_after", "24h")
+	v.SetDefault("shutdown_timeout", "15s")
 
 	// 5. Unmarshal the config
 	var cfg Config
@@ -155,6 +239,195 @@ func Load() (*Config, error) {
 	if cfg.Bot.Moderator.AdminReviewChannelID == 0 {
 		return nil, errors.New("bot.moderator.admin_review_channel_id is not set in config.yaml")
 	}
+	if cfg.Scheduler.Interval <= 0 || cfg.Scheduler.RequestTTL <= 0 || cfg.Scheduler.RegistrationNudgeAfter <= 0 {
+		return nil, errors.New("scheduler durations must be positive in config.yaml")
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		return nil, errors.New("shutdown_timeout must be positive in config.yaml")
+	}
+	registry, err := buildCurrencyRegistry(cfg.Market)
+	if err != nil {
+		return nil, fmt.Errorf("market is invalid in config.yaml: %w", err)
+	}
+	cfg.Currencies = registry
+	limits, err := buildLimitTable(cfg.Limits, registry)
+	if err != nil {
+		return nil, fmt.Errorf("limits are invalid in config.yaml: %w", err)
+	}
+	cfg.TradingLimits = limits
+	schedule, err := buildFeeSchedule(cfg.Fees, registry, limits)
+	if err != nil {
+		return nil, fmt.Errorf("fees are invalid in config.yaml: %w", err)
+	}
+	cfg.FeeSchedule = schedule
+	if err := validateVerification(cfg.Verification, cfg.Bot.Customer.CountryStrategies); err != nil {
+		return nil, fmt.Errorf("verification is invalid in config.yaml: %w", err)
+	}
 
 	return &cfg, nil
 }
+
+// validateVerification checks the registries and that every country uses
+// either manual review or one of them.
+func validateVerification(verification VerificationConfig, countries map[string]CountryConfig) error {
+	known := map[string]bool{domain.VerificationStrategyManual: true}
+	for i, r := range verification.Registries {
+		if r.Strategy == "" || r.URL == "" {
+			return fmt.Errorf("registry %d: strategy and url are required", i+1)
+		}
+		if known[r.Strategy] {
+			return fmt.Errorf("registry %d: strategy %q is defined twice", i+1, r.Strategy)
+		}
+		if r.Timeout <= 0 {
+			return fmt.Errorf("registry %s: timeout must be positive", r.Strategy)
+		}
+		known[r.Strategy] = true
+	}
+	for code, country := range countries {
+		if !known[country.Strategy] {
+			return fmt.Errorf("country %s uses unknown strategy %q", strings.ToUpper(code), country.Strategy)
+		}
+	}
+	return nil
+}
+
+// buildCurrencyRegistry parses the market section into a currency registry.
+// Pairs that reference an unknown currency are rejected.
+func buildCurrencyRegistry(market MarketConfig) (*domain.CurrencyRegistry, error) {
+	currencies := make([]domain.Currency, 0, len(market.Currencies))
+	for _, c := range market.Currencies {
+		currency := domain.Currency{Code: c.Code, Name: c.Name, Decimals: c.Decimals}
+		var err error
+		if c.MinAmount != "" {
+			if currency.MinAmount, err = domain.ParseDecimal(c.MinAmount); err != nil {
+				return nil, fmt.Errorf("currency %s: invalid min_amount %q", c.Code, c.MinAmount)
+			}
+		}
+		if c.MaxAmount != "" {
+			if currency.MaxAmount, err = domain.ParseDecimal(c.MaxAmount); err != nil {
+				return nil, fmt.Errorf("currency %s: invalid max_amount %q", c.Code, c.MaxAmount)
+			}
+		}
+		currencies = append(currencies, currency)
+	}
+
+	pairs := make([]domain.CurrencyPair, 0, len(market.Pairs))
+	for _, p := range market.Pairs {
+		pairs = append(pairs, domain.CurrencyPair{Base: p.Base, Quote: p.Quote})
+	}
+	return domain.NewCurrencyRegistry(currencies, pairs)
+}
+
+// buildLimitTable parses the limits section into a limit table.
+// Only the levels listed here may trade.
+func buildLimitTable(tiers []TradingLimitConfig, currencies *domain.CurrencyRegistry) (*domain.LimitTable, error) {
+	parsed := make([]domain.TradingLimits, 0, len(tiers))
+	for _, tc := range tiers {
+		tier := domain.TradingLimits{
+			Level:           domain.UserVerificationStatus(tc.Level),
+			MaxOpenRequests: tc.MaxOpenRequests,
+			Currencies:      make(map[string]domain.CurrencyLimits, len(tc.Currencies)),
+		}
+		for _, cc := range tc.Currencies {
+			if _, ok := currencies.Get(cc.Code); !ok {
+				return nil, fmt.Errorf("level %s: unknown currency %q", tc.Level, cc.Code)
+			}
+			if _, dup := tier.Currencies[cc.Code]; dup {
+				return nil, fmt.Errorf("level %s: currency %s is listed twice", tc.Level, cc.Code)
+			}
+			var limits domain.CurrencyLimits
+			for name, field := range map[string]struct {
+				raw string
+				dst *domain.Decimal
+			}{
+				"per_transaction": {cc.PerTransaction, &limits.PerTransaction},
+				"daily":           {cc.Daily, &limits.Daily},
+				"monthly":         {cc.Monthly, &limits.Monthly},
+			} {
+				if field.raw == "" {
+					continue
+				}
+				value, err := domain.ParseDecimal(field.raw)
+				if err != nil {
+					return nil, fmt.Errorf("level %s: invalid %s limit %q for %s", tc.Level, name, field.raw, cc.Code)
+				}
+				*field.dst = value
+			}
+			tier.Currencies[cc.Code] = limits
+		}
+		parsed = append(parsed, tier)
+	}
+	return domain.NewLimitTable(parsed)
+}
+
+// buildFeeSchedule parses the fees section into a fee schedule.
+// Rules may only reference currencies of the registry.
+func buildFeeSchedule(fees FeesConfig, currencies *domain.CurrencyRegistry, limits *domain.LimitTable) (*domain.FeeSchedule, error) {
+	if fees.Version == "" {
+		return nil, errors.New("version is not set")
+	}
+	schedule := &domain.FeeSchedule{Version: fees.Version}
+
+	var err error
+	if schedule.Default, err = buildFeeRule(fees.Default, currencies, limits); err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+	if schedule.Default.Base != "" || schedule.Default.Quote != "" || schedule.Default.Level != "" {
+		return nil, errors.New("default: must not set base, quote or level")
+	}
+	for i, rc := range fees.Rules {
+		rule, err := buildFeeRule(rc, currencies, limits)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		schedule.Rules = append(schedule.Rules, rule)
+	}
+	return schedule, nil
+}
+
+// buildFeeRule parses and checks one fee rule.
+func buildFeeRule(rc FeeRuleConfig, currencies *domain.CurrencyRegistry, limits *domain.LimitTable) (domain.FeeRule, error) {
+	rule := domain.FeeRule{Base: rc.Base, Quote: rc.Quote, Level: domain.UserVerificationStatus(rc.Level)}
+	for _, code := range []string{rc.Base, rc.Quote} {
+		if _, ok := currencies.Get(code); code != "" && !ok {
+			return rule, fmt.Errorf("unknown currency %q", code)
+		}
+	}
+	if rule.Level != "" && !limits.CanTrade(rule.Level) {
+		return rule, fmt.Errorf("verification level %q has no trading limits", rc.Level)
+	}
+
+	hundred := domain.NewDecimalFromInt(100)
+	for name, field := range map[string]struct {
+		raw string
+		dst *domain.Decimal
+	}{
+		"percent":        {rc.Percent, &rule.Percent},
+		"spread_percent": {rc.SpreadPercent, &rule.SpreadPercent},
+	} {
+		if field.raw == "" {
+			continue
+		}
+		value, err := domain.ParseDecimal(field.raw)
+		if err != nil || value.Sign() < 0 || value.Cmp(hundred) >= 0 {
+			return rule, fmt.Errorf("%s must be a number from 0 to below 100, got %q", name, field.raw)
+		}
+		*field.dst = value
+	}
+
+	for code, raw := range rc.Flat {
+		code = strings.ToUpper(code) // Viper lower-cases map keys
+		if _, ok := currencies.Get(code); !ok {
+			return rule, fmt.Errorf("flat fee in unknown currency %q", code)
+		}
+		value, err := domain.ParseDecimal(raw)
+		if err != nil || value.Sign() < 0 {
+			return rule, fmt.Errorf("flat fee in %s must be a non-negative number, got %q", code, raw)
+		}
+		if rule.Flat == nil {
+			rule.Flat = map[string]domain.Decimal{}
+		}
+		rule.Flat[code] = value
+	}
+	return rule, nil
+}